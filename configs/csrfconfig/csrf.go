@@ -16,8 +16,9 @@ import (
 
 func isProd() bool { return envconfig.IsProd() }
 
-// Gerekirse muaf yollar
-var csrfExemptPaths = []string{"/healthz", "/readyz"}
+// Gerekirse muaf yollar. /api/ yalnızca Bearer anahtarıyla kimlik doğrular, oturum çerezini hiç okumaz;
+// tarayıcının kendiliğinden gönderdiği bir kimlik bilgisi olmadığından CSRF kontrolüne gerek yoktur.
+var csrfExemptPaths = []string{"/healthz", "/readyz", "/webhooks/", "/api/"}
 
 // SameSite değerini env'den oku: CSRF_COOKIE_SAMESITE (Strict|Lax|None)
 // Prod varsayılan: Strict, Dev varsayılan: Lax
@@ -90,6 +91,10 @@ func SetupCSRF() fiber.Handler {
 					return true
 				}
 			}
+			// Apple girişi geri dönüşü siteler arası form POST'udur; isteği state ve bağlama çerezi korur
+			if c.Method() == fiber.MethodPost && strings.HasPrefix(path, "/auth/") && strings.HasSuffix(path, "/callback") {
+				return true
+			}
 			return false
 		},
 	}
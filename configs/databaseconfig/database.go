@@ -73,6 +73,7 @@ func InitDB() {
 	}
 
 	models.RegisterBaseModelCallbacks(DB)
+	models.RegisterAuditCallbacks(DB)
 
 	sqlDB, err := DB.DB()
 	if err != nil {
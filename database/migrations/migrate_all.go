@@ -15,21 +15,43 @@ import (
 func MigrateAll(db *gorm.DB) error {
 	logconfig.SLog.Info("Tüm migrasyon işlemleri başlatılıyor...")
 
+	// Türkçe karakterden bağımsız arama (turkishsearch) unaccent eklentisine ihtiyaç duyar
+	if err := db.Exec("CREATE EXTENSION IF NOT EXISTS unaccent").Error; err != nil {
+		logconfig.Log.Error("unaccent eklentisi kurulamadı", zap.Error(err))
+		return err
+	}
+
 	// Migrasyon sırası (foreign key ilişkilerine göre)
 	modelsToMigrate := []interface{}{
 		&models.UserType{},
+		&models.UserTypePermission{},
 		&models.User{},
+		&models.TwoFactorRecoveryCode{},
+		&models.TrustedDevice{},
+		&models.LoginLockout{},
+		&models.UserToken{},
+		&models.UserIdentity{},
+		&models.WebAuthnCredential{},
+		&models.PersonalAccessToken{},
 		&models.Country{},
 		&models.City{},
 		&models.District{},
 		&models.Address{},
 		&models.BusinessType{},
 		&models.Business{},
+		&models.BusinessSlugHistory{},
 		&models.Account{},
 		&models.Professional{},
 		&models.ProfessionalService{},
 		&models.Gallery{},
 		&models.Service{},
+		&models.SmsMessage{},
+		&models.ProfessionalWorkingHour{},
+		&models.ProfessionalTimeOff{},
+		&models.Appointment{},
+		&models.Review{},
+		&models.VenueReservation{},
+		&models.AuditLog{},
 	}
 
 	for _, model := range modelsToMigrate {
@@ -47,10 +69,166 @@ func MigrateAll(db *gorm.DB) error {
 		logconfig.SLog.Info(fmt.Sprintf("%s tablosu migrate edildi.", tableName))
 	}
 
+	if err := seedLegacyPermissions(db); err != nil {
+		logconfig.Log.Error("Rol yetkileri aktarılamadı", zap.Error(err))
+		return err
+	}
+
+	if err := dropLegacyUserTokens(db); err != nil {
+		logconfig.Log.Error("Eski kullanıcı anahtar kolonları kaldırılamadı", zap.Error(err))
+		return err
+	}
+
+	if err := moveLegacyProviderIdentities(db); err != nil {
+		logconfig.Log.Error("Eski sosyal giriş bilgileri aktarılamadı", zap.Error(err))
+		return err
+	}
+
+	if err := protectAuditLogs(db); err != nil {
+		logconfig.Log.Error("Denetim kaydı tablosu korunamadı", zap.Error(err))
+		return err
+	}
+
+	if err := grantAuditLogPermission(db); err != nil {
+		logconfig.Log.Error("Denetim kaydı yetkisi tanımlanamadı", zap.Error(err))
+		return err
+	}
+
 	logconfig.SLog.Info("Tüm migrasyon işlemleri başarıyla tamamlandı.")
 	return nil
 }
 
+// seedLegacyPermissions, yetki tablosu boşken eski sabit rol kontrollerini (1: yönetim, 2: işletme paneli)
+// yetkilere taşır; böylece yükseltilen kurulumlarda kimse panellerin dışında kalmaz.
+func seedLegacyPermissions(db *gorm.DB) error {
+	var count int64
+	if err := db.Model(&models.UserTypePermission{}).Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return nil
+	}
+
+	legacy := map[uint][]string{
+		1: models.DefaultRolePermissions["Admin"],
+		2: models.DefaultRolePermissions["User"],
+	}
+	for userTypeID, permissions := range legacy {
+		var exists int64
+		if err := db.Model(&models.UserType{}).Where("id = ?", userTypeID).Count(&exists).Error; err != nil {
+			return err
+		}
+		if exists == 0 {
+			continue
+		}
+		for _, permission := range permissions {
+			row := models.UserTypePermission{UserTypeID: userTypeID, Permission: permission}
+			if err := db.Create(&row).Error; err != nil {
+				return err
+			}
+		}
+		logconfig.SLog.Info(fmt.Sprintf("%d numaralı kullanıcı türüne varsayılan yetkiler tanımlandı.", userTypeID))
+	}
+	return nil
+}
+
+// dropLegacyUserTokens, users tablosunda düz metin tutulan eski sıfırlama ve doğrulama anahtarlarını
+// kaldırır; anahtarlar artık user_tokens tablosunda özet olarak saklanır. Bekleyen eski bağlantılar
+// geçersiz olur, kullanıcılar yeni bağlantı isteyebilir.
+func dropLegacyUserTokens(db *gorm.DB) error {
+	for _, column := range []string{"reset_token", "verification_token"} {
+		if !db.Migrator().HasColumn(&models.User{}, column) {
+			continue
+		}
+		if err := db.Migrator().DropColumn(&models.User{}, column); err != nil {
+			return err
+		}
+		logconfig.SLog.Info(fmt.Sprintf("users.%s kolonu kaldırıldı.", column))
+	}
+	return nil
+}
+
+// moveLegacyProviderIdentities, users tablosundaki tek sağlayıcılık provider/provider_id kolonlarını
+// user_identities tablosuna taşır ve kolonları kaldırır. Kullanıcı artık birden fazla sağlayıcı bağlayabilir.
+func moveLegacyProviderIdentities(db *gorm.DB) error {
+	if !db.Migrator().HasColumn(&models.User{}, "provider_id") {
+		return nil
+	}
+	return db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Exec(`INSERT INTO user_identities (user_id, provider, subject, email, created_at)
+			SELECT id, provider, provider_id, email, NOW() FROM users
+			WHERE provider <> '' AND provider_id <> ''
+			ON CONFLICT DO NOTHING`)
+		if result.Error != nil {
+			return result.Error
+		}
+		logconfig.SLog.Info(fmt.Sprintf("%d sosyal giriş kaydı user_identities tablosuna aktarıldı.", result.RowsAffected))
+
+		for _, column := range []string{"provider", "provider_id"} {
+			if err := tx.Migrator().DropColumn(&models.User{}, column); err != nil {
+				return err
+			}
+			logconfig.SLog.Info(fmt.Sprintf("users.%s kolonu kaldırıldı.", column))
+		}
+		return nil
+	})
+}
+
+// protectAuditLogs, audit_logs tablosunu yalnızca eklenebilir kılar; uygulama hatası ya da elle
+// çalıştırılan bir sorgu geçmiş kayıtları güncelleyemez, silemez.
+func protectAuditLogs(db *gorm.DB) error {
+	statements := []string{
+		`CREATE OR REPLACE FUNCTION audit_logs_append_only() RETURNS trigger AS $$
+		BEGIN
+			RAISE EXCEPTION 'audit_logs kayıtları değiştirilemez ve silinemez';
+		END;
+		$$ LANGUAGE plpgsql`,
+		`DROP TRIGGER IF EXISTS audit_logs_append_only ON audit_logs`,
+		`CREATE TRIGGER audit_logs_append_only BEFORE UPDATE OR DELETE ON audit_logs
+			FOR EACH ROW EXECUTE FUNCTION audit_logs_append_only()`,
+		`DROP TRIGGER IF EXISTS audit_logs_no_truncate ON audit_logs`,
+		`CREATE TRIGGER audit_logs_no_truncate BEFORE TRUNCATE ON audit_logs
+			FOR EACH STATEMENT EXECUTE FUNCTION audit_logs_append_only()`,
+	}
+	return db.Transaction(func(tx *gorm.DB) error {
+		for _, statement := range statements {
+			if err := tx.Exec(statement).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// grantAuditLogPermission, denetim kaydı yetkisi henüz hiçbir role verilmemişse kullanıcı yönetimi
+// yetkisine sahip rollere verir; yükseltilen kurulumlarda yöneticiler kayıtları hemen görebilir.
+// Yetki bir kez tanımlandıktan sonra rollerden alınması kalıcıdır.
+func grantAuditLogPermission(db *gorm.DB) error {
+	var granted int64
+	if err := db.Model(&models.UserTypePermission{}).
+		Where("permission = ?", models.PermissionAuditLogsView).Count(&granted).Error; err != nil {
+		return err
+	}
+	if granted > 0 {
+		return nil
+	}
+
+	var userTypeIDs []uint
+	if err := db.Model(&models.UserTypePermission{}).
+		Where("permission = ?", models.PermissionUsersManage).
+		Pluck("user_type_id", &userTypeIDs).Error; err != nil {
+		return err
+	}
+	for _, userTypeID := range userTypeIDs {
+		row := models.UserTypePermission{UserTypeID: userTypeID, Permission: models.PermissionAuditLogsView}
+		if err := db.Create(&row).Error; err != nil {
+			return err
+		}
+		logconfig.SLog.Info(fmt.Sprintf("%d numaralı kullanıcı türüne denetim kaydı yetkisi tanımlandı.", userTypeID))
+	}
+	return nil
+}
+
 // modelName fonksiyonu struct tipinin adını çözer
 func modelName(m interface{}) string {
 	typeName := fmt.Sprintf("%T", m)
@@ -27,6 +27,9 @@ func SeedUserTypes(db *gorm.DB) error {
 			CreatedBy: 1,
 			UpdatedBy: 1,
 		}
+		for _, permission := range models.DefaultRolePermissions[ut.Name] {
+			ut.Permissions = append(ut.Permissions, models.UserTypePermission{Permission: permission})
+		}
 
 		if err := db.Create(&ut).Error; err != nil {
 			logconfig.SLog.Error("Kullanıcı tipi eklenirken hata: "+ut.Name, err)
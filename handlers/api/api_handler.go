@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"strconv"
+	"time"
+
+	"zatrano/configs/envconfig"
+	"zatrano/middlewares"
+	"zatrano/models"
+	"zatrano/pkg/apiresponse"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// APIVersionPrefix, sürümlü API'nin kök yoludur; uyumsuz değişiklikler yeni bir sürümle yayınlanır.
+const APIVersionPrefix = "/api/v1"
+
+// paramID, :id parametresini okur; geçersizse 404 döner (kayıt yokmuş gibi davranılır).
+func paramID(c *fiber.Ctx) (uint, error) {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil || id == 0 {
+		return 0, fiber.NewError(fiber.StatusNotFound, "Kayıt bulunamadı.")
+	}
+	return uint(id), nil
+}
+
+func apiPath(resource string, id uint) string {
+	return APIVersionPrefix + resource + strconv.FormatUint(uint64(id), 10)
+}
+
+func uploadURL(contentType, fileName string) string {
+	return envconfig.String("APP_BASE_URL", "") + "/uploads/" + contentType + "/" + fileName
+}
+
+// TokenResource, isteği yapan anahtarın bilgileridir; entegrasyonların anahtarı denetlemesi içindir.
+type TokenResource struct {
+	Name      string     `json:"name"`
+	Prefix    string     `json:"prefix"`
+	Scopes    []string   `json:"scopes"`
+	ExpiresAt *time.Time `json:"expires_at"`
+	User      struct {
+		ID    uint   `json:"id"`
+		Name  string `json:"name"`
+		Email string `json:"email"`
+	} `json:"user"`
+}
+
+type APITokenHandler struct{}
+
+func NewAPITokenHandler() *APITokenHandler {
+	return &APITokenHandler{}
+}
+
+// Me, anahtarın adını, kapsamlarını ve sahibini döner.
+func (h *APITokenHandler) Me(c *fiber.Ctx) error {
+	token, ok := c.Locals(middlewares.APITokenLocalsKey).(*models.PersonalAccessToken)
+	if !ok {
+		return fiber.ErrUnauthorized
+	}
+	res := TokenResource{
+		Name:      token.Name,
+		Prefix:    token.Prefix,
+		Scopes:    token.ScopeList(),
+		ExpiresAt: token.ExpiresAt,
+	}
+	res.User.ID = token.User.ID
+	res.User.Name = token.User.Name
+	res.User.Email = token.User.Email
+	return apiresponse.Data(c, fiber.StatusOK, res)
+}
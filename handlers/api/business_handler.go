@@ -0,0 +1,269 @@
+package handlers
+
+import (
+	"errors"
+	"time"
+
+	"zatrano/models"
+	"zatrano/pkg/apiresponse"
+	"zatrano/pkg/currentuser"
+	"zatrano/pkg/filemanager"
+	"zatrano/pkg/queryparams"
+	"zatrano/requests"
+	"zatrano/services"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const businessContentType = "businesses"
+
+// BusinessResource, API'nin işletme gösterimidir; modelin iç alanları (created_by vb.) dışarı verilmez.
+type BusinessResource struct {
+	ID             uint                  `json:"id"`
+	BusinessTypeID uint                  `json:"business_type_id"`
+	BusinessType   string                `json:"business_type,omitempty"`
+	Title          string                `json:"title"`
+	Slug           string                `json:"slug"`
+	Description    string                `json:"description"`
+	Capacity       uint                  `json:"capacity"`
+	Gsm            string                `json:"gsm"`
+	Telephone      string                `json:"telephone"`
+	Email          string                `json:"email"`
+	Website        string                `json:"website"`
+	TaxOffice      string                `json:"tax_office"`
+	TaxNumber      string                `json:"tax_number"`
+	KEPAddress     string                `json:"kep_address"`
+	MersisNo       string                `json:"mersis_no"`
+	IbanNo         string                `json:"iban_no"`
+	Address        *AddressResource      `json:"address,omitempty"`
+	Map            string                `json:"map"`
+	Latitude       *float64              `json:"latitude"`
+	Longitude      *float64              `json:"longitude"`
+	Logo           string                `json:"logo_url,omitempty"`
+	Banner         string                `json:"banner_url,omitempty"`
+	Video          string                `json:"video"`
+	Whatapp        string                `json:"whatapp"`
+	Social         BusinessSocialProfile `json:"social"`
+	RatingAverage  float64               `json:"rating_average"`
+	RatingCount    uint                  `json:"rating_count"`
+	IsActive       bool                  `json:"is_active"`
+	CreatedAt      time.Time             `json:"created_at"`
+	UpdatedAt      time.Time             `json:"updated_at"`
+}
+
+type AddressResource struct {
+	CountryID  uint   `json:"country_id"`
+	CityID     uint   `json:"city_id"`
+	City       string `json:"city,omitempty"`
+	DistrictID uint   `json:"district_id"`
+	District   string `json:"district,omitempty"`
+	Address    string `json:"address"`
+}
+
+type BusinessSocialProfile struct {
+	Instagram string `json:"instagram"`
+	Facebook  string `json:"facebook"`
+	Twitter   string `json:"twitter"`
+	Linkedin  string `json:"linkedin"`
+	Youtube   string `json:"youtube"`
+	Tiktok    string `json:"tiktok"`
+}
+
+type BusinessTypeResource struct {
+	ID   uint   `json:"id"`
+	Name string `json:"name"`
+}
+
+func newBusinessResource(b *models.Business) BusinessResource {
+	res := BusinessResource{
+		ID:             b.ID,
+		BusinessTypeID: b.BusinessTypeID,
+		Title:          b.Title,
+		Slug:           b.Slug,
+		Description:    b.Description,
+		Capacity:       b.Capacity,
+		Gsm:            b.Gsm,
+		Telephone:      b.Telephone,
+		Email:          b.Email,
+		Website:        b.Website,
+		TaxOffice:      b.TaxOffice,
+		TaxNumber:      b.TaxNumber,
+		KEPAddress:     b.KEPAddress,
+		MersisNo:       b.MersisNo,
+		IbanNo:         b.IbanNo,
+		Map:            b.Map,
+		Latitude:       b.Latitude,
+		Longitude:      b.Longitude,
+		Video:          b.Video,
+		Whatapp:        b.Whatapp,
+		Social: BusinessSocialProfile{
+			Instagram: b.Instagram,
+			Facebook:  b.Facebook,
+			Twitter:   b.Twitter,
+			Linkedin:  b.Linkedin,
+			Youtube:   b.Youtube,
+			Tiktok:    b.Tiktok,
+		},
+		RatingAverage: b.RatingAverage,
+		RatingCount:   b.RatingCount,
+		IsActive:      b.IsActive,
+		CreatedAt:     b.CreatedAt,
+		UpdatedAt:     b.UpdatedAt,
+	}
+	if b.BusinessType != nil {
+		res.BusinessType = b.BusinessType.Name
+	}
+	if b.Logo != "" {
+		res.Logo = uploadURL(businessContentType, b.Logo)
+	}
+	if b.Banner != "" {
+		res.Banner = uploadURL(businessContentType, b.Banner)
+	}
+	if a := b.Address; a != nil {
+		res.Address = &AddressResource{
+			CountryID:  a.CountryID,
+			CityID:     a.CityID,
+			DistrictID: a.DistrictID,
+			Address:    a.Address,
+		}
+		if a.City != nil {
+			res.Address.City = a.City.Name
+		}
+		if a.District != nil {
+			res.Address.District = a.District.Name
+		}
+	}
+	return res
+}
+
+type APIBusinessHandler struct {
+	businessService services.IBusinessService
+}
+
+func NewAPIBusinessHandler() *APIBusinessHandler {
+	return &APIBusinessHandler{businessService: services.NewBusinessService()}
+}
+
+// ListBusinesses, anahtar sahibinin işletmelerini sayfalı döner (?name=&sortBy=&orderBy=&page=&perPage=).
+func (h *APIBusinessHandler) ListBusinesses(c *fiber.Ctx) error {
+	params, fieldErrors, err := requests.ParseAndValidateBusinessList(c)
+	if err != nil {
+		return apiresponse.ValidationError(c, "Sorgu parametreleri geçersiz.", fieldErrors)
+	}
+
+	result, err := h.businessService.GetUserBusinesses(c.UserContext(), currentuser.FromFiber(c).ID, params)
+	if err != nil {
+		return err
+	}
+	businesses, _ := result.Data.([]models.Business)
+	data := make([]BusinessResource, 0, len(businesses))
+	for i := range businesses {
+		data = append(data, newBusinessResource(&businesses[i]))
+	}
+	return apiresponse.List(c, data, queryparams.PaginationMeta(result.Meta))
+}
+
+func (h *APIBusinessHandler) GetBusiness(c *fiber.Ctx) error {
+	id, err := paramID(c)
+	if err != nil {
+		return err
+	}
+	business, err := h.businessService.GetUserBusinessByID(c.UserContext(), currentuser.FromFiber(c).ID, id)
+	if err != nil {
+		return businessError(c, err)
+	}
+	return apiresponse.Data(c, fiber.StatusOK, newBusinessResource(business))
+}
+
+// CreateBusiness; logo ve banner API üzerinden yüklenmez, panelden eklenir.
+func (h *APIBusinessHandler) CreateBusiness(c *fiber.Ctx) error {
+	req, fieldErrors, err := requests.ParseAndValidateBusinessAPIRequest(c)
+	if err != nil {
+		if len(fieldErrors) == 0 {
+			return apiresponse.Error(c, fiber.StatusBadRequest, apiresponse.CodeBadRequest, err.Error())
+		}
+		return apiresponse.ValidationError(c, "İşletme bilgileri geçersiz.", fieldErrors)
+	}
+
+	userID := currentuser.FromFiber(c).ID
+	created, err := h.businessService.CreateBusiness(c.UserContext(), userID, req, services.BusinessMedia{})
+	if err != nil {
+		return businessError(c, err)
+	}
+	business, err := h.businessService.GetUserBusinessByID(c.UserContext(), userID, created.ID)
+	if err != nil {
+		business = created
+	}
+	c.Set(fiber.HeaderLocation, apiPath("/businesses/", business.ID))
+	return apiresponse.Data(c, fiber.StatusCreated, newBusinessResource(business))
+}
+
+// UpdateBusiness, işletmenin tüm alanlarını gönderilen gövdeyle değiştirir; logo ve banner korunur.
+func (h *APIBusinessHandler) UpdateBusiness(c *fiber.Ctx) error {
+	id, err := paramID(c)
+	if err != nil {
+		return err
+	}
+	req, fieldErrors, err := requests.ParseAndValidateBusinessAPIRequest(c)
+	if err != nil {
+		if len(fieldErrors) == 0 {
+			return apiresponse.Error(c, fiber.StatusBadRequest, apiresponse.CodeBadRequest, err.Error())
+		}
+		return apiresponse.ValidationError(c, "İşletme bilgileri geçersiz.", fieldErrors)
+	}
+
+	userID := currentuser.FromFiber(c).ID
+	if err := h.businessService.UpdateBusiness(c.UserContext(), userID, id, req, services.BusinessMedia{}); err != nil {
+		return businessError(c, err)
+	}
+	business, err := h.businessService.GetUserBusinessByID(c.UserContext(), userID, id)
+	if err != nil {
+		return businessError(c, err)
+	}
+	return apiresponse.Data(c, fiber.StatusOK, newBusinessResource(business))
+}
+
+func (h *APIBusinessHandler) DeleteBusiness(c *fiber.Ctx) error {
+	id, err := paramID(c)
+	if err != nil {
+		return err
+	}
+	business, err := h.businessService.DeleteBusiness(c.UserContext(), currentuser.FromFiber(c).ID, id)
+	if err != nil {
+		return businessError(c, err)
+	}
+	filemanager.DeleteFile(businessContentType, business.Logo)
+	filemanager.DeleteFile(businessContentType, business.Banner)
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// ListBusinessTypes, işletme oluştururken kullanılacak business_type_id değerlerini döner.
+func (h *APIBusinessHandler) ListBusinessTypes(c *fiber.Ctx) error {
+	types, err := h.businessService.GetBusinessTypes(c.UserContext())
+	if err != nil {
+		return err
+	}
+	data := make([]BusinessTypeResource, 0, len(types))
+	for _, t := range types {
+		data = append(data, BusinessTypeResource{ID: t.ID, Name: t.Name})
+	}
+	return apiresponse.Data(c, fiber.StatusOK, data)
+}
+
+// businessError, servis hatalarını HTTP durumlarına çevirir; referans hataları ilgili alana yazılır.
+func businessError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, services.ErrBusinessNotFound):
+		return apiresponse.Error(c, fiber.StatusNotFound, apiresponse.CodeNotFound, "İşletme bulunamadı.")
+	case errors.Is(err, services.ErrBusinessSlugTaken):
+		return apiresponse.Error(c, fiber.StatusConflict, apiresponse.CodeConflict, "Bu bağlantı adı başka bir işletme tarafından kullanılıyor.")
+	case errors.Is(err, services.ErrBusinessSlugInvalid):
+		return apiresponse.ValidationError(c, "İşletme bilgileri geçersiz.", map[string]string{"slug": "Bağlantı adı geçerli karakter içermiyor."})
+	case errors.Is(err, services.ErrBusinessTypeNotFound):
+		return apiresponse.ValidationError(c, "İşletme bilgileri geçersiz.", map[string]string{"business_type_id": "İşletme türü bulunamadı."})
+	case errors.Is(err, services.ErrBusinessAddress):
+		return apiresponse.ValidationError(c, "İşletme bilgileri geçersiz.", map[string]string{"district_id": "Seçilen il/ilçe bilgisi tutarsız."})
+	default:
+		return err
+	}
+}
@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"zatrano/pkg/flashmessages"
+	"zatrano/pkg/renderer"
+	"zatrano/requests"
+	"zatrano/services"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// CreateAccessToken, kişisel API erişim anahtarı oluşturur. Anahtar yalnızca bu yanıtta gösterilir.
+func (h *AuthHandler) CreateAccessToken(c *fiber.Ctx) error {
+	userID, err := h.getSessionUser(c)
+	if err != nil {
+		return h.handleError(c, services.ErrUserNotFound, 0, "", "Erişim Anahtarı Oluşturma")
+	}
+	req, ok := c.Locals("accessTokenRequest").(requests.AccessTokenRequest)
+	if !ok {
+		_ = flashmessages.SetFlashMessage(c, flashmessages.FlashErrorKey, "Geçersiz istek formatı")
+		return c.Redirect("/auth/profile", fiber.StatusSeeOther)
+	}
+
+	days, _ := strconv.Atoi(req.ExpiresInDays)
+	raw, token, err := h.accessTokens.Create(c.UserContext(), userID, req.Name, req.Scopes, days)
+	if err != nil {
+		msg := "Erişim anahtarı oluşturulamadı. Lütfen tekrar deneyin."
+		switch {
+		case errors.Is(err, services.ErrAccessTokenScope):
+			msg = "En az bir geçerli kapsam seçilmelidir."
+		case errors.Is(err, services.ErrAccessTokenLimit):
+			msg = "Etkin erişim anahtarı sınırına ulaştınız. Kullanmadığınız bir anahtarı iptal edin."
+		}
+		_ = flashmessages.SetFlashMessage(c, flashmessages.FlashErrorKey, msg)
+		return c.Redirect("/auth/profile", fiber.StatusSeeOther)
+	}
+
+	return renderer.Render(c, "auth/access_token_created", "layouts/auth", fiber.Map{
+		"Title":   "Erişim Anahtarı",
+		"Token":   token,
+		"Raw":     raw,
+		"Success": "Erişim anahtarı oluşturuldu.",
+	}, http.StatusOK)
+}
+
+// RevokeAccessToken, anahtarı iptal eder; anahtarla yapılan sonraki istekler reddedilir.
+func (h *AuthHandler) RevokeAccessToken(c *fiber.Ctx) error {
+	userID, err := h.getSessionUser(c)
+	if err != nil {
+		return h.handleError(c, services.ErrUserNotFound, 0, "", "Erişim Anahtarı İptali")
+	}
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err == nil {
+		err = h.accessTokens.Revoke(c.UserContext(), userID, uint(id))
+	} else {
+		err = services.ErrAccessTokenNotFound
+	}
+	switch {
+	case errors.Is(err, services.ErrAccessTokenNotFound):
+		_ = flashmessages.SetFlashMessage(c, flashmessages.FlashErrorKey, "Erişim anahtarı bulunamadı veya zaten iptal edilmiş.")
+		return c.Redirect("/auth/profile", fiber.StatusSeeOther)
+	case err != nil:
+		return h.handleError(c, err, userID, "", "Erişim Anahtarı İptali")
+	}
+
+	_ = flashmessages.SetFlashMessage(c, flashmessages.FlashSuccessKey, "Erişim anahtarı iptal edildi.")
+	return c.Redirect("/auth/profile", fiber.StatusSeeOther)
+}
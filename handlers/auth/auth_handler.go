@@ -1,10 +1,9 @@
 package handlers
 
 import (
-	"crypto/rand"
-	"encoding/hex"
+	"errors"
 	"net/http"
-	"os"
+	"time"
 
 	"zatrano/configs/logconfig"
 	"zatrano/configs/sessionconfig"
@@ -19,15 +18,30 @@ import (
 )
 
 type AuthHandler struct {
-	service    services.IAuthService
-	mailSender services.IMailService
+	service      services.IAuthService
+	twoFactor    services.ITwoFactorService
+	sessions     services.ISessionService
+	throttle     services.ILoginThrottleService
+	oauth        services.IOAuthService
+	magicLinks   services.IMagicLinkService
+	passkeys     services.IPasskeyService
+	accessTokens services.IPersonalAccessTokenService
+	login        *loginFlow
 }
 
 func NewAuthHandler() *AuthHandler {
-	return &AuthHandler{
-		service:    services.NewAuthService(),
-		mailSender: services.NewMailService(),
-	}
+	h := &AuthHandler{
+		service:      services.NewAuthService(),
+		twoFactor:    services.NewTwoFactorService(),
+		sessions:     services.NewSessionService(),
+		throttle:     services.NewLoginThrottleService(),
+		oauth:        services.NewOAuthService(),
+		magicLinks:   services.NewMagicLinkService(),
+		passkeys:     services.NewPasskeyService(),
+		accessTokens: services.NewPersonalAccessTokenService(),
+	}
+	h.login = newLoginFlow(h.service, h.twoFactor, h.sessions, h.throttle)
+	return h
 }
 
 func (h *AuthHandler) handleError(c *fiber.Ctx, err error, userID uint, email string, action string) error {
@@ -146,6 +160,7 @@ func (h *AuthHandler) ShowLogin(c *fiber.Ctx) error {
 		"Title":               "Giriş Yap",
 		"PendingVerification": pendingVerification,
 		"UserEmail":           userEmail,
+		"OAuthProviders":      h.oauth.Providers(),
 	}, http.StatusOK)
 }
 
@@ -156,8 +171,19 @@ func (h *AuthHandler) Login(c *fiber.Ctx) error {
 		return c.Redirect("/auth/login", fiber.StatusSeeOther)
 	}
 
+	// Hesap ya da IP kilitliyse parola hiç denenmez
+	if locked, err := h.login.locked(c, req.Email); locked {
+		return err
+	}
+
 	user, err := h.service.Authenticate(req.Email, req.Password)
 	if err != nil {
+		// Kayıtlı olmayan e-postalar da sayılır; aksi halde kilit davranışı hesabın varlığını ele verirdi
+		if errors.Is(err, services.ErrInvalidCredentials) || errors.Is(err, services.ErrUserNotFound) {
+			if locked, err := h.login.recordFailure(c, req.Email, nil); locked {
+				return err
+			}
+		}
 		return h.handleError(c, err, 0, req.Email, "Login")
 	}
 
@@ -174,32 +200,8 @@ func (h *AuthHandler) Login(c *fiber.Ctx) error {
 		return c.Redirect("/auth/login", fiber.StatusSeeOther)
 	}
 
-	// Normal oturum açma işlemleri...
-	sess, err := sessionconfig.SessionStart(c)
-	if err != nil {
-		logconfig.Log.Error("Oturum başlatılamadı",
-			zap.Uint("user_id", user.ID),
-			zap.String("email", user.Email),
-			zap.Error(err))
-		return h.handleError(c, fiber.ErrInternalServerError, user.ID, user.Email, "Login")
-	}
-
-	sess.Set("user_id", user.ID)
-	sess.Set("user_type_id", user.UserTypeID)
-	sess.Set("is_active", user.IsActive)
-	if err := sess.Save(); err != nil {
-		logconfig.Log.Error("Oturum kaydedilemedi",
-			zap.Uint("user_id", user.ID),
-			zap.String("email", user.Email),
-			zap.Error(err))
-		return h.handleError(c, fiber.ErrInternalServerError, user.ID, user.Email, "Login")
-	}
-
-	_ = flashmessages.SetFlashMessage(c, flashmessages.FlashSuccessKey, "Başarıyla giriş yapıldı")
-	if user.UserTypeID == 1 {
-		return c.Redirect("/dashboard/home", fiber.StatusFound)
-	}
-	return c.Redirect("/panel/anasayfa", fiber.StatusFound)
+	// İki adımlı doğrulama etkinse oturum ikinci adımdan sonra açılır
+	return h.login.start(c, user, "Başarıyla giriş yapıldı")
 }
 
 func (h *AuthHandler) Profile(c *fiber.Ctx) error {
@@ -216,13 +218,51 @@ func (h *AuthHandler) Profile(c *fiber.Ctx) error {
 		return h.handleError(c, err, userID, "", "Profil")
 	}
 
+	twoFactor, err := h.twoFactor.Status(c.UserContext(), user)
+	if err != nil {
+		return h.handleError(c, err, userID, user.Email, "Profil")
+	}
+
+	var currentSessionID string
+	if sess, err := sessionconfig.SessionStart(c); err == nil {
+		currentSessionID = sess.ID()
+	}
+	sessions, err := h.sessions.List(c.UserContext(), userID, currentSessionID)
+	if err != nil {
+		logconfig.Log.Error("Profil: Oturumlar listelenemedi", zap.Uint("user_id", userID), zap.Error(err))
+	}
+	linkedAccounts, err := h.oauth.LinkedAccounts(c.UserContext(), userID)
+	if err != nil {
+		logconfig.Log.Error("Profil: Bağlı hesaplar listelenemedi", zap.Uint("user_id", userID), zap.Error(err))
+	}
+	passkeys, err := h.passkeys.List(c.UserContext(), userID)
+	if err != nil {
+		logconfig.Log.Error("Profil: Geçiş anahtarları listelenemedi", zap.Uint("user_id", userID), zap.Error(err))
+	}
+	accessTokens, err := h.accessTokens.List(c.UserContext(), userID)
+	if err != nil {
+		logconfig.Log.Error("Profil: Erişim anahtarları listelenemedi", zap.Uint("user_id", userID), zap.Error(err))
+	}
+
 	return renderer.Render(c, "auth/profile", "layouts/auth", fiber.Map{
-		"Title": "Profilim",
-		"User":  user,
+		"Title":          "Profilim",
+		"User":           user,
+		"TwoFactor":      twoFactor,
+		"Sessions":       sessions,
+		"LinkedAccounts": linkedAccounts,
+		"Passkeys":       passkeys,
+		"AccessTokens":   accessTokens,
+		"TokenScopes":    models.TokenScopeCatalog,
+		"Now":            time.Now(),
 	}, http.StatusOK)
 }
 
 func (h *AuthHandler) Logout(c *fiber.Ctx) error {
+	if sess, err := sessionconfig.SessionStart(c); err == nil {
+		if userID, err := h.getSessionUser(c); err == nil {
+			_ = h.sessions.Unregister(c.UserContext(), userID, sess.ID())
+		}
+	}
 	h.destroySession(c)
 	_ = flashmessages.SetFlashMessage(c, flashmessages.FlashSuccessKey, "Başarıyla çıkış yapıldı.")
 	return c.Redirect("/auth/login", fiber.StatusFound)
@@ -253,18 +293,11 @@ func (h *AuthHandler) UpdatePassword(c *fiber.Ctx) error {
 
 func (h *AuthHandler) ShowRegister(c *fiber.Ctx) error {
 	return renderer.Render(c, "auth/register", "layouts/auth", fiber.Map{
-		"Title": "Kayıt Ol",
+		"Title":          "Kayıt Ol",
+		"OAuthProviders": h.oauth.Providers(),
 	}, http.StatusOK)
 }
 
-func generateToken() (string, error) {
-	b := make([]byte, 16)
-	if _, err := rand.Read(b); err != nil {
-		return "", err
-	}
-	return hex.EncodeToString(b), nil
-}
-
 func (h *AuthHandler) Register(c *fiber.Ctx) error {
 	req, ok := c.Locals("registerRequest").(requests.RegisterRequest)
 	if !ok {
@@ -273,22 +306,10 @@ func (h *AuthHandler) Register(c *fiber.Ctx) error {
 	}
 
 	user := &models.User{
-		Name:              req.Name,
-		Email:             req.Email,
-		Password:          req.Password,
-		UserTypeID:        ptrUint(2),
-		ResetToken:        "",
-		EmailVerified:     false,
-		VerificationToken: "",
-		Provider:          "",
-		ProviderID:        "",
-	}
-
-	if token, err := generateToken(); err == nil {
-		user.ResetToken = token
-	}
-	if vtoken, err := generateToken(); err == nil {
-		user.VerificationToken = vtoken
+		Name:          req.Name,
+		Email:         req.Email,
+		Password:      req.Password,
+		EmailVerified: false,
 	}
 
 	if err := h.service.CreateUser(c.UserContext(), user); err != nil {
@@ -297,17 +318,21 @@ func (h *AuthHandler) Register(c *fiber.Ctx) error {
 	}
 
 	_ = flashmessages.SetFlashMessage(c, flashmessages.FlashSuccessKey, "Kayıt işlemi tamamlandı. Lütfen email adresinizi doğrulayın.")
-	baseURL := os.Getenv("APP_BASE_URL")
-	verificationLink := baseURL + "/auth/verify-email?token=" + user.VerificationToken
-	_ = h.mailSender.SendMail(user.Email, "Email Doğrulama", "Lütfen doğrulamak için tıklayın: "+verificationLink)
+	if err := h.service.SendVerificationLink(c.UserContext(), user, c.Get(fiber.HeaderUserAgent), c.IP()); err != nil {
+		logconfig.Log.Error("Kayıt: Doğrulama bağlantısı gönderilemedi", zap.Uint("user_id", user.ID), zap.Error(err))
+	}
 
 	return renderer.Render(c, "auth/verify_email_notice", "layouts/auth", fiber.Map{
 		"Title": "Email Doğrulama",
 	}, http.StatusOK)
 }
 
-func ptrUint(v uint) uint {
-	return v
+// tokenErrorMessage, e-postadaki bağlantının geçersiz ya da süresi dolmuş olmasını diğer hatalardan ayırır.
+func tokenErrorMessage(err error, action string) string {
+	if errors.Is(err, services.ErrUserTokenInvalid) {
+		return "Bağlantı geçersiz, süresi dolmuş ya da daha önce kullanılmış. Lütfen yeni bir bağlantı isteyin."
+	}
+	return action + " başarısız."
 }
 
 func (h *AuthHandler) ShowForgotPassword(c *fiber.Ctx) error {
@@ -322,7 +347,7 @@ func (h *AuthHandler) ForgotPassword(c *fiber.Ctx) error {
 		_ = flashmessages.SetFlashMessage(c, flashmessages.FlashErrorKey, "Geçersiz istek")
 		return c.Redirect("/auth/forgot-password", fiber.StatusSeeOther)
 	}
-	if err := h.service.SendPasswordResetLink(req.Email); err != nil {
+	if err := h.service.SendPasswordResetLink(c.UserContext(), req.Email, c.Get(fiber.HeaderUserAgent), c.IP()); err != nil {
 		_ = flashmessages.SetFlashMessage(c, flashmessages.FlashErrorKey, "Şifre sıfırlama bağlantısı gönderilemedi.")
 		return c.Redirect("/auth/forgot-password", fiber.StatusSeeOther)
 	}
@@ -336,6 +361,10 @@ func (h *AuthHandler) ShowResetPassword(c *fiber.Ctx) error {
 		_ = flashmessages.SetFlashMessage(c, flashmessages.FlashErrorKey, "Geçersiz veya eksik token.")
 		return c.Redirect("/auth/forgot-password", fiber.StatusSeeOther)
 	}
+	if err := h.service.CheckResetToken(c.UserContext(), token); err != nil {
+		_ = flashmessages.SetFlashMessage(c, flashmessages.FlashErrorKey, tokenErrorMessage(err, "Şifre sıfırlama"))
+		return c.Redirect("/auth/forgot-password", fiber.StatusSeeOther)
+	}
 	return renderer.Render(c, "auth/reset_password", "layouts/auth", fiber.Map{
 		"Title": "Şifre Sıfırla",
 		"Token": token,
@@ -348,9 +377,9 @@ func (h *AuthHandler) ResetPassword(c *fiber.Ctx) error {
 		_ = flashmessages.SetFlashMessage(c, flashmessages.FlashErrorKey, "Geçersiz veya eksik token.")
 		return c.Redirect("/auth/forgot-password", fiber.StatusSeeOther)
 	}
-	if err := h.service.ResetPassword(req.Token, req.NewPassword); err != nil {
-		_ = flashmessages.SetFlashMessage(c, flashmessages.FlashErrorKey, "Şifre sıfırlama başarısız.")
-		return c.Redirect("/auth/reset-password", fiber.StatusSeeOther)
+	if err := h.service.ResetPassword(c.UserContext(), req.Token, req.NewPassword, c.Get(fiber.HeaderUserAgent), c.IP()); err != nil {
+		_ = flashmessages.SetFlashMessage(c, flashmessages.FlashErrorKey, tokenErrorMessage(err, "Şifre sıfırlama"))
+		return c.Redirect("/auth/forgot-password", fiber.StatusSeeOther)
 	}
 	_ = flashmessages.SetFlashMessage(c, flashmessages.FlashSuccessKey, "Şifre sıfırlandı. Lütfen giriş yapın.")
 	return c.Redirect("/auth/login", fiber.StatusSeeOther)
@@ -362,9 +391,9 @@ func (h *AuthHandler) VerifyEmail(c *fiber.Ctx) error {
 		_ = flashmessages.SetFlashMessage(c, flashmessages.FlashErrorKey, "Doğrulama tokeni eksik veya geçersiz.")
 		return c.Redirect("/auth/forgot-password", fiber.StatusSeeOther)
 	}
-	if err := h.service.VerifyEmail(token); err != nil {
-		_ = flashmessages.SetFlashMessage(c, flashmessages.FlashErrorKey, "Email doğrulama başarısız.")
-		return c.Redirect("/auth/forgot-password", fiber.StatusSeeOther)
+	if err := h.service.VerifyEmail(c.UserContext(), token, c.Get(fiber.HeaderUserAgent), c.IP()); err != nil {
+		_ = flashmessages.SetFlashMessage(c, flashmessages.FlashErrorKey, tokenErrorMessage(err, "Email doğrulama"))
+		return c.Redirect("/auth/resend-verification", fiber.StatusSeeOther)
 	}
 	_ = flashmessages.SetFlashMessage(c, flashmessages.FlashSuccessKey, "Email başarıyla doğrulandı.")
 	return c.Redirect("/auth/login", fiber.StatusSeeOther)
@@ -386,7 +415,7 @@ func (h *AuthHandler) ResendVerification(c *fiber.Ctx) error {
 		_ = flashmessages.SetFlashMessage(c, flashmessages.FlashErrorKey, "Geçersiz istek")
 		return c.Redirect("/auth/resend-verification", fiber.StatusSeeOther)
 	}
-	if err := h.service.ResendVerificationLink(req.Email); err != nil {
+	if err := h.service.ResendVerificationLink(c.UserContext(), req.Email, c.Get(fiber.HeaderUserAgent), c.IP()); err != nil {
 		_ = flashmessages.SetFlashMessage(c, flashmessages.FlashErrorKey, "Doğrulama linki gönderilemedi.")
 		return c.Redirect("/auth/resend-verification", fiber.StatusSeeOther)
 	}
@@ -0,0 +1,123 @@
+package handlers
+
+import (
+	"fmt"
+	"time"
+
+	"zatrano/configs/logconfig"
+	"zatrano/configs/sessionconfig"
+	"zatrano/models"
+	"zatrano/pkg/flashmessages"
+	"zatrano/services"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+// loginFlow, parola ve OAuth girişlerinin ortak son adımıdır: gerekirse iki adımlı doğrulamaya
+// yönlendirir, ardından oturumu açıp oturum dizinine kaydeder.
+type loginFlow struct {
+	auth      services.IAuthService
+	twoFactor services.ITwoFactorService
+	sessions  services.ISessionService
+	throttle  services.ILoginThrottleService
+}
+
+func newLoginFlow(auth services.IAuthService, twoFactor services.ITwoFactorService, sessions services.ISessionService, throttle services.ILoginThrottleService) *loginFlow {
+	return &loginFlow{auth: auth, twoFactor: twoFactor, sessions: sessions, throttle: throttle}
+}
+
+// locked, hesap ya da IP kilitliyse kullanıcıyı bilgilendirip giriş sayfasına yönlendirir.
+// Kilit durumu okunamazsa giriş engellenmez.
+func (f *loginFlow) locked(c *fiber.Ctx, email string) (bool, error) {
+	remaining, err := f.throttle.Check(c.UserContext(), email, c.IP())
+	if err != nil || remaining <= 0 {
+		return false, nil
+	}
+	return true, f.lockedOut(c, remaining)
+}
+
+// recordFailure, başarısız denemeyi sayar; deneme bir kilidi tetiklediyse kullanıcıyı yönlendirir.
+func (f *loginFlow) recordFailure(c *fiber.Ctx, email string, userID *uint) (bool, error) {
+	duration, err := f.throttle.RecordFailure(c.UserContext(), email, c.IP(), userID)
+	if err != nil || duration <= 0 {
+		return false, nil
+	}
+	return true, f.lockedOut(c, duration)
+}
+
+func (f *loginFlow) lockedOut(c *fiber.Ctx, remaining time.Duration) error {
+	_ = flashmessages.SetFlashMessage(c, flashmessages.FlashErrorKey,
+		"Çok fazla başarısız giriş denemesi. Lütfen "+lockoutWait(remaining)+" sonra tekrar deneyin.")
+	return c.Redirect("/auth/login", fiber.StatusSeeOther)
+}
+
+// lockoutWait, kalan süreyi yukarı yuvarlayarak "3 dakika" ya da "45 saniye" biçiminde yazar.
+func lockoutWait(d time.Duration) string {
+	if d > time.Minute {
+		return fmt.Sprintf("%d dakika", int((d+time.Minute-1)/time.Minute))
+	}
+	return fmt.Sprintf("%d saniye", int((d+time.Second-1)/time.Second))
+}
+
+// start, parola veya OAuth doğrulamasından sonra çağrılır. İki adımlı doğrulama etkinse ve
+// tarayıcı güvenilen cihaz değilse oturumu açmadan ikinci adıma yönlendirir.
+func (f *loginFlow) start(c *fiber.Ctx, user *models.User, successMessage string) error {
+	if !user.TwoFactorEnabled() || f.twoFactor.IsTrustedDevice(c.UserContext(), user.ID, c.Cookies(trustedDeviceCookie)) {
+		return f.complete(c, user, successMessage)
+	}
+
+	sess, err := sessionconfig.SessionStart(c)
+	if err != nil {
+		logconfig.Log.Error("Oturum başlatılamadı", zap.Uint("user_id", user.ID), zap.Error(err))
+		_ = flashmessages.SetFlashMessage(c, flashmessages.FlashErrorKey, "Oturum başlatılamadı. Lütfen tekrar deneyin.")
+		return c.Redirect("/auth/login", fiber.StatusSeeOther)
+	}
+
+	sess.Set(twoFactorPendingUserKey, user.ID)
+	sess.Set(twoFactorPendingAtKey, time.Now().Unix())
+	sess.Set(twoFactorPendingAttemptKey, 0)
+	sess.Set(twoFactorPendingMessageKey, successMessage)
+	if err := sess.Save(); err != nil {
+		logconfig.Log.Error("Oturum kaydedilemedi", zap.Uint("user_id", user.ID), zap.Error(err))
+		_ = flashmessages.SetFlashMessage(c, flashmessages.FlashErrorKey, "Oturum başlatılamadı. Lütfen tekrar deneyin.")
+		return c.Redirect("/auth/login", fiber.StatusSeeOther)
+	}
+
+	return c.Redirect("/auth/two-factor", fiber.StatusSeeOther)
+}
+
+// complete, tüm doğrulamalar tamamlandıktan sonra oturumu açar.
+func (f *loginFlow) complete(c *fiber.Ctx, user *models.User, successMessage string) error {
+	sess, err := sessionconfig.SessionStart(c)
+	if err != nil {
+		logconfig.Log.Error("Oturum başlatılamadı",
+			zap.Uint("user_id", user.ID),
+			zap.String("email", user.Email),
+			zap.Error(err))
+		_ = flashmessages.SetFlashMessage(c, flashmessages.FlashErrorKey, "Oturum başlatılamadı. Lütfen tekrar deneyin.")
+		return c.Redirect("/auth/login", fiber.StatusSeeOther)
+	}
+
+	sess.Set("user_id", user.ID)
+	sess.Set("user_type_id", user.UserTypeID)
+	sess.Set("is_active", user.IsActive)
+	// Dizine eklenemezse oturum yine açılır; AuthMiddleware ilk istekte yeniden dener
+	if err := f.sessions.Register(c.UserContext(), user.ID, sess.ID(), c.Get(fiber.HeaderUserAgent), c.IP()); err == nil {
+		sess.Set(services.SessionIndexedKey, true)
+	}
+	if err := sess.Save(); err != nil {
+		logconfig.Log.Error("Oturum kaydedilemedi",
+			zap.Uint("user_id", user.ID),
+			zap.String("email", user.Email),
+			zap.Error(err))
+		_ = flashmessages.SetFlashMessage(c, flashmessages.FlashErrorKey, "Oturum kaydedilemedi. Lütfen tekrar deneyin.")
+		return c.Redirect("/auth/login", fiber.StatusSeeOther)
+	}
+	// Sayaç ancak tüm adımlar tamamlanınca sıfırlanır; parolayı bilen biri iki adımlı doğrulama
+	// kodunu denerken sayacı sıfırlayamaz
+	_ = f.throttle.RecordSuccess(c.UserContext(), user.Email)
+
+	_ = flashmessages.SetFlashMessage(c, flashmessages.FlashSuccessKey, successMessage)
+	return c.Redirect(f.auth.LandingPath(user), fiber.StatusFound)
+}
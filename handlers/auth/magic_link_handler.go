@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"zatrano/configs/envconfig"
+	"zatrano/configs/logconfig"
+	"zatrano/pkg/flashmessages"
+	"zatrano/pkg/renderer"
+	"zatrano/requests"
+	"zatrano/services"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+// magicLinkBrowserCookie, giriş bağlantısını isteyen tarayıcıyı tanır; bağlantı yalnızca bu çerezle çalışır.
+const magicLinkBrowserCookie = "magic_link_browser"
+
+// setMagicLinkBrowserCookie; e-postadaki bağlantı siteler arası üst düzey GET ile açıldığından Lax yeterlidir.
+func setMagicLinkBrowserCookie(c *fiber.Ctx, value string, expires time.Time) {
+	c.Cookie(&fiber.Cookie{
+		Name:     magicLinkBrowserCookie,
+		Value:    value,
+		Path:     "/auth",
+		Expires:  expires,
+		HTTPOnly: true,
+		Secure:   envconfig.IsProd(),
+		SameSite: "Lax",
+	})
+}
+
+func (h *AuthHandler) ShowMagicLink(c *fiber.Ctx) error {
+	return renderer.Render(c, "auth/magic_link", "layouts/auth", fiber.Map{
+		"Title": "Giriş Bağlantısı",
+	}, http.StatusOK)
+}
+
+func (h *AuthHandler) SendMagicLink(c *fiber.Ctx) error {
+	req, ok := c.Locals("magicLinkRequest").(requests.MagicLinkRequest)
+	if !ok {
+		_ = flashmessages.SetFlashMessage(c, flashmessages.FlashErrorKey, "Geçersiz istek")
+		return c.Redirect("/auth/magic-link", fiber.StatusSeeOther)
+	}
+
+	browser, err := h.magicLinks.Send(c.UserContext(), req.Email, c.Get(fiber.HeaderUserAgent), c.IP())
+	if err != nil {
+		msg := "Giriş bağlantısı gönderilemedi. Lütfen tekrar deneyin."
+		if errors.Is(err, services.ErrMagicLinkRateLimited) {
+			msg = "Bu adres için çok fazla giriş bağlantısı istendi. Lütfen biraz sonra tekrar deneyin."
+		} else {
+			logconfig.Log.Error("Giriş bağlantısı gönderilemedi", zap.String("email", req.Email), zap.Error(err))
+		}
+		_ = flashmessages.SetFlashMessage(c, flashmessages.FlashErrorKey, msg)
+		return c.Redirect("/auth/magic-link", fiber.StatusSeeOther)
+	}
+
+	setMagicLinkBrowserCookie(c, browser, time.Now().Add(h.magicLinks.LinkTTL()))
+	_ = flashmessages.SetFlashMessage(c, flashmessages.FlashSuccessKey,
+		"Adres kayıtlıysa giriş bağlantısı gönderildi. Bağlantıyı bu tarayıcıda açın.")
+	return c.Redirect("/auth/login", fiber.StatusSeeOther)
+}
+
+// MagicLinkLogin, e-postadaki bağlantıyla giriş yapar. İki adımlı doğrulama etkinse yine istenir.
+func (h *AuthHandler) MagicLinkLogin(c *fiber.Ctx) error {
+	browser := c.Cookies(magicLinkBrowserCookie)
+	result, err := h.magicLinks.Login(c.UserContext(), c.Query("token"), browser, c.Get(fiber.HeaderUserAgent), c.IP())
+	if err != nil {
+		msg := tokenErrorMessage(err, "Bağlantıyla giriş")
+		switch {
+		case errors.Is(err, services.ErrUserTokenWrongBrowser):
+			msg = "Bu giriş bağlantısı başka bir tarayıcıda istendi. Lütfen bağlantıyı istediğiniz tarayıcıda açın ya da yeni bir bağlantı isteyin."
+		case errors.Is(err, services.ErrUserInactive):
+			msg = "Hesabınız aktif değil. Lütfen yöneticinizle iletişime geçin."
+		}
+		_ = flashmessages.SetFlashMessage(c, flashmessages.FlashErrorKey, msg)
+		_ = c.Redirect("/auth/login", fiber.StatusSeeOther)
+		return sameSiteRedirect(c)
+	}
+
+	setMagicLinkBrowserCookie(c, "", time.Now().Add(-time.Hour))
+	// Bağlantı kullanılmış olur; kilit süresi dolunca yeni bağlantı istenmelidir
+	if locked, err := h.login.locked(c, result.User.Email); locked {
+		if err != nil {
+			return err
+		}
+		return sameSiteRedirect(c)
+	}
+
+	msg := "Giriş bağlantısıyla giriş yapıldı."
+	if result.PasswordCleared {
+		msg += " E-posta adresiniz doğrulandı; adres doğrulanmadan önce hesaba tanımlanmış parola güvenlik nedeniyle kaldırıldı." +
+			" Profil sayfanızdan yeni bir parola belirleyebilirsiniz."
+	}
+	if err := h.login.start(c, result.User, msg); err != nil {
+		return err
+	}
+	return sameSiteRedirect(c)
+}
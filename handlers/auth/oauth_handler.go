@@ -0,0 +1,170 @@
+package handlers
+
+import (
+	"errors"
+	"html"
+	"net/http"
+	"time"
+
+	"zatrano/configs/envconfig"
+	"zatrano/configs/logconfig"
+	"zatrano/pkg/flashmessages"
+	"zatrano/services"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+// oauthBindingCookie, yetkilendirmeyi başlatan tarayıcıyı geri dönüşe bağlar; state tek başına
+// yeterli değildir, aksi halde saldırgan kendi state'iyle kurbanı kendi hesabına giriş yaptırabilirdi.
+const oauthBindingCookie = "oauth_binding"
+
+var oauthErrorMessages = map[error]string{
+	services.ErrOAuthUnknownProvider:   "Bu giriş yöntemi kullanılamıyor.",
+	services.ErrOAuthInvalidState:      "Giriş isteği geçersiz ya da süresi dolmuş. Lütfen tekrar deneyin.",
+	services.ErrOAuthEmailMissing:      "Sağlayıcı e-posta adresinizi paylaşmadı. Lütfen e-posta izni vererek tekrar deneyin.",
+	services.ErrOAuthEmailUnverified:   "Bu e-posta adresiyle kayıtlı bir hesap var. Önce şifrenizle giriş yapıp hesabı profilinizden bağlayın.",
+	services.ErrOAuthAccountUnverified: "Bu e-posta adresiyle doğrulanmamış bir hesap var. Önce e-postanızı doğrulayın ya da şifrenizle giriş yapıp hesabı profilinizden bağlayın.",
+	services.ErrOAuthIdentityTaken:     "Bu hesap başka bir kullanıcıya bağlı.",
+	services.ErrOAuthAlreadyLinked:     "Bu sağlayıcıya zaten bir hesap bağlı.",
+	services.ErrOAuthLastLoginMethod:   "Son giriş yönteminizi kaldırmadan önce bir şifre belirleyin.",
+	services.ErrOAuthIdentityNotFound:  "Bağlı hesap bulunamadı.",
+	services.ErrUserInactive:           "Hesabınız aktif değil. Lütfen yöneticinizle iletişime geçin.",
+}
+
+func oauthErrorMessage(err error) string {
+	for target, msg := range oauthErrorMessages {
+		if errors.Is(err, target) {
+			return msg
+		}
+	}
+	return "Dış hesapla giriş başarısız. Lütfen tekrar deneyin."
+}
+
+// setOAuthBindingCookie; Apple geri dönüşü siteler arası form POST'u olduğundan prod'da çerez
+// SameSite=None olmalıdır, geliştirmede (HTTP) Secure olmayan None çerezi tarayıcılar reddeder.
+func setOAuthBindingCookie(c *fiber.Ctx, value string, expires time.Time) {
+	sameSite := "Lax"
+	if envconfig.IsProd() {
+		sameSite = "None"
+	}
+	c.Cookie(&fiber.Cookie{
+		Name:     oauthBindingCookie,
+		Value:    value,
+		Path:     "/auth",
+		Expires:  expires,
+		HTTPOnly: true,
+		Secure:   envconfig.IsProd(),
+		SameSite: sameSite,
+	})
+}
+
+// sameSiteRedirect, geri dönüşün sonundaki yönlendirmeyi sayfa içinden yapar. Başka bir siteden
+// (kimlik sağlayıcısı ya da e-posta istemcisi) başlayan yönlendirme zinciri siteler arası sayıldığı
+// için tarayıcı SameSite=Strict oturum çerezini göndermez; sayfa üzerinden yapılan yönlendirme ise
+// aynı siteden gelir ve yeni oturum görünür.
+func sameSiteRedirect(c *fiber.Ctx) error {
+	status := c.Response().StatusCode()
+	location := string(c.Response().Header.Peek(fiber.HeaderLocation))
+	if status < 300 || status >= 400 || location == "" {
+		return nil
+	}
+	c.Response().Header.Del(fiber.HeaderLocation)
+	target := html.EscapeString(location)
+	c.Set(fiber.HeaderContentType, fiber.MIMETextHTMLCharsetUTF8)
+	return c.Status(http.StatusOK).SendString(`<!DOCTYPE html><html><head><meta charset="utf-8">` +
+		`<meta http-equiv="refresh" content="0;url=` + target + `"></head>` +
+		`<body><a href="` + target + `">Devam et</a></body></html>`)
+}
+
+// OAuthLogin, misafir kullanıcıyı sağlayıcının giriş sayfasına yönlendirir.
+func (h *AuthHandler) OAuthLogin(c *fiber.Ctx) error {
+	return h.beginOAuth(c, services.OAuthModeLogin, 0, "/auth/login")
+}
+
+// LinkOAuth, oturumdaki kullanıcının hesabına yeni bir sağlayıcı bağlamak için yetkilendirmeyi başlatır.
+func (h *AuthHandler) LinkOAuth(c *fiber.Ctx) error {
+	userID, err := h.getSessionUser(c)
+	if err != nil {
+		return h.handleError(c, services.ErrUserNotFound, 0, "", "Hesap Bağlama")
+	}
+	return h.beginOAuth(c, services.OAuthModeLink, userID, "/auth/profile")
+}
+
+func (h *AuthHandler) beginOAuth(c *fiber.Ctx, mode string, userID uint, fallback string) error {
+	start, err := h.oauth.Begin(c.UserContext(), c.Params("provider"), mode, userID)
+	if err != nil {
+		_ = flashmessages.SetFlashMessage(c, flashmessages.FlashErrorKey, oauthErrorMessage(err))
+		return c.Redirect(fallback, fiber.StatusSeeOther)
+	}
+	setOAuthBindingCookie(c, start.Binding, time.Now().Add(15*time.Minute))
+	return c.Redirect(start.URL, fiber.StatusSeeOther)
+}
+
+// OAuthCallback, sağlayıcıdan dönen isteği karşılar. Google, Microsoft ve Facebook GET ile,
+// Apple form POST ile döner; iki durumda da parametreler aynı adla okunur.
+func (h *AuthHandler) OAuthCallback(c *fiber.Ctx) error {
+	binding := c.Cookies(oauthBindingCookie)
+	setOAuthBindingCookie(c, "", time.Now().Add(-time.Hour))
+
+	currentUserID, _ := h.getSessionUser(c)
+	fallback := "/auth/login"
+	if currentUserID != 0 {
+		fallback = "/auth/profile"
+	}
+
+	param := func(key string) string {
+		if v := c.FormValue(key); v != "" {
+			return v
+		}
+		return c.Query(key)
+	}
+
+	if providerErr := param("error"); providerErr != "" {
+		// Kullanıcı izin vermediyse state yine de tüketilir; aynı geri dönüş tekrar kullanılamaz
+		_, _ = h.oauth.Complete(c.UserContext(), c.Params("provider"), param("state"), binding, "", nil, currentUserID)
+		logconfig.Log.Info("OAuth sağlayıcısı hata döndürdü", zap.String("provider", c.Params("provider")), zap.String("error", providerErr))
+		_ = flashmessages.SetFlashMessage(c, flashmessages.FlashErrorKey, "Dış hesapla giriş iptal edildi.")
+		_ = c.Redirect(fallback, fiber.StatusSeeOther)
+		return sameSiteRedirect(c)
+	}
+
+	result, err := h.oauth.Complete(c.UserContext(), c.Params("provider"), param("state"), binding, param("code"), param, currentUserID)
+	if err != nil {
+		_ = flashmessages.SetFlashMessage(c, flashmessages.FlashErrorKey, oauthErrorMessage(err))
+		_ = c.Redirect(fallback, fiber.StatusSeeOther)
+		return sameSiteRedirect(c)
+	}
+
+	if result.Mode == services.OAuthModeLink {
+		_ = flashmessages.SetFlashMessage(c, flashmessages.FlashSuccessKey, result.Provider.DisplayName+" hesabı bağlandı.")
+		_ = c.Redirect("/auth/profile", fiber.StatusSeeOther)
+		return sameSiteRedirect(c)
+	}
+
+	if err := h.login.start(c, result.User, result.Provider.DisplayName+" ile giriş başarılı."); err != nil {
+		return err
+	}
+	return sameSiteRedirect(c)
+}
+
+// UnlinkOAuth, sağlayıcı bağlantısını kaldırır.
+func (h *AuthHandler) UnlinkOAuth(c *fiber.Ctx) error {
+	userID, err := h.getSessionUser(c)
+	if err != nil {
+		return h.handleError(c, services.ErrUserNotFound, 0, "", "Hesap Bağlantısını Kaldırma")
+	}
+
+	provider := c.Params("provider")
+	if err := h.oauth.Unlink(c.UserContext(), userID, provider); err != nil {
+		_ = flashmessages.SetFlashMessage(c, flashmessages.FlashErrorKey, oauthErrorMessage(err))
+		return c.Redirect("/auth/profile", fiber.StatusSeeOther)
+	}
+
+	name := provider
+	if info, ok := h.oauth.Provider(provider); ok {
+		name = info.DisplayName
+	}
+	_ = flashmessages.SetFlashMessage(c, flashmessages.FlashSuccessKey, name+" hesabının bağlantısı kaldırıldı.")
+	return c.Redirect("/auth/profile", fiber.StatusSeeOther)
+}
@@ -0,0 +1,175 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"strconv"
+
+	"zatrano/configs/logconfig"
+	"zatrano/configs/sessionconfig"
+	"zatrano/pkg/flashmessages"
+	"zatrano/services"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+// Tören durumu oturumda tutulur; Finish isteği aynı tarayıcıdan gelmek zorundadır ve durum bir kez kullanılır.
+const (
+	passkeyRegistrationKey = "webauthn_registration"
+	passkeyLoginKey        = "webauthn_login"
+)
+
+var passkeyErrorMessages = map[error]string{
+	services.ErrPasskeyUnavailable: "Geçiş anahtarı ile giriş şu anda kullanılamıyor.",
+	services.ErrPasskeyInvalid:     "Geçiş anahtarı doğrulanamadı. Lütfen tekrar deneyin.",
+	services.ErrPasskeyCloned:      "Bu geçiş anahtarı güvenlik nedeniyle kullanılamıyor. Lütfen başka bir yöntemle giriş yapıp anahtarı kaldırın.",
+	services.ErrPasskeyNotFound:    "Geçiş anahtarı bulunamadı.",
+	services.ErrUserInactive:       "Hesabınız aktif değil. Lütfen yöneticinizle iletişime geçin.",
+}
+
+func passkeyErrorMessage(err error) string {
+	for target, msg := range passkeyErrorMessages {
+		if errors.Is(err, target) {
+			return msg
+		}
+	}
+	return "Geçiş anahtarı işlemi başarısız. Lütfen tekrar deneyin."
+}
+
+func passkeyError(c *fiber.Ctx, status int, err error) error {
+	return c.Status(status).JSON(fiber.Map{"error": passkeyErrorMessage(err)})
+}
+
+// jsonRedirect, giriş akışının ürettiği yönlendirmeyi tarayıcıdaki betiğin izleyeceği JSON yanıta çevirir;
+// fetch ile yapılan isteklerde 303 yanıtı sayfayı değiştirmez.
+func jsonRedirect(c *fiber.Ctx) error {
+	location := string(c.Response().Header.Peek(fiber.HeaderLocation))
+	c.Response().Header.Del(fiber.HeaderLocation)
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"redirect": location})
+}
+
+// takePasskeyState, oturumdaki tören durumunu okur ve siler.
+func takePasskeyState(c *fiber.Ctx, key string) string {
+	sess, err := sessionconfig.SessionStart(c)
+	if err != nil {
+		return ""
+	}
+	state, _ := sess.Get(key).(string)
+	sess.Delete(key)
+	_ = sess.Save()
+	return state
+}
+
+func storePasskeyState(c *fiber.Ctx, key, state string) error {
+	sess, err := sessionconfig.SessionStart(c)
+	if err != nil {
+		return err
+	}
+	sess.Set(key, state)
+	return sess.Save()
+}
+
+// BeginPasskeyLogin, cihazda kayıtlı anahtarlardan birini seçtirecek doğrulama seçeneklerini döner.
+func (h *AuthHandler) BeginPasskeyLogin(c *fiber.Ctx) error {
+	assertion, state, err := h.passkeys.BeginLogin(c.UserContext())
+	if err != nil {
+		return passkeyError(c, fiber.StatusServiceUnavailable, err)
+	}
+	if err := storePasskeyState(c, passkeyLoginKey, state); err != nil {
+		logconfig.Log.Error("Geçiş anahtarı durumu oturuma yazılamadı", zap.Error(err))
+		return passkeyError(c, fiber.StatusInternalServerError, err)
+	}
+	return c.JSON(assertion)
+}
+
+// FinishPasskeyLogin, imzalı yanıtı doğrular ve oturumu açar. Cihaz kullanıcıyı doğruladıysa (PIN,
+// biyometri) anahtar tek başına iki adımlı sayılır; aksi halde etkinse TOTP yine istenir.
+func (h *AuthHandler) FinishPasskeyLogin(c *fiber.Ctx) error {
+	state := takePasskeyState(c, passkeyLoginKey)
+	result, err := h.passkeys.FinishLogin(c.UserContext(), state, bytes.NewReader(c.Body()))
+	if err != nil {
+		return passkeyError(c, fiber.StatusUnauthorized, err)
+	}
+	if locked, err := h.login.locked(c, result.User.Email); locked {
+		if err != nil {
+			return err
+		}
+		return jsonRedirect(c)
+	}
+
+	if result.UserVerified {
+		err = h.login.complete(c, result.User, "Geçiş anahtarı ile giriş yapıldı.")
+	} else {
+		err = h.login.start(c, result.User, "Geçiş anahtarı ile giriş yapıldı.")
+	}
+	if err != nil {
+		return err
+	}
+	return jsonRedirect(c)
+}
+
+// BeginPasskeyRegistration, oturumdaki kullanıcı için yeni anahtar oluşturma seçeneklerini döner.
+func (h *AuthHandler) BeginPasskeyRegistration(c *fiber.Ctx) error {
+	userID, err := h.getSessionUser(c)
+	if err != nil {
+		return passkeyError(c, fiber.StatusUnauthorized, err)
+	}
+	user, err := h.service.GetUserProfile(userID)
+	if err != nil {
+		return passkeyError(c, fiber.StatusUnauthorized, err)
+	}
+
+	creation, state, err := h.passkeys.BeginRegistration(c.UserContext(), user)
+	if err != nil {
+		return passkeyError(c, fiber.StatusServiceUnavailable, err)
+	}
+	if err := storePasskeyState(c, passkeyRegistrationKey, state); err != nil {
+		logconfig.Log.Error("Geçiş anahtarı durumu oturuma yazılamadı", zap.Uint("user_id", userID), zap.Error(err))
+		return passkeyError(c, fiber.StatusInternalServerError, err)
+	}
+	return c.JSON(creation)
+}
+
+// FinishPasskeyRegistration, tarayıcının oluşturduğu anahtarı doğrulayıp kaydeder; ad ?name= ile gelir.
+func (h *AuthHandler) FinishPasskeyRegistration(c *fiber.Ctx) error {
+	userID, err := h.getSessionUser(c)
+	if err != nil {
+		return passkeyError(c, fiber.StatusUnauthorized, err)
+	}
+	user, err := h.service.GetUserProfile(userID)
+	if err != nil {
+		return passkeyError(c, fiber.StatusUnauthorized, err)
+	}
+
+	state := takePasskeyState(c, passkeyRegistrationKey)
+	credential, err := h.passkeys.FinishRegistration(c.UserContext(), user, state, c.Query("name"), bytes.NewReader(c.Body()))
+	if err != nil {
+		return passkeyError(c, fiber.StatusBadRequest, err)
+	}
+
+	_ = flashmessages.SetFlashMessage(c, flashmessages.FlashSuccessKey, "\""+credential.Name+"\" geçiş anahtarı eklendi.")
+	return c.JSON(fiber.Map{"redirect": "/auth/profile"})
+}
+
+// DeletePasskey, oturumdaki kullanıcının bir geçiş anahtarını kaldırır.
+func (h *AuthHandler) DeletePasskey(c *fiber.Ctx) error {
+	userID, err := h.getSessionUser(c)
+	if err != nil {
+		return h.handleError(c, services.ErrUserNotFound, 0, "", "Geçiş Anahtarı Silme")
+	}
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err == nil {
+		err = h.passkeys.Delete(c.UserContext(), userID, uint(id))
+	} else {
+		err = services.ErrPasskeyNotFound
+	}
+	if err != nil {
+		_ = flashmessages.SetFlashMessage(c, flashmessages.FlashErrorKey, passkeyErrorMessage(err))
+		return c.Redirect("/auth/profile", fiber.StatusSeeOther)
+	}
+
+	_ = flashmessages.SetFlashMessage(c, flashmessages.FlashSuccessKey, "Geçiş anahtarı kaldırıldı.")
+	return c.Redirect("/auth/profile", fiber.StatusSeeOther)
+}
@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"errors"
+
+	"zatrano/configs/sessionconfig"
+	"zatrano/pkg/flashmessages"
+	"zatrano/services"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// RevokeSession, kullanıcının başka bir cihazdaki oturumunu kapatır.
+func (h *AuthHandler) RevokeSession(c *fiber.Ctx) error {
+	userID, err := h.getSessionUser(c)
+	if err != nil {
+		return h.handleError(c, services.ErrUserNotFound, 0, "", "Oturum Kapatma")
+	}
+
+	err = h.sessions.Revoke(c.UserContext(), userID, c.Params("handle"))
+	switch {
+	case errors.Is(err, services.ErrSessionNotFound):
+		_ = flashmessages.SetFlashMessage(c, flashmessages.FlashErrorKey, "Oturum bulunamadı veya zaten kapatılmış.")
+		return c.Redirect("/auth/profile", fiber.StatusSeeOther)
+	case err != nil:
+		return h.handleError(c, err, userID, "", "Oturum Kapatma")
+	}
+
+	_ = flashmessages.SetFlashMessage(c, flashmessages.FlashSuccessKey, "Oturum kapatıldı.")
+	return c.Redirect("/auth/profile", fiber.StatusSeeOther)
+}
+
+// RevokeOtherSessions, mevcut oturum dışındaki tüm oturumları kapatır.
+func (h *AuthHandler) RevokeOtherSessions(c *fiber.Ctx) error {
+	userID, err := h.getSessionUser(c)
+	if err != nil {
+		return h.handleError(c, services.ErrUserNotFound, 0, "", "Diğer Oturumları Kapatma")
+	}
+	sess, err := sessionconfig.SessionStart(c)
+	if err != nil {
+		return h.handleError(c, err, userID, "", "Diğer Oturumları Kapatma")
+	}
+
+	count, err := h.sessions.RevokeOthers(c.UserContext(), userID, sess.ID())
+	if err != nil {
+		return h.handleError(c, err, userID, "", "Diğer Oturumları Kapatma")
+	}
+
+	if count == 0 {
+		_ = flashmessages.SetFlashMessage(c, flashmessages.FlashSuccessKey, "Açık başka oturum yok.")
+	} else {
+		_ = flashmessages.SetFlashMessage(c, flashmessages.FlashSuccessKey, "Diğer tüm oturumlar kapatıldı.")
+	}
+	return c.Redirect("/auth/profile", fiber.StatusSeeOther)
+}
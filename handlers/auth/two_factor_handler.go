@@ -0,0 +1,355 @@
+package handlers
+
+import (
+	"encoding/base64"
+	"errors"
+	"html/template"
+	"net/http"
+	"time"
+
+	"zatrano/configs/envconfig"
+	"zatrano/configs/logconfig"
+	"zatrano/configs/sessionconfig"
+	"zatrano/models"
+	"zatrano/pkg/flashmessages"
+	"zatrano/pkg/renderer"
+	"zatrano/requests"
+	"zatrano/services"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+const (
+	// Parolası doğrulanmış ama ikinci adımı bekleyen kullanıcı oturumda bu anahtarlarla tutulur
+	twoFactorPendingUserKey    = "two_factor_user_id"
+	twoFactorPendingAtKey      = "two_factor_started_at"
+	twoFactorPendingAttemptKey = "two_factor_attempts"
+	twoFactorPendingMessageKey = "two_factor_success_message"
+	// Kurulum tamamlanana kadar yeni anahtar veritabanına değil oturuma yazılır
+	twoFactorSetupSecretKey = "two_factor_setup_secret"
+
+	trustedDeviceCookie = "trusted_device"
+
+	twoFactorPendingTTL  = 5 * time.Minute
+	twoFactorMaxAttempts = 5
+)
+
+var twoFactorErrorMessages = map[error]string{
+	services.ErrTwoFactorInvalidCode:    "Doğrulama kodu hatalı.",
+	services.ErrTwoFactorCodeUsed:       "Bu kod zaten kullanıldı. Lütfen uygulamanızdaki yeni kodu bekleyin.",
+	services.ErrTwoFactorAlreadyEnabled: "İki adımlı doğrulama zaten etkin.",
+	services.ErrTwoFactorNotEnabled:     "İki adımlı doğrulama etkin değil.",
+	services.ErrTwoFactorRequired:       "Rolünüz iki adımlı doğrulamayı zorunlu kıldığı için kapatılamaz.",
+}
+
+func twoFactorErrorMessage(err error) string {
+	for known, msg := range twoFactorErrorMessages {
+		if errors.Is(err, known) {
+			return msg
+		}
+	}
+	return "İşlem sırasında bir sorun oluştu. Lütfen tekrar deneyin."
+}
+
+func sessionUint(v interface{}) uint {
+	switch n := v.(type) {
+	case uint:
+		return n
+	case int:
+		return uint(n)
+	case float64:
+		return uint(n)
+	}
+	return 0
+}
+
+func sessionInt64(v interface{}) int64 {
+	switch n := v.(type) {
+	case int64:
+		return n
+	case int:
+		return int64(n)
+	case float64:
+		return int64(n)
+	}
+	return 0
+}
+
+func setTrustedDeviceCookie(c *fiber.Ctx, value string, expires time.Time) {
+	c.Cookie(&fiber.Cookie{
+		Name:     trustedDeviceCookie,
+		Value:    value,
+		Path:     "/auth",
+		Expires:  expires,
+		HTTPOnly: true,
+		Secure:   envconfig.IsProd(),
+		SameSite: "Lax",
+	})
+}
+
+func clearTrustedDeviceCookie(c *fiber.Ctx) {
+	setTrustedDeviceCookie(c, "", time.Now().Add(-time.Hour))
+}
+
+// pendingTwoFactorUser, ikinci adımı bekleyen kullanıcının ID'sini döner; süre dolduysa bekleyen girişi siler.
+func pendingTwoFactorUser(c *fiber.Ctx) uint {
+	sess, err := sessionconfig.SessionStart(c)
+	if err != nil {
+		return 0
+	}
+	userID := sessionUint(sess.Get(twoFactorPendingUserKey))
+	if userID == 0 {
+		return 0
+	}
+	startedAt := time.Unix(sessionInt64(sess.Get(twoFactorPendingAtKey)), 0)
+	if time.Since(startedAt) > twoFactorPendingTTL {
+		clearPendingTwoFactor(c)
+		return 0
+	}
+	return userID
+}
+
+func clearPendingTwoFactor(c *fiber.Ctx) {
+	sess, err := sessionconfig.SessionStart(c)
+	if err != nil {
+		return
+	}
+	sess.Delete(twoFactorPendingUserKey)
+	sess.Delete(twoFactorPendingAtKey)
+	sess.Delete(twoFactorPendingAttemptKey)
+	sess.Delete(twoFactorPendingMessageKey)
+	_ = sess.Save()
+}
+
+func (h *AuthHandler) ShowTwoFactorChallenge(c *fiber.Ctx) error {
+	if pendingTwoFactorUser(c) == 0 {
+		_ = flashmessages.SetFlashMessage(c, flashmessages.FlashErrorKey, "Doğrulama süresi doldu. Lütfen tekrar giriş yapın.")
+		return c.Redirect("/auth/login", fiber.StatusSeeOther)
+	}
+
+	return renderer.Render(c, "auth/two_factor", "layouts/auth", fiber.Map{
+		"Title":     "İki Adımlı Doğrulama",
+		"TrustDays": int(h.twoFactor.TrustDuration().Hours() / 24),
+	}, http.StatusOK)
+}
+
+func (h *AuthHandler) TwoFactorChallenge(c *fiber.Ctx) error {
+	userID := pendingTwoFactorUser(c)
+	if userID == 0 {
+		_ = flashmessages.SetFlashMessage(c, flashmessages.FlashErrorKey, "Doğrulama süresi doldu. Lütfen tekrar giriş yapın.")
+		return c.Redirect("/auth/login", fiber.StatusSeeOther)
+	}
+
+	req, ok := c.Locals("twoFactorChallengeRequest").(requests.TwoFactorChallengeRequest)
+	if !ok {
+		_ = flashmessages.SetFlashMessage(c, flashmessages.FlashErrorKey, "Geçersiz istek formatı")
+		return c.Redirect("/auth/two-factor", fiber.StatusSeeOther)
+	}
+
+	user, err := h.service.GetUserProfile(userID)
+	if err != nil || !user.IsActive {
+		clearPendingTwoFactor(c)
+		_ = flashmessages.SetFlashMessage(c, flashmessages.FlashErrorKey, "Kullanıcı bulunamadı, lütfen tekrar giriş yapın.")
+		return c.Redirect("/auth/login", fiber.StatusSeeOther)
+	}
+
+	if locked, err := h.login.locked(c, user.Email); locked {
+		clearPendingTwoFactor(c)
+		return err
+	}
+
+	sess, err := sessionconfig.SessionStart(c)
+	if err != nil {
+		return h.handleError(c, err, userID, user.Email, "İki Adımlı Doğrulama")
+	}
+
+	recoveryUsed, err := h.twoFactor.Verify(c.UserContext(), user, req.Code)
+	if err != nil {
+		// Hatalı kodlar parola denemeleriyle aynı hesap ve IP sayaçlarına yazılır
+		if locked, lockErr := h.login.recordFailure(c, user.Email, &user.ID); locked {
+			clearPendingTwoFactor(c)
+			return lockErr
+		}
+
+		attempts := sessionInt64(sess.Get(twoFactorPendingAttemptKey)) + 1
+		logconfig.Log.Warn("İki adımlı doğrulama başarısız",
+			zap.Uint("user_id", userID),
+			zap.Int64("attempt", attempts),
+			zap.String("ip", c.IP()),
+			zap.Error(err))
+
+		if attempts >= twoFactorMaxAttempts {
+			clearPendingTwoFactor(c)
+			_ = flashmessages.SetFlashMessage(c, flashmessages.FlashErrorKey, "Çok fazla hatalı deneme yaptınız. Lütfen tekrar giriş yapın.")
+			return c.Redirect("/auth/login", fiber.StatusSeeOther)
+		}
+		sess.Set(twoFactorPendingAttemptKey, attempts)
+		_ = sess.Save()
+
+		_ = flashmessages.SetFlashMessage(c, flashmessages.FlashErrorKey, twoFactorErrorMessage(err))
+		return c.Redirect("/auth/two-factor", fiber.StatusSeeOther)
+	}
+
+	successMessage, _ := sess.Get(twoFactorPendingMessageKey).(string)
+	if successMessage == "" {
+		successMessage = "Başarıyla giriş yapıldı"
+	}
+	if recoveryUsed {
+		successMessage += " Bir kurtarma kodu kullandınız; kalan kodlarınızı profilinizden kontrol edin."
+	}
+	clearPendingTwoFactor(c)
+
+	if req.TrustDevice {
+		token, err := h.twoFactor.TrustDevice(c.UserContext(), user.ID, c.Get(fiber.HeaderUserAgent), c.IP())
+		if err == nil {
+			setTrustedDeviceCookie(c, token, time.Now().Add(h.twoFactor.TrustDuration()))
+		}
+	}
+
+	return h.login.complete(c, user, successMessage)
+}
+
+// setupUser, iki adımlı doğrulama sayfaları için oturumdaki kullanıcıyı yükler.
+func (h *AuthHandler) setupUser(c *fiber.Ctx) (*models.User, error) {
+	userID, err := h.getSessionUser(c)
+	if err != nil {
+		return nil, err
+	}
+	return h.service.GetUserProfile(userID)
+}
+
+func (h *AuthHandler) ShowTwoFactorSetup(c *fiber.Ctx) error {
+	user, err := h.setupUser(c)
+	if err != nil {
+		return h.handleError(c, services.ErrUserNotFound, 0, "", "İki Adımlı Doğrulama Kurulumu")
+	}
+	if user.TwoFactorEnabled() {
+		_ = flashmessages.SetFlashMessage(c, flashmessages.FlashSuccessKey, "İki adımlı doğrulama zaten etkin.")
+		return c.Redirect("/auth/profile", fiber.StatusSeeOther)
+	}
+
+	sess, err := sessionconfig.SessionStart(c)
+	if err != nil {
+		return h.handleError(c, err, user.ID, user.Email, "İki Adımlı Doğrulama Kurulumu")
+	}
+	secret, _ := sess.Get(twoFactorSetupSecretKey).(string)
+	if secret == "" {
+		if secret, err = h.twoFactor.NewSecret(); err != nil {
+			return h.handleError(c, err, user.ID, user.Email, "İki Adımlı Doğrulama Kurulumu")
+		}
+		sess.Set(twoFactorSetupSecretKey, secret)
+		if err := sess.Save(); err != nil {
+			return h.handleError(c, err, user.ID, user.Email, "İki Adımlı Doğrulama Kurulumu")
+		}
+	}
+
+	qr, err := h.twoFactor.ProvisioningQR(user.Email, secret)
+	if err != nil {
+		return h.handleError(c, err, user.ID, user.Email, "İki Adımlı Doğrulama Kurulumu")
+	}
+
+	return renderer.Render(c, "auth/two_factor_setup", "layouts/auth", fiber.Map{
+		"Title":    "İki Adımlı Doğrulama Kurulumu",
+		"Secret":   secret,
+		"QRCode":   template.URL("data:image/png;base64," + base64.StdEncoding.EncodeToString(qr)),
+		"Required": user.UserType.RequireTwoFactor,
+	}, http.StatusOK)
+}
+
+func (h *AuthHandler) EnableTwoFactor(c *fiber.Ctx) error {
+	user, err := h.setupUser(c)
+	if err != nil {
+		return h.handleError(c, services.ErrUserNotFound, 0, "", "İki Adımlı Doğrulama Etkinleştirme")
+	}
+	req, ok := c.Locals("twoFactorCodeRequest").(requests.TwoFactorCodeRequest)
+	if !ok {
+		_ = flashmessages.SetFlashMessage(c, flashmessages.FlashErrorKey, "Geçersiz istek formatı")
+		return c.Redirect("/auth/two-factor/setup", fiber.StatusSeeOther)
+	}
+
+	sess, err := sessionconfig.SessionStart(c)
+	if err != nil {
+		return h.handleError(c, err, user.ID, user.Email, "İki Adımlı Doğrulama Etkinleştirme")
+	}
+	secret, _ := sess.Get(twoFactorSetupSecretKey).(string)
+	if secret == "" {
+		_ = flashmessages.SetFlashMessage(c, flashmessages.FlashErrorKey, "Kurulum süresi doldu. Lütfen QR kodu yeniden okutun.")
+		return c.Redirect("/auth/two-factor/setup", fiber.StatusSeeOther)
+	}
+
+	codes, err := h.twoFactor.Enable(c.UserContext(), user, secret, req.Code)
+	if err != nil {
+		_ = flashmessages.SetFlashMessage(c, flashmessages.FlashErrorKey, twoFactorErrorMessage(err))
+		return c.Redirect("/auth/two-factor/setup", fiber.StatusSeeOther)
+	}
+
+	sess.Delete(twoFactorSetupSecretKey)
+	_ = sess.Save()
+
+	return renderer.Render(c, "auth/two_factor_recovery_codes", "layouts/auth", fiber.Map{
+		"Title":   "Kurtarma Kodları",
+		"Codes":   codes,
+		"Success": "İki adımlı doğrulama etkinleştirildi.",
+	}, http.StatusOK)
+}
+
+func (h *AuthHandler) RegenerateRecoveryCodes(c *fiber.Ctx) error {
+	user, err := h.setupUser(c)
+	if err != nil {
+		return h.handleError(c, services.ErrUserNotFound, 0, "", "Kurtarma Kodu Yenileme")
+	}
+	req, ok := c.Locals("twoFactorCodeRequest").(requests.TwoFactorCodeRequest)
+	if !ok {
+		_ = flashmessages.SetFlashMessage(c, flashmessages.FlashErrorKey, "Geçersiz istek formatı")
+		return c.Redirect("/auth/profile", fiber.StatusSeeOther)
+	}
+
+	codes, err := h.twoFactor.RegenerateRecoveryCodes(c.UserContext(), user, req.Code)
+	if err != nil {
+		_ = flashmessages.SetFlashMessage(c, flashmessages.FlashErrorKey, twoFactorErrorMessage(err))
+		return c.Redirect("/auth/profile", fiber.StatusSeeOther)
+	}
+
+	return renderer.Render(c, "auth/two_factor_recovery_codes", "layouts/auth", fiber.Map{
+		"Title":   "Kurtarma Kodları",
+		"Codes":   codes,
+		"Success": "Yeni kurtarma kodları oluşturuldu. Eski kodlar artık geçersiz.",
+	}, http.StatusOK)
+}
+
+func (h *AuthHandler) DisableTwoFactor(c *fiber.Ctx) error {
+	user, err := h.setupUser(c)
+	if err != nil {
+		return h.handleError(c, services.ErrUserNotFound, 0, "", "İki Adımlı Doğrulama Kapatma")
+	}
+	req, ok := c.Locals("twoFactorCodeRequest").(requests.TwoFactorCodeRequest)
+	if !ok {
+		_ = flashmessages.SetFlashMessage(c, flashmessages.FlashErrorKey, "Geçersiz istek formatı")
+		return c.Redirect("/auth/profile", fiber.StatusSeeOther)
+	}
+
+	if err := h.twoFactor.Disable(c.UserContext(), user, req.Code); err != nil {
+		_ = flashmessages.SetFlashMessage(c, flashmessages.FlashErrorKey, twoFactorErrorMessage(err))
+		return c.Redirect("/auth/profile", fiber.StatusSeeOther)
+	}
+
+	clearTrustedDeviceCookie(c)
+	_ = flashmessages.SetFlashMessage(c, flashmessages.FlashSuccessKey, "İki adımlı doğrulama kapatıldı.")
+	return c.Redirect("/auth/profile", fiber.StatusSeeOther)
+}
+
+func (h *AuthHandler) ForgetTrustedDevices(c *fiber.Ctx) error {
+	userID, err := h.getSessionUser(c)
+	if err != nil {
+		return h.handleError(c, services.ErrUserNotFound, 0, "", "Güvenilen Cihazları Unutma")
+	}
+
+	if err := h.twoFactor.ForgetDevices(c.UserContext(), userID); err != nil {
+		return h.handleError(c, err, userID, "", "Güvenilen Cihazları Unutma")
+	}
+
+	clearTrustedDeviceCookie(c)
+	_ = flashmessages.SetFlashMessage(c, flashmessages.FlashSuccessKey, "Güvenilen cihazlar kaldırıldı. Sonraki girişlerde doğrulama kodu istenecek.")
+	return c.Redirect("/auth/profile", fiber.StatusSeeOther)
+}
@@ -0,0 +1,125 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"zatrano/models"
+	"zatrano/pkg/flashmessages"
+	"zatrano/pkg/renderer"
+	"zatrano/requests"
+	"zatrano/services"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type DashboardAuditLogHandler struct {
+	auditLogService services.IAuditLogService
+}
+
+func NewDashboardAuditLogHandler() *DashboardAuditLogHandler {
+	return &DashboardAuditLogHandler{
+		auditLogService: services.NewAuditLogService(),
+	}
+}
+
+// ListAuditLogs, denetim kayıtlarını yeniden eskiye listeler; kullanıcı, kayıt türü, işlem ve tarih
+// aralığına göre süzülebilir, arama değişen değerleri de kapsar.
+func (h *DashboardAuditLogHandler) ListAuditLogs(c *fiber.Ctx) error {
+	params, fieldErrors, err := requests.ParseAndValidateAuditLogList(c, services.AppointmentLocation())
+	entityTypes, _ := h.auditLogService.EntityTypes(c.UserContext())
+	renderData := fiber.Map{
+		"Title":       "Denetim Kayıtları",
+		"EntityTypes": entityTypes,
+		"Params":      auditLogParamsView(params),
+	}
+	emptyResult := &requests.PaginatedResult{
+		Data: []models.AuditLog{},
+		Meta: requests.PaginationMeta{CurrentPage: params.Page, PerPage: params.PerPage},
+	}
+
+	if err != nil {
+		renderData["ValidationErrors"] = fieldErrors
+		renderData["Result"] = emptyResult
+		return renderer.Render(c, "dashboard/audit-logs/list", "layouts/app", renderData, http.StatusBadRequest)
+	}
+
+	result, err := h.auditLogService.List(c.UserContext(), params)
+	if err != nil {
+		renderData[renderer.FlashErrorKeyView] = "Denetim kayıtları getirilirken bir hata oluştu."
+		result = emptyResult
+	}
+	renderData["Result"] = result
+
+	return renderer.Render(c, "dashboard/audit-logs/list", "layouts/app", renderData, http.StatusOK)
+}
+
+// EntityHistory, tek bir kaydın oluşturulmasından bu yana tüm değişikliklerini farklarıyla gösterir.
+func (h *DashboardAuditLogHandler) EntityHistory(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return c.Status(fiber.StatusBadRequest).SendString("Geçersiz kayıt ID")
+	}
+	entityType := c.Params("entity")
+
+	params, _, err := requests.ParseAndValidateAuditLogList(c, services.AppointmentLocation())
+	if err != nil {
+		params = requests.AuditLogListParams{Page: 1, PerPage: 50}
+	}
+	params.EntityType = entityType
+	params.EntityID = uint(id)
+
+	renderData := fiber.Map{
+		"Title":      "Kayıt Geçmişi",
+		"EntityType": entityType,
+		"EntityID":   id,
+		"Params":     auditLogParamsView(params),
+	}
+	result, err := h.auditLogService.List(c.UserContext(), params)
+	if err != nil {
+		renderData[renderer.FlashErrorKeyView] = "Kayıt geçmişi getirilirken bir hata oluştu."
+		result = &requests.PaginatedResult{
+			Data: []models.AuditLog{},
+			Meta: requests.PaginationMeta{CurrentPage: params.Page, PerPage: params.PerPage},
+		}
+	}
+	renderData["Result"] = result
+
+	return renderer.Render(c, "dashboard/audit-logs/history", "layouts/app", renderData, http.StatusOK)
+}
+
+func (h *DashboardAuditLogHandler) ShowAuditLog(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).SendString("Geçersiz denetim kaydı ID")
+	}
+
+	entry, err := h.auditLogService.GetByID(c.UserContext(), uint(id))
+	if err != nil {
+		errMsg := "Denetim kaydı getirilirken bir hata oluştu."
+		if errors.Is(err, services.ErrAuditLogNotFound) {
+			errMsg = "Denetim kaydı bulunamadı."
+		}
+		flashmessages.SetFlashMessage(c, flashmessages.FlashErrorKey, errMsg)
+		return c.Redirect("/dashboard/audit-logs", fiber.StatusSeeOther)
+	}
+
+	return renderer.Render(c, "dashboard/audit-logs/show", "layouts/app", fiber.Map{
+		"Title": "Denetim Kaydı",
+		"Entry": entry,
+	})
+}
+
+func auditLogParamsView(params requests.AuditLogListParams) fiber.Map {
+	return fiber.Map{
+		"Search":     params.Search,
+		"EntityType": params.EntityType,
+		"EntityID":   params.EntityID,
+		"Action":     params.Action,
+		"ActorID":    params.ActorID,
+		"DateFrom":   params.DateFrom,
+		"DateTo":     params.DateTo,
+		"Page":       params.Page,
+		"PerPage":    params.PerPage,
+	}
+}
@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"zatrano/models"
+	"zatrano/pkg/currentuser"
+	"zatrano/pkg/flashmessages"
+	"zatrano/pkg/renderer"
+	"zatrano/requests"
+	"zatrano/services"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type DashboardLoginLockoutHandler struct {
+	throttleService services.ILoginThrottleService
+}
+
+func NewDashboardLoginLockoutHandler() *DashboardLoginLockoutHandler {
+	return &DashboardLoginLockoutHandler{
+		throttleService: services.NewLoginThrottleService(),
+	}
+}
+
+// ListLockouts, başarısız girişler nedeniyle kilitlenen hesap ve IP adreslerini listeler;
+// durum seçilmezse yalnızca süresi dolmamış kilitler gösterilir.
+func (h *DashboardLoginLockoutHandler) ListLockouts(c *fiber.Ctx) error {
+	params, fieldErrors, err := requests.ParseAndValidateLoginLockoutList(c)
+	renderData := fiber.Map{
+		"Title": "Giriş Kilitleri",
+		"Now":   time.Now(),
+		"Params": fiber.Map{
+			"Status":  params.Status,
+			"Search":  params.Search,
+			"Page":    params.Page,
+			"PerPage": params.PerPage,
+		},
+	}
+	emptyResult := &requests.PaginatedResult{
+		Data: []models.LoginLockout{},
+		Meta: requests.PaginationMeta{CurrentPage: params.Page, PerPage: params.PerPage},
+	}
+
+	if err != nil {
+		renderData["ValidationErrors"] = fieldErrors
+		renderData["Result"] = emptyResult
+		return renderer.Render(c, "dashboard/login-lockouts/list", "layouts/app", renderData, http.StatusBadRequest)
+	}
+
+	result, err := h.throttleService.List(c.UserContext(), params)
+	if err != nil {
+		renderData[renderer.FlashErrorKeyView] = "Giriş kilitleri getirilirken bir hata oluştu."
+		result = emptyResult
+	}
+	renderData["Result"] = result
+
+	return renderer.Render(c, "dashboard/login-lockouts/list", "layouts/app", renderData, http.StatusOK)
+}
+
+func (h *DashboardLoginLockoutHandler) Unlock(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).SendString("Geçersiz kilit ID")
+	}
+
+	if err := h.throttleService.Unlock(c.UserContext(), uint(id), currentuser.FromFiber(c).ID); err != nil {
+		status := fiber.StatusInternalServerError
+		errMsg := "Kilit açılamadı: beklenmeyen bir hata oluştu."
+		switch {
+		case errors.Is(err, services.ErrLoginLockoutNotFound):
+			status = fiber.StatusNotFound
+			errMsg = "Kilit açılamadı: " + err.Error()
+		case errors.Is(err, services.ErrLoginLockoutInactive):
+			status = fiber.StatusConflict
+			errMsg = "Kilit açılamadı: " + err.Error()
+		}
+		if strings.Contains(c.Get("Accept"), "application/json") {
+			return c.Status(status).JSON(fiber.Map{"error": errMsg})
+		}
+		flashmessages.SetFlashMessage(c, flashmessages.FlashErrorKey, errMsg)
+		return c.Redirect("/dashboard/login-lockouts", fiber.StatusSeeOther)
+	}
+
+	successMsg := "Kilit açıldı."
+	if strings.Contains(c.Get("Accept"), "application/json") {
+		return c.JSON(fiber.Map{"message": successMsg})
+	}
+	flashmessages.SetFlashMessage(c, flashmessages.FlashSuccessKey, successMsg)
+	return c.Redirect("/dashboard/login-lockouts", fiber.StatusFound)
+}
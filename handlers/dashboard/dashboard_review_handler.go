@@ -0,0 +1,118 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"zatrano/models"
+	"zatrano/pkg/flashmessages"
+	"zatrano/pkg/renderer"
+	"zatrano/requests"
+	"zatrano/services"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type DashboardReviewHandler struct {
+	reviewService services.IReviewService
+}
+
+func NewDashboardReviewHandler() *DashboardReviewHandler {
+	return &DashboardReviewHandler{
+		reviewService: services.NewReviewService(),
+	}
+}
+
+// ListReviews, moderasyon kuyruğudur; durum seçilmezse onay bekleyen yorumlar listelenir.
+func (h *DashboardReviewHandler) ListReviews(c *fiber.Ctx) error {
+	params, fieldErrors, err := requests.ParseAndValidateReviewList(c, models.ReviewStatusPending)
+	renderData := fiber.Map{
+		"Title": "Yorum Moderasyonu",
+		"Params": fiber.Map{
+			"Status":  params.Status,
+			"Rating":  params.Rating,
+			"Page":    params.Page,
+			"PerPage": params.PerPage,
+		},
+	}
+	emptyResult := &requests.PaginatedResult{
+		Data: []models.Review{},
+		Meta: requests.PaginationMeta{CurrentPage: params.Page, PerPage: params.PerPage},
+	}
+
+	if err != nil {
+		renderData["ValidationErrors"] = fieldErrors
+		renderData["Result"] = emptyResult
+		return renderer.Render(c, "dashboard/reviews/list", "layouts/app", renderData, http.StatusBadRequest)
+	}
+
+	result, err := h.reviewService.GetReviews(c.UserContext(), params)
+	if err != nil {
+		renderData[renderer.FlashErrorKeyView] = "Yorumlar getirilirken bir hata oluştu."
+		result = emptyResult
+	}
+	renderData["Result"] = result
+
+	return renderer.Render(c, "dashboard/reviews/list", "layouts/app", renderData, http.StatusOK)
+}
+
+func (h *DashboardReviewHandler) ApproveReview(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).SendString("Geçersiz Yorum ID")
+	}
+
+	req, _, err := requests.ParseAndValidateReviewModeration(c)
+	if err != nil {
+		return moderationFailure(c, fiber.StatusBadRequest, "Yorum onaylanamadı: "+err.Error())
+	}
+
+	err = h.reviewService.Approve(c.UserContext(), uint(id), req.Note)
+	return moderationResponse(c, err, "Yorum onaylanamadı: ", "Yorum onaylandı ve yayına alındı.")
+}
+
+func (h *DashboardReviewHandler) RejectReview(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).SendString("Geçersiz Yorum ID")
+	}
+
+	req, _, err := requests.ParseAndValidateReviewModeration(c)
+	if err != nil {
+		return moderationFailure(c, fiber.StatusBadRequest, "Yorum reddedilemedi: "+err.Error())
+	}
+
+	err = h.reviewService.Reject(c.UserContext(), uint(id), req.Note)
+	return moderationResponse(c, err, "Yorum reddedilemedi: ", "Yorum reddedildi.")
+}
+
+func moderationResponse(c *fiber.Ctx, err error, failPrefix, successMsg string) error {
+	if err != nil {
+		status := fiber.StatusInternalServerError
+		errMsg := failPrefix + err.Error()
+		switch {
+		case errors.Is(err, services.ErrReviewNotFound):
+			status = fiber.StatusNotFound
+		case errors.Is(err, services.ErrReviewAlreadyModerated):
+			status = fiber.StatusConflict
+		default:
+			errMsg = failPrefix + "beklenmeyen bir hata oluştu."
+		}
+		return moderationFailure(c, status, errMsg)
+	}
+
+	if strings.Contains(c.Get("Accept"), "application/json") {
+		return c.JSON(fiber.Map{"message": successMsg})
+	}
+	flashmessages.SetFlashMessage(c, flashmessages.FlashSuccessKey, successMsg)
+	return c.Redirect("/dashboard/reviews", fiber.StatusFound)
+}
+
+func moderationFailure(c *fiber.Ctx, status int, errMsg string) error {
+	if strings.Contains(c.Get("Accept"), "application/json") {
+		return c.Status(status).JSON(fiber.Map{"error": errMsg})
+	}
+	flashmessages.SetFlashMessage(c, flashmessages.FlashErrorKey, errMsg)
+	return c.Redirect("/dashboard/reviews", fiber.StatusSeeOther)
+}
@@ -16,12 +16,16 @@ import (
 )
 
 type DashboardUserHandler struct {
-	userService services.IUserService
+	userService      services.IUserService
+	userTypeService  services.IUserTypeService
+	twoFactorService services.ITwoFactorService
 }
 
 func NewDashboardUserHandler() *DashboardUserHandler {
 	return &DashboardUserHandler{
-		userService: services.NewUserService(),
+		userService:      services.NewUserService(),
+		userTypeService:  services.NewUserTypeService(),
+		twoFactorService: services.NewTwoFactorService(),
 	}
 }
 
@@ -51,6 +55,7 @@ func (h *DashboardUserHandler) ListUsers(c *fiber.Ctx) error {
 				},
 			},
 		}
+		renderData["UserTypes"], _ = h.userTypeService.GetUserTypeOptions(c.UserContext())
 		return renderer.Render(c, "dashboard/users/list", "layouts/app", renderData, http.StatusBadRequest)
 	}
 
@@ -84,12 +89,15 @@ func (h *DashboardUserHandler) ListUsers(c *fiber.Ctx) error {
 		}
 	}
 
+	renderData["UserTypes"], _ = h.userTypeService.GetUserTypeOptions(c.UserContext())
 	return renderer.Render(c, "dashboard/users/list", "layouts/app", renderData, http.StatusOK)
 }
 
 func (h *DashboardUserHandler) ShowCreateUser(c *fiber.Ctx) error {
+	userTypes, _ := h.userTypeService.GetUserTypeOptions(c.UserContext())
 	return renderer.Render(c, "dashboard/users/create", "layouts/app", fiber.Map{
-		"Title": "Yeni Kullanıcı Ekle",
+		"Title":     "Yeni Kullanıcı Ekle",
+		"UserTypes": userTypes,
 	})
 }
 
@@ -142,9 +150,11 @@ func (h *DashboardUserHandler) ShowUpdateUser(c *fiber.Ctx) error {
 		return c.Redirect("/dashboard/users", fiber.StatusSeeOther)
 	}
 
+	userTypes, _ := h.userTypeService.GetUserTypeOptions(c.UserContext())
 	return renderer.Render(c, "dashboard/users/update", "layouts/app", fiber.Map{
-		"Title": "Kullanıcı Düzenle",
-		"User":  user,
+		"Title":     "Kullanıcı Düzenle",
+		"User":      user,
+		"UserTypes": userTypes,
 	})
 }
 
@@ -190,6 +200,28 @@ func (h *DashboardUserHandler) UpdateUser(c *fiber.Ctx) error {
 	return c.Redirect("/dashboard/users", fiber.StatusFound)
 }
 
+// ResetTwoFactor, doğrulama uygulamasına ve kurtarma kodlarına erişimini kaybeden kullanıcının
+// iki adımlı doğrulamasını kapatır. Rol zorunlu kılıyorsa kullanıcı sonraki girişte yeniden kurar.
+func (h *DashboardUserHandler) ResetTwoFactor(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).SendString("Geçersiz kullanıcı ID")
+	}
+
+	if _, err := h.userService.GetUserByID(c.UserContext(), uint(id)); err != nil {
+		flashmessages.SetFlashMessage(c, flashmessages.FlashErrorKey, "Kullanıcı bulunamadı.")
+		return c.Redirect("/dashboard/users", fiber.StatusSeeOther)
+	}
+
+	if err := h.twoFactorService.Reset(c.UserContext(), uint(id)); err != nil {
+		flashmessages.SetFlashMessage(c, flashmessages.FlashErrorKey, "İki adımlı doğrulama sıfırlanamadı.")
+		return c.Redirect("/dashboard/users/update/"+c.Params("id"), fiber.StatusSeeOther)
+	}
+
+	flashmessages.SetFlashMessage(c, flashmessages.FlashSuccessKey, "Kullanıcının iki adımlı doğrulaması sıfırlandı.")
+	return c.Redirect("/dashboard/users/update/"+c.Params("id"), fiber.StatusSeeOther)
+}
+
 func (h *DashboardUserHandler) DeleteUser(c *fiber.Ctx) error {
 	id, err := c.ParamsInt("id")
 	if err != nil {
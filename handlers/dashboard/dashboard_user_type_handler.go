@@ -84,17 +84,13 @@ func (h *DashboardUserTypeHandler) ListUserTypes(c *fiber.Ctx) error {
 
 func (h *DashboardUserTypeHandler) ShowCreateUserType(c *fiber.Ctx) error {
 	return renderer.Render(c, "dashboard/user-types/create", "layouts/app", fiber.Map{
-		"Title": "Yeni Kullanıcı Tipi Ekle",
+		"Title":             "Yeni Kullanıcı Tipi Ekle",
+		"PermissionCatalog": models.PermissionCatalog,
 	})
 }
 
 func (h *DashboardUserTypeHandler) CreateUserType(c *fiber.Ctx) error {
-	formData := make(map[string]string)
-
-	args := c.Request().PostArgs()
-	args.VisitAll(func(key, value []byte) {
-		formData[string(key)] = string(value)
-	})
+	formData := userTypeFormData(c)
 
 	req, fieldErrors, err := requests.ParseAndValidateUserTypeRequest(c)
 
@@ -136,8 +132,9 @@ func (h *DashboardUserTypeHandler) ShowUpdateUserType(c *fiber.Ctx) error {
 	}
 
 	return renderer.Render(c, "dashboard/user-types/update", "layouts/app", fiber.Map{
-		"Title":    "Kullanıcı Tipi Düzenle",
-		"UserType": userType,
+		"Title":             "Kullanıcı Tipi Düzenle",
+		"UserType":          userType,
+		"PermissionCatalog": models.PermissionCatalog,
 	})
 }
 
@@ -147,12 +144,7 @@ func (h *DashboardUserTypeHandler) UpdateUserType(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).SendString("Geçersiz Kullanıcı Tipi ID")
 	}
 
-	formData := make(map[string]string)
-
-	args := c.Request().PostArgs()
-	args.VisitAll(func(key, value []byte) {
-		formData[string(key)] = string(value)
-	})
+	formData := userTypeFormData(c)
 
 	req, fieldErrors, err := requests.ParseAndValidateUserTypeRequest(c)
 
@@ -206,3 +198,18 @@ func (h *DashboardUserTypeHandler) DeleteUserType(c *fiber.Ctx) error {
 
 	return c.Redirect("/dashboard/user-types", fiber.StatusFound)
 }
+
+// userTypeFormData, form verisini flash için düzleştirir; çoklu seçilen yetkiler
+// "permissions.<anahtar>" olarak saklanır ki hata sonrası işaretler korunabilsin.
+func userTypeFormData(c *fiber.Ctx) map[string]string {
+	formData := make(map[string]string)
+	args := c.Request().PostArgs()
+	args.VisitAll(func(key, value []byte) {
+		if string(key) == "permissions" {
+			formData["permissions."+string(value)] = "1"
+			return
+		}
+		formData[string(key)] = string(value)
+	})
+	return formData
+}
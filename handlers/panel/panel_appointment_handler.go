@@ -0,0 +1,174 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"zatrano/models"
+	"zatrano/pkg/currentuser"
+	"zatrano/pkg/flashmessages"
+	"zatrano/pkg/renderer"
+	"zatrano/requests"
+	"zatrano/services"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type PanelAppointmentHandler struct {
+	appointmentService services.IAppointmentService
+	businessService    services.IBusinessService
+}
+
+func NewPanelAppointmentHandler() *PanelAppointmentHandler {
+	return &PanelAppointmentHandler{
+		appointmentService: services.NewAppointmentService(),
+		businessService:    services.NewBusinessService(),
+	}
+}
+
+func (h *PanelAppointmentHandler) ListAppointments(c *fiber.Ctx) error {
+	userID := currentuser.FromFiber(c).ID
+
+	params, fieldErrors, err := requests.ParseAndValidateAppointmentList(c, services.AppointmentLocation())
+	renderData := fiber.Map{
+		"Title": "Randevular",
+		"Params": fiber.Map{
+			"BusinessID": params.BusinessID,
+			"Status":     params.Status,
+			"Date":       params.Date,
+			"OrderBy":    params.OrderBy,
+			"Page":       params.Page,
+			"PerPage":    params.PerPage,
+		},
+		"Location": services.AppointmentLocation(),
+	}
+	emptyResult := &requests.PaginatedResult{
+		Data: []models.Appointment{},
+		Meta: requests.PaginationMeta{CurrentPage: params.Page, PerPage: params.PerPage},
+	}
+
+	businesses, bizErr := h.businessService.GetUserBusinesses(c.UserContext(), userID, requests.BusinessListParams{
+		SortBy: "title", OrderBy: "asc", Page: 1, PerPage: 200,
+	})
+	if bizErr == nil {
+		renderData["Businesses"] = businesses.Data
+	}
+
+	if err != nil {
+		renderData["ValidationErrors"] = fieldErrors
+		renderData["Result"] = emptyResult
+		return renderer.Render(c, "panel/appointments/list", "layouts/panel", renderData, http.StatusBadRequest)
+	}
+
+	result, err := h.appointmentService.GetOwnerAppointments(c.UserContext(), userID, params)
+	if err != nil {
+		renderData[renderer.FlashErrorKeyView] = "Randevular getirilirken bir hata oluştu."
+		result = emptyResult
+	}
+	renderData["Result"] = result
+
+	return renderer.Render(c, "panel/appointments/list", "layouts/panel", renderData, http.StatusOK)
+}
+
+func (h *PanelAppointmentHandler) ConfirmAppointment(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).SendString("Geçersiz Randevu ID")
+	}
+
+	err = h.appointmentService.Confirm(c.UserContext(), currentuser.FromFiber(c).ID, uint(id))
+	return appointmentActionResponse(c, err, "Randevu onaylanamadı: ", "Randevu onaylandı.")
+}
+
+func (h *PanelAppointmentHandler) CancelAppointment(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).SendString("Geçersiz Randevu ID")
+	}
+
+	req, _, err := requests.ParseAndValidateAppointmentCancel(c)
+	if err != nil {
+		return appointmentActionFailure(c, fiber.StatusBadRequest, "Randevu iptal edilemedi: "+err.Error())
+	}
+
+	err = h.appointmentService.CancelByBusiness(c.UserContext(), currentuser.FromFiber(c).ID, uint(id), req.Reason)
+	return appointmentActionResponse(c, err, "Randevu iptal edilemedi: ", "Randevu iptal edildi.")
+}
+
+func (h *PanelAppointmentHandler) RescheduleAppointment(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).SendString("Geçersiz Randevu ID")
+	}
+
+	req, _, err := requests.ParseAndValidateAppointmentReschedule(c)
+	if err != nil {
+		return appointmentActionFailure(c, fiber.StatusBadRequest, "Randevu ertelenemedi: "+err.Error())
+	}
+
+	err = h.appointmentService.RescheduleByBusiness(c.UserContext(), currentuser.FromFiber(c).ID, uint(id), req.StartsAt)
+	return appointmentActionResponse(c, err, "Randevu ertelenemedi: ", "Randevu yeni saate taşındı.")
+}
+
+// RescheduleSlots, erteleme penceresinde seçilen gün için boş saatleri JSON olarak döner.
+func (h *PanelAppointmentHandler) RescheduleSlots(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Geçersiz Randevu ID"})
+	}
+
+	appointment, err := h.appointmentService.GetOwnerAppointment(c.UserContext(), currentuser.FromFiber(c).ID, uint(id))
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	result, err := h.appointmentService.RescheduleSlots(c.UserContext(), appointment, c.Query("date"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	items := make([]fiber.Map, 0, len(result.Starts))
+	for _, t := range result.Starts {
+		items = append(items, fiber.Map{
+			"value": t.Format(requests.AppointmentStartLayout),
+			"label": t.Format("15:04"),
+		})
+	}
+	return c.JSON(fiber.Map{"date": result.Day.Format("2006-01-02"), "slots": items})
+}
+
+// appointmentActionResponse, onay/iptal/erteleme sonucunu fetch isteklerine JSON, form gönderimlerine flash ile bildirir.
+func appointmentActionResponse(c *fiber.Ctx, err error, failPrefix, successMsg string) error {
+	if err != nil {
+		status := fiber.StatusInternalServerError
+		errMsg := failPrefix + err.Error()
+		switch {
+		case errors.Is(err, services.ErrAppointmentNotFound):
+			status = fiber.StatusNotFound
+		case errors.Is(err, services.ErrAppointmentSlotTaken):
+			status = fiber.StatusConflict
+		case errors.Is(err, services.ErrAppointmentSlotUnavailable),
+			errors.Is(err, services.ErrAppointmentNotPending),
+			errors.Is(err, services.ErrAppointmentServiceNotFound):
+			status = fiber.StatusUnprocessableEntity
+		default:
+			errMsg = failPrefix + "beklenmeyen bir hata oluştu."
+		}
+		return appointmentActionFailure(c, status, errMsg)
+	}
+
+	if strings.Contains(c.Get("Accept"), "application/json") {
+		return c.JSON(fiber.Map{"message": successMsg})
+	}
+	flashmessages.SetFlashMessage(c, flashmessages.FlashSuccessKey, successMsg)
+	return c.Redirect("/panel/randevular", fiber.StatusFound)
+}
+
+func appointmentActionFailure(c *fiber.Ctx, status int, errMsg string) error {
+	if strings.Contains(c.Get("Accept"), "application/json") {
+		return c.Status(status).JSON(fiber.Map{"error": errMsg})
+	}
+	flashmessages.SetFlashMessage(c, flashmessages.FlashErrorKey, errMsg)
+	return c.Redirect("/panel/randevular", fiber.StatusSeeOther)
+}
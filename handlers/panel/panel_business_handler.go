@@ -0,0 +1,279 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"zatrano/models"
+	"zatrano/pkg/currentuser"
+	"zatrano/pkg/filemanager"
+	"zatrano/pkg/flashmessages"
+	"zatrano/pkg/formflash"
+	"zatrano/pkg/renderer"
+	"zatrano/requests"
+	"zatrano/services"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const businessContentType = "businesses"
+
+type PanelBusinessHandler struct {
+	businessService services.IBusinessService
+	locationService services.ILocationService
+}
+
+func NewPanelBusinessHandler() *PanelBusinessHandler {
+	return &PanelBusinessHandler{
+		businessService: services.NewBusinessService(),
+		locationService: services.NewLocationService(),
+	}
+}
+
+func (h *PanelBusinessHandler) ListBusinesses(c *fiber.Ctx) error {
+	userID := currentuser.FromFiber(c).ID
+
+	params, fieldErrors, err := requests.ParseAndValidateBusinessList(c)
+	renderData := fiber.Map{
+		"Title": "İşletmelerim",
+		"Params": fiber.Map{
+			"Name":    params.Name,
+			"SortBy":  params.SortBy,
+			"OrderBy": params.OrderBy,
+			"Page":    params.Page,
+			"PerPage": params.PerPage,
+		},
+	}
+	emptyResult := &requests.PaginatedResult{
+		Data: []models.Business{},
+		Meta: requests.PaginationMeta{CurrentPage: params.Page, PerPage: params.PerPage},
+	}
+
+	if err != nil {
+		renderData["ValidationErrors"] = fieldErrors
+		renderData["Result"] = emptyResult
+		return renderer.Render(c, "panel/businesses/list", "layouts/panel", renderData, http.StatusBadRequest)
+	}
+
+	result, err := h.businessService.GetUserBusinesses(c.UserContext(), userID, params)
+	if err != nil {
+		renderData[renderer.FlashErrorKeyView] = "İşletmeler getirilirken bir hata oluştu."
+		result = emptyResult
+	}
+	renderData["Result"] = result
+
+	return renderer.Render(c, "panel/businesses/list", "layouts/panel", renderData, http.StatusOK)
+}
+
+func (h *PanelBusinessHandler) ShowCreateBusiness(c *fiber.Ctx) error {
+	data := fiber.Map{"Title": "Yeni İşletme"}
+	h.addFormOptions(c, data)
+	return renderer.Render(c, "panel/businesses/create", "layouts/panel", data)
+}
+
+func (h *PanelBusinessHandler) CreateBusiness(c *fiber.Ctx) error {
+	userID := currentuser.FromFiber(c).ID
+	formData := collectFormData(c)
+
+	req, fieldErrors, err := requests.ParseAndValidateBusinessRequest(c)
+	if err != nil {
+		formflash.SetData(c, formData)
+		formflash.SetValidationErrors(c, fieldErrors)
+		flashmessages.SetFlashMessage(c, flashmessages.FlashErrorKey, err.Error())
+		return c.Redirect("/panel/isletmeler/olustur", fiber.StatusSeeOther)
+	}
+
+	media, uploadErrors := uploadBusinessMedia(c)
+	if len(uploadErrors) > 0 {
+		removeBusinessMedia(media)
+		formflash.SetData(c, formData)
+		formflash.SetValidationErrors(c, uploadErrors)
+		flashmessages.SetFlashMessage(c, flashmessages.FlashErrorKey, "Lütfen formdaki hataları düzeltin.")
+		return c.Redirect("/panel/isletmeler/olustur", fiber.StatusSeeOther)
+	}
+
+	if _, err := h.businessService.CreateBusiness(c.UserContext(), userID, req, media); err != nil {
+		removeBusinessMedia(media)
+		formflash.SetData(c, formData)
+		flashmessages.SetFlashMessage(c, flashmessages.FlashErrorKey, "İşletme oluşturulamadı: "+err.Error())
+		return c.Redirect("/panel/isletmeler/olustur", fiber.StatusSeeOther)
+	}
+
+	formflash.ClearData(c)
+	flashmessages.SetFlashMessage(c, flashmessages.FlashSuccessKey, "İşletme başarıyla oluşturuldu.")
+	return c.Redirect("/panel/isletmeler", fiber.StatusFound)
+}
+
+func (h *PanelBusinessHandler) ShowUpdateBusiness(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).SendString("Geçersiz İşletme ID")
+	}
+
+	business, err := h.businessService.GetUserBusinessByID(c.UserContext(), currentuser.FromFiber(c).ID, uint(id))
+	if err != nil {
+		flashmessages.SetFlashMessage(c, flashmessages.FlashErrorKey, "İşletme bulunamadı.")
+		return c.Redirect("/panel/isletmeler", fiber.StatusSeeOther)
+	}
+
+	data := fiber.Map{
+		"Title":    "İşletme Düzenle",
+		"Business": business,
+	}
+	h.addFormOptions(c, data)
+	return renderer.Render(c, "panel/businesses/update", "layouts/panel", data)
+}
+
+func (h *PanelBusinessHandler) UpdateBusiness(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).SendString("Geçersiz İşletme ID")
+	}
+	userID := currentuser.FromFiber(c).ID
+	redirectURL := "/panel/isletmeler/guncelle/" + c.Params("id")
+
+	existing, err := h.businessService.GetUserBusinessByID(c.UserContext(), userID, uint(id))
+	if err != nil {
+		flashmessages.SetFlashMessage(c, flashmessages.FlashErrorKey, "İşletme bulunamadı.")
+		return c.Redirect("/panel/isletmeler", fiber.StatusSeeOther)
+	}
+
+	formData := collectFormData(c)
+
+	req, fieldErrors, err := requests.ParseAndValidateBusinessRequest(c)
+	if err != nil {
+		formflash.SetData(c, formData)
+		formflash.SetValidationErrors(c, fieldErrors)
+		flashmessages.SetFlashMessage(c, flashmessages.FlashErrorKey, err.Error())
+		return c.Redirect(redirectURL, fiber.StatusSeeOther)
+	}
+
+	media, uploadErrors := uploadBusinessMedia(c)
+	if len(uploadErrors) > 0 {
+		removeBusinessMedia(media)
+		formflash.SetData(c, formData)
+		formflash.SetValidationErrors(c, uploadErrors)
+		flashmessages.SetFlashMessage(c, flashmessages.FlashErrorKey, "Lütfen formdaki hataları düzeltin.")
+		return c.Redirect(redirectURL, fiber.StatusSeeOther)
+	}
+
+	if err := h.businessService.UpdateBusiness(c.UserContext(), userID, uint(id), req, media); err != nil {
+		removeBusinessMedia(media)
+		formflash.SetData(c, formData)
+		flashmessages.SetFlashMessage(c, flashmessages.FlashErrorKey, "İşletme güncellenemedi: "+err.Error())
+		return c.Redirect(redirectURL, fiber.StatusSeeOther)
+	}
+
+	// Yeni dosya yüklendiyse eskisini temizle
+	if media.Logo != "" {
+		filemanager.DeleteFile(businessContentType, existing.Logo)
+	}
+	if media.Banner != "" {
+		filemanager.DeleteFile(businessContentType, existing.Banner)
+	}
+
+	formflash.ClearData(c)
+	flashmessages.SetFlashMessage(c, flashmessages.FlashSuccessKey, "İşletme başarıyla güncellendi.")
+	return c.Redirect("/panel/isletmeler", fiber.StatusFound)
+}
+
+func (h *PanelBusinessHandler) DeleteBusiness(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).SendString("Geçersiz İşletme ID")
+	}
+
+	business, err := h.businessService.DeleteBusiness(c.UserContext(), currentuser.FromFiber(c).ID, uint(id))
+	if err != nil {
+		errMsg := "İşletme silinemedi: " + err.Error()
+		status := fiber.StatusInternalServerError
+		if errors.Is(err, services.ErrBusinessNotFound) {
+			status = fiber.StatusNotFound
+		}
+
+		if strings.Contains(c.Get("Accept"), "application/json") {
+			return c.Status(status).JSON(fiber.Map{"error": errMsg})
+		}
+
+		flashmessages.SetFlashMessage(c, flashmessages.FlashErrorKey, errMsg)
+		return c.Redirect("/panel/isletmeler", fiber.StatusSeeOther)
+	}
+
+	removeBusinessMedia(services.BusinessMedia{Logo: business.Logo, Banner: business.Banner})
+
+	if strings.Contains(c.Get("Accept"), "application/json") {
+		return c.JSON(fiber.Map{"message": "İşletme başarıyla silindi."})
+	}
+
+	flashmessages.SetFlashMessage(c, flashmessages.FlashSuccessKey, "İşletme başarıyla silindi.")
+	return c.Redirect("/panel/isletmeler", fiber.StatusFound)
+}
+
+// addFormOptions, işletme türlerini ve ülke listesini form verisine ekler.
+func (h *PanelBusinessHandler) addFormOptions(c *fiber.Ctx, data fiber.Map) {
+	businessTypes, err := h.businessService.GetBusinessTypes(c.UserContext())
+	if err != nil {
+		data[renderer.FlashErrorKeyView] = "İşletme türleri getirilemedi."
+	}
+	countries, err := h.locationService.GetCountries(c.UserContext())
+	if err != nil {
+		data[renderer.FlashErrorKeyView] = "Ülke listesi getirilemedi."
+	}
+	data["BusinessTypes"] = businessTypes
+	data["Countries"] = countries
+}
+
+// uploadBusinessMedia, logo ve banner alanlarını yükler; dosya seçilmemişse alan boş kalır.
+func uploadBusinessMedia(c *fiber.Ctx) (services.BusinessMedia, map[string]string) {
+	var media services.BusinessMedia
+	fieldErrors := make(map[string]string)
+
+	for field, target := range map[string]*string{"logo": &media.Logo, "banner": &media.Banner} {
+		fileName, err := filemanager.UploadFile(c, field, businessContentType)
+		if err != nil {
+			if errors.Is(err, filemanager.ErrFileNotProvided) {
+				continue
+			}
+			fieldErrors[field] = uploadErrorMessage(err)
+			continue
+		}
+		*target = fileName
+	}
+	return media, fieldErrors
+}
+
+func removeBusinessMedia(media services.BusinessMedia) {
+	filemanager.DeleteFile(businessContentType, media.Logo)
+	filemanager.DeleteFile(businessContentType, media.Banner)
+}
+
+func uploadErrorMessage(err error) string {
+	switch {
+	case errors.Is(err, filemanager.ErrFileTooLarge):
+		return "Dosya boyutu en fazla 2 MB olabilir."
+	case errors.Is(err, filemanager.ErrInvalidFileType):
+		return "Yalnızca jpg, jpeg, png veya webp dosyası yükleyebilirsiniz."
+	default:
+		return "Dosya yüklenemedi."
+	}
+}
+
+// collectFormData, multipart ve urlencoded formlardaki alanları formflash için toplar.
+func collectFormData(c *fiber.Ctx) map[string]string {
+	formData := make(map[string]string)
+	if form, err := c.MultipartForm(); err == nil && form != nil {
+		for key, values := range form.Value {
+			if len(values) > 0 && key != "csrf_token" {
+				formData[key] = values[0]
+			}
+		}
+		return formData
+	}
+	c.Request().PostArgs().VisitAll(func(key, value []byte) {
+		if string(key) != "csrf_token" {
+			formData[string(key)] = string(value)
+		}
+	})
+	return formData
+}
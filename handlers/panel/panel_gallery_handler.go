@@ -0,0 +1,174 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"zatrano/pkg/currentuser"
+	"zatrano/pkg/filemanager"
+	"zatrano/pkg/flashmessages"
+	"zatrano/pkg/renderer"
+	"zatrano/requests"
+	"zatrano/services"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type PanelGalleryHandler struct {
+	galleryService services.IGalleryService
+}
+
+func NewPanelGalleryHandler() *PanelGalleryHandler {
+	return &PanelGalleryHandler{
+		galleryService: services.NewGalleryService(),
+	}
+}
+
+func (h *PanelGalleryHandler) ShowGallery(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).SendString("Geçersiz İşletme ID")
+	}
+
+	gallery, err := h.galleryService.GetGallery(c.UserContext(), currentuser.FromFiber(c).ID, uint(id))
+	if err != nil {
+		if errors.Is(err, services.ErrBusinessNotFound) {
+			flashmessages.SetFlashMessage(c, flashmessages.FlashErrorKey, "İşletme bulunamadı.")
+		} else {
+			flashmessages.SetFlashMessage(c, flashmessages.FlashErrorKey, "Galeri getirilirken bir hata oluştu.")
+		}
+		return c.Redirect("/panel/isletmeler", fiber.StatusSeeOther)
+	}
+
+	return renderer.Render(c, "panel/galleries/show", "layouts/panel", fiber.Map{
+		"Title":   gallery.Business.Title + " · Galeri",
+		"Gallery": gallery,
+	}, http.StatusOK)
+}
+
+func (h *PanelGalleryHandler) UploadImages(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).SendString("Geçersiz İşletme ID")
+	}
+	userID := currentuser.FromFiber(c).ID
+
+	count := 0
+	if form, err := c.MultipartForm(); err == nil && form != nil {
+		count = len(form.File["images"])
+	}
+	if count == 0 {
+		return galleryActionFailure(c, fiber.StatusUnprocessableEntity, "Lütfen en az bir görsel seçin.")
+	}
+
+	// Limit dolu ise dosyalar hiç diske yazılmaz; kesin kontrol kayıt sırasında tekrarlanır
+	if err := h.galleryService.CheckCapacity(c.UserContext(), userID, uint(id), count); err != nil {
+		return galleryActionResponse(c, err, "Görseller yüklenemedi: ", "")
+	}
+
+	fileNames, err := filemanager.UploadFiles(c, "images", businessContentType)
+	if err != nil {
+		return galleryActionFailure(c, fiber.StatusUnprocessableEntity, "Görseller yüklenemedi: "+uploadErrorMessage(err))
+	}
+
+	if _, err := h.galleryService.AddImages(c.UserContext(), userID, uint(id), fileNames); err != nil {
+		for _, name := range fileNames {
+			filemanager.DeleteFile(businessContentType, name)
+		}
+		return galleryActionResponse(c, err, "Görseller yüklenemedi: ", "")
+	}
+
+	return galleryActionResponse(c, nil, "", fmt.Sprintf("%d görsel galeriye eklendi.", len(fileNames)))
+}
+
+func (h *PanelGalleryHandler) UpdateCaption(c *fiber.Ctx) error {
+	id, errID := c.ParamsInt("id")
+	imageID, errImage := c.ParamsInt("imageId")
+	if errID != nil || errImage != nil {
+		return c.Status(fiber.StatusBadRequest).SendString("Geçersiz Görsel ID")
+	}
+
+	req, _, err := requests.ParseAndValidateGalleryCaption(c)
+	if err != nil {
+		return galleryActionFailure(c, fiber.StatusUnprocessableEntity, err.Error())
+	}
+
+	err = h.galleryService.UpdateCaption(c.UserContext(), currentuser.FromFiber(c).ID, uint(id), uint(imageID), req.Caption)
+	return galleryActionResponse(c, err, "Açıklama kaydedilemedi: ", "Açıklama kaydedildi.")
+}
+
+func (h *PanelGalleryHandler) ReorderImages(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).SendString("Geçersiz İşletme ID")
+	}
+
+	req, _, err := requests.ParseAndValidateGalleryOrder(c)
+	if err != nil {
+		return galleryActionFailure(c, fiber.StatusUnprocessableEntity, err.Error())
+	}
+
+	err = h.galleryService.Reorder(c.UserContext(), currentuser.FromFiber(c).ID, uint(id), req.IDs)
+	return galleryActionResponse(c, err, "Sıralama kaydedilemedi: ", "Sıralama kaydedildi.")
+}
+
+func (h *PanelGalleryHandler) SetCover(c *fiber.Ctx) error {
+	id, errID := c.ParamsInt("id")
+	imageID, errImage := c.ParamsInt("imageId")
+	if errID != nil || errImage != nil {
+		return c.Status(fiber.StatusBadRequest).SendString("Geçersiz Görsel ID")
+	}
+
+	err := h.galleryService.SetCover(c.UserContext(), currentuser.FromFiber(c).ID, uint(id), uint(imageID))
+	return galleryActionResponse(c, err, "Kapak görseli ayarlanamadı: ", "Kapak görseli güncellendi.")
+}
+
+func (h *PanelGalleryHandler) DeleteImage(c *fiber.Ctx) error {
+	id, errID := c.ParamsInt("id")
+	imageID, errImage := c.ParamsInt("imageId")
+	if errID != nil || errImage != nil {
+		return c.Status(fiber.StatusBadRequest).SendString("Geçersiz Görsel ID")
+	}
+
+	image, err := h.galleryService.DeleteImage(c.UserContext(), currentuser.FromFiber(c).ID, uint(id), uint(imageID))
+	if err == nil {
+		filemanager.DeleteFile(businessContentType, image.Image)
+	}
+	return galleryActionResponse(c, err, "Görsel silinemedi: ", "Görsel silindi.")
+}
+
+func galleryActionResponse(c *fiber.Ctx, err error, failPrefix, successMsg string) error {
+	if err != nil {
+		status := fiber.StatusInternalServerError
+		errMsg := failPrefix + err.Error()
+		switch {
+		case errors.Is(err, services.ErrBusinessNotFound), errors.Is(err, services.ErrGalleryImageNotFound):
+			status = fiber.StatusNotFound
+		case errors.Is(err, services.ErrGalleryLimitReached):
+			status = fiber.StatusConflict
+		case errors.Is(err, services.ErrGalleryInvalidOrder):
+			status = fiber.StatusUnprocessableEntity
+		}
+		return galleryActionFailure(c, status, errMsg)
+	}
+
+	if strings.Contains(c.Get("Accept"), "application/json") {
+		return c.JSON(fiber.Map{"message": successMsg})
+	}
+	flashmessages.SetFlashMessage(c, flashmessages.FlashSuccessKey, successMsg)
+	return c.Redirect(galleryURL(c), fiber.StatusFound)
+}
+
+func galleryActionFailure(c *fiber.Ctx, status int, errMsg string) error {
+	if strings.Contains(c.Get("Accept"), "application/json") {
+		return c.Status(status).JSON(fiber.Map{"error": errMsg})
+	}
+	flashmessages.SetFlashMessage(c, flashmessages.FlashErrorKey, errMsg)
+	return c.Redirect(galleryURL(c), fiber.StatusSeeOther)
+}
+
+func galleryURL(c *fiber.Ctx) string {
+	return "/panel/isletmeler/" + c.Params("id") + "/galeri"
+}
@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"zatrano/services"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type PanelLocationHandler struct {
+	locationService services.ILocationService
+}
+
+func NewPanelLocationHandler() *PanelLocationHandler {
+	return &PanelLocationHandler{
+		locationService: services.NewLocationService(),
+	}
+}
+
+type locationOption struct {
+	ID   uint   `json:"id"`
+	Name string `json:"name"`
+}
+
+// Cities, seçilen ülkenin illerini JSON olarak döner (adres seçicisi için).
+func (h *PanelLocationHandler) Cities(c *fiber.Ctx) error {
+	countryID, err := c.ParamsInt("countryId")
+	if err != nil || countryID <= 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Geçersiz ülke ID"})
+	}
+
+	cities, err := h.locationService.GetCities(c.UserContext(), uint(countryID))
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "İller getirilemedi."})
+	}
+
+	options := make([]locationOption, 0, len(cities))
+	for _, city := range cities {
+		options = append(options, locationOption{ID: city.ID, Name: city.Name})
+	}
+	return c.JSON(options)
+}
+
+// Districts, seçilen ilin ilçelerini JSON olarak döner.
+func (h *PanelLocationHandler) Districts(c *fiber.Ctx) error {
+	cityID, err := c.ParamsInt("cityId")
+	if err != nil || cityID <= 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Geçersiz il ID"})
+	}
+
+	districts, err := h.locationService.GetDistricts(c.UserContext(), uint(cityID))
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "İlçeler getirilemedi."})
+	}
+
+	options := make([]locationOption, 0, len(districts))
+	for _, district := range districts {
+		options = append(options, locationOption{ID: district.ID, Name: district.Name})
+	}
+	return c.JSON(options)
+}
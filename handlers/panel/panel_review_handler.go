@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"zatrano/models"
+	"zatrano/pkg/currentuser"
+	"zatrano/pkg/flashmessages"
+	"zatrano/pkg/renderer"
+	"zatrano/requests"
+	"zatrano/services"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type PanelReviewHandler struct {
+	reviewService   services.IReviewService
+	businessService services.IBusinessService
+}
+
+func NewPanelReviewHandler() *PanelReviewHandler {
+	return &PanelReviewHandler{
+		reviewService:   services.NewReviewService(),
+		businessService: services.NewBusinessService(),
+	}
+}
+
+func (h *PanelReviewHandler) ListReviews(c *fiber.Ctx) error {
+	userID := currentuser.FromFiber(c).ID
+
+	params, fieldErrors, err := requests.ParseAndValidateReviewList(c, "")
+	renderData := fiber.Map{
+		"Title": "Yorumlar",
+		"Params": fiber.Map{
+			"BusinessID": params.BusinessID,
+			"Status":     params.Status,
+			"Rating":     params.Rating,
+			"Page":       params.Page,
+			"PerPage":    params.PerPage,
+		},
+	}
+	emptyResult := &requests.PaginatedResult{
+		Data: []models.Review{},
+		Meta: requests.PaginationMeta{CurrentPage: params.Page, PerPage: params.PerPage},
+	}
+
+	businesses, bizErr := h.businessService.GetUserBusinesses(c.UserContext(), userID, requests.BusinessListParams{
+		SortBy: "title", OrderBy: "asc", Page: 1, PerPage: 200,
+	})
+	if bizErr == nil {
+		renderData["Businesses"] = businesses.Data
+	}
+
+	if err != nil {
+		renderData["ValidationErrors"] = fieldErrors
+		renderData["Result"] = emptyResult
+		return renderer.Render(c, "panel/reviews/list", "layouts/panel", renderData, http.StatusBadRequest)
+	}
+
+	result, err := h.reviewService.GetOwnerReviews(c.UserContext(), userID, params)
+	if err != nil {
+		renderData[renderer.FlashErrorKeyView] = "Yorumlar getirilirken bir hata oluştu."
+		result = emptyResult
+	}
+	renderData["Result"] = result
+
+	return renderer.Render(c, "panel/reviews/list", "layouts/panel", renderData, http.StatusOK)
+}
+
+func (h *PanelReviewHandler) ReplyReview(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).SendString("Geçersiz Yorum ID")
+	}
+
+	req, _, err := requests.ParseAndValidateReviewReply(c)
+	if err != nil {
+		return reviewActionFailure(c, fiber.StatusBadRequest, "Yanıt kaydedilemedi: "+err.Error())
+	}
+
+	successMsg := "Yanıtınız kaydedildi."
+	if req.Reply == "" {
+		successMsg = "Yanıtınız kaldırıldı."
+	}
+	err = h.reviewService.Reply(c.UserContext(), currentuser.FromFiber(c).ID, uint(id), req.Reply)
+	return reviewActionResponse(c, err, "Yanıt kaydedilemedi: ", successMsg)
+}
+
+// reviewActionResponse, yanıt sonucunu fetch isteklerine JSON, form gönderimlerine flash ile bildirir.
+func reviewActionResponse(c *fiber.Ctx, err error, failPrefix, successMsg string) error {
+	if err != nil {
+		status := fiber.StatusInternalServerError
+		errMsg := failPrefix + err.Error()
+		switch {
+		case errors.Is(err, services.ErrReviewNotFound):
+			status = fiber.StatusNotFound
+		case errors.Is(err, services.ErrReviewReplyNotAllowed):
+			status = fiber.StatusUnprocessableEntity
+		default:
+			errMsg = failPrefix + "beklenmeyen bir hata oluştu."
+		}
+		return reviewActionFailure(c, status, errMsg)
+	}
+
+	if strings.Contains(c.Get("Accept"), "application/json") {
+		return c.JSON(fiber.Map{"message": successMsg})
+	}
+	flashmessages.SetFlashMessage(c, flashmessages.FlashSuccessKey, successMsg)
+	return c.Redirect("/panel/yorumlar", fiber.StatusFound)
+}
+
+func reviewActionFailure(c *fiber.Ctx, status int, errMsg string) error {
+	if strings.Contains(c.Get("Accept"), "application/json") {
+		return c.Status(status).JSON(fiber.Map{"error": errMsg})
+	}
+	flashmessages.SetFlashMessage(c, flashmessages.FlashErrorKey, errMsg)
+	return c.Redirect("/panel/yorumlar", fiber.StatusSeeOther)
+}
@@ -0,0 +1,188 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"zatrano/pkg/currentuser"
+	"zatrano/pkg/flashmessages"
+	"zatrano/pkg/formflash"
+	"zatrano/pkg/renderer"
+	"zatrano/requests"
+	"zatrano/services"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// scheduleWeekdays, formda Pazartesi'den başlayan gün sırasıdır (time.Weekday değerleri).
+var scheduleWeekdays = []struct {
+	Index int
+	Name  string
+}{
+	{1, "Pazartesi"}, {2, "Salı"}, {3, "Çarşamba"}, {4, "Perşembe"}, {5, "Cuma"}, {6, "Cumartesi"}, {0, "Pazar"},
+}
+
+type scheduleFormRow struct {
+	Index int
+	Name  string
+	requests.WeeklyScheduleDay
+}
+
+type PanelScheduleHandler struct {
+	scheduleService services.IScheduleService
+}
+
+func NewPanelScheduleHandler() *PanelScheduleHandler {
+	return &PanelScheduleHandler{
+		scheduleService: services.NewScheduleService(),
+	}
+}
+
+func (h *PanelScheduleHandler) ListProfessionals(c *fiber.Ctx) error {
+	professionals, err := h.scheduleService.GetOwnerProfessionals(c.UserContext(), currentuser.FromFiber(c).ID)
+	data := fiber.Map{
+		"Title":         "Çalışma Saatleri",
+		"Professionals": professionals,
+	}
+	if err != nil {
+		data[renderer.FlashErrorKeyView] = "Uzmanlar getirilirken bir hata oluştu."
+	}
+	return renderer.Render(c, "panel/schedules/list", "layouts/panel", data, http.StatusOK)
+}
+
+func (h *PanelScheduleHandler) ShowSchedule(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).SendString("Geçersiz Uzman ID")
+	}
+
+	professional, err := h.scheduleService.GetOwnerProfessional(c.UserContext(), currentuser.FromFiber(c).ID, uint(id))
+	if err != nil {
+		flashmessages.SetFlashMessage(c, flashmessages.FlashErrorKey, "Uzman bulunamadı.")
+		return c.Redirect("/panel/calisma-saatleri", fiber.StatusSeeOther)
+	}
+
+	data := fiber.Map{
+		"Title":        professional.Title + " · Çalışma Saatleri",
+		"Professional": professional,
+		"Location":     services.AppointmentLocation(),
+	}
+
+	schedule, usingDefaults, err := h.scheduleService.GetWeeklySchedule(c.UserContext(), professional)
+	if err != nil {
+		data[renderer.FlashErrorKeyView] = "Çalışma saatleri getirilemedi."
+	}
+	rows := make([]scheduleFormRow, 0, len(scheduleWeekdays))
+	for _, d := range scheduleWeekdays {
+		rows = append(rows, scheduleFormRow{Index: d.Index, Name: d.Name, WeeklyScheduleDay: schedule.Days[d.Index]})
+	}
+	data["Rows"] = rows
+	data["WorksOnHolidays"] = schedule.WorksOnHolidays
+	data["UsingDefaults"] = usingDefaults
+
+	timeOffs, err := h.scheduleService.GetUpcomingTimeOffs(c.UserContext(), professional.ID)
+	if err != nil {
+		data[renderer.FlashErrorKeyView] = "İzinler getirilemedi."
+	}
+	data["TimeOffs"] = timeOffs
+
+	calendar, err := h.scheduleService.MonthCalendar(c.UserContext(), professional, c.Query("month"))
+	if err != nil {
+		if errors.Is(err, services.ErrScheduleInvalidMonth) {
+			return c.Redirect(fmt.Sprintf("/panel/calisma-saatleri/%d", professional.ID), fiber.StatusSeeOther)
+		}
+		data[renderer.FlashErrorKeyView] = "Takvim hesaplanamadı."
+	}
+	data["Calendar"] = calendar
+
+	return renderer.Render(c, "panel/schedules/show", "layouts/panel", data, http.StatusOK)
+}
+
+func (h *PanelScheduleHandler) SaveSchedule(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).SendString("Geçersiz Uzman ID")
+	}
+	redirectURL := "/panel/calisma-saatleri/" + c.Params("id")
+
+	req, fieldErrors, err := requests.ParseAndValidateWeeklySchedule(c)
+	if err != nil {
+		formflash.SetData(c, collectFormData(c))
+		formflash.SetValidationErrors(c, fieldErrors)
+		flashmessages.SetFlashMessage(c, flashmessages.FlashErrorKey, err.Error())
+		return c.Redirect(redirectURL, fiber.StatusSeeOther)
+	}
+
+	if err := h.scheduleService.SaveWeeklySchedule(c.UserContext(), currentuser.FromFiber(c).ID, uint(id), req); err != nil {
+		flashmessages.SetFlashMessage(c, flashmessages.FlashErrorKey, "Çalışma saatleri kaydedilemedi: "+err.Error())
+		return c.Redirect(redirectURL, fiber.StatusSeeOther)
+	}
+
+	formflash.ClearData(c)
+	flashmessages.SetFlashMessage(c, flashmessages.FlashSuccessKey, "Çalışma saatleri kaydedildi.")
+	return c.Redirect(redirectURL, fiber.StatusFound)
+}
+
+func (h *PanelScheduleHandler) AddTimeOff(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).SendString("Geçersiz Uzman ID")
+	}
+	redirectURL := "/panel/calisma-saatleri/" + c.Params("id")
+
+	req, fieldErrors, err := requests.ParseAndValidateTimeOffRequest(c)
+	if err != nil {
+		formflash.SetData(c, collectFormData(c))
+		formflash.SetValidationErrors(c, fieldErrors)
+		flashmessages.SetFlashMessage(c, flashmessages.FlashErrorKey, err.Error())
+		return c.Redirect(redirectURL, fiber.StatusSeeOther)
+	}
+
+	conflicts, err := h.scheduleService.AddTimeOff(c.UserContext(), currentuser.FromFiber(c).ID, uint(id), req)
+	if err != nil {
+		flashmessages.SetFlashMessage(c, flashmessages.FlashErrorKey, "İzin eklenemedi: "+err.Error())
+		return c.Redirect(redirectURL, fiber.StatusSeeOther)
+	}
+
+	formflash.ClearData(c)
+	message := "İzin eklendi."
+	if conflicts > 0 {
+		message = fmt.Sprintf("İzin eklendi. Bu aralıkta %d randevu var; Randevular sayfasından ertelemeyi ya da iptal etmeyi unutmayın.", conflicts)
+	}
+	flashmessages.SetFlashMessage(c, flashmessages.FlashSuccessKey, message)
+	return c.Redirect(redirectURL, fiber.StatusFound)
+}
+
+func (h *PanelScheduleHandler) DeleteTimeOff(c *fiber.Ctx) error {
+	id, errID := c.ParamsInt("id")
+	timeOffID, errOff := c.ParamsInt("timeOffId")
+	if errID != nil || errOff != nil {
+		return c.Status(fiber.StatusBadRequest).SendString("Geçersiz İzin ID")
+	}
+	redirectURL := "/panel/calisma-saatleri/" + c.Params("id")
+
+	err := h.scheduleService.DeleteTimeOff(c.UserContext(), currentuser.FromFiber(c).ID, uint(id), uint(timeOffID))
+	if err != nil {
+		errMsg := "İzin silinemedi: " + err.Error()
+		status := fiber.StatusInternalServerError
+		if errors.Is(err, services.ErrScheduleTimeOffNotFound) || errors.Is(err, services.ErrScheduleProfessionalNotFound) {
+			status = fiber.StatusNotFound
+		}
+
+		if strings.Contains(c.Get("Accept"), "application/json") {
+			return c.Status(status).JSON(fiber.Map{"error": errMsg})
+		}
+
+		flashmessages.SetFlashMessage(c, flashmessages.FlashErrorKey, errMsg)
+		return c.Redirect(redirectURL, fiber.StatusSeeOther)
+	}
+
+	if strings.Contains(c.Get("Accept"), "application/json") {
+		return c.JSON(fiber.Map{"message": "İzin silindi."})
+	}
+
+	flashmessages.SetFlashMessage(c, flashmessages.FlashSuccessKey, "İzin silindi.")
+	return c.Redirect(redirectURL, fiber.StatusFound)
+}
@@ -0,0 +1,209 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"zatrano/models"
+	"zatrano/pkg/currentuser"
+	"zatrano/pkg/flashmessages"
+	"zatrano/pkg/formflash"
+	"zatrano/pkg/renderer"
+	"zatrano/requests"
+	"zatrano/services"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type PanelVenueReservationHandler struct {
+	reservationService services.IVenueReservationService
+}
+
+func NewPanelVenueReservationHandler() *PanelVenueReservationHandler {
+	return &PanelVenueReservationHandler{
+		reservationService: services.NewVenueReservationService(),
+	}
+}
+
+// ShowCalendar, seçilen mekanın aylık seans takvimini ve o ayın rezervasyon listesini gösterir.
+func (h *PanelVenueReservationHandler) ShowCalendar(c *fiber.Ctx) error {
+	params, fieldErrors, err := requests.ParseAndValidateVenueCalendar(c, services.AppointmentLocation())
+	renderData := fiber.Map{
+		"Title":    "Salon Rezervasyonları",
+		"Params":   params,
+		"Location": services.AppointmentLocation(),
+	}
+	status := http.StatusOK
+	if err != nil {
+		renderData["ValidationErrors"] = fieldErrors
+		status = http.StatusBadRequest
+	}
+
+	calendar, err := h.reservationService.GetCalendar(c.UserContext(), currentuser.FromFiber(c).ID, params)
+	if err != nil {
+		if errors.Is(err, services.ErrVenueNotFound) {
+			flashmessages.SetFlashMessage(c, flashmessages.FlashErrorKey, err.Error())
+			return c.Redirect("/panel/rezervasyonlar", fiber.StatusSeeOther)
+		}
+		renderData[renderer.FlashErrorKeyView] = "Rezervasyon takvimi getirilirken bir hata oluştu."
+		calendar = &services.VenueCalendar{Month: params.Month}
+	}
+	renderData["Calendar"] = calendar
+
+	return renderer.Render(c, "panel/reservations/calendar", "layouts/panel", renderData, status)
+}
+
+func (h *PanelVenueReservationHandler) ShowCreateReservation(c *fiber.Ctx) error {
+	venue, done, err := h.venue(c)
+	if done || err != nil {
+		return err
+	}
+
+	return renderer.Render(c, "panel/reservations/create", "layouts/panel", fiber.Map{
+		"Title":      "Yeni Rezervasyon",
+		"Venue":      venue,
+		"Date":       c.Query("date"),
+		"Session":    c.Query("session"),
+		"OptionDays": h.reservationService.OptionDays(),
+	})
+}
+
+func (h *PanelVenueReservationHandler) CreateReservation(c *fiber.Ctx) error {
+	businessID, err := c.ParamsInt("id")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).SendString("Geçersiz Mekan ID")
+	}
+	formURL := fmt.Sprintf("/panel/rezervasyonlar/%d/olustur", businessID)
+	formData := collectFormData(c)
+
+	req, fieldErrors, err := requests.ParseAndValidateVenueReservation(c)
+	if err != nil {
+		formflash.SetData(c, formData)
+		formflash.SetValidationErrors(c, fieldErrors)
+		flashmessages.SetFlashMessage(c, flashmessages.FlashErrorKey, err.Error())
+		return c.Redirect(formURL, fiber.StatusSeeOther)
+	}
+
+	reservation, err := h.reservationService.Create(c.UserContext(), currentuser.FromFiber(c).ID, uint(businessID), req)
+	if err != nil {
+		formflash.SetData(c, formData)
+		flashmessages.SetFlashMessage(c, flashmessages.FlashErrorKey, "Rezervasyon oluşturulamadı: "+reservationErrorMessage(err))
+		return c.Redirect(formURL, fiber.StatusSeeOther)
+	}
+
+	formflash.ClearData(c)
+	message := "Kesin rezervasyon oluşturuldu."
+	if reservation.Status == models.VenueReservationStatusOption {
+		message = fmt.Sprintf("Opsiyon oluşturuldu; tarih %s tarihine kadar tutulacak.", reservation.OptionExpiresAt.In(services.AppointmentLocation()).Format("02.01.2006 15:04"))
+	}
+	flashmessages.SetFlashMessage(c, flashmessages.FlashSuccessKey, message)
+	return c.Redirect(fmt.Sprintf("/panel/rezervasyonlar?business_id=%d&month=%s", businessID, reservation.Date.Format(requests.VenueMonthLayout)), fiber.StatusFound)
+}
+
+func (h *PanelVenueReservationHandler) ConfirmReservation(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).SendString("Geçersiz Rezervasyon ID")
+	}
+
+	err = h.reservationService.Confirm(c.UserContext(), currentuser.FromFiber(c).ID, uint(id))
+	return reservationActionResponse(c, err, "Rezervasyon kesinleştirilemedi: ", "Rezervasyon kesinleştirildi.")
+}
+
+func (h *PanelVenueReservationHandler) RecordDeposit(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).SendString("Geçersiz Rezervasyon ID")
+	}
+
+	req, _, err := requests.ParseAndValidateVenueDeposit(c)
+	if err != nil {
+		return reservationActionFailure(c, fiber.StatusBadRequest, "Kapora kaydedilemedi: "+err.Error())
+	}
+
+	err = h.reservationService.RecordDeposit(c.UserContext(), currentuser.FromFiber(c).ID, uint(id), req.AmountValue())
+	return reservationActionResponse(c, err, "Kapora kaydedilemedi: ", "Kapora ödemesi kaydedildi.")
+}
+
+func (h *PanelVenueReservationHandler) CancelReservation(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).SendString("Geçersiz Rezervasyon ID")
+	}
+
+	req, _, err := requests.ParseAndValidateAppointmentCancel(c)
+	if err != nil {
+		return reservationActionFailure(c, fiber.StatusBadRequest, "Rezervasyon iptal edilemedi: "+err.Error())
+	}
+
+	err = h.reservationService.Cancel(c.UserContext(), currentuser.FromFiber(c).ID, uint(id), req.Reason)
+	return reservationActionResponse(c, err, "Rezervasyon iptal edilemedi: ", "Rezervasyon iptal edildi.")
+}
+
+// venue, rota parametresindeki mekanı yükler; bulunamazsa takvime yönlendirir ve done=true döner.
+func (h *PanelVenueReservationHandler) venue(c *fiber.Ctx) (*models.Business, bool, error) {
+	businessID, err := c.ParamsInt("id")
+	if err != nil {
+		return nil, true, c.Status(fiber.StatusBadRequest).SendString("Geçersiz Mekan ID")
+	}
+	venue, err := h.reservationService.GetVenue(c.UserContext(), currentuser.FromFiber(c).ID, uint(businessID))
+	if err != nil {
+		flashmessages.SetFlashMessage(c, flashmessages.FlashErrorKey, reservationErrorMessage(err))
+		return nil, true, c.Redirect("/panel/rezervasyonlar", fiber.StatusSeeOther)
+	}
+	return venue, false, nil
+}
+
+func reservationErrorMessage(err error) string {
+	for _, known := range []error{
+		services.ErrVenueNotFound,
+		services.ErrVenueNoCapacity,
+		services.ErrVenueSessionTaken,
+		services.ErrVenueOverCapacity,
+		services.ErrVenueInvalidDate,
+		services.ErrVenuePastDate,
+		services.ErrVenueDepositTooHigh,
+	} {
+		if errors.Is(err, known) {
+			return err.Error()
+		}
+	}
+	return "beklenmeyen bir hata oluştu."
+}
+
+// reservationActionResponse, kesinleştirme/kapora/iptal sonucunu fetch isteklerine JSON, form gönderimlerine flash ile bildirir.
+func reservationActionResponse(c *fiber.Ctx, err error, failPrefix, successMsg string) error {
+	if err != nil {
+		status := fiber.StatusInternalServerError
+		errMsg := failPrefix + err.Error()
+		switch {
+		case errors.Is(err, services.ErrVenueReservationNotFound):
+			status = fiber.StatusNotFound
+		case errors.Is(err, services.ErrVenueSessionTaken):
+			status = fiber.StatusConflict
+		case errors.Is(err, services.ErrVenueReservationNotOption),
+			errors.Is(err, services.ErrVenueReservationClosed),
+			errors.Is(err, services.ErrVenueDepositTooHigh):
+			status = fiber.StatusUnprocessableEntity
+		default:
+			errMsg = failPrefix + "beklenmeyen bir hata oluştu."
+		}
+		return reservationActionFailure(c, status, errMsg)
+	}
+
+	if strings.Contains(c.Get("Accept"), "application/json") {
+		return c.JSON(fiber.Map{"message": successMsg})
+	}
+	flashmessages.SetFlashMessage(c, flashmessages.FlashSuccessKey, successMsg)
+	return c.Redirect("/panel/rezervasyonlar", fiber.StatusFound)
+}
+
+func reservationActionFailure(c *fiber.Ctx, status int, errMsg string) error {
+	if strings.Contains(c.Get("Accept"), "application/json") {
+		return c.Status(status).JSON(fiber.Map{"error": errMsg})
+	}
+	flashmessages.SetFlashMessage(c, flashmessages.FlashErrorKey, errMsg)
+	return c.Redirect("/panel/rezervasyonlar", fiber.StatusSeeOther)
+}
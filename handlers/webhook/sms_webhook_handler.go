@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"crypto/subtle"
+	"errors"
+
+	"zatrano/configs/envconfig"
+	"zatrano/configs/logconfig"
+	"zatrano/services"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+type SmsWebhookHandler struct {
+	smsService services.ISmsService
+	token      string
+}
+
+func NewSmsWebhookHandler() *SmsWebhookHandler {
+	return &SmsWebhookHandler{
+		smsService: services.NewSmsService(),
+		token:      envconfig.String("SMS_WEBHOOK_TOKEN", ""),
+	}
+}
+
+// DeliveryReport, sağlayıcıdan gelen teslim raporunu işler.
+// Sağlayıcı panelinde adres ?token=<SMS_WEBHOOK_TOKEN> ile tanımlanmalıdır.
+func (h *SmsWebhookHandler) DeliveryReport(c *fiber.Ctx) error {
+	if h.token == "" || subtle.ConstantTimeCompare([]byte(c.Query("token")), []byte(h.token)) != 1 {
+		return c.SendStatus(fiber.StatusUnauthorized)
+	}
+
+	value := func(key string) string {
+		if v := c.FormValue(key); v != "" {
+			return v
+		}
+		return c.Query(key)
+	}
+
+	provider := c.Params("provider")
+	if err := h.smsService.HandleDeliveryReport(c.UserContext(), provider, value); err != nil {
+		if errors.Is(err, services.ErrSmsUnknownReport) || errors.Is(err, services.ErrSmsProviderReport) {
+			logconfig.Log.Warn("SMS teslim raporu eşleşmedi", zap.String("provider", provider), zap.Error(err))
+			return c.SendStatus(fiber.StatusNotFound)
+		}
+		logconfig.Log.Error("SMS teslim raporu işlenemedi", zap.String("provider", provider), zap.Error(err))
+		return c.SendStatus(fiber.StatusInternalServerError)
+	}
+	return c.SendStatus(fiber.StatusOK)
+}
@@ -0,0 +1,268 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+
+	"zatrano/models"
+	"zatrano/pkg/flashmessages"
+	"zatrano/pkg/formflash"
+	"zatrano/pkg/renderer"
+	"zatrano/requests"
+	"zatrano/services"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type AppointmentHandler struct {
+	profileService     services.IBusinessProfileService
+	appointmentService services.IAppointmentService
+	reviewService      services.IReviewService
+}
+
+func NewAppointmentHandler() *AppointmentHandler {
+	return &AppointmentHandler{
+		profileService:     services.NewBusinessProfileService(),
+		appointmentService: services.NewAppointmentService(),
+		reviewService:      services.NewReviewService(),
+	}
+}
+
+func (h *AppointmentHandler) ShowBooking(c *fiber.Ctx) error {
+	profile, done, err := h.loadProfile(c, "/randevu")
+	if done || err != nil {
+		return err
+	}
+
+	return renderer.Render(c, "website/appointments/book", "layouts/website", fiber.Map{
+		"MetaTitle": profile.Business.Title + " | Randevu Al",
+		"Profile":   profile,
+		"Business":  profile.Business,
+	}, http.StatusOK)
+}
+
+// Slots, seçilen hizmet ve gün için boş saatleri JSON olarak döner.
+func (h *AppointmentHandler) Slots(c *fiber.Ctx) error {
+	profile, done, err := h.loadProfile(c, "/randevu/saatler")
+	if done || err != nil {
+		return err
+	}
+
+	req, _, err := requests.ParseAndValidateAppointmentSlots(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	result, err := h.appointmentService.AvailableSlots(c.UserContext(), profile.Business.ID, req.ProfessionalServiceIDUint(), req.Date)
+	return slotsResponse(c, result, err)
+}
+
+func (h *AppointmentHandler) Book(c *fiber.Ctx) error {
+	profile, done, err := h.loadProfile(c, "/randevu")
+	if done || err != nil {
+		return err
+	}
+	redirectURL := "/isletme/" + profile.Business.Slug + "/randevu"
+
+	req, fieldErrors, err := requests.ParseAndValidateAppointmentRequest(c)
+	if err != nil {
+		formflash.SetData(c, collectAppointmentFormData(c))
+		formflash.SetValidationErrors(c, fieldErrors)
+		flashmessages.SetFlashMessage(c, flashmessages.FlashErrorKey, err.Error())
+		return c.Redirect(redirectURL, fiber.StatusSeeOther)
+	}
+
+	appointment, err := h.appointmentService.Book(c.UserContext(), profile.Business.ID, req)
+	if err != nil {
+		formflash.SetData(c, collectAppointmentFormData(c))
+		flashmessages.SetFlashMessage(c, flashmessages.FlashErrorKey, bookingErrorMessage(err))
+		return c.Redirect(redirectURL, fiber.StatusSeeOther)
+	}
+
+	formflash.ClearData(c)
+	flashmessages.SetFlashMessage(c, flashmessages.FlashSuccessKey, "Randevu talebiniz alındı. Bu sayfanın bağlantısını saklayarak randevunuzu yönetebilirsiniz.")
+	return c.Redirect("/randevu/"+appointment.ManageToken, fiber.StatusSeeOther)
+}
+
+func (h *AppointmentHandler) ShowAppointment(c *fiber.Ctx) error {
+	appointment, err := h.appointmentService.GetByToken(c.UserContext(), c.Params("token"))
+	if err != nil {
+		if errors.Is(err, services.ErrAppointmentNotFound) {
+			return renderer.Render(c, "website/error", "layouts/website", fiber.Map{}, http.StatusNotFound)
+		}
+		return fiber.NewError(fiber.StatusInternalServerError, "Randevu yüklenemedi")
+	}
+
+	review, err := h.reviewService.GetAppointmentReview(c.UserContext(), appointment)
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "Randevu yüklenemedi")
+	}
+
+	return renderer.Render(c, "website/appointments/show", "layouts/website", fiber.Map{
+		"MetaTitle":   "Randevum | zatrano",
+		"Appointment": appointment,
+		"Review":      review,
+		"Location":    services.AppointmentLocation(),
+	}, http.StatusOK)
+}
+
+// Review, tamamlanmış randevu için müşterinin puan ve yorumunu kaydeder.
+func (h *AppointmentHandler) Review(c *fiber.Ctx) error {
+	token := c.Params("token")
+	redirectURL := "/randevu/" + token
+
+	req, fieldErrors, err := requests.ParseAndValidateReviewRequest(c)
+	if err != nil {
+		formflash.SetData(c, collectAppointmentFormData(c))
+		formflash.SetValidationErrors(c, fieldErrors)
+		flashmessages.SetFlashMessage(c, flashmessages.FlashErrorKey, err.Error())
+		return c.Redirect(redirectURL, fiber.StatusSeeOther)
+	}
+
+	review, err := h.reviewService.Submit(c.UserContext(), token, req)
+	if err != nil {
+		formflash.SetData(c, collectAppointmentFormData(c))
+		flashmessages.SetFlashMessage(c, flashmessages.FlashErrorKey, "Değerlendirmeniz kaydedilemedi: "+reviewErrorMessage(err))
+		return c.Redirect(redirectURL, fiber.StatusSeeOther)
+	}
+
+	formflash.ClearData(c)
+	message := "Değerlendirmeniz alındı. Onaylandıktan sonra işletme sayfasında yayınlanacak."
+	if review.Status == models.ReviewStatusApproved {
+		message = "Değerlendirmeniz için teşekkürler!"
+	}
+	flashmessages.SetFlashMessage(c, flashmessages.FlashSuccessKey, message)
+	return c.Redirect(redirectURL, fiber.StatusSeeOther)
+}
+
+func (h *AppointmentHandler) RescheduleSlots(c *fiber.Ctx) error {
+	appointment, err := h.appointmentService.GetByToken(c.UserContext(), c.Params("token"))
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+	}
+	result, err := h.appointmentService.RescheduleSlots(c.UserContext(), appointment, c.Query("date"))
+	return slotsResponse(c, result, err)
+}
+
+func (h *AppointmentHandler) Reschedule(c *fiber.Ctx) error {
+	token := c.Params("token")
+	redirectURL := "/randevu/" + token
+
+	req, _, err := requests.ParseAndValidateAppointmentReschedule(c)
+	if err != nil {
+		flashmessages.SetFlashMessage(c, flashmessages.FlashErrorKey, err.Error())
+		return c.Redirect(redirectURL, fiber.StatusSeeOther)
+	}
+
+	if err := h.appointmentService.RescheduleByCustomer(c.UserContext(), token, req.StartsAt); err != nil {
+		flashmessages.SetFlashMessage(c, flashmessages.FlashErrorKey, "Randevu ertelenemedi: "+bookingErrorMessage(err))
+		return c.Redirect(redirectURL, fiber.StatusSeeOther)
+	}
+
+	flashmessages.SetFlashMessage(c, flashmessages.FlashSuccessKey, "Randevunuz yeni saate taşındı.")
+	return c.Redirect(redirectURL, fiber.StatusSeeOther)
+}
+
+func (h *AppointmentHandler) Cancel(c *fiber.Ctx) error {
+	token := c.Params("token")
+	redirectURL := "/randevu/" + token
+
+	req, _, err := requests.ParseAndValidateAppointmentCancel(c)
+	if err != nil {
+		flashmessages.SetFlashMessage(c, flashmessages.FlashErrorKey, err.Error())
+		return c.Redirect(redirectURL, fiber.StatusSeeOther)
+	}
+
+	if err := h.appointmentService.CancelByCustomer(c.UserContext(), token, req.Reason); err != nil {
+		flashmessages.SetFlashMessage(c, flashmessages.FlashErrorKey, "Randevu iptal edilemedi: "+bookingErrorMessage(err))
+		return c.Redirect(redirectURL, fiber.StatusSeeOther)
+	}
+
+	flashmessages.SetFlashMessage(c, flashmessages.FlashSuccessKey, "Randevunuz iptal edildi.")
+	return c.Redirect(redirectURL, fiber.StatusSeeOther)
+}
+
+// loadProfile, slug'a ait işletmeyi yükler. Eski bir slug ise güncel adrese yönlendirir,
+// bulunamazsa hata sayfasını basar; her iki durumda da done=true döner.
+func (h *AppointmentHandler) loadProfile(c *fiber.Ctx, suffix string) (*services.BusinessProfile, bool, error) {
+	slug, err := url.PathUnescape(c.Params("slug"))
+	if err != nil || slug == "" {
+		return nil, true, renderer.Render(c, "website/error", "layouts/website", fiber.Map{}, http.StatusNotFound)
+	}
+
+	profile, redirectSlug, err := h.profileService.GetProfile(c.UserContext(), slug)
+	if redirectSlug != "" {
+		return nil, true, c.Redirect("/isletme/"+redirectSlug+suffix, fiber.StatusMovedPermanently)
+	}
+	if err != nil {
+		if errors.Is(err, services.ErrBusinessProfileNotFound) {
+			return nil, true, renderer.Render(c, "website/error", "layouts/website", fiber.Map{}, http.StatusNotFound)
+		}
+		return nil, true, fiber.NewError(fiber.StatusInternalServerError, "İşletme sayfası yüklenemedi")
+	}
+	return profile, false, nil
+}
+
+func slotsResponse(c *fiber.Ctx, result *services.AppointmentSlots, err error) error {
+	if err != nil {
+		status := fiber.StatusInternalServerError
+		if errors.Is(err, services.ErrAppointmentServiceNotFound) || errors.Is(err, services.ErrAppointmentInvalidDate) {
+			status = fiber.StatusBadRequest
+		}
+		return c.Status(status).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	items := make([]fiber.Map, 0, len(result.Starts))
+	for _, t := range result.Starts {
+		items = append(items, fiber.Map{
+			"value": t.Format(requests.AppointmentStartLayout),
+			"label": t.Format("15:04"),
+		})
+	}
+	return c.JSON(fiber.Map{
+		"date":     result.Day.Format("2006-01-02"),
+		"duration": result.Service.Duration,
+		"slots":    items,
+	})
+}
+
+func bookingErrorMessage(err error) string {
+	for _, known := range []error{
+		services.ErrAppointmentSlotTaken,
+		services.ErrAppointmentSlotUnavailable,
+		services.ErrAppointmentServiceNotFound,
+		services.ErrAppointmentInvalidPhone,
+		services.ErrAppointmentTooLate,
+		services.ErrAppointmentNotFound,
+	} {
+		if errors.Is(err, known) {
+			return err.Error()
+		}
+	}
+	return "beklenmeyen bir hata oluştu, lütfen tekrar deneyin"
+}
+
+func reviewErrorMessage(err error) string {
+	for _, known := range []error{
+		services.ErrReviewNotAllowed,
+		services.ErrReviewAlreadyExists,
+		services.ErrReviewPeriodExpired,
+		services.ErrAppointmentNotFound,
+	} {
+		if errors.Is(err, known) {
+			return err.Error()
+		}
+	}
+	return "beklenmeyen bir hata oluştu, lütfen tekrar deneyin"
+}
+
+func collectAppointmentFormData(c *fiber.Ctx) map[string]string {
+	formData := make(map[string]string)
+	c.Request().PostArgs().VisitAll(func(key, value []byte) {
+		if string(key) != "csrf_token" {
+			formData[string(key)] = string(value)
+		}
+	})
+	return formData
+}
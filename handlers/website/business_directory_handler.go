@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"net/http"
+
+	"zatrano/pkg/renderer"
+	"zatrano/requests"
+	"zatrano/services"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type BusinessDirectoryHandler struct {
+	directoryService services.IBusinessDirectoryService
+}
+
+func NewBusinessDirectoryHandler() *BusinessDirectoryHandler {
+	return &BusinessDirectoryHandler{
+		directoryService: services.NewBusinessDirectoryService(),
+	}
+}
+
+func (h *BusinessDirectoryHandler) ListBusinesses(c *fiber.Ctx) error {
+	params, fieldErrors, err := requests.ParseAndValidateBusinessDirectory(c)
+	if err != nil {
+		// Bozuk bir paylaşım bağlantısı hata sayfası yerine filtresiz listeye düşer
+		params = (&requests.BusinessDirectoryRequest{}).ToServiceParams()
+	}
+
+	result, err := h.directoryService.Search(c.UserContext(), params)
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "İşletmeler yüklenemedi")
+	}
+
+	filters, err := h.directoryService.GetFilters(c.UserContext(), params.CityID)
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "Filtreler yüklenemedi")
+	}
+
+	sortOptions := []fiber.Map{
+		{"Value": requests.DirectorySortRelevance, "Label": "En alakalı"},
+		{"Value": requests.DirectorySortRating, "Label": "En yüksek puan"},
+		{"Value": requests.DirectorySortPriceAsc, "Label": "Fiyat (artan)"},
+		{"Value": requests.DirectorySortPriceDesc, "Label": "Fiyat (azalan)"},
+		{"Value": requests.DirectorySortNewest, "Label": "En yeni"},
+	}
+	if params.Near != nil {
+		sortOptions = append([]fiber.Map{{"Value": requests.DirectorySortDistance, "Label": "En yakın"}}, sortOptions...)
+	}
+
+	return renderer.Render(c, "website/businesses", "layouts/website", fiber.Map{
+		"MetaTitle":        "İşletmeler | zatrano",
+		"MetaDescription":  "Şehrinizdeki işletmeleri türe, hizmete ve konuma göre arayın, puanlarını ve fiyatlarını karşılaştırın.",
+		"Result":           result,
+		"Params":           params,
+		"Filters":          filters,
+		"ValidationErrors": fieldErrors,
+		"SortOptions":      sortOptions,
+		"RadiusOptions":    requests.DirectoryRadiusOptions,
+	}, http.StatusOK)
+}
@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+
+	"zatrano/pkg/renderer"
+	"zatrano/services"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type BusinessProfileHandler struct {
+	profileService services.IBusinessProfileService
+}
+
+func NewBusinessProfileHandler() *BusinessProfileHandler {
+	return &BusinessProfileHandler{
+		profileService: services.NewBusinessProfileService(),
+	}
+}
+
+func (h *BusinessProfileHandler) ShowBusiness(c *fiber.Ctx) error {
+	slug, err := url.PathUnescape(c.Params("slug"))
+	if err != nil || slug == "" {
+		return renderer.Render(c, "website/error", "layouts/website", fiber.Map{}, http.StatusNotFound)
+	}
+
+	profile, redirectSlug, err := h.profileService.GetProfile(c.UserContext(), slug)
+	if redirectSlug != "" {
+		return c.Redirect("/isletme/"+redirectSlug, fiber.StatusMovedPermanently)
+	}
+	if err != nil {
+		if errors.Is(err, services.ErrBusinessProfileNotFound) {
+			return renderer.Render(c, "website/error", "layouts/website", fiber.Map{}, http.StatusNotFound)
+		}
+		return fiber.NewError(fiber.StatusInternalServerError, "İşletme sayfası yüklenemedi")
+	}
+
+	b := profile.Business
+	description := b.Description
+	if r := []rune(description); len(r) > 160 {
+		description = string(r[:157]) + "..."
+	}
+
+	return renderer.Render(c, "website/business", "layouts/website", fiber.Map{
+		"MetaTitle":       b.Title + " | zatrano",
+		"MetaDescription": description,
+		"CanonicalURL":    profile.CanonicalURL,
+		"JSONLD":          profile.JSONLD,
+		"Profile":         profile,
+		"Business":        b,
+	}, http.StatusOK)
+}
@@ -20,6 +20,7 @@ import (
 	"zatrano/pkg/flashmessages"
 	"zatrano/pkg/templatehelpers"
 	"zatrano/routes"
+	"zatrano/services"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/compress"
@@ -59,6 +60,7 @@ func main() {
 	fileconfig.InitFileConfig()
 	fileconfig.Config.SetAllowedExtensions("invitations", []string{"jpg", "jpeg", "png", "webp"})
 	fileconfig.Config.SetAllowedExtensions("post-categories", []string{"jpg", "jpeg", "png", "webp"})
+	fileconfig.Config.SetAllowedExtensions("businesses", []string{"jpg", "jpeg", "png", "webp"})
 
 	// Template engine
 	engine := html.New("./views", ".html")
@@ -162,6 +164,10 @@ func startServer(app *fiber.App) {
 		logconfig.Log.Warn("APP_BASE_URL HTTPS değil, production için önerilmez", zap.String("base_url", baseURL))
 	}
 
+	// Süresi dolan parola sıfırlama ve e-posta doğrulama anahtarlarının temizliği
+	cleanupInterval := time.Duration(envconfig.Int("USER_TOKEN_CLEANUP_MINUTES", 60)) * time.Minute
+	go services.NewUserTokenService().RunCleanup(ctx, cleanupInterval)
+
 	go func() {
 		logconfig.SLog.Infow("Uygulama dinleniyor",
 			"env", envconfig.String("APP_ENV", "development"),
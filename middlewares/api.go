@@ -0,0 +1,110 @@
+package middlewares
+
+import (
+	"errors"
+	"strings"
+
+	"zatrano/configs/logconfig"
+	"zatrano/models"
+	"zatrano/pkg/apiresponse"
+	"zatrano/pkg/currentuser"
+	"zatrano/services"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+// APITokenLocalsKey, doğrulanan kişisel erişim anahtarının Locals anahtarıdır.
+const APITokenLocalsKey = "apiToken"
+
+// APIErrors — API grubunda handler'ların döndüğü hataları (bulunamayan rota dahil) JSON hata zarfına çevirir.
+// Uygulamanın genel hata işleyicisi düz metin döndüğü için API'de kullanılmaz.
+func APIErrors() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		err := c.Next()
+		if err == nil {
+			return nil
+		}
+
+		var fe *fiber.Error
+		if errors.As(err, &fe) {
+			return apiresponse.Error(c, fe.Code, apiresponse.CodeForStatus(fe.Code), fe.Message)
+		}
+
+		logconfig.Log.Error("API isteği işlenemedi",
+			zap.Error(err),
+			zap.String("method", c.Method()),
+			zap.String("path", c.Path()),
+			zap.String("ip", c.IP()))
+		return apiresponse.Error(c, fiber.StatusInternalServerError, apiresponse.CodeInternal, "Beklenmeyen bir hata oluştu.")
+	}
+}
+
+// APITokenAuth — "Authorization: Bearer <anahtar>" başlığındaki kişisel erişim anahtarını doğrular.
+// Oturum çerezi kullanılmaz; bu yüzden API rotaları CSRF kontrolünden muaftır.
+func APITokenAuth(tokens services.IPersonalAccessTokenService) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		header := c.Get(fiber.HeaderAuthorization)
+		raw, found := strings.CutPrefix(header, "Bearer ")
+		if !found || strings.TrimSpace(raw) == "" {
+			c.Set(fiber.HeaderWWWAuthenticate, `Bearer realm="api"`)
+			return apiresponse.Error(c, fiber.StatusUnauthorized, apiresponse.CodeUnauthorized, "Erişim anahtarı gönderilmedi.")
+		}
+
+		token, err := tokens.Authenticate(c.UserContext(), strings.TrimSpace(raw), c.IP())
+		if err != nil {
+			if errors.Is(err, services.ErrAccessTokenInvalid) || errors.Is(err, services.ErrUserInactive) {
+				c.Set(fiber.HeaderWWWAuthenticate, `Bearer realm="api", error="invalid_token"`)
+				return apiresponse.Error(c, fiber.StatusUnauthorized, apiresponse.CodeUnauthorized, "Erişim anahtarı geçersiz, süresi dolmuş ya da iptal edilmiş.")
+			}
+			return apiresponse.Error(c, fiber.StatusInternalServerError, apiresponse.CodeInternal, "Erişim anahtarı doğrulanamadı.")
+		}
+
+		user := token.User
+		authUser := AuthUser{
+			ID:            user.ID,
+			Email:         user.Email,
+			UserTypeID:    user.UserTypeID,
+			IsActive:      user.IsActive,
+			EmailVerified: user.EmailVerified,
+			Permissions:   user.UserType.PermissionKeys(),
+			LandingPage:   user.UserType.LandingPath(),
+		}
+		c.Locals(APITokenLocalsKey, token)
+		c.Locals("authUser", authUser)
+
+		// created_by / updated_by alanları anahtarın sahibine yazılır
+		ctx := currentuser.SetToContext(c.UserContext(), currentuser.CurrentUser{
+			ID:          user.ID,
+			Email:       user.Email,
+			UserTypeID:  user.UserTypeID,
+			Permissions: authUser.Permissions,
+		})
+		c.SetUserContext(ctx)
+
+		return c.Next()
+	}
+}
+
+// RequireTokenScope — anahtarın kapsamı ve sahibinin rol yetkileri birlikte kontrol edilir; rolünden
+// yetkisi alınan kullanıcının eski anahtarları da o uç noktalara erişemez. APITokenAuth'tan sonra kullanılmalıdır.
+func RequireTokenScope(scope string, permissions ...string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		token, ok := c.Locals(APITokenLocalsKey).(*models.PersonalAccessToken)
+		if !ok {
+			return apiresponse.Error(c, fiber.StatusUnauthorized, apiresponse.CodeUnauthorized, "Erişim anahtarı gönderilmedi.")
+		}
+		if !token.HasScope(scope) {
+			return apiresponse.Error(c, fiber.StatusForbidden, apiresponse.CodeForbidden,
+				"Erişim anahtarının bu işlem için \""+scope+"\" kapsamı yok.")
+		}
+
+		user, _ := c.Locals("authUser").(AuthUser)
+		for _, permission := range permissions {
+			if !user.Can(permission) {
+				return apiresponse.Error(c, fiber.StatusForbidden, apiresponse.CodeForbidden, "Bu işlem için yetkiniz bulunmamaktadır.")
+			}
+		}
+		return c.Next()
+	}
+}
@@ -1,6 +1,8 @@
 package middlewares
 
 import (
+	"strings"
+
 	"zatrano/configs/sessionconfig"
 	"zatrano/pkg/currentuser"
 	"zatrano/pkg/flashmessages"
@@ -16,12 +18,56 @@ type AuthUser struct {
 	UserTypeID    uint
 	IsActive      bool
 	EmailVerified bool
+	Permissions   []string
+	LandingPage   string
+}
+
+// Can — kullanıcının rolü verilen yetkiye sahip mi?
+func (u AuthUser) Can(permission string) bool {
+	for _, p := range u.Permissions {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// trackSession, oturumun son görülme zamanını günceller ve uzaktan kapatılıp kapatılmadığını söyler.
+// Redis hatalarında kullanıcı dışarıda bırakılmaz.
+func trackSession(c *fiber.Ctx, userID uint) bool {
+	sess, err := sessionconfig.SessionStart(c)
+	if err != nil {
+		return false
+	}
+
+	sessions := services.NewSessionService()
+	userAgent := c.Get(fiber.HeaderUserAgent)
+	alive, err := sessions.Touch(c.UserContext(), userID, sess.ID(), userAgent, c.IP())
+	if err != nil || alive {
+		return false
+	}
+
+	if indexed, _ := sess.Get(services.SessionIndexedKey).(bool); indexed {
+		return true
+	}
+	// Oturum dizini öncesinden kalan oturum: şimdi kaydet
+	if err := sessions.Register(c.UserContext(), userID, sess.ID(), userAgent, c.IP()); err == nil {
+		sess.Set(services.SessionIndexedKey, true)
+		_ = sess.Save()
+	}
+	return false
+}
+
+func twoFactorSetupAllowed(path string) bool {
+	return strings.HasPrefix(path, "/auth/two-factor/") || path == "/auth/logout"
 }
 
 // AuthMiddleware — Sırayla tüm authentication kontrollerini yapar:
 // 1. Oturum kontrolü
 // 2. Email doğrulanmış mı?
 // 3. Kullanıcı aktif mi?
+// 4. Oturum uzaktan kapatılmış mı?
+// 5. Rol iki adımlı doğrulamayı zorunlu kılıyorsa etkin mi?
 func AuthMiddleware(c *fiber.Ctx) error {
 	// 1. OTURUM KONTROLÜ
 	userID, err := sessionconfig.GetUserIDFromSession(c)
@@ -68,6 +114,21 @@ func AuthMiddleware(c *fiber.Ctx) error {
 		return c.Redirect("/auth/login", fiber.StatusSeeOther)
 	}
 
+	// 4. OTURUM DİZİNİ (son görülme / uzaktan kapatma)
+	if revoked := trackSession(c, user.ID); revoked {
+		_ = sessionconfig.DestroySession(c)
+		_ = flashmessages.SetFlashMessage(c, flashmessages.FlashErrorKey, "Oturumunuz kapatıldı. Lütfen tekrar giriş yapın.")
+		return c.Redirect("/auth/login", fiber.StatusSeeOther)
+	}
+
+	// 5. İKİ ADIMLI DOĞRULAMA ZORUNLULUĞU
+	// Rol zorunlu kılıyorsa kurulum tamamlanana kadar yalnızca kurulum sayfaları ve çıkış açıktır
+	if user.UserType.RequireTwoFactor && !user.TwoFactorEnabled() && !twoFactorSetupAllowed(c.Path()) {
+		_ = flashmessages.SetFlashMessage(c, flashmessages.FlashErrorKey,
+			"Rolünüz iki adımlı doğrulamayı zorunlu kılıyor. Devam etmek için doğrulama uygulamanızı bağlayın.")
+		return c.Redirect("/auth/two-factor/setup", fiber.StatusSeeOther)
+	}
+
 	// Tüm kontroller başarılı, kullanıcı bilgilerini kaydet
 	authUser := AuthUser{
 		ID:            user.ID,
@@ -75,6 +136,8 @@ func AuthMiddleware(c *fiber.Ctx) error {
 		UserTypeID:    user.UserTypeID,
 		IsActive:      user.IsActive,
 		EmailVerified: user.EmailVerified,
+		Permissions:   user.UserType.PermissionKeys(),
+		LandingPage:   user.UserType.LandingPath(),
 	}
 
 	// Locals'a kaydet
@@ -82,9 +145,10 @@ func AuthMiddleware(c *fiber.Ctx) error {
 
 	// Context'e kaydet
 	ctx := currentuser.SetToContext(c.UserContext(), currentuser.CurrentUser{
-		ID:         user.ID,
-		Email:      user.Email,
-		UserTypeID: user.UserTypeID,
+		ID:          user.ID,
+		Email:       user.Email,
+		UserTypeID:  user.UserTypeID,
+		Permissions: authUser.Permissions,
 	})
 	c.SetUserContext(ctx)
 
@@ -36,9 +36,6 @@ func GuestMiddleware(c *fiber.Ctx) error {
 	})
 	c.SetUserContext(ctx)
 
-	// Kullanıcı türüne göre yönlendir
-	if user.UserTypeID == 1 { // Admin
-		return c.Redirect("/dashboard/home", fiber.StatusSeeOther)
-	}
-	return c.Redirect("/panel/anasayfa", fiber.StatusSeeOther)
+	// Rolün açılış sayfasına yönlendir
+	return c.Redirect(user.UserType.LandingPath(), fiber.StatusSeeOther)
 }
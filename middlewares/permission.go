@@ -0,0 +1,55 @@
+package middlewares
+
+import (
+	"strings"
+
+	"zatrano/configs/sessionconfig"
+	"zatrano/pkg/flashmessages"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// RequirePermission — Kullanıcının rolünün verilen yetkilerin tamamına sahip olduğunu kontrol eder.
+// AuthMiddleware'den sonra kullanılmalıdır.
+func RequirePermission(permissions ...string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		val := c.Locals("authUser")
+		if val == nil {
+			_ = flashmessages.SetFlashMessage(c, flashmessages.FlashErrorKey, "Oturum bulunamadı")
+			return c.Redirect("/auth/login", fiber.StatusSeeOther)
+		}
+
+		user, ok := val.(AuthUser)
+		if !ok {
+			_ = sessionconfig.DestroySession(c)
+			_ = flashmessages.SetFlashMessage(c, flashmessages.FlashErrorKey, "Oturum bilgileri geçersiz")
+			return c.Redirect("/auth/login", fiber.StatusSeeOther)
+		}
+
+		for _, permission := range permissions {
+			if !user.Can(permission) {
+				return forbidden(c, user)
+			}
+		}
+
+		return c.Next()
+	}
+}
+
+// forbidden — Yetkisiz erişimde kullanıcıyı rolünün açılış sayfasına gönderir.
+// Açılış sayfasının kendisi yetki dışındaysa döngüye girmemek için 403 döner.
+func forbidden(c *fiber.Ctx, user AuthUser) error {
+	const msg = "Bu sayfaya erişim yetkiniz bulunmamaktadır."
+
+	if strings.Contains(c.Get("Accept"), "application/json") {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": msg})
+	}
+
+	landing := user.LandingPage
+	if landing == "" || landing == c.Path() {
+		return c.Status(fiber.StatusForbidden).SendString(msg)
+	}
+
+	_ = flashmessages.SetFlashMessage(c, flashmessages.FlashErrorKey, msg)
+	return c.Redirect(landing, fiber.StatusSeeOther)
+}
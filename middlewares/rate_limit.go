@@ -5,9 +5,11 @@ import (
 	"time"
 
 	"zatrano/configs/envconfig"
+	"zatrano/configs/redisconfig"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/limiter"
+	"github.com/gofiber/storage/redis/v3"
 )
 
 // Ortak whitelist IP’leri (örn: local, internal)
@@ -69,16 +71,19 @@ func FormPostRateLimit() fiber.Handler {
 	})
 }
 
-// 🔐 Login özel limiter — brute force engelleme
+// 🔐 Login özel limiter — brute force engelleme (parola ve iki adımlı doğrulama kodu aynı sayacı paylaşır).
+// Sayaç Redis'te tutulur; böylece birden fazla uygulama örneği aynı limiti uygular. Hesap ve IP bazlı
+// kilitler LoginThrottleService tarafından ayrıca (tüm ortamlarda) uygulanır.
 func LoginRateLimit() fiber.Handler {
 	return limiter.New(limiter.Config{
 		Max:        envconfig.Int("LOGIN_RATE_MAX", 5),
 		Expiration: time.Minute,
+		Storage:    redis.NewFromConnection(redisconfig.GetClient()),
 		KeyGenerator: func(c *fiber.Ctx) string {
-			return "login:" + c.IP()
+			return redisconfig.GetPrefixedKey("rate_limit", "login:"+c.IP())
 		},
 		Next: func(c *fiber.Ctx) bool {
-			if !(c.Method() == fiber.MethodPost && c.Path() == "/auth/login") {
+			if !(c.Method() == fiber.MethodPost && (c.Path() == "/auth/login" || c.Path() == "/auth/two-factor" || c.Path() == "/auth/magic-link" || c.Path() == "/auth/passkey/login/finish")) {
 				return true
 			}
 			return shouldSkipLimit(c)
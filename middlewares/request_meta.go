@@ -0,0 +1,47 @@
+package middlewares
+
+import (
+	"zatrano/pkg/requestmeta"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+)
+
+// RequestMeta — her isteğe bir istek kimliği verir ve kimliği, IP adresini context'e koyar.
+// Önündeki vekil sunucunun gönderdiği X-Request-ID geçerliyse korunur; böylece denetim kaydı
+// ile sunucu logları aynı kimlikle eşleştirilebilir.
+func RequestMeta() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		requestID := c.Get(requestmeta.HeaderRequestID)
+		if !validRequestID(requestID) {
+			requestID = utils.UUIDv4()
+		}
+		requestID = utils.CopyString(requestID)
+		c.Set(requestmeta.HeaderRequestID, requestID)
+		c.Locals("requestID", requestID)
+
+		ctx := requestmeta.WithContext(c.UserContext(), requestmeta.Meta{
+			RequestID: requestID,
+			IP:        utils.CopyString(c.IP()),
+			UserAgent: utils.CopyString(c.Get(fiber.HeaderUserAgent)),
+		})
+		c.SetUserContext(ctx)
+
+		return c.Next()
+	}
+}
+
+// validRequestID, dışarıdan gelen kimliği kısa ve log/HTML'e güvenle yazılabilecek karakterlerle sınırlar.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > 64 {
+		return false
+	}
+	for _, r := range id {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_', r == '.':
+		default:
+			return false
+		}
+	}
+	return true
+}
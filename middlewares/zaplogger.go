@@ -19,6 +19,7 @@ func ZapLogger() fiber.Handler {
 		logconfig.SLog.Desugar().Sugar().Infow("request",
 			"ip", ip,
 			"path", path,
+			"request_id", c.Locals("requestID"),
 		)
 
 		return err
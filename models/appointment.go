@@ -0,0 +1,54 @@
+package models
+
+import "time"
+
+const (
+	AppointmentStatusPending   = "pending"
+	AppointmentStatusConfirmed = "confirmed"
+	AppointmentStatusCancelled = "cancelled"
+)
+
+const (
+	AppointmentActorCustomer = "customer"
+	AppointmentActorBusiness = "business"
+)
+
+type Appointment struct {
+	BaseModel
+
+	BusinessID            uint `gorm:"index;not null"`
+	ProfessionalID        uint `gorm:"index:idx_appointments_professional_time,priority:1;not null"`
+	ProfessionalServiceID uint `gorm:"index;not null"`
+
+	StartsAt time.Time `gorm:"index:idx_appointments_professional_time,priority:2;not null"`
+	EndsAt   time.Time `gorm:"not null"`
+
+	// Randevu anındaki süre ve fiyat; hizmet sonradan değişse de korunur
+	Duration uint    `gorm:"not null"`
+	Price    float64 `gorm:"not null"`
+
+	CustomerName  string `gorm:"type:varchar(100);not null"`
+	CustomerPhone string `gorm:"type:varchar(20);not null;index"`
+	CustomerEmail string `gorm:"type:varchar(100)"`
+	Note          string `gorm:"type:text"`
+
+	Status       string `gorm:"type:varchar(20);not null;index"`
+	CancelledBy  string `gorm:"type:varchar(20)"`
+	CancelReason string `gorm:"type:varchar(255)"`
+
+	// Müşterinin giriş yapmadan randevusunu yönetebilmesi için
+	ManageToken string `gorm:"type:varchar(64);uniqueIndex;not null"`
+
+	Business            *Business            `gorm:"foreignKey:BusinessID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	Professional        *Professional        `gorm:"foreignKey:ProfessionalID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	ProfessionalService *ProfessionalService `gorm:"foreignKey:ProfessionalServiceID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+}
+
+func (Appointment) TableName() string {
+	return "appointments"
+}
+
+// Blocks — iptal edilmemiş randevular takvimde yer kaplar
+func (a Appointment) Blocks() bool {
+	return a.Status == AppointmentStatusPending || a.Status == AppointmentStatusConfirmed
+}
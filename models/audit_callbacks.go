@@ -0,0 +1,366 @@
+package models
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"time"
+
+	"zatrano/pkg/currentuser"
+	"zatrano/pkg/requestmeta"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+const (
+	auditBeforeKey = "audit:before"
+	// auditMaxRows, tek bir toplu işlemde kaydı tutulan en fazla satır sayısıdır.
+	auditMaxRows = 1000
+)
+
+// auditSkippedTables, denetim kaydı tutulmayan tablolardır.
+var auditSkippedTables = map[string]bool{
+	"audit_logs": true,
+}
+
+// auditIgnoredColumns, farka yazılmayan kayıt tutma kolonlarıdır (kimlik zaten EntityID'de); bir güncellemede
+// yalnızca bunlar değiştiyse (ör. erişim anahtarının son kullanım zamanı) kayıt yazılmaz.
+var auditIgnoredColumns = map[string]bool{
+	"id":                   true,
+	"created_at":           true,
+	"updated_at":           true,
+	"created_by":           true,
+	"updated_by":           true,
+	"deleted_by":           true,
+	"last_used_at":         true,
+	"last_used_ip":         true,
+	"two_factor_last_step": true,
+}
+
+// auditMaskedPatterns; adında bunlardan biri geçen kolonların değeri kayda yazılmaz, yalnızca değiştiği görülür.
+var auditMaskedPatterns = []string{"password", "secret", "token", "_hash"}
+
+func auditMasked(column string) bool {
+	for _, pattern := range auditMaskedPatterns {
+		if strings.Contains(column, pattern) {
+			return true
+		}
+	}
+	return false
+}
+
+// RegisterAuditCallbacks; GORM üzerinden yapılan oluşturma, güncelleme ve silmeleri aynı işlem (transaction)
+// içinde audit_logs tablosuna yazar. Yalnızca bir isteğe ya da oturum açmış kullanıcıya bağlı değişiklikler
+// kaydedilir; migrasyon, seed ve zamanlanmış temizlik işleri kayıt üretmez. Exec ile çalıştırılan ham SQL
+// kapsam dışındadır. Kayıt yazılamazsa değişiklik de geri alınır.
+func RegisterAuditCallbacks(db *gorm.DB) {
+	db.Callback().Create().Before("gorm:after_create").Register("audit:after_create", auditAfterCreate)
+
+	db.Callback().Update().Before("gorm:update").Register("audit:before_update", auditBeforeChange)
+	db.Callback().Update().Before("gorm:after_update").Register("audit:after_update", auditAfterUpdate)
+
+	db.Callback().Delete().Before("gorm:delete").Register("audit:before_delete", auditBeforeChange)
+	db.Callback().Delete().Before("gorm:after_delete").Register("audit:after_delete", auditAfterDelete)
+}
+
+// auditTarget, işlemin kaydı tutulacak bir modele ait olup olmadığını söyler ve birincil anahtarı döner.
+func auditTarget(tx *gorm.DB) (*schema.Field, bool) {
+	stmt := tx.Statement
+	if tx.Error != nil || tx.DryRun || stmt.Schema == nil || auditSkippedTables[stmt.Schema.Table] {
+		return nil, false
+	}
+	if _, ok := requestmeta.FromContext(stmt.Context); !ok && getCurrentUserID(stmt.Context) == 0 {
+		return nil, false
+	}
+
+	pk := stmt.Schema.PrioritizedPrimaryField
+	if pk == nil {
+		return nil, false
+	}
+	switch pk.IndirectFieldType.Kind() {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return pk, true
+	}
+	return nil, false
+}
+
+// auditPrimaryKeys, işleme verilen model değer(ler)inin dolu birincil anahtarlarını toplar.
+func auditPrimaryKeys(tx *gorm.DB, pk *schema.Field) []uint {
+	ctx := tx.Statement.Context
+	rv := tx.Statement.ReflectValue
+	var ids []uint
+	add := func(v reflect.Value) {
+		if v.Kind() != reflect.Struct || v.Type() != tx.Statement.Schema.ModelType {
+			return
+		}
+		if val, zero := pk.ValueOf(ctx, v); !zero {
+			if id := auditUint(val); id > 0 {
+				ids = append(ids, id)
+			}
+		}
+	}
+
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < rv.Len(); i++ {
+			add(reflect.Indirect(rv.Index(i)))
+		}
+	case reflect.Struct:
+		add(rv)
+	}
+	return ids
+}
+
+// auditSnapshot, satırların veritabanındaki güncel hâlini kolon adı → değer olarak okur.
+func auditSnapshot(tx *gorm.DB, pk *schema.Field, ids []uint, where *clause.Where, unscoped bool) ([]map[string]interface{}, error) {
+	query := tx.Session(&gorm.Session{NewDB: true}).Model(reflect.New(tx.Statement.Schema.ModelType).Interface())
+	if unscoped {
+		query = query.Unscoped()
+	}
+	if len(ids) > 0 {
+		values := make([]interface{}, len(ids))
+		for i, id := range ids {
+			values[i] = id
+		}
+		query = query.Where(clause.IN{Column: clause.Column{Table: clause.CurrentTable, Name: pk.DBName}, Values: values})
+	}
+	if where != nil {
+		query = query.Clauses(clause.Where{Exprs: where.Exprs})
+	}
+
+	var rows []map[string]interface{}
+	if err := query.Order(clause.OrderByColumn{Column: clause.Column{Table: clause.CurrentTable, Name: pk.DBName}}).
+		Limit(auditMaxRows).Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	if len(rows) == auditMaxRows {
+		tx.Logger.Warn(tx.Statement.Context, "denetim kaydı %s tablosundaki ilk %d satırla sınırlandı", tx.Statement.Schema.Table, auditMaxRows)
+	}
+	return rows, nil
+}
+
+// auditBeforeChange, güncelleme ve silmeden önce etkilenecek satırların eski hâlini saklar.
+func auditBeforeChange(tx *gorm.DB) {
+	pk, ok := auditTarget(tx)
+	if !ok {
+		return
+	}
+
+	ids := auditPrimaryKeys(tx, pk)
+	var where *clause.Where
+	if c, ok := tx.Statement.Clauses["WHERE"]; ok {
+		if w, ok := c.Expression.(clause.Where); ok && len(w.Exprs) > 0 {
+			where = &w
+		}
+	}
+	// Koşulsuz toplu işlemleri GORM zaten reddeder; tüm tabloyu okumaya gerek yok
+	if len(ids) == 0 && where == nil {
+		return
+	}
+
+	rows, err := auditSnapshot(tx, pk, ids, where, tx.Statement.Unscoped)
+	if err != nil {
+		_ = tx.AddError(err)
+		return
+	}
+	tx.InstanceSet(auditBeforeKey, rows)
+}
+
+func auditBeforeRows(tx *gorm.DB) []map[string]interface{} {
+	if tx.Error != nil {
+		return nil
+	}
+	v, ok := tx.InstanceGet(auditBeforeKey)
+	if !ok {
+		return nil
+	}
+	rows, _ := v.([]map[string]interface{})
+	return rows
+}
+
+func auditAfterCreate(tx *gorm.DB) {
+	pk, ok := auditTarget(tx)
+	if !ok {
+		return
+	}
+	ids := auditPrimaryKeys(tx, pk)
+	if len(ids) == 0 {
+		return
+	}
+
+	rows, err := auditSnapshot(tx, pk, ids, nil, true)
+	if err != nil {
+		_ = tx.AddError(err)
+		return
+	}
+	logs := make([]AuditLog, 0, len(rows))
+	for _, row := range rows {
+		if changes := auditDiff(nil, row); len(changes) > 0 {
+			logs = append(logs, newAuditLog(tx, AuditActionCreate, auditUint(row[pk.DBName]), changes))
+		}
+	}
+	auditWrite(tx, logs)
+}
+
+func auditAfterUpdate(tx *gorm.DB) {
+	before := auditBeforeRows(tx)
+	if len(before) == 0 {
+		return
+	}
+	pk := tx.Statement.Schema.PrioritizedPrimaryField
+
+	ids := make([]uint, 0, len(before))
+	for _, row := range before {
+		ids = append(ids, auditUint(row[pk.DBName]))
+	}
+	rows, err := auditSnapshot(tx, pk, ids, nil, true)
+	if err != nil {
+		_ = tx.AddError(err)
+		return
+	}
+	after := make(map[uint]map[string]interface{}, len(rows))
+	for _, row := range rows {
+		after[auditUint(row[pk.DBName])] = row
+	}
+
+	logs := make([]AuditLog, 0, len(before))
+	for _, old := range before {
+		id := auditUint(old[pk.DBName])
+		current, ok := after[id]
+		if !ok {
+			continue
+		}
+		if changes := auditDiff(old, current); len(changes) > 0 {
+			logs = append(logs, newAuditLog(tx, AuditActionUpdate, id, changes))
+		}
+	}
+	auditWrite(tx, logs)
+}
+
+func auditAfterDelete(tx *gorm.DB) {
+	before := auditBeforeRows(tx)
+	if len(before) == 0 || tx.RowsAffected == 0 {
+		return
+	}
+	pk := tx.Statement.Schema.PrioritizedPrimaryField
+
+	logs := make([]AuditLog, 0, len(before))
+	for _, old := range before {
+		if changes := auditDiff(old, nil); len(changes) > 0 {
+			logs = append(logs, newAuditLog(tx, AuditActionDelete, auditUint(old[pk.DBName]), changes))
+		}
+	}
+	auditWrite(tx, logs)
+}
+
+// auditDiff, iki satır arasındaki farkı döner; oluşturmada old, silmede current nil verilir.
+// Hassas kolonların değerleri karşılaştırıldıktan sonra maskelenir.
+func auditDiff(old, current map[string]interface{}) map[string]AuditChange {
+	changes := make(map[string]AuditChange)
+	columns := make(map[string]bool, len(old)+len(current))
+	for column := range old {
+		columns[column] = true
+	}
+	for column := range current {
+		columns[column] = true
+	}
+
+	for column := range columns {
+		if auditIgnoredColumns[column] {
+			continue
+		}
+		oldValue, newValue := auditValue(old[column]), auditValue(current[column])
+		if old != nil && current != nil && auditEqual(oldValue, newValue) {
+			continue
+		}
+		if oldValue == nil && newValue == nil {
+			continue
+		}
+		if auditMasked(column) {
+			oldValue, newValue = auditMask(oldValue), auditMask(newValue)
+		}
+		changes[column] = AuditChange{Old: oldValue, New: newValue}
+	}
+	return changes
+}
+
+// auditValue, sürücüden gelen değeri JSON'a yazılabilir hâle getirir.
+func auditValue(v interface{}) interface{} {
+	switch val := v.(type) {
+	case time.Time:
+		return val.UTC()
+	case *time.Time:
+		if val == nil {
+			return nil
+		}
+		return val.UTC()
+	}
+	return v
+}
+
+func auditEqual(a, b interface{}) bool {
+	if at, ok := a.(time.Time); ok {
+		bt, ok := b.(time.Time)
+		return ok && at.Equal(bt)
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+func auditMask(v interface{}) interface{} {
+	if v == nil || v == "" {
+		return v
+	}
+	return AuditMaskedValue
+}
+
+func auditUint(v interface{}) uint {
+	rv := reflect.Indirect(reflect.ValueOf(v))
+	switch rv.Kind() {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return uint(rv.Uint())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if rv.Int() > 0 {
+			return uint(rv.Int())
+		}
+	}
+	return 0
+}
+
+func newAuditLog(tx *gorm.DB, action string, entityID uint, changes map[string]AuditChange) AuditLog {
+	ctx := tx.Statement.Context
+	entry := AuditLog{
+		EntityType: tx.Statement.Schema.Table,
+		EntityID:   entityID,
+		Action:     action,
+	}
+	if b, err := json.Marshal(changes); err == nil {
+		entry.Changes = string(b)
+	} else {
+		entry.Changes = "{}"
+	}
+
+	if cu := currentuser.FromContext(ctx); cu.ID != 0 {
+		actorID := cu.ID
+		entry.ActorID = &actorID
+		entry.ActorEmail = cu.Email
+	}
+	if meta, ok := requestmeta.FromContext(ctx); ok {
+		entry.IP = meta.IP
+		entry.RequestID = meta.RequestID
+	}
+	return entry
+}
+
+// auditWrite, kayıtları değişiklikle aynı bağlantı ve işlem üzerinden yazar.
+func auditWrite(tx *gorm.DB, logs []AuditLog) {
+	if len(logs) == 0 {
+		return
+	}
+	err := tx.Session(&gorm.Session{NewDB: true, SkipHooks: true, SkipDefaultTransaction: true}).Create(&logs).Error
+	if err != nil {
+		_ = tx.AddError(err)
+	}
+}
@@ -0,0 +1,143 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"regexp"
+	"testing"
+	"time"
+
+	"zatrano/pkg/requestmeta"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func TestAuditMasked(t *testing.T) {
+	tests := []struct {
+		column string
+		want   bool
+	}{
+		{"password", true},
+		{"two_factor_secret", true},
+		{"token_hash", true},
+		{"browser_hash", true},
+		{"refresh_token", true},
+		{"email", false},
+		{"name", false},
+		{"two_factor_enabled_at", false},
+	}
+	for _, tt := range tests {
+		if got := auditMasked(tt.column); got != tt.want {
+			t.Errorf("auditMasked(%q) = %v, beklenen %v", tt.column, got, tt.want)
+		}
+	}
+}
+
+func TestAuditDiff(t *testing.T) {
+	t1 := time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC)
+	t2 := t1.Add(time.Hour)
+
+	tests := []struct {
+		name    string
+		old     map[string]interface{}
+		current map[string]interface{}
+		want    map[string]AuditChange
+	}{
+		{
+			name:    "oluşturma",
+			current: map[string]interface{}{"id": 1, "name": "Salon", "password": "hash", "note": nil, "created_at": t1},
+			want: map[string]AuditChange{
+				"name":     {Old: nil, New: "Salon"},
+				"password": {Old: nil, New: AuditMaskedValue},
+			},
+		},
+		{
+			name: "silme",
+			old:  map[string]interface{}{"id": 1, "name": "Salon", "token_hash": "abc", "deleted_at": nil},
+			want: map[string]AuditChange{
+				"name":       {Old: "Salon", New: nil},
+				"token_hash": {Old: AuditMaskedValue, New: nil},
+			},
+		},
+		{
+			name:    "güncelleme",
+			old:     map[string]interface{}{"id": 1, "name": "Salon", "phone": "555", "updated_at": t1},
+			current: map[string]interface{}{"id": 1, "name": "Salon A", "phone": "555", "updated_at": t2},
+			want:    map[string]AuditChange{"name": {Old: "Salon", New: "Salon A"}},
+		},
+		{
+			name:    "maskeli kolon değişti",
+			old:     map[string]interface{}{"password": "old-hash", "two_factor_secret": ""},
+			current: map[string]interface{}{"password": "new-hash", "two_factor_secret": "secret"},
+			want: map[string]AuditChange{
+				"password":          {Old: AuditMaskedValue, New: AuditMaskedValue},
+				"two_factor_secret": {Old: "", New: AuditMaskedValue},
+			},
+		},
+		{
+			name:    "maskeli kolon değişmedi",
+			old:     map[string]interface{}{"password": "hash", "name": "a"},
+			current: map[string]interface{}{"password": "hash", "name": "b"},
+			want:    map[string]AuditChange{"name": {Old: "a", New: "b"}},
+		},
+		{
+			name:    "yalnızca yok sayılan kolonlar",
+			old:     map[string]interface{}{"id": 1, "updated_at": t1, "last_used_at": t1, "last_used_ip": "10.0.0.1", "updated_by": 3},
+			current: map[string]interface{}{"id": 1, "updated_at": t2, "last_used_at": t2, "last_used_ip": "10.0.0.2", "updated_by": 4},
+			want:    map[string]AuditChange{},
+		},
+		{
+			name:    "aynı an farklı saat dilimi",
+			old:     map[string]interface{}{"starts_at": t1},
+			current: map[string]interface{}{"starts_at": t1.In(time.FixedZone("TRT", 3*60*60))},
+			want:    map[string]AuditChange{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := auditDiff(tt.old, tt.current); !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("auditDiff = %#v, beklenen %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+type auditTestItem struct {
+	ID   uint
+	Name string
+}
+
+func TestAuditWriteFailureRollsBackChange(t *testing.T) {
+	conn, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: conn}), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatal(err)
+	}
+	RegisterAuditCallbacks(db)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "audit_test_items"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "audit_test_items" WHERE "audit_test_items"."id" = $1 ORDER BY "audit_test_items"."id"`)).
+		WithArgs(1, auditMaxRows).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "Salon"))
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "audit_logs"`)).
+		WillReturnError(errors.New("audit_logs yazılamadı"))
+	mock.ExpectRollback()
+
+	ctx := requestmeta.WithContext(context.Background(), requestmeta.Meta{RequestID: "req-1", IP: "10.0.0.1"})
+	if err := db.WithContext(ctx).Create(&auditTestItem{Name: "Salon"}).Error; err == nil {
+		t.Fatal("denetim kaydı yazılamadığı hâlde oluşturma başarılı döndü")
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
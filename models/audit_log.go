@@ -0,0 +1,93 @@
+package models
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+	"time"
+)
+
+const (
+	AuditActionCreate = "create"
+	AuditActionUpdate = "update"
+	AuditActionDelete = "delete"
+)
+
+// AuditMaskedValue, hassas kolonların denetim kaydındaki karşılığıdır; değerin değiştiği görülür, kendisi görülmez.
+const AuditMaskedValue = "***"
+
+// AuditLog, bir kaydın kim tarafından, nereden ve nasıl değiştirildiğini tutar. Kayıtlar yalnızca eklenir;
+// güncelleme ve silme veritabanı tetikleyicisiyle engellenir (bkz. migrations.protectAuditLogs).
+type AuditLog struct {
+	ID         uint   `gorm:"primaryKey"`
+	ActorID    *uint  `gorm:"index"`
+	ActorEmail string `gorm:"size:100"`
+	IP         string `gorm:"size:45"`
+	RequestID  string `gorm:"size:64;index"`
+	EntityType string `gorm:"size:100;not null;index:idx_audit_entity"`
+	EntityID   uint   `gorm:"not null;index:idx_audit_entity"`
+	Action     string `gorm:"type:varchar(10);not null;index"`
+	// Changes, {"kolon": {"old": ..., "new": ...}} biçiminde JSON farkıdır.
+	Changes   string    `gorm:"type:jsonb;not null"`
+	CreatedAt time.Time `gorm:"autoCreateTime;index"`
+}
+
+func (AuditLog) TableName() string {
+	return "audit_logs"
+}
+
+// AuditChange, tek bir kolonun eski ve yeni değeridir; oluşturmada Old, silmede New boştur.
+type AuditChange struct {
+	Old interface{} `json:"old"`
+	New interface{} `json:"new"`
+}
+
+// AuditFieldChange, görüntüleme için metne çevrilmiş kolon farkıdır.
+type AuditFieldChange struct {
+	Field  string
+	Old    string
+	New    string
+	HasOld bool
+	HasNew bool
+}
+
+// ChangeList, kolon farklarını kolon adına göre sıralı döner; sayılar olduğu gibi korunur.
+// Değer alıcılıdır; şablonlarda dilim elemanı ya da alt şablona geçirilen kopya üzerinde de çağrılabilir.
+func (a AuditLog) ChangeList() []AuditFieldChange {
+	var changes map[string]AuditChange
+	decoder := json.NewDecoder(bytes.NewReader([]byte(a.Changes)))
+	decoder.UseNumber()
+	if err := decoder.Decode(&changes); err != nil {
+		return nil
+	}
+
+	list := make([]AuditFieldChange, 0, len(changes))
+	for field, change := range changes {
+		list = append(list, AuditFieldChange{
+			Field:  field,
+			Old:    auditDisplayValue(change.Old),
+			New:    auditDisplayValue(change.New),
+			HasOld: change.Old != nil,
+			HasNew: change.New != nil,
+		})
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Field < list[j].Field })
+	return list
+}
+
+func auditDisplayValue(v interface{}) string {
+	switch val := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return val
+	case json.Number:
+		return val.String()
+	default:
+		b, err := json.Marshal(val)
+		if err != nil {
+			return ""
+		}
+		return string(b)
+	}
+}
@@ -1,5 +1,7 @@
 package models
 
+import "strconv"
+
 type Business struct {
 	BaseModel
 
@@ -23,6 +25,10 @@ type Business struct {
 	AddressID uint   `gorm:"index;not null"`
 	Map       string `gorm:"type:text"`
 
+	// Harita bağlantısından çıkarılan konum; yakınlık aramasında kutu filtresi bu indeksi kullanır
+	Latitude  *float64 `gorm:"index:idx_businesses_coordinates"`
+	Longitude *float64 `gorm:"index:idx_businesses_coordinates"`
+
 	Logo   string `gorm:"type:varchar(255)"`
 	Banner string `gorm:"type:varchar(255)"`
 	Video  string `gorm:"type:varchar(255)"`
@@ -35,6 +41,10 @@ type Business struct {
 	Youtube   string `gorm:"type:varchar(255)"`
 	Tiktok    string `gorm:"type:varchar(255)"`
 
+	// Yorumlardan hesaplanan özet; listelemede sıralama için tutulur
+	RatingAverage float64 `gorm:"not null;default:0;index"`
+	RatingCount   uint    `gorm:"not null;default:0"`
+
 	Galleries            []Gallery             `gorm:"foreignKey:BusinessID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
 	Professionals        []Professional        `gorm:"foreignKey:BusinessID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
 	ProfessionalServices []ProfessionalService `gorm:"-"`
@@ -47,3 +57,34 @@ type Business struct {
 func (Business) TableName() string {
 	return "businesses"
 }
+
+// HasCoordinates — konumu bilinen işletmeler yakınlık aramasında listelenir
+func (b Business) HasCoordinates() bool {
+	return b.Latitude != nil && b.Longitude != nil
+}
+
+// LatitudeText ve LongitudeText, şablonlarda işaretçi açmadan koordinat gösterir.
+func (b Business) LatitudeText() string {
+	return formatCoordinate(b.Latitude)
+}
+
+func (b Business) LongitudeText() string {
+	return formatCoordinate(b.Longitude)
+}
+
+// CoverImage, galerideki kapak görselini döner; galeri yüklenmemişse ya da kapak yoksa boştur.
+func (b Business) CoverImage() string {
+	for _, g := range b.Galleries {
+		if g.IsCover {
+			return g.Image
+		}
+	}
+	return ""
+}
+
+func formatCoordinate(v *float64) string {
+	if v == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*v, 'f', -1, 64)
+}
@@ -0,0 +1,15 @@
+package models
+
+// BusinessSlugHistory, işletmenin eski bağlantı adlarını tutar; eski linkler 301 ile yeni adrese yönlenir.
+type BusinessSlugHistory struct {
+	BaseModel
+
+	BusinessID uint   `gorm:"index;not null"`
+	Slug       string `gorm:"type:varchar(150);uniqueIndex;not null"`
+
+	Business *Business `gorm:"foreignKey:BusinessID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+}
+
+func (BusinessSlugHistory) TableName() string {
+	return "business_slug_histories"
+}
@@ -1,5 +1,10 @@
 package models
 
+// Galeri görsel türleri
+const (
+	GalleryTypeGallery = "gallery"
+)
+
 type Gallery struct {
 	BaseModel
 
@@ -8,6 +13,10 @@ type Gallery struct {
 
 	Type string `gorm:"type:varchar(50);not null"` // "logo", "banner", "gallery" gibi
 
+	Position int    `gorm:"not null;default:0;index"` // Sürükle-bırak sırası, küçükten büyüğe
+	Caption  string `gorm:"type:varchar(255)"`        // Görsel altı açıklama
+	IsCover  bool   `gorm:"not null;default:false"`   // İşletme başına en fazla bir kapak görseli
+
 	Business *Business `gorm:"foreignKey:BusinessID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
 }
 
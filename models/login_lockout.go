@@ -0,0 +1,37 @@
+package models
+
+import "time"
+
+const (
+	LockoutScopeAccount = "account"
+	LockoutScopeIP      = "ip"
+)
+
+// LoginLockout, başarısız giriş denemeleri nedeniyle hesabın ya da IP adresinin geçici olarak
+// kilitlendiği olayın denetim kaydıdır. Kilidin kendisi Redis'te tutulur; bu tablo geçmişi ve
+// yöneticinin kilidi açtığı bilgisini saklar.
+type LoginLockout struct {
+	ID      uint   `gorm:"primaryKey"`
+	Scope   string `gorm:"type:varchar(10);not null;index:idx_login_lockout_subject"`
+	Subject string `gorm:"type:varchar(255);not null;index:idx_login_lockout_subject"` // e-posta ya da IP
+	UserID  *uint  `gorm:"index"`
+	IP      string `gorm:"type:varchar(45)"`
+
+	Failures    int       `gorm:"not null"`
+	Level       int       `gorm:"not null"` // art arda kaçıncı kilit (süre her seferinde iki katına çıkar)
+	LockedUntil time.Time `gorm:"not null;index"`
+	UnlockedAt  *time.Time
+	UnlockedBy  *uint
+	CreatedAt   time.Time
+
+	User *User `gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL;"`
+}
+
+func (LoginLockout) TableName() string {
+	return "login_lockouts"
+}
+
+// Active, kilit süresi dolmamış ve yönetici tarafından açılmamışsa true döner.
+func (l LoginLockout) Active(now time.Time) bool {
+	return l.UnlockedAt == nil && l.LockedUntil.After(now)
+}
@@ -0,0 +1,60 @@
+package models
+
+import "time"
+
+// Yetkiler koddan tanımlanır; roller (UserType) bu anahtarları user_type_permissions tablosu üzerinden alır.
+const (
+	PermissionDashboardAccess     = "dashboard.access"
+	PermissionPanelAccess         = "panel.access"
+	PermissionUsersManage         = "users.manage"
+	PermissionUserTypesManage     = "user_types.manage"
+	PermissionReviewsModerate     = "reviews.moderate"
+	PermissionInvitationsModerate = "invitations.moderate"
+	PermissionAuditLogsView       = "audit_logs.view"
+)
+
+type PermissionDefinition struct {
+	Key   string
+	Label string
+	Group string
+}
+
+// PermissionCatalog, kullanıcı türü ekranlarında listelenen yetkilerin tamamıdır.
+var PermissionCatalog = []PermissionDefinition{
+	{Key: PermissionDashboardAccess, Label: "Yönetim paneline erişim", Group: "Yönetim"},
+	{Key: PermissionUsersManage, Label: "Kullanıcıları yönetme", Group: "Yönetim"},
+	{Key: PermissionUserTypesManage, Label: "Kullanıcı türlerini ve yetkilerini yönetme", Group: "Yönetim"},
+	{Key: PermissionAuditLogsView, Label: "Denetim kayıtlarını görüntüleme", Group: "Yönetim"},
+	{Key: PermissionReviewsModerate, Label: "Yorumları onaylama / reddetme", Group: "Moderasyon"},
+	{Key: PermissionInvitationsModerate, Label: "Davetiyeleri denetleme", Group: "Moderasyon"},
+	{Key: PermissionPanelAccess, Label: "İşletme paneline erişim", Group: "İşletme"},
+}
+
+// DefaultRolePermissions, kurulumda oluşturulan rollerin başlangıç yetkileridir.
+var DefaultRolePermissions = map[string][]string{
+	"Admin": {
+		PermissionDashboardAccess, PermissionUsersManage, PermissionUserTypesManage,
+		PermissionReviewsModerate, PermissionInvitationsModerate, PermissionAuditLogsView,
+	},
+	"User": {PermissionPanelAccess},
+}
+
+func IsKnownPermission(key string) bool {
+	for _, p := range PermissionCatalog {
+		if p.Key == key {
+			return true
+		}
+	}
+	return false
+}
+
+type UserTypePermission struct {
+	ID         uint      `gorm:"primaryKey"`
+	UserTypeID uint      `gorm:"not null;uniqueIndex:idx_user_type_permission"`
+	Permission string    `gorm:"size:100;not null;uniqueIndex:idx_user_type_permission"`
+	CreatedAt  time.Time `gorm:"autoCreateTime"`
+}
+
+func (UserTypePermission) TableName() string {
+	return "user_type_permissions"
+}
@@ -0,0 +1,82 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// API kapsamları; kişisel erişim anahtarı yalnızca seçilen kapsamlardaki uç noktaları çağırabilir.
+// Kapsam, sahibinin rol yetkilerini genişletmez; iki kontrol birlikte uygulanır.
+const (
+	TokenScopeBusinessesRead  = "businesses:read"
+	TokenScopeBusinessesWrite = "businesses:write"
+)
+
+type TokenScopeDefinition struct {
+	Key   string
+	Label string
+}
+
+// TokenScopeCatalog, anahtar oluşturma formunda listelenen kapsamların tamamıdır.
+var TokenScopeCatalog = []TokenScopeDefinition{
+	{Key: TokenScopeBusinessesRead, Label: "İşletmeleri görüntüleme"},
+	{Key: TokenScopeBusinessesWrite, Label: "İşletme oluşturma, güncelleme ve silme"},
+}
+
+func IsKnownTokenScope(key string) bool {
+	for _, s := range TokenScopeCatalog {
+		if s.Key == key {
+			return true
+		}
+	}
+	return false
+}
+
+// PersonalAccessToken, kullanıcının kendi sistemlerinden /api/v1 uç noktalarını çağırmak için ürettiği
+// anahtardır. Anahtarın kendisi yalnızca oluşturulduğunda bir kez gösterilir; SHA-256 özeti saklanır.
+type PersonalAccessToken struct {
+	ID        uint   `gorm:"primaryKey"`
+	UserID    uint   `gorm:"not null;index"`
+	Name      string `gorm:"size:100;not null"`
+	TokenHash string `gorm:"size:64;not null;uniqueIndex"`
+	// Prefix, listede anahtarı tanımak için saklanan ilk karakterlerdir
+	Prefix string `gorm:"size:16;not null"`
+	// Scopes, virgülle ayrılmış kapsam listesidir
+	Scopes     string `gorm:"size:255;not null"`
+	ExpiresAt  *time.Time
+	LastUsedAt *time.Time
+	LastUsedIP string `gorm:"size:45"`
+	RevokedAt  *time.Time
+	CreatedAt  time.Time
+
+	User *User `gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+}
+
+func (PersonalAccessToken) TableName() string {
+	return "personal_access_tokens"
+}
+
+func (t *PersonalAccessToken) ScopeList() []string {
+	if t.Scopes == "" {
+		return nil
+	}
+	return strings.Split(t.Scopes, ",")
+}
+
+func (t *PersonalAccessToken) HasScope(scope string) bool {
+	for _, s := range t.ScopeList() {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// Expired, süresi tanımlı ve dolmuş anahtarlar içindir; süresiz anahtarlar iptal edilene kadar geçerlidir.
+func (t *PersonalAccessToken) Expired(now time.Time) bool {
+	return t.ExpiresAt != nil && !now.Before(*t.ExpiresAt)
+}
+
+func (t *PersonalAccessToken) Active(now time.Time) bool {
+	return t.RevokedAt == nil && !t.Expired(now)
+}
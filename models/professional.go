@@ -9,6 +9,13 @@ type Professional struct {
 	Image       string `gorm:"type:varchar(255)"`
 	Description string `gorm:"type:text"`
 
+	// Resmi tatillerde de randevu alınabilir mi
+	WorksOnHolidays bool `gorm:"not null;default:false"`
+
+	// Onaylı yorumlardan hesaplanır; yorum onaylandığında/reddedildiğinde güncellenir
+	RatingAverage float64 `gorm:"not null;default:0"`
+	RatingCount   uint    `gorm:"not null;default:0"`
+
 	// İlişkiler
 	User     *User     `gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
 	Business *Business `gorm:"foreignKey:BusinessID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
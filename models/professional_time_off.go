@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// ProfessionalTimeOff, uzmanın izinli olduğu tek seferlik aralıktır (yıllık izin, rapor vb.).
+type ProfessionalTimeOff struct {
+	BaseModel
+
+	ProfessionalID uint      `gorm:"index:idx_time_offs_professional_range,priority:1;not null"`
+	StartsAt       time.Time `gorm:"index:idx_time_offs_professional_range,priority:2;not null"`
+	EndsAt         time.Time `gorm:"not null"`
+	Reason         string    `gorm:"type:varchar(255)"`
+
+	Professional *Professional `gorm:"foreignKey:ProfessionalID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+}
+
+func (ProfessionalTimeOff) TableName() string {
+	return "professional_time_offs"
+}
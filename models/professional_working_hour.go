@@ -0,0 +1,20 @@
+package models
+
+// ProfessionalWorkingHour, uzmanın haftalık takvimindeki bir aralıktır.
+// IsBreak=true olan satırlar aynı günün çalışma aralığından düşülen molalardır.
+// Saatler işletmenin saat diliminde "15:04" biçiminde tutulur.
+type ProfessionalWorkingHour struct {
+	BaseModel
+
+	ProfessionalID uint   `gorm:"index:idx_working_hours_professional_day,priority:1;not null"`
+	Weekday        int    `gorm:"index:idx_working_hours_professional_day,priority:2;not null"` // 0=Pazar ... 6=Cumartesi
+	StartTime      string `gorm:"type:varchar(5);not null"`
+	EndTime        string `gorm:"type:varchar(5);not null"`
+	IsBreak        bool   `gorm:"not null;default:false"`
+
+	Professional *Professional `gorm:"foreignKey:ProfessionalID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+}
+
+func (ProfessionalWorkingHour) TableName() string {
+	return "professional_working_hours"
+}
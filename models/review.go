@@ -0,0 +1,57 @@
+package models
+
+import (
+	"strings"
+	"time"
+	"unicode"
+)
+
+const (
+	ReviewStatusPending  = "pending"
+	ReviewStatusApproved = "approved"
+	ReviewStatusRejected = "rejected"
+)
+
+// Review, tamamlanmış bir randevuya bağlı müşteri değerlendirmesidir.
+// Her randevu en fazla bir kez değerlendirilebildiği için yorumlar doğrulanmış kabul edilir.
+type Review struct {
+	BaseModel
+
+	BusinessID     uint `gorm:"index;not null"`
+	ProfessionalID uint `gorm:"index;not null"`
+	AppointmentID  uint `gorm:"uniqueIndex;not null"`
+
+	Rating       uint8  `gorm:"not null"`
+	Comment      string `gorm:"type:text"`
+	CustomerName string `gorm:"type:varchar(100);not null"`
+
+	Status         string `gorm:"type:varchar(20);not null;index"`
+	ModeratedBy    *uint  `gorm:"index"`
+	ModeratedAt    *time.Time
+	ModerationNote string `gorm:"type:varchar(255)"`
+
+	// İşletme sahibinin yanıtı
+	Reply     string `gorm:"type:text"`
+	RepliedAt *time.Time
+
+	Business     *Business     `gorm:"foreignKey:BusinessID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	Professional *Professional `gorm:"foreignKey:ProfessionalID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	Appointment  *Appointment  `gorm:"foreignKey:AppointmentID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+}
+
+func (Review) TableName() string {
+	return "reviews"
+}
+
+// DisplayName, herkese açık sayfalarda müşterinin adını soyadının baş harfiyle gösterir (ör. "Ayşe Y.").
+func (r Review) DisplayName() string {
+	parts := strings.Fields(r.CustomerName)
+	switch len(parts) {
+	case 0:
+		return "Müşteri"
+	case 1:
+		return parts[0]
+	}
+	last := []rune(parts[len(parts)-1])
+	return strings.Join(parts[:len(parts)-1], " ") + " " + strings.ToUpperSpecial(unicode.TurkishCase, string(last[0])) + "."
+}
@@ -0,0 +1,32 @@
+package models
+
+import "time"
+
+const (
+	SmsStatusQueued    = "queued"
+	SmsStatusSent      = "sent"
+	SmsStatusDelivered = "delivered"
+	SmsStatusFailed    = "failed"
+)
+
+type SmsMessage struct {
+	BaseModel
+
+	UserID            *uint      `gorm:"index"` // nil: sistem gönderimi (kotaya tabi değil)
+	To                string     `gorm:"type:varchar(20);not null;index"`
+	Body              string     `gorm:"type:text;not null"`
+	Provider          string     `gorm:"type:varchar(30);not null"`
+	ProviderMessageID string     `gorm:"type:varchar(100);index"`
+	Encoding          string     `gorm:"type:varchar(10);not null"` // GSM7, GSM7-TR, UCS2
+	Segments          uint       `gorm:"not null;default:1"`
+	Status            string     `gorm:"type:varchar(20);not null;index"`
+	ErrorMessage      string     `gorm:"type:text"`
+	SentAt            *time.Time `gorm:"index"`
+	DeliveredAt       *time.Time
+
+	User *User `gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL;"`
+}
+
+func (SmsMessage) TableName() string {
+	return "sms_messages"
+}
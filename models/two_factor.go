@@ -0,0 +1,38 @@
+package models
+
+import "time"
+
+// TwoFactorRecoveryCode, doğrulama uygulamasına erişilemediğinde kullanılacak tek kullanımlık kurtarma kodudur.
+// Kodun kendisi saklanmaz; yalnızca SHA-256 özeti tutulur.
+type TwoFactorRecoveryCode struct {
+	ID        uint   `gorm:"primaryKey"`
+	UserID    uint   `gorm:"index;not null"`
+	CodeHash  string `gorm:"size:64;not null;index"`
+	UsedAt    *time.Time
+	CreatedAt time.Time
+
+	User *User `gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+}
+
+func (TwoFactorRecoveryCode) TableName() string {
+	return "two_factor_recovery_codes"
+}
+
+// TrustedDevice, "bu cihaza güven" seçilerek ikinci adımı belirli bir süre atlayan tarayıcıdır.
+// Çereze yazılan rastgele anahtarın yalnızca SHA-256 özeti saklanır.
+type TrustedDevice struct {
+	ID         uint      `gorm:"primaryKey"`
+	UserID     uint      `gorm:"index;not null"`
+	TokenHash  string    `gorm:"size:64;not null;uniqueIndex"`
+	UserAgent  string    `gorm:"size:255"`
+	IP         string    `gorm:"size:45"`
+	ExpiresAt  time.Time `gorm:"not null;index"`
+	LastUsedAt *time.Time
+	CreatedAt  time.Time
+
+	User *User `gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+}
+
+func (TrustedDevice) TableName() string {
+	return "trusted_devices"
+}
@@ -1,27 +1,35 @@
 package models
 
 import (
+	"time"
+
 	"golang.org/x/crypto/bcrypt"
 )
 
 type User struct {
 	BaseModel
-	Name              string `gorm:"size:100;not null;index"`
-	Email             string `gorm:"size:100;unique;not null"`
-	Password          string `gorm:"size:255;not null"`
-	UserTypeID        uint   `gorm:"index"`
-	ResetToken        string `gorm:"size:255;index"`
-	EmailVerified     bool   `gorm:"default:false;index"`
-	VerificationToken string `gorm:"size:255;index"`
-	Provider          string `gorm:"size:50;index"`
-	ProviderID        string `gorm:"size:100;index"`
+	Name          string `gorm:"size:100;not null;index"`
+	Email         string `gorm:"size:100;unique;not null"`
+	Password      string `gorm:"size:255;not null"`
+	UserTypeID    uint   `gorm:"index"`
+	EmailVerified bool   `gorm:"default:false;index"`
+
+	// İki adımlı doğrulama (TOTP). Son kabul edilen zaman adımı, aynı kodun tekrar kullanılmasını engeller.
+	TwoFactorSecret    string `gorm:"size:64" json:"-"`
+	TwoFactorEnabledAt *time.Time
+	TwoFactorLastStep  int64 `gorm:"default:0"`
 
 	UserType UserType `gorm:"foreignKey:UserTypeID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL;"`
 }
 
+// TwoFactorEnabled - Kullanıcı iki adımlı doğrulamayı etkinleştirmiş mi?
+func (u *User) TwoFactorEnabled() bool {
+	return u.TwoFactorEnabledAt != nil && u.TwoFactorSecret != ""
+}
+
 // SetPassword - Kullanıcının şifresini hashler ve set eder
 func (u *User) SetPassword(password string) error {
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(u.Password), bcrypt.DefaultCost)
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
 	if err != nil {
 		return err
 	}
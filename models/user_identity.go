@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+// UserIdentity, kullanıcının bağladığı dış kimlik sağlayıcı hesabıdır (Google, Microsoft, Facebook,
+// Apple ya da genel OIDC). Sağlayıcı içindeki hesap Subject ile tanınır; e-posta yalnızca bilgi amaçlıdır.
+// Bir sağlayıcı hesabı tek kullanıcıya, bir kullanıcı da her sağlayıcıya en fazla bir hesapla bağlanabilir.
+type UserIdentity struct {
+	ID          uint   `gorm:"primaryKey"`
+	UserID      uint   `gorm:"not null;uniqueIndex:idx_user_identity_user_provider"`
+	Provider    string `gorm:"type:varchar(32);not null;uniqueIndex:idx_user_identity_subject;uniqueIndex:idx_user_identity_user_provider"`
+	Subject     string `gorm:"size:255;not null;uniqueIndex:idx_user_identity_subject"`
+	Email       string `gorm:"size:100"`
+	LastLoginAt *time.Time
+	CreatedAt   time.Time
+
+	User *User `gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+}
+
+func (UserIdentity) TableName() string {
+	return "user_identities"
+}
@@ -0,0 +1,36 @@
+package models
+
+import "time"
+
+const (
+	UserTokenPurposePasswordReset     = "password_reset"
+	UserTokenPurposeEmailVerification = "email_verification"
+	UserTokenPurposeMagicLogin        = "magic_login"
+)
+
+// UserToken, e-postayla gönderilen parola sıfırlama, e-posta doğrulama ve giriş bağlantılarının anahtarıdır.
+// Bağlantıdaki anahtar saklanmaz; yalnızca SHA-256 özeti tutulur. Anahtar tek kullanımlıktır ve
+// süresi dolduktan sonra zamanlanmış temizlikte silinir.
+type UserToken struct {
+	ID        uint      `gorm:"primaryKey"`
+	UserID    uint      `gorm:"not null;index:idx_user_token_purpose"`
+	Purpose   string    `gorm:"type:varchar(32);not null;index:idx_user_token_purpose"`
+	TokenHash string    `gorm:"size:64;not null;uniqueIndex"`
+	ExpiresAt time.Time `gorm:"not null;index"`
+	// BrowserHash, bağlantıyı isteyen tarayıcıya yazılan çerezin özetidir; doluysa anahtar yalnızca
+	// aynı çerezi taşıyan tarayıcıda kullanılabilir (iletilen e-postalar işe yaramaz).
+	BrowserHash string `gorm:"size:64;not null;default:''"`
+	// Anahtarı isteyen ve kullanan istemci
+	IP            string `gorm:"size:45"`
+	UserAgent     string `gorm:"size:255"`
+	UsedAt        *time.Time
+	UsedIP        string `gorm:"size:45"`
+	UsedUserAgent string `gorm:"size:255"`
+	CreatedAt     time.Time
+
+	User *User `gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+}
+
+func (UserToken) TableName() string {
+	return "user_tokens"
+}
@@ -2,5 +2,51 @@ package models
 
 type UserType struct {
 	BaseModel
-	Name string `gorm:"size:50;unique;not null;index"`
+	Name        string `gorm:"size:50;unique;not null;index"`
+	LandingPage string `gorm:"size:255"`
+	// RequireTwoFactor, bu roldeki kullanıcıların iki adımlı doğrulamayı etkinleştirmeden sisteme erişememesini sağlar.
+	RequireTwoFactor bool `gorm:"default:false"`
+
+	Permissions []UserTypePermission `gorm:"foreignKey:UserTypeID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+}
+
+// PermissionKeys, rolün yetki anahtarlarını döner. Pasif rollerin hiçbir yetkisi yoktur.
+func (t UserType) PermissionKeys() []string {
+	if !t.IsActive {
+		return nil
+	}
+	keys := make([]string, 0, len(t.Permissions))
+	for _, p := range t.Permissions {
+		keys = append(keys, p.Permission)
+	}
+	return keys
+}
+
+// HasPermission, düzenleme ekranları için rolün yetkiyi tanımlayıp tanımlamadığını (durumdan bağımsız) söyler.
+func (t UserType) HasPermission(key string) bool {
+	for _, p := range t.Permissions {
+		if p.Permission == key {
+			return true
+		}
+	}
+	return false
+}
+
+// LandingPath, girişten sonra ve yetkisiz erişimlerde yönlendirilecek sayfadır.
+// Tanımlı değilse yetkilere göre panel ana sayfası, o da yoksa site ana sayfası seçilir.
+func (t UserType) LandingPath() string {
+	if t.LandingPage != "" {
+		return t.LandingPage
+	}
+	for _, p := range t.PermissionKeys() {
+		if p == PermissionDashboardAccess {
+			return "/dashboard/home"
+		}
+	}
+	for _, p := range t.PermissionKeys() {
+		if p == PermissionPanelAccess {
+			return "/panel/anasayfa"
+		}
+	}
+	return "/"
 }
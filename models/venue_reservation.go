@@ -0,0 +1,88 @@
+package models
+
+import "time"
+
+// Salon seansları; tam gün rezervasyonu sabah ve akşam seansını birlikte kapatır.
+const (
+	VenueSessionMorning = "morning"
+	VenueSessionEvening = "evening"
+	VenueSessionFullDay = "full_day"
+)
+
+const (
+	// VenueReservationStatusOption, süresi dolana kadar tarihi tutan ön rezervasyondur (opsiyon)
+	VenueReservationStatusOption    = "option"
+	VenueReservationStatusConfirmed = "confirmed"
+	VenueReservationStatusCancelled = "cancelled"
+	VenueReservationStatusExpired   = "expired"
+)
+
+// VenueReservation, kapasiteli mekanlar (düğün salonu vb.) için tarih ve seans bazlı rezervasyondur.
+type VenueReservation struct {
+	BaseModel
+
+	BusinessID uint      `gorm:"index:idx_venue_reservations_business_date,priority:1;not null"`
+	Date       time.Time `gorm:"type:date;index:idx_venue_reservations_business_date,priority:2;not null"`
+	Session    string    `gorm:"type:varchar(20);not null"`
+
+	EventType  string `gorm:"type:varchar(50)"`
+	GuestCount uint   `gorm:"not null;default:0"`
+
+	CustomerName  string `gorm:"type:varchar(100);not null"`
+	CustomerPhone string `gorm:"type:varchar(20);not null"`
+	CustomerEmail string `gorm:"type:varchar(100)"`
+	Note          string `gorm:"type:text"`
+
+	TotalPrice    float64 `gorm:"not null;default:0"`
+	DepositAmount float64 `gorm:"not null;default:0"`
+	DepositPaidAt *time.Time
+
+	Status          string `gorm:"type:varchar(20);not null;index"`
+	OptionExpiresAt *time.Time
+	ConfirmedAt     *time.Time
+	CancelReason    string `gorm:"type:varchar(255)"`
+
+	Business *Business `gorm:"foreignKey:BusinessID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+}
+
+func (VenueReservation) TableName() string {
+	return "venue_reservations"
+}
+
+// Blocks, rezervasyonun verilen anda takvimde yer kaplayıp kaplamadığını döner;
+// süresi geçmiş opsiyonlar tarihi artık tutmaz.
+func (r VenueReservation) Blocks(now time.Time) bool {
+	switch r.Status {
+	case VenueReservationStatusConfirmed:
+		return true
+	case VenueReservationStatusOption:
+		return r.OptionExpiresAt != nil && r.OptionExpiresAt.After(now)
+	}
+	return false
+}
+
+// Covers, rezervasyonun verilen seansı kapatıp kapatmadığını döner.
+func (r VenueReservation) Covers(session string) bool {
+	return r.Session == session || r.Session == VenueSessionFullDay || session == VenueSessionFullDay
+}
+
+// RemainingBalance, kapora düşüldükten sonra kalan tutardır.
+func (r VenueReservation) RemainingBalance() float64 {
+	if r.DepositPaidAt == nil {
+		return r.TotalPrice
+	}
+	if left := r.TotalPrice - r.DepositAmount; left > 0 {
+		return left
+	}
+	return 0
+}
+
+var venueSessionLabels = map[string]string{
+	VenueSessionMorning: "Gündüz",
+	VenueSessionEvening: "Akşam",
+	VenueSessionFullDay: "Tam Gün",
+}
+
+func (r VenueReservation) SessionLabel() string {
+	return venueSessionLabels[r.Session]
+}
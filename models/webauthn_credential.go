@@ -0,0 +1,46 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// WebAuthnCredential, kullanıcının kaydettiği geçiş anahtarıdır (passkey). Özel anahtar cihazda kalır;
+// burada yalnızca açık anahtar ve imza sayacı tutulur. Sayaç geriye giderse anahtarın kopyalandığından
+// şüphelenilir ve CloneWarning işaretlenir.
+type WebAuthnCredential struct {
+	ID              uint   `gorm:"primaryKey"`
+	UserID          uint   `gorm:"not null;index"`
+	Name            string `gorm:"size:100;not null"`
+	CredentialID    []byte `gorm:"not null;uniqueIndex"`
+	PublicKey       []byte `gorm:"not null"`
+	AttestationType string `gorm:"size:32"`
+	// Transports, tarayıcının bildirdiği bağlantı türleridir (usb, nfc, ble, internal, hybrid), virgülle ayrılır.
+	Transports     string `gorm:"size:100"`
+	AAGUID         []byte
+	SignCount      uint32 `gorm:"not null;default:0"`
+	BackupEligible bool   `gorm:"not null;default:false"`
+	BackupState    bool   `gorm:"not null;default:false"`
+	CloneWarning   bool   `gorm:"not null;default:false"`
+	LastUsedAt     *time.Time
+	CreatedAt      time.Time
+
+	User *User `gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+}
+
+func (WebAuthnCredential) TableName() string {
+	return "webauthn_credentials"
+}
+
+// TransportList - Kayıtlı bağlantı türlerini liste olarak döner
+func (c *WebAuthnCredential) TransportList() []string {
+	if c.Transports == "" {
+		return nil
+	}
+	return strings.Split(c.Transports, ",")
+}
+
+// Synced - Anahtar bir parola yöneticisi ya da bulut hesabıyla cihazlar arasında eşitleniyor mu?
+func (c *WebAuthnCredential) Synced() bool {
+	return c.BackupEligible && c.BackupState
+}
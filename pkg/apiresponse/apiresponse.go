@@ -0,0 +1,85 @@
+// Package apiresponse, /api altındaki tüm uç noktaların kullandığı JSON zarflarını üretir.
+//
+// Başarılı yanıtlar {"data": ...} (listelerde ayrıca "meta"), hatalar ise
+// {"error": {"code": ..., "message": ..., "fields": {...}}} biçimindedir.
+package apiresponse
+
+import (
+	"zatrano/pkg/queryparams"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Makinece okunacak hata kodları; mesajlar kullanıcıya gösterilmek içindir ve değişebilir.
+const (
+	CodeBadRequest   = "bad_request"
+	CodeValidation   = "validation_failed"
+	CodeUnauthorized = "unauthorized"
+	CodeForbidden    = "forbidden"
+	CodeNotFound     = "not_found"
+	CodeConflict     = "conflict"
+	CodeRateLimited  = "rate_limited"
+	CodeInternal     = "internal_error"
+)
+
+type ErrorBody struct {
+	Code    string            `json:"code"`
+	Message string            `json:"message"`
+	Fields  map[string]string `json:"fields,omitempty"`
+}
+
+type ErrorEnvelope struct {
+	Error ErrorBody `json:"error"`
+}
+
+type DataEnvelope struct {
+	Data interface{} `json:"data"`
+}
+
+type ListEnvelope struct {
+	Data interface{}                `json:"data"`
+	Meta queryparams.PaginationMeta `json:"meta"`
+}
+
+func Data(c *fiber.Ctx, status int, data interface{}) error {
+	return c.Status(status).JSON(DataEnvelope{Data: data})
+}
+
+func List(c *fiber.Ctx, data interface{}, meta queryparams.PaginationMeta) error {
+	return c.Status(fiber.StatusOK).JSON(ListEnvelope{Data: data, Meta: meta})
+}
+
+func Error(c *fiber.Ctx, status int, code, message string) error {
+	return c.Status(status).JSON(ErrorEnvelope{Error: ErrorBody{Code: code, Message: message}})
+}
+
+// ValidationError, alan bazlı doğrulama hatalarını 422 ile döner.
+func ValidationError(c *fiber.Ctx, message string, fields map[string]string) error {
+	return c.Status(fiber.StatusUnprocessableEntity).JSON(ErrorEnvelope{Error: ErrorBody{
+		Code:    CodeValidation,
+		Message: message,
+		Fields:  fields,
+	}})
+}
+
+// CodeForStatus, fiber.Error gibi yalnızca durum kodu bilinen hatalar için zarf kodunu seçer.
+func CodeForStatus(status int) string {
+	switch status {
+	case fiber.StatusBadRequest:
+		return CodeBadRequest
+	case fiber.StatusUnauthorized:
+		return CodeUnauthorized
+	case fiber.StatusForbidden:
+		return CodeForbidden
+	case fiber.StatusNotFound, fiber.StatusMethodNotAllowed:
+		return CodeNotFound
+	case fiber.StatusConflict:
+		return CodeConflict
+	case fiber.StatusUnprocessableEntity:
+		return CodeValidation
+	case fiber.StatusTooManyRequests:
+		return CodeRateLimited
+	default:
+		return CodeInternal
+	}
+}
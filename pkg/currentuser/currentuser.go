@@ -10,16 +10,29 @@ import (
 type contextKey string
 
 const (
-	ContextUserIDKey     contextKey = "user_id"
-	ContextUserEmailKey  contextKey = "user_email"
-	ContextUserTypeIDKey contextKey = "user_type_id"
+	ContextUserIDKey      contextKey = "user_id"
+	ContextUserEmailKey   contextKey = "user_email"
+	ContextUserTypeIDKey  contextKey = "user_type_id"
+	ContextPermissionsKey contextKey = "permissions"
 )
 
 // CurrentUser — context veya locals içinden alınan kullanıcı bilgilerini tutar
 type CurrentUser struct {
-	ID         uint
-	Email      string
-	UserTypeID uint
+	ID          uint
+	Email       string
+	UserTypeID  uint
+	Permissions []string
+	LandingPage string
+}
+
+// Can — kullanıcının rolü verilen yetkiye sahip mi?
+func (u CurrentUser) Can(permission string) bool {
+	for _, p := range u.Permissions {
+		if p == permission {
+			return true
+		}
+	}
+	return false
 }
 
 // FromFiber — Fiber locals içinden CurrentUser oluşturur
@@ -59,6 +72,8 @@ func FromFiber(c *fiber.Ctx) CurrentUser {
 		idField := rv.FieldByName("ID")
 		emailField := rv.FieldByName("Email")
 		userTypeIDField := rv.FieldByName("UserTypeID")
+		permissionsField := rv.FieldByName("Permissions")
+		landingPageField := rv.FieldByName("LandingPage")
 		
 		if idField.IsValid() {
 			var id uint
@@ -92,11 +107,20 @@ func FromFiber(c *fiber.Ctx) CurrentUser {
 				}
 			}
 			
-			return CurrentUser{
+			cu := CurrentUser{
 				ID:         id,
 				Email:      email,
 				UserTypeID: userTypeID,
 			}
+			if permissionsField.IsValid() {
+				if permissions, ok := permissionsField.Interface().([]string); ok {
+					cu.Permissions = permissions
+				}
+			}
+			if landingPageField.IsValid() && landingPageField.Kind() == reflect.String {
+				cu.LandingPage = landingPageField.String()
+			}
+			return cu
 		}
 	}
 	
@@ -108,6 +132,7 @@ func SetToContext(ctx context.Context, user CurrentUser) context.Context {
 	ctx = context.WithValue(ctx, ContextUserIDKey, user.ID)
 	ctx = context.WithValue(ctx, ContextUserEmailKey, user.Email)
 	ctx = context.WithValue(ctx, ContextUserTypeIDKey, user.UserTypeID)
+	ctx = context.WithValue(ctx, ContextPermissionsKey, user.Permissions)
 	return ctx
 }
 
@@ -125,6 +150,9 @@ func FromContext(ctx context.Context) CurrentUser {
 	if v := ctx.Value(ContextUserTypeIDKey); v != nil {
 		cu.UserTypeID = convertToUint(v)
 	}
+	if v, ok := ctx.Value(ContextPermissionsKey).([]string); ok {
+		cu.Permissions = v
+	}
 	return cu
 }
 
@@ -42,7 +42,36 @@ func UploadFile(c *fiber.Ctx, formFieldName, contentType string) (string, error)
 		}
 		return "", err
 	}
+	return SaveFileHeader(c, fileHeader, contentType)
+}
+
+// UploadFiles, aynı alanla gönderilmiş birden çok dosyayı sırayla kaydeder. Bir dosya
+// hatalıysa o ana kadar kaydedilenler silinir ve hata dosya adıyla birlikte döner.
+func UploadFiles(c *fiber.Ctx, formFieldName, contentType string) ([]string, error) {
+	form, err := c.MultipartForm()
+	if err != nil {
+		return nil, ErrFileNotProvided
+	}
+	headers := form.File[formFieldName]
+	if len(headers) == 0 {
+		return nil, ErrFileNotProvided
+	}
+
+	fileNames := make([]string, 0, len(headers))
+	for _, fileHeader := range headers {
+		fileName, err := SaveFileHeader(c, fileHeader, contentType)
+		if err != nil {
+			for _, saved := range fileNames {
+				DeleteFile(contentType, saved)
+			}
+			return nil, fmt.Errorf("%s: %w", fileHeader.Filename, err)
+		}
+		fileNames = append(fileNames, fileName)
+	}
+	return fileNames, nil
+}
 
+func SaveFileHeader(c *fiber.Ctx, fileHeader *multipart.FileHeader, contentType string) (string, error) {
 	if err := validateFile(fileHeader, contentType); err != nil {
 		return "", err
 	}
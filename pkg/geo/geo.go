@@ -0,0 +1,143 @@
+package geo
+
+import (
+	"math"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// EarthRadiusKm — haversine hesabında kullanılan ortalama dünya yarıçapı
+const EarthRadiusKm = 6371.0
+
+// Point — WGS84 enlem/boylam çifti
+type Point struct {
+	Lat float64
+	Lng float64
+}
+
+func (p Point) Valid() bool {
+	return p.Lat >= -90 && p.Lat <= 90 && p.Lng >= -180 && p.Lng <= 180 && !(p.Lat == 0 && p.Lng == 0)
+}
+
+var (
+	iframeSrcPattern = regexp.MustCompile(`(?i)<iframe[^>]+src=["']([^"']+)["']`)
+	// Yer (place) bağlantılarındaki işaretçi: ...!3d41.0082!4d28.9784
+	placePattern = regexp.MustCompile(`!3d(-?\d+(?:\.\d+)?)!4d(-?\d+(?:\.\d+)?)`)
+	// Embed (pb) parametresi boylamı önce yazar: ...!2d28.9784!3d41.0082
+	embedPattern = regexp.MustCompile(`!2d(-?\d+(?:\.\d+)?)!3d(-?\d+(?:\.\d+)?)`)
+	// Harita görünümünün merkezi: /@41.0082,28.9784,15z
+	viewportPattern = regexp.MustCompile(`@(-?\d+(?:\.\d+)?),(-?\d+(?:\.\d+)?)`)
+	pairPattern     = regexp.MustCompile(`^\s*(-?\d+(?:\.\d+)?)\s*,\s*(-?\d+(?:\.\d+)?)\s*$`)
+)
+
+// Parse, yapıştırılmış Google Maps bağlantısından, embed iframe kodundan ya da
+// "41.0082, 28.9784" biçimindeki metinden koordinat çıkarır. İşaretçi konumu
+// harita merkezinden önceliklidir. maps.app.goo.gl gibi kısa linkler ağ isteği
+// gerektirdiğinden çözülmez; bu durumda ok false döner.
+func Parse(raw string) (Point, bool) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return Point{}, false
+	}
+	if m := iframeSrcPattern.FindStringSubmatch(raw); len(m) == 2 {
+		raw = m[1]
+	}
+	if decoded, err := url.PathUnescape(raw); err == nil {
+		raw = decoded
+	}
+
+	if m := placePattern.FindStringSubmatch(raw); len(m) == 3 {
+		if p, ok := point(m[1], m[2]); ok {
+			return p, true
+		}
+	}
+	if m := embedPattern.FindStringSubmatch(raw); len(m) == 3 {
+		if p, ok := point(m[2], m[1]); ok {
+			return p, true
+		}
+	}
+	if u, err := url.Parse(raw); err == nil && u.Host != "" {
+		query := u.Query()
+		for _, key := range []string{"q", "query", "ll", "sll", "center", "destination", "daddr"} {
+			if m := pairPattern.FindStringSubmatch(query.Get(key)); len(m) == 3 {
+				if p, ok := point(m[1], m[2]); ok {
+					return p, true
+				}
+			}
+		}
+	}
+	if m := viewportPattern.FindStringSubmatch(raw); len(m) == 3 {
+		if p, ok := point(m[1], m[2]); ok {
+			return p, true
+		}
+	}
+	if m := pairPattern.FindStringSubmatch(raw); len(m) == 3 {
+		return point(m[1], m[2])
+	}
+	return Point{}, false
+}
+
+func point(latText, lngText string) (Point, bool) {
+	lat, err := strconv.ParseFloat(latText, 64)
+	if err != nil {
+		return Point{}, false
+	}
+	lng, err := strconv.ParseFloat(lngText, 64)
+	if err != nil {
+		return Point{}, false
+	}
+	p := Point{Lat: lat, Lng: lng}
+	return p, p.Valid()
+}
+
+// Distance, iki nokta arasındaki büyük daire mesafesini km olarak döner (haversine).
+func Distance(a, b Point) float64 {
+	dLat := radians(b.Lat - a.Lat)
+	dLng := radians(b.Lng - a.Lng)
+	h := math.Pow(math.Sin(dLat/2), 2) +
+		math.Cos(radians(a.Lat))*math.Cos(radians(b.Lat))*math.Pow(math.Sin(dLng/2), 2)
+	return 2 * EarthRadiusKm * math.Asin(math.Min(1, math.Sqrt(h)))
+}
+
+// Box — enlem/boylam sınırları; indeksli ön eleme için kullanılır
+type Box struct {
+	MinLat, MaxLat float64
+	MinLng, MaxLng float64
+}
+
+// BoundingBox, merkezden radiusKm uzaklıktaki tüm noktaları kapsayan kutuyu döner.
+// Kutu daireden büyüktür; kesin eleme haversine ile yapılır.
+func BoundingBox(center Point, radiusKm float64) Box {
+	dLat := radiusKm / EarthRadiusKm * 180 / math.Pi
+	box := Box{
+		MinLat: math.Max(center.Lat-dLat, -90),
+		MaxLat: math.Min(center.Lat+dLat, 90),
+		MinLng: -180,
+		MaxLng: 180,
+	}
+	// Kutuplara yakın enlemlerde boylam farkı anlamsızlaşır; tüm boylamlar alınır
+	if cos := math.Cos(radians(center.Lat)); cos > 0.01 {
+		dLng := dLat / cos
+		if center.Lng-dLng >= -180 && center.Lng+dLng <= 180 {
+			box.MinLng = center.Lng - dLng
+			box.MaxLng = center.Lng + dLng
+		}
+	}
+	return box
+}
+
+// DistanceSQL, verilen sütunlar ile merkez arasındaki km mesafesini hesaplayan
+// PostgreSQL ifadesini ve parametrelerini döner. PostGIS gerektirmez.
+func DistanceSQL(latColumn, lngColumn string, center Point) (string, []interface{}) {
+	sql := "(2 * " + strconv.FormatFloat(EarthRadiusKm, 'f', -1, 64) + " * asin(least(1, sqrt(" +
+		"power(sin(radians(" + latColumn + " - ?) / 2), 2) + " +
+		"cos(radians(?)) * cos(radians(" + latColumn + ")) * " +
+		"power(sin(radians(" + lngColumn + " - ?) / 2), 2)))))"
+	return sql, []interface{}{center.Lat, center.Lat, center.Lng}
+}
+
+func radians(deg float64) float64 {
+	return deg * math.Pi / 180
+}
@@ -0,0 +1,169 @@
+// Package iplocation, IP adreslerini gömülü aralık veritabanlarından ülke/şehir bilgisine çevirir.
+//
+// Aralık verisinin biçimi DB-IP "IP to City Lite" CSV dosyasıyla aynıdır (CC BY 4.0, https://db-ip.com/db/lite.php):
+//
+//	ip_start,ip_end,continent,country,stateprov,city[,latitude,longitude]
+//
+// Depodaki data/ip_ranges.csv yalnızca ayrılmış (yerel/özel) blokları içerir; şehir düzeyinde konum için
+// IP_LOCATION_DB ile çalışma zamanında DB-IP City Lite CSV'si yüklenebilir (LoadFile).
+//
+// Aralıklarda bulunmayan genel adresler için ülke, github.com/phuslu/iploc paketine gömülü
+// IP2Location LITE DB1 verisinden çözülür. Bu veri CC BY-SA 4.0 lisanslıdır ve konumun gösterildiği
+// yerde "This site or product includes IP2Location LITE data available from https://lite.ip2location.com"
+// atfı bulunmalıdır; veri, paket sürümü yükseltilerek (go get -u github.com/phuslu/iploc) güncellenir.
+package iplocation
+
+import (
+	"bufio"
+	_ "embed"
+	"encoding/csv"
+	"errors"
+	"io"
+	"net/netip"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/phuslu/iploc"
+)
+
+// LocalCountry, ayrılmış bloklar için kullanılan ülke kodudur.
+const LocalCountry = "ZZ"
+
+//go:embed data/ip_ranges.csv
+var embedded string
+
+type Location struct {
+	CountryCode string
+	Region      string
+	City        string
+}
+
+// String, oturum listesinde gösterilecek kısa adı üretir (ör. "Kadıköy, İstanbul, TR").
+func (l Location) String() string {
+	if l.CountryCode == LocalCountry {
+		return l.City
+	}
+	parts := make([]string, 0, 3)
+	for _, p := range []string{l.City, l.Region, l.CountryCode} {
+		if p != "" && (len(parts) == 0 || parts[len(parts)-1] != p) {
+			parts = append(parts, p)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+type ipRange struct {
+	start, end netip.Addr
+	loc        Location
+}
+
+// DB, başlangıç adresine göre sıralı aralık listesidir.
+type DB struct {
+	ranges []ipRange
+}
+
+var (
+	defaultOnce sync.Once
+	defaultDB   *DB
+)
+
+// Default, gömülü veritabanını ilk kullanımda yükler.
+func Default() *DB {
+	defaultOnce.Do(func() {
+		db, err := Load(strings.NewReader(embedded))
+		if err != nil {
+			db = &DB{}
+		}
+		defaultDB = db
+	})
+	return defaultDB
+}
+
+// LoadFile, harici bir CSV dosyasını yükler.
+func LoadFile(path string) (*DB, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return Load(bufio.NewReader(f))
+}
+
+// Load, CSV biçimindeki aralıkları okur. Boş ve # ile başlayan satırlar atlanır.
+func Load(r io.Reader) (*DB, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.Comment = '#'
+
+	db := &DB{}
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(record) < 6 {
+			continue
+		}
+		start, err1 := netip.ParseAddr(strings.TrimSpace(record[0]))
+		end, err2 := netip.ParseAddr(strings.TrimSpace(record[1]))
+		if err1 != nil || err2 != nil || start.Is4() != end.Is4() || end.Less(start) {
+			continue
+		}
+		db.ranges = append(db.ranges, ipRange{
+			start: start,
+			end:   end,
+			loc: Location{
+				CountryCode: strings.TrimSpace(record[3]),
+				Region:      strings.TrimSpace(record[4]),
+				City:        strings.TrimSpace(record[5]),
+			},
+		})
+	}
+
+	sort.SliceStable(db.ranges, func(i, j int) bool { return db.ranges[i].start.Less(db.ranges[j].start) })
+	return db, nil
+}
+
+// Len, yüklü aralık sayısıdır.
+func (db *DB) Len() int {
+	return len(db.ranges)
+}
+
+// Lookup, adresi içeren aralığın konumunu döner; aralıklarda yoksa gömülü ülke verisine bakar.
+func (db *DB) Lookup(ip string) (Location, bool) {
+	addr, err := netip.ParseAddr(strings.TrimSpace(ip))
+	if err != nil {
+		return Location{}, false
+	}
+	addr = addr.Unmap()
+
+	if loc, ok := db.lookupRange(addr); ok {
+		return loc, true
+	}
+	if country := iploc.IPCountry(addr); country != "" && country != LocalCountry {
+		return Location{CountryCode: country}, true
+	}
+	return Location{}, false
+}
+
+func (db *DB) lookupRange(addr netip.Addr) (Location, bool) {
+	// start <= addr olan son aralık
+	i := sort.Search(len(db.ranges), func(i int) bool { return addr.Less(db.ranges[i].start) }) - 1
+	for ; i >= 0; i-- {
+		r := db.ranges[i]
+		if r.start.Is4() != addr.Is4() {
+			continue
+		}
+		if !r.end.Less(addr) {
+			return r.loc, true
+		}
+		// İç içe olmayan aralıklarda daha geriye bakmaya gerek yok
+		break
+	}
+	return Location{}, false
+}
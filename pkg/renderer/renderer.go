@@ -44,6 +44,8 @@ func prepareRenderData(c *fiber.Ctx, data fiber.Map) fiber.Map {
 	currentUser := currentuser.FromFiber(c)
 	if currentUser.ID != 0 {
 		renderData["User"] = currentUser
+		// Handler'lar "User" anahtarını düzenlenen kullanıcıyla ezebildiğinden layout'lar bunu kullanır
+		renderData["CurrentUser"] = currentUser
 	}
 
 	var handlerError string
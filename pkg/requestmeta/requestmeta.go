@@ -0,0 +1,32 @@
+// Package requestmeta, isteğin kimliğini ve IP adresini context üzerinden veri katmanına taşır;
+// denetim kayıtları bu bilgiyle kimin, nereden, hangi istekle değişiklik yaptığını yazar.
+package requestmeta
+
+import "context"
+
+type contextKey string
+
+const metaKey contextKey = "request_meta"
+
+// HeaderRequestID, istek kimliğinin okunduğu ve yanıta yazıldığı başlıktır.
+const HeaderRequestID = "X-Request-ID"
+
+type Meta struct {
+	RequestID string
+	IP        string
+	UserAgent string
+}
+
+// WithContext — Meta bilgisini context içine koyar
+func WithContext(ctx context.Context, meta Meta) context.Context {
+	return context.WithValue(ctx, metaKey, meta)
+}
+
+// FromContext — context içinden Meta okur; istek dışındaki işlerde (zamanlanmış görevler, seed) ok false döner.
+func FromContext(ctx context.Context) (Meta, bool) {
+	if ctx == nil {
+		return Meta{}, false
+	}
+	meta, ok := ctx.Value(metaKey).(Meta)
+	return meta, ok
+}
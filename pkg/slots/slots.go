@@ -0,0 +1,87 @@
+package slots
+
+import (
+	"sort"
+	"time"
+)
+
+// Interval — [Start, End) aralığı
+type Interval struct {
+	Start time.Time
+	End   time.Time
+}
+
+func (i Interval) Overlaps(o Interval) bool {
+	return i.Start.Before(o.End) && o.Start.Before(i.End)
+}
+
+// Free, çalışma pencereleri içinde dolu aralıklarla çakışmayan başlangıç saatlerini döner.
+// Başlangıçlar her pencerenin başından itibaren step aralıklarla denenir;
+// notBefore'dan önce başlayan slotlar elenir (aynı gün için minimum bildirim süresi).
+func Free(windows, busy []Interval, duration, step time.Duration, notBefore time.Time) []time.Time {
+	if duration <= 0 || step <= 0 {
+		return nil
+	}
+
+	sorted := make([]Interval, len(busy))
+	copy(sorted, busy)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Start.Before(sorted[j].Start) })
+
+	var result []time.Time
+	for _, w := range windows {
+		for start := w.Start; !start.Add(duration).After(w.End); start = start.Add(step) {
+			if start.Before(notBefore) {
+				continue
+			}
+			candidate := Interval{Start: start, End: start.Add(duration)}
+			if !overlapsAny(candidate, sorted) {
+				result = append(result, start)
+			}
+		}
+	}
+	return result
+}
+
+// Subtract, pencerelerden verilen aralıkları (mola, izin vb.) çıkarır.
+func Subtract(windows, cuts []Interval) []Interval {
+	result := windows
+	for _, cut := range cuts {
+		var next []Interval
+		for _, w := range result {
+			if !w.Overlaps(cut) {
+				next = append(next, w)
+				continue
+			}
+			if w.Start.Before(cut.Start) {
+				next = append(next, Interval{Start: w.Start, End: cut.Start})
+			}
+			if cut.End.Before(w.End) {
+				next = append(next, Interval{Start: cut.End, End: w.End})
+			}
+		}
+		result = next
+	}
+	return result
+}
+
+// Contains, aralığın pencerelerden birinin içinde kaldığını kontrol eder.
+func Contains(windows []Interval, i Interval) bool {
+	for _, w := range windows {
+		if !i.Start.Before(w.Start) && !i.End.After(w.End) {
+			return true
+		}
+	}
+	return false
+}
+
+func overlapsAny(candidate Interval, busy []Interval) bool {
+	for _, b := range busy {
+		if !b.Start.Before(candidate.End) {
+			return false
+		}
+		if candidate.Overlaps(b) {
+			return true
+		}
+	}
+	return false
+}
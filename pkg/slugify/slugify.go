@@ -0,0 +1,37 @@
+package slugify
+
+import "strings"
+
+var turkishReplacer = strings.NewReplacer(
+	"ç", "c", "Ç", "c",
+	"ğ", "g", "Ğ", "g",
+	"ı", "i", "I", "i", "İ", "i",
+	"ö", "o", "Ö", "o",
+	"ş", "s", "Ş", "s",
+	"ü", "u", "Ü", "u",
+	"â", "a", "Â", "a",
+	"î", "i", "Î", "i",
+	"û", "u", "Û", "u",
+	"&", " ve ",
+)
+
+// Make, metni Türkçe karakterleri dönüştürerek URL'de kullanılabilir hâle getirir.
+// Örn: "Güneş Düğün Salonu & Bahçe" -> "gunes-dugun-salonu-ve-bahce"
+func Make(text string) string {
+	text = turkishReplacer.Replace(text)
+
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(text) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+			dash = false
+			continue
+		}
+		if !dash && b.Len() > 0 {
+			b.WriteByte('-')
+			dash = true
+		}
+	}
+	return strings.Trim(b.String(), "-")
+}
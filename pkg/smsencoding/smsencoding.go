@@ -0,0 +1,95 @@
+package smsencoding
+
+const (
+	EncodingGSM7   = "GSM7"
+	EncodingGSM7TR = "GSM7-TR" // GSM 03.38 + Türkçe single shift tablosu
+	EncodingUCS2   = "UCS2"
+)
+
+// Segment başına kapasiteler (UDH başlıkları düşülmüş hâlleri)
+const (
+	gsm7Single   = 160
+	gsm7Multi    = 153
+	gsm7TRSingle = 155 // single shift IE (3 oktet) eklenir
+	gsm7TRMulti  = 149 // concat (5) + single shift (3) IE
+	ucs2Single   = 70
+	ucs2Multi    = 67
+)
+
+// GSM 03.38 temel karakter seti
+var gsm7Basic = runeSet("@£$¥èéùìòÇ\nØø\rÅåΔ_ΦΓΛΩΠΨΣΘΞÆæßÉ !\"#¤%&'()*+,-./0123456789:;<=>?" +
+	"¡ABCDEFGHIJKLMNOPQRSTUVWXYZÄÖÑÜ§¿abcdefghijklmnopqrstuvwxyzäöñüà")
+
+// GSM 03.38 genişletme tablosu (ESC ile, 2 septet)
+var gsm7Extension = runeSet("\f^{}\\[~]|€")
+
+// 3GPP TS 23.038 Türkçe single shift tablosu (ESC ile, 2 septet)
+var gsm7TurkishShift = runeSet("\f^{}\\[~]|ĞİŞçğış€")
+
+// Info, bir mesajın kodlama ve segment bilgisini taşır.
+type Info struct {
+	Encoding   string
+	Units      int // GSM7 için septet, UCS2 için 16-bit birim sayısı
+	Segments   int
+	PerSegment int
+}
+
+// Analyze, mesajı gönderilebilecek en ucuz kodlamaya göre değerlendirir.
+// allowTurkishShift false ise ğ, ş, ı gibi karakterler mesajı UCS-2'ye taşır.
+func Analyze(text string, allowTurkishShift bool) Info {
+	if units, ok := countGSM7(text, gsm7Extension); ok {
+		return build(EncodingGSM7, units, gsm7Single, gsm7Multi)
+	}
+	if allowTurkishShift {
+		if units, ok := countGSM7(text, gsm7TurkishShift); ok {
+			return build(EncodingGSM7TR, units, gsm7TRSingle, gsm7TRMulti)
+		}
+	}
+	return build(EncodingUCS2, countUCS2(text), ucs2Single, ucs2Multi)
+}
+
+func build(encoding string, units, single, multi int) Info {
+	info := Info{Encoding: encoding, Units: units, Segments: 1, PerSegment: single}
+	if units > single {
+		info.PerSegment = multi
+		info.Segments = (units + multi - 1) / multi
+	}
+	return info
+}
+
+func countGSM7(text string, extension map[rune]struct{}) (int, bool) {
+	units := 0
+	for _, r := range text {
+		if _, ok := gsm7Basic[r]; ok {
+			units++
+			continue
+		}
+		if _, ok := extension[r]; ok {
+			units += 2
+			continue
+		}
+		return 0, false
+	}
+	return units, true
+}
+
+// UCS-2 BMP dışındaki karakterler (emoji vb.) iki birim kaplar
+func countUCS2(text string) int {
+	units := 0
+	for _, r := range text {
+		if r > 0xFFFF {
+			units += 2
+		} else {
+			units++
+		}
+	}
+	return units
+}
+
+func runeSet(chars string) map[rune]struct{} {
+	set := make(map[rune]struct{}, len(chars))
+	for _, r := range chars {
+		set[r] = struct{}{}
+	}
+	return set
+}
@@ -82,6 +82,14 @@ func TemplateHelpers() template.FuncMap {
 			}
 			return a == *b
 		},
+
+		// can, arayüz öğelerini yetkiye göre gizlemek için kullanılır: {{if can .User "users.manage"}}
+		"can": func(user interface{}, permission string) bool {
+			if u, ok := user.(interface{ Can(string) bool }); ok {
+				return u.Can(permission)
+			}
+			return false
+		},
 	}
 	return fm
 }
@@ -0,0 +1,158 @@
+// Package trholidays, Türkiye'deki resmi tatilleri (2429 sayılı kanun) verilen yıl için üretir.
+//
+// Sabit tarihli bayramlar hesaplanır. Ramazan ve Kurban bayramları Hicri takvime bağlı olduğundan
+// Diyanet'in ilan ettiği ilk gün tarihleri religiousStarts tablosunda tutulur; tabloda olmayan
+// yıllar için tabular Hicri takvimden tahmin üretilir (±1 gün), Estimated=true işaretlenir ve
+// her yıl için bir kez uyarı loglanır. Diyanet yeni yılların takvimini ilan ettikçe tablo güncellenmelidir.
+package trholidays
+
+import (
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"zatrano/configs/logconfig"
+
+	"go.uber.org/zap"
+)
+
+// HalfDayFrom, arife ve 28 Ekim gibi yarım günlerde tatilin başladığı saattir.
+const HalfDayFrom = 13
+
+type Holiday struct {
+	Date      time.Time // loc saat diliminde günün başlangıcı
+	Name      string
+	HalfDay   bool // true ise 13:00'ten sonrası tatildir
+	Estimated bool
+}
+
+// Off, tatilin o gün kapladığı aralığı döner.
+func (h Holiday) Off() (time.Time, time.Time) {
+	end := h.Date.AddDate(0, 0, 1)
+	if h.HalfDay {
+		return h.Date.Add(HalfDayFrom * time.Hour), end
+	}
+	return h.Date, end
+}
+
+// religiousStarts, Diyanet takvimine göre bayramların ilk günleridir: {Ramazan, Kurban}.
+var religiousStarts = map[int][2]string{
+	2024: {"2024-04-10", "2024-06-16"},
+	2025: {"2025-03-30", "2025-06-06"},
+	2026: {"2026-03-20", "2026-05-27"},
+	2027: {"2027-03-09", "2027-05-16"},
+	2028: {"2028-02-26", "2028-05-05"},
+	2029: {"2029-02-14", "2029-04-24"},
+	2030: {"2030-02-04", "2030-04-13"},
+}
+
+// estimatedYears, tahmin uyarısı loglanmış yılları tutar.
+var estimatedYears sync.Map
+
+type fixedHoliday struct {
+	month   time.Month
+	day     int
+	name    string
+	halfDay bool
+}
+
+var fixedHolidays = []fixedHoliday{
+	{time.January, 1, "Yılbaşı", false},
+	{time.April, 23, "Ulusal Egemenlik ve Çocuk Bayramı", false},
+	{time.May, 1, "Emek ve Dayanışma Günü", false},
+	{time.May, 19, "Atatürk'ü Anma, Gençlik ve Spor Bayramı", false},
+	{time.July, 15, "Demokrasi ve Milli Birlik Günü", false},
+	{time.August, 30, "Zafer Bayramı", false},
+	{time.October, 28, "Cumhuriyet Bayramı Arifesi", true},
+	{time.October, 29, "Cumhuriyet Bayramı", false},
+}
+
+// ForYear, yılın resmi tatillerini tarih sırasıyla döner.
+func ForYear(year int, loc *time.Location) []Holiday {
+	var holidays []Holiday
+	for _, f := range fixedHolidays {
+		holidays = append(holidays, Holiday{
+			Date:    time.Date(year, f.month, f.day, 0, 0, 0, 0, loc),
+			Name:    f.name,
+			HalfDay: f.halfDay,
+		})
+	}
+
+	ramazan, kurban, estimated := religiousStartDates(year, loc)
+	for _, r := range ramazan {
+		holidays = append(holidays, bayram(r, "Ramazan Bayramı", 3, estimated)...)
+	}
+	for _, k := range kurban {
+		holidays = append(holidays, bayram(k, "Kurban Bayramı", 4, estimated)...)
+	}
+
+	// Arife önceki yıla düşebilir (ör. 1 Ocak bayramı); yalnızca bu yılın günleri kalsın
+	filtered := holidays[:0]
+	for _, h := range holidays {
+		if h.Date.Year() == year {
+			filtered = append(filtered, h)
+		}
+	}
+	sort.SliceStable(filtered, func(i, j int) bool { return filtered[i].Date.Before(filtered[j].Date) })
+	return filtered
+}
+
+// Between, [from, to) aralığına düşen tatilleri "2006-01-02" anahtarıyla döner.
+// Aynı güne denk gelen iki tatil varsa tam gün olan öncelik alır.
+func Between(from, to time.Time, loc *time.Location) map[string]Holiday {
+	result := make(map[string]Holiday)
+	for year := from.In(loc).Year(); year <= to.In(loc).Year(); year++ {
+		for _, h := range ForYear(year, loc) {
+			if h.Date.Before(from) || !h.Date.Before(to) {
+				continue
+			}
+			key := h.Date.Format("2006-01-02")
+			if existing, ok := result[key]; ok && !existing.HalfDay {
+				continue
+			}
+			result[key] = h
+		}
+	}
+	return result
+}
+
+func bayram(first time.Time, name string, days int, estimated bool) []Holiday {
+	items := []Holiday{{Date: first.AddDate(0, 0, -1), Name: name + " Arifesi", HalfDay: true, Estimated: estimated}}
+	for i := 0; i < days; i++ {
+		items = append(items, Holiday{Date: first.AddDate(0, 0, i), Name: name, Estimated: estimated})
+	}
+	return items
+}
+
+// religiousStartDates, bir Gregoryen yılda başlayan bayramların ilk günlerini döner.
+// Hicri yıl Gregoryen yıldan ~11 gün kısa olduğundan aynı bayram bir yılda iki kez görülebilir.
+func religiousStartDates(year int, loc *time.Location) (ramazan, kurban []time.Time, estimated bool) {
+	if known, ok := religiousStarts[year]; ok {
+		r, _ := time.ParseInLocation("2006-01-02", known[0], loc)
+		k, _ := time.ParseInLocation("2006-01-02", known[1], loc)
+		return []time.Time{r}, []time.Time{k}, false
+	}
+	if _, logged := estimatedYears.LoadOrStore(year, true); !logged && logconfig.Log != nil {
+		logconfig.Log.Warn("Dini bayram tarihleri tablo dışında, Hicri takvimden tahmin ediliyor (±1 gün)", zap.Int("year", year))
+	}
+
+	approx := int(float64(year-622) * 33 / 32)
+	for hy := approx - 1; hy <= approx+2; hy++ {
+		if r := hijriToDate(hy, 10, 1, loc); r.Year() == year {
+			ramazan = append(ramazan, r)
+		}
+		if k := hijriToDate(hy, 12, 10, loc); k.Year() == year {
+			kurban = append(kurban, k)
+		}
+	}
+	return ramazan, kurban, true
+}
+
+// hijriToDate, tabular (aritmetik) Hicri takvimle tarihi Gregoryen güne çevirir.
+func hijriToDate(year, month, day int, loc *time.Location) time.Time {
+	jd := float64(day) + math.Ceil(29.5*float64(month-1)) + float64((year-1)*354) +
+		math.Floor(float64(3+11*year)/30) + 1948439.5 - 1
+	t := time.Unix(int64((jd-2440587.5)*86400), 0).UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc)
+}
@@ -0,0 +1,117 @@
+// Package trvalidators, Türkiye'ye özgü kimlik ve işletme numaralarını doğrular.
+//
+// TCKN ve VKN için Gelir İdaresi/Nüfus Müdürlüğü'nün kontrol hanesi algoritmaları, TR IBAN için
+// ISO 13616 mod-97 kontrolü uygulanır. MERSIS numarasının yayımlanmış bir kontrol hanesi
+// olmadığından yalnızca biçimi (16 hane) denetlenir.
+package trvalidators
+
+import (
+	"strings"
+	"unicode"
+)
+
+// ibanLength, TR IBAN uzunluğudur: TR + 2 kontrol + 5 banka kodu + 1 rezerv + 16 hesap numarası.
+const ibanLength = 26
+
+func digitsOf(s string, n int) ([]int, bool) {
+	if len(s) != n {
+		return nil, false
+	}
+	d := make([]int, n)
+	for i, r := range s {
+		if r < '0' || r > '9' {
+			return nil, false
+		}
+		d[i] = int(r - '0')
+	}
+	return d, true
+}
+
+// TCKN, 11 haneli T.C. kimlik numarasının kontrol hanelerini doğrular.
+func TCKN(s string) bool {
+	d, ok := digitsOf(s, 11)
+	if !ok || d[0] == 0 {
+		return false
+	}
+	odd := d[0] + d[2] + d[4] + d[6] + d[8]
+	even := d[1] + d[3] + d[5] + d[7]
+	if ((odd*7-even)%10+10)%10 != d[9] {
+		return false
+	}
+	sum := 0
+	for _, v := range d[:10] {
+		sum += v
+	}
+	return sum%10 == d[10]
+}
+
+// VKN, 10 haneli vergi kimlik numarasının kontrol hanesini doğrular.
+func VKN(s string) bool {
+	d, ok := digitsOf(s, 10)
+	if !ok {
+		return false
+	}
+	sum := 0
+	for i := 0; i < 9; i++ {
+		tmp := (d[i] + 9 - i) % 10
+		if tmp == 9 {
+			sum += 9
+			continue
+		}
+		sum += (tmp << (9 - i)) % 9
+	}
+	return (10-sum%10)%10 == d[9]
+}
+
+// IBAN, boşluksuz ve büyük harfli TR IBAN'ın uzunluğunu ve mod-97 kontrolünü doğrular.
+func IBAN(s string) bool {
+	if len(s) != ibanLength || !strings.HasPrefix(s, "TR") {
+		return false
+	}
+	if _, ok := digitsOf(s[2:], ibanLength-2); !ok {
+		return false
+	}
+	// İlk dört karakter sona alınır, harfler A=10 ... Z=35 olarak sayıya çevrilir.
+	rearranged := s[4:] + s[:4]
+	rem := 0
+	for _, r := range rearranged {
+		if unicode.IsLetter(r) {
+			v := int(r-'A') + 10
+			rem = (rem*100 + v) % 97
+			continue
+		}
+		rem = (rem*10 + int(r-'0')) % 97
+	}
+	return rem == 1
+}
+
+// MERSIS, 16 haneli MERSIS numarasının biçimini doğrular.
+func MERSIS(s string) bool {
+	_, ok := digitsOf(s, 16)
+	return ok
+}
+
+// NormalizeMobile, Türkiye cep numarasını E.164 (+905xxxxxxxxx) biçimine getirir.
+// 05xx, 5xx, 905xx, +90 5xx ve 0090 5xx yazımları kabul edilir; boşluk, tire ve parantezler yok sayılır.
+func NormalizeMobile(raw string) (string, bool) {
+	var digits strings.Builder
+	for _, r := range raw {
+		switch {
+		case r >= '0' && r <= '9':
+			digits.WriteRune(r)
+		case r == '+' || r == ' ' || r == '-' || r == '(' || r == ')' || r == '.':
+		default:
+			return "", false
+		}
+	}
+	d := strings.TrimPrefix(digits.String(), "00")
+	switch {
+	case len(d) == 10 && d[0] == '5':
+		return "+90" + d, true
+	case len(d) == 11 && strings.HasPrefix(d, "05"):
+		return "+9" + d, true
+	case len(d) == 12 && strings.HasPrefix(d, "905"):
+		return "+" + d, true
+	}
+	return "", false
+}
@@ -32,12 +32,26 @@ func MatchNormalized(text, keyword string) bool {
 	return strings.Contains(normText, normKeyword)
 }
 
+// SQLFilter, GORM Where'e verilecek "içerir" koşulunu üretir. Arama Go tarafında, sütun ise
+// veritabanında unaccent ile sadeleştirildiği için "Çiçekçi" araması "cicekci" ile de eşleşir.
+// unaccent eklentisi migrasyonda kurulur.
 func SQLFilter(columnName, search string) (string, []interface{}) {
-	filterValue := "%" + strings.ToLower(search) + "%"
+	return sqlPattern(columnName, "%"+escapeLike(normalize(search))+"%")
+}
+
+// SQLPrefixFilter, SQLFilter'ın "ile başlar" karşılığıdır; alaka sıralamasında kullanılır.
+func SQLPrefixFilter(columnName, search string) (string, []interface{}) {
+	return sqlPattern(columnName, escapeLike(normalize(search))+"%")
+}
 
-	query := "unaccent(lower(" + columnName + ")) ILIKE unaccent($1)"
+func sqlPattern(columnName, pattern string) (string, []interface{}) {
+	query := "lower(unaccent(" + columnName + ")) LIKE ?"
 
-	params := []interface{}{filterValue}
+	params := []interface{}{pattern}
 
 	return query, params
 }
+
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
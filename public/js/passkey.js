@@ -0,0 +1,142 @@
+// ===================== PASSKEY (WEBAUTHN) =====================
+// Sunucu seçenekleri JSON olarak döner; ikili alanlar base64url kodludur ve tarayıcı API'si
+// ArrayBuffer beklediği için iki yönde de dönüştürülür.
+
+(function() {
+    function toBuffer(value) {
+        const base64 = value.replace(/-/g, '+').replace(/_/g, '/');
+        const padded = base64 + '='.repeat((4 - base64.length % 4) % 4);
+        return Uint8Array.from(atob(padded), c => c.charCodeAt(0)).buffer;
+    }
+
+    function toBase64url(buffer) {
+        const bytes = new Uint8Array(buffer);
+        let binary = '';
+        bytes.forEach(b => { binary += String.fromCharCode(b); });
+        return btoa(binary).replace(/\+/g, '-').replace(/\//g, '_').replace(/=+$/, '');
+    }
+
+    function csrfToken() {
+        const input = document.querySelector('input[name="csrf_token"]');
+        return input ? input.value : '';
+    }
+
+    async function post(url, body) {
+        const response = await fetch(url, {
+            method: 'POST',
+            credentials: 'same-origin',
+            headers: {
+                'Content-Type': 'application/json',
+                'Accept': 'application/json',
+                'X-CSRF-Token': csrfToken()
+            },
+            body: body ? JSON.stringify(body) : null
+        });
+        const data = await response.json().catch(() => ({}));
+        if (!response.ok) {
+            throw new Error(data.error || 'Geçiş anahtarı işlemi başarısız. Lütfen tekrar deneyin.');
+        }
+        return data;
+    }
+
+    function showError(message) {
+        if (window.Swal) {
+            Swal.fire({ title: 'Hata!', text: message, icon: 'error', showConfirmButton: true });
+        } else {
+            alert(message);
+        }
+    }
+
+    function credentialJSON(credential) {
+        const response = credential.response;
+        const json = {
+            id: credential.id,
+            rawId: toBase64url(credential.rawId),
+            type: credential.type,
+            clientExtensionResults: credential.getClientExtensionResults(),
+            response: { clientDataJSON: toBase64url(response.clientDataJSON) }
+        };
+        if (response.attestationObject) {
+            json.response.attestationObject = toBase64url(response.attestationObject);
+            if (response.getTransports) {
+                json.response.transports = response.getTransports();
+            }
+        } else {
+            json.response.authenticatorData = toBase64url(response.authenticatorData);
+            json.response.signature = toBase64url(response.signature);
+            if (response.userHandle) {
+                json.response.userHandle = toBase64url(response.userHandle);
+            }
+        }
+        return json;
+    }
+
+    async function login() {
+        const options = await post('/auth/passkey/login/begin');
+        const publicKey = options.publicKey;
+        publicKey.challenge = toBuffer(publicKey.challenge);
+        (publicKey.allowCredentials || []).forEach(c => { c.id = toBuffer(c.id); });
+
+        const credential = await navigator.credentials.get({ publicKey: publicKey });
+        const result = await post('/auth/passkey/login/finish', credentialJSON(credential));
+        window.location.href = result.redirect || '/';
+    }
+
+    async function register(name) {
+        const options = await post('/auth/passkey/register/begin');
+        const publicKey = options.publicKey;
+        publicKey.challenge = toBuffer(publicKey.challenge);
+        publicKey.user.id = toBuffer(publicKey.user.id);
+        (publicKey.excludeCredentials || []).forEach(c => { c.id = toBuffer(c.id); });
+
+        const credential = await navigator.credentials.create({ publicKey: publicKey });
+        const url = '/auth/passkey/register/finish?name=' + encodeURIComponent(name || '');
+        const result = await post(url, credentialJSON(credential));
+        window.location.href = result.redirect || '/auth/profile';
+    }
+
+    // Kullanıcı iletişim kutusunu kapattığında tarayıcı NotAllowedError fırlatır; bu bir hata sayılmaz
+    function handle(promise) {
+        promise.catch(err => {
+            if (err && err.name === 'NotAllowedError') {
+                return;
+            }
+            if (err && err.name === 'InvalidStateError') {
+                showError('Bu cihazda zaten bir geçiş anahtarınız kayıtlı.');
+                return;
+            }
+            showError(err && err.message ? err.message : 'Geçiş anahtarı işlemi başarısız. Lütfen tekrar deneyin.');
+        });
+    }
+
+    document.addEventListener('DOMContentLoaded', function() {
+        const supported = !!(window.PublicKeyCredential && navigator.credentials);
+
+        document.querySelectorAll('[data-passkey-login]').forEach(button => {
+            if (!supported) {
+                button.classList.add('d-none');
+                return;
+            }
+            button.addEventListener('click', e => {
+                e.preventDefault();
+                handle(login());
+            });
+        });
+
+        document.querySelectorAll('[data-passkey-register]').forEach(button => {
+            if (!supported) {
+                button.disabled = true;
+                button.title = 'Tarayıcınız geçiş anahtarlarını desteklemiyor';
+                return;
+            }
+            button.addEventListener('click', e => {
+                e.preventDefault();
+                const name = prompt('Geçiş anahtarı için bir ad girin (ör. "İş bilgisayarı"):', '');
+                if (name === null) {
+                    return;
+                }
+                handle(register(name));
+            });
+        });
+    });
+})();
@@ -0,0 +1,244 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"zatrano/configs/databaseconfig"
+	"zatrano/models"
+	"zatrano/pkg/currentuser"
+	"zatrano/requests"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrSlotTaken, seçilen saat aralığı başka bir randevuyla çakıştığında döner.
+var ErrSlotTaken = errors.New("seçilen saat dolu")
+
+var blockingAppointmentStatuses = []string{models.AppointmentStatusPending, models.AppointmentStatusConfirmed}
+
+type IAppointmentRepository interface {
+	GetBookableService(ctx context.Context, businessID, professionalServiceID uint) (*models.ProfessionalService, error)
+	GetBusyAppointments(ctx context.Context, professionalID uint, from, to time.Time, excludeID uint) ([]models.Appointment, error)
+	Book(ctx context.Context, appointment *models.Appointment) error
+	Reschedule(ctx context.Context, appointment *models.Appointment, startsAt, endsAt time.Time, status string) error
+	UpdateStatus(ctx context.Context, id uint, data map[string]interface{}) error
+	FindByToken(ctx context.Context, token string) (*models.Appointment, error)
+	GetOwnerAppointmentByID(ctx context.Context, ownerID, id uint) (*models.Appointment, error)
+	GetOwnerAppointments(ctx context.Context, ownerID uint, params requests.AppointmentListParams) ([]models.Appointment, int64, error)
+}
+
+type AppointmentRepository struct {
+	db *gorm.DB
+}
+
+func NewAppointmentRepository() IAppointmentRepository {
+	return &AppointmentRepository{db: databaseconfig.GetDB()}
+}
+
+// GetBookableService, hizmetin verilen işletmenin aktif bir uzmanına ait olduğunu doğrular.
+func (r *AppointmentRepository) GetBookableService(ctx context.Context, businessID, professionalServiceID uint) (*models.ProfessionalService, error) {
+	var item models.ProfessionalService
+	err := r.db.WithContext(ctx).
+		Preload("Service").
+		Preload("Professional").
+		Joins("JOIN professionals ON professionals.id = professional_services.professional_id AND professionals.deleted_at IS NULL").
+		Where("professional_services.id = ? AND professional_services.is_active = ?", professionalServiceID, true).
+		Where("professionals.business_id = ? AND professionals.is_active = ?", businessID, true).
+		First(&item).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+func (r *AppointmentRepository) GetBusyAppointments(ctx context.Context, professionalID uint, from, to time.Time, excludeID uint) ([]models.Appointment, error) {
+	var items []models.Appointment
+	err := overlapQuery(r.db.WithContext(ctx), professionalID, from, to, excludeID).
+		Order("starts_at asc").
+		Find(&items).Error
+	return items, err
+}
+
+// Book, uzman satırını FOR UPDATE ile kilitleyip çakışma kontrolünü aynı transaction içinde yapar;
+// aynı uzman için eşzamanlı istekler sıraya girer ve çift rezervasyon oluşamaz.
+func (r *AppointmentRepository) Book(ctx context.Context, appointment *models.Appointment) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockProfessional(tx, appointment.ProfessionalID); err != nil {
+			return err
+		}
+		if err := ensureFree(tx, appointment.ProfessionalID, appointment.StartsAt, appointment.EndsAt, 0); err != nil {
+			return err
+		}
+		return tx.Omit(clause.Associations).Create(appointment).Error
+	})
+}
+
+// Reschedule, randevuyu aynı kilit altında yeni saate taşır.
+func (r *AppointmentRepository) Reschedule(ctx context.Context, appointment *models.Appointment, startsAt, endsAt time.Time, status string) error {
+	data := map[string]interface{}{
+		"starts_at": startsAt,
+		"ends_at":   endsAt,
+		"status":    status,
+	}
+	if uid, ok := ctx.Value(currentuser.ContextUserIDKey).(uint); ok && uid > 0 {
+		data["updated_by"] = uid
+	}
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockProfessional(tx, appointment.ProfessionalID); err != nil {
+			return err
+		}
+		if err := ensureFree(tx, appointment.ProfessionalID, startsAt, endsAt, appointment.ID); err != nil {
+			return err
+		}
+		result := tx.Model(&models.Appointment{}).
+			Where("id = ? AND status IN ?", appointment.ID, blockingAppointmentStatuses).
+			Updates(data)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrNotFound
+		}
+		return nil
+	})
+}
+
+func (r *AppointmentRepository) UpdateStatus(ctx context.Context, id uint, data map[string]interface{}) error {
+	if uid, ok := ctx.Value(currentuser.ContextUserIDKey).(uint); ok && uid > 0 {
+		data["updated_by"] = uid
+	}
+	result := r.db.WithContext(ctx).Model(&models.Appointment{}).
+		Where("id = ? AND status IN ?", id, blockingAppointmentStatuses).
+		Updates(data)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *AppointmentRepository) FindByToken(ctx context.Context, token string) (*models.Appointment, error) {
+	var appointment models.Appointment
+	err := r.appointmentDetails(ctx).Where("appointments.manage_token = ?", token).First(&appointment).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &appointment, nil
+}
+
+// GetOwnerAppointmentByID, randevuyu yalnızca işletmenin sahibine döner.
+func (r *AppointmentRepository) GetOwnerAppointmentByID(ctx context.Context, ownerID, id uint) (*models.Appointment, error) {
+	var appointment models.Appointment
+	err := r.appointmentDetails(ctx).
+		Joins("JOIN businesses ON businesses.id = appointments.business_id AND businesses.deleted_at IS NULL").
+		Where("appointments.id = ? AND businesses.user_id = ?", id, ownerID).
+		First(&appointment).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &appointment, nil
+}
+
+func (r *AppointmentRepository) GetOwnerAppointments(ctx context.Context, ownerID uint, params requests.AppointmentListParams) ([]models.Appointment, int64, error) {
+	var appointments []models.Appointment
+	var totalCount int64
+
+	query := r.db.WithContext(ctx).Model(&models.Appointment{}).
+		Joins("JOIN businesses ON businesses.id = appointments.business_id AND businesses.deleted_at IS NULL").
+		Where("businesses.user_id = ?", ownerID)
+
+	// Filtreleme
+	if params.BusinessID != 0 {
+		query = query.Where("appointments.business_id = ?", params.BusinessID)
+	}
+	if params.Status != "" {
+		query = query.Where("appointments.status = ?", params.Status)
+	}
+	if !params.From.IsZero() {
+		query = query.Where("appointments.starts_at >= ?", params.From)
+	}
+	if !params.To.IsZero() {
+		query = query.Where("appointments.starts_at < ?", params.To)
+	}
+
+	// Count
+	if err := query.Count(&totalCount).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if totalCount == 0 {
+		return []models.Appointment{}, 0, nil
+	}
+
+	// Sorting & Pagination
+	query = query.Order("appointments.starts_at " + params.OrderBy).
+		Limit(params.PerPage).Offset(params.CalculateOffset())
+
+	if err := query.Select("appointments.*").
+		Preload("Business").
+		Preload("Professional").
+		Preload("ProfessionalService.Service").
+		Find(&appointments).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return appointments, totalCount, nil
+}
+
+func (r *AppointmentRepository) appointmentDetails(ctx context.Context) *gorm.DB {
+	return r.db.WithContext(ctx).Model(&models.Appointment{}).
+		Select("appointments.*").
+		Preload("Business").
+		Preload("Professional").
+		Preload("ProfessionalService.Service")
+}
+
+func lockProfessional(tx *gorm.DB, professionalID uint) error {
+	var professional models.Professional
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Select("id").
+		Where("id = ?", professionalID).
+		First(&professional).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrNotFound
+	}
+	return err
+}
+
+func ensureFree(tx *gorm.DB, professionalID uint, startsAt, endsAt time.Time, excludeID uint) error {
+	var count int64
+	if err := overlapQuery(tx, professionalID, startsAt, endsAt, excludeID).Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrSlotTaken
+	}
+	return nil
+}
+
+// overlapQuery, [from, to) aralığıyla kesişen ve takvimde yer kaplayan randevuları seçer.
+func overlapQuery(db *gorm.DB, professionalID uint, from, to time.Time, excludeID uint) *gorm.DB {
+	query := db.Model(&models.Appointment{}).
+		Where("professional_id = ? AND status IN ?", professionalID, blockingAppointmentStatuses).
+		Where("starts_at < ? AND ends_at > ?", to, from)
+	if excludeID != 0 {
+		query = query.Where("id <> ?", excludeID)
+	}
+	return query
+}
+
+var _ IAppointmentRepository = (*AppointmentRepository)(nil)
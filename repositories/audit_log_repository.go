@@ -0,0 +1,101 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"zatrano/configs/databaseconfig"
+	"zatrano/models"
+	"zatrano/requests"
+
+	"gorm.io/gorm"
+)
+
+// IAuditLogRepository yalnızca okuma sunar; kayıtlar models.RegisterAuditCallbacks tarafından yazılır.
+type IAuditLogRepository interface {
+	GetByID(ctx context.Context, id uint) (*models.AuditLog, error)
+	List(ctx context.Context, params requests.AuditLogListParams) ([]models.AuditLog, int64, error)
+	// EntityTypes, kaydı bulunan tablo adlarını alfabetik döner (filtre seçenekleri için).
+	EntityTypes(ctx context.Context) ([]string, error)
+}
+
+type AuditLogRepository struct {
+	db *gorm.DB
+}
+
+func NewAuditLogRepository() IAuditLogRepository {
+	return &AuditLogRepository{db: databaseconfig.GetDB()}
+}
+
+func (r *AuditLogRepository) GetByID(ctx context.Context, id uint) (*models.AuditLog, error) {
+	var entry models.AuditLog
+	err := r.db.WithContext(ctx).First(&entry, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+func (r *AuditLogRepository) List(ctx context.Context, params requests.AuditLogListParams) ([]models.AuditLog, int64, error) {
+	var entries []models.AuditLog
+	var totalCount int64
+
+	query := r.db.WithContext(ctx).Model(&models.AuditLog{})
+
+	// Filtreleme
+	if params.EntityType != "" {
+		query = query.Where("entity_type = ?", params.EntityType)
+	}
+	if params.EntityID != 0 {
+		query = query.Where("entity_id = ?", params.EntityID)
+	}
+	if params.Action != "" {
+		query = query.Where("action = ?", params.Action)
+	}
+	if params.ActorID != 0 {
+		query = query.Where("actor_id = ?", params.ActorID)
+	}
+	if !params.From.IsZero() {
+		query = query.Where("created_at >= ?", params.From)
+	}
+	if !params.To.IsZero() {
+		query = query.Where("created_at < ?", params.To)
+	}
+	// Arama; kullanıcı e-postası, IP, istek kimliği ve değişen değerlerde yapılır
+	if params.Search != "" {
+		like := "%" + strings.ToLower(params.Search) + "%"
+		query = query.Where("LOWER(actor_email) LIKE ? OR ip LIKE ? OR request_id = ? OR LOWER(changes::text) LIKE ?",
+			like, like, params.Search, like)
+	}
+
+	// Count
+	if err := query.Count(&totalCount).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if totalCount == 0 {
+		return []models.AuditLog{}, 0, nil
+	}
+
+	// Sorting & Pagination
+	if err := query.Order("created_at desc, id desc").
+		Limit(params.PerPage).Offset(params.CalculateOffset()).
+		Find(&entries).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return entries, totalCount, nil
+}
+
+func (r *AuditLogRepository) EntityTypes(ctx context.Context) ([]string, error) {
+	var types []string
+	err := r.db.WithContext(ctx).Model(&models.AuditLog{}).
+		Distinct("entity_type").Order("entity_type").Pluck("entity_type", &types).Error
+	return types, err
+}
+
+var _ IAuditLogRepository = (*AuditLogRepository)(nil)
@@ -16,9 +16,11 @@ type IAuthRepository interface {
 	FindUserByID(id uint) (*models.User, error)
 	UpdateUser(ctx context.Context, user *models.User) error
 	CreateUser(ctx context.Context, user *models.User) error
-	FindUserByResetToken(token string) (*models.User, error)
-	FindUserByVerificationToken(token string) (*models.User, error)
-	FindByProviderAndID(provider, providerID string) (*models.User, error)
+	// ClaimUnverifiedEmail, adresi sahibi doğruladığında hesabı doğrulanmış işaretler ve doğrulamadan önce
+	// belirlenmiş parolayı kaldırır. Doğrulanmamış hesapla oturum açılamadığından adresi sahibinden önce
+	// kaydeden birinin bırakabileceği tek giriş yolu paroladır; diğer giriş yollarına dokunulmaz.
+	// Hesap zaten doğrulanmışsa hiçbir şey yapmaz ve false döner.
+	ClaimUnverifiedEmail(ctx context.Context, userID uint) (bool, error)
 }
 
 type AuthRepository struct {
@@ -40,7 +42,7 @@ func (r *AuthRepository) executeQuery(query *gorm.DB, operation string, fields .
 
 func (r *AuthRepository) findUser(query *gorm.DB, operation string, fields ...zap.Field) (*models.User, error) {
 	var user models.User
-	err := r.executeQuery(query.First(&user), operation, fields...)
+	err := r.executeQuery(query.Preload("UserType.Permissions").First(&user), operation, fields...)
 	if err != nil {
 		return nil, err
 	}
@@ -80,29 +82,15 @@ func (r *AuthRepository) CreateUser(ctx context.Context, user *models.User) erro
 	)
 }
 
-func (r *AuthRepository) FindUserByResetToken(token string) (*models.User, error) {
-	return r.findUser(
-		r.db.Where("reset_token = ?", token),
-		"Kullanıcı sorgulama (reset token)",
-		zap.String("reset_token", token),
-	)
-}
-
-func (r *AuthRepository) FindUserByVerificationToken(token string) (*models.User, error) {
-	return r.findUser(
-		r.db.Where("verification_token = ?", token),
-		"Kullanıcı sorgulama (verification token)",
-		zap.String("verification_token", token),
-	)
-}
-
-func (r *AuthRepository) FindByProviderAndID(provider, providerID string) (*models.User, error) {
-	return r.findUser(
-		r.db.Where("provider = ? AND provider_id = ?", provider, providerID),
-		"Kullanıcı sorgulama (provider ve ID)",
-		zap.String("provider", provider),
-		zap.String("provider_id", providerID),
-	)
+func (r *AuthRepository) ClaimUnverifiedEmail(ctx context.Context, userID uint) (bool, error) {
+	res := r.db.WithContext(ctx).Model(&models.User{}).
+		Where("id = ? AND email_verified = ?", userID, false).
+		Updates(map[string]interface{}{"email_verified": true, "password": ""})
+	if res.Error != nil {
+		logconfig.Log.Error("Doğrulanmamış hesap sahiplenme hatası", zap.Uint("user_id", userID), zap.Error(res.Error))
+		return false, res.Error
+	}
+	return res.RowsAffected > 0, nil
 }
 
 var _ IAuthRepository = (*AuthRepository)(nil)
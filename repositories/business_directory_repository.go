@@ -0,0 +1,193 @@
+package repositories
+
+import (
+	"context"
+
+	"zatrano/configs/databaseconfig"
+	"zatrano/models"
+	"zatrano/pkg/geo"
+	"zatrano/pkg/turkishsearch"
+	"zatrano/requests"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// BusinessDirectoryRow, dizin sorgusunun sıralı sonucudur; işletmelerin kendisi ayrıca yüklenir.
+type BusinessDirectoryRow struct {
+	ID       uint
+	MinPrice *float64
+	Distance *float64
+}
+
+type IBusinessDirectoryRepository interface {
+	Search(ctx context.Context, params requests.BusinessDirectoryParams) ([]BusinessDirectoryRow, int64, error)
+	GetBusinessesByIDs(ctx context.Context, ids []uint) ([]models.Business, error)
+	GetCitiesWithBusinesses(ctx context.Context) ([]models.City, error)
+	GetDistrictsWithBusinesses(ctx context.Context, cityID uint) ([]models.District, error)
+	GetActiveServices(ctx context.Context) ([]models.Service, error)
+}
+
+type BusinessDirectoryRepository struct {
+	db *gorm.DB
+}
+
+func NewBusinessDirectoryRepository() IBusinessDirectoryRepository {
+	return &BusinessDirectoryRepository{db: databaseconfig.GetDB()}
+}
+
+// offeredServices, işletmenin aktif uzmanlarının sunduğu aktif hizmetleri seçen alt sorgunun gövdesidir.
+const offeredServices = `FROM professional_services ps
+	JOIN professionals p ON p.id = ps.professional_id AND p.deleted_at IS NULL AND p.is_active = true
+	WHERE p.business_id = businesses.id AND ps.deleted_at IS NULL AND ps.is_active = true`
+
+func (r *BusinessDirectoryRepository) Search(ctx context.Context, params requests.BusinessDirectoryParams) ([]BusinessDirectoryRow, int64, error) {
+	var rows []BusinessDirectoryRow
+	var totalCount int64
+
+	query := r.db.WithContext(ctx).Model(&models.Business{}).
+		Joins("JOIN addresses ON addresses.id = businesses.address_id").
+		Where("businesses.is_active = ?", true)
+
+	// Filtreleme
+	if params.CityID != 0 {
+		query = query.Where("addresses.city_id = ?", params.CityID)
+	}
+	if params.DistrictID != 0 {
+		query = query.Where("addresses.district_id = ?", params.DistrictID)
+	}
+	if params.BusinessTypeID != 0 {
+		query = query.Where("businesses.business_type_id = ?", params.BusinessTypeID)
+	}
+	if params.ServiceID != 0 {
+		query = query.Where("EXISTS (SELECT 1 "+offeredServices+" AND ps.service_id = ?)", params.ServiceID)
+	}
+	if params.Name != "" {
+		titleSQL, titleArgs := turkishsearch.SQLFilter("businesses.title", params.Name)
+		descSQL, descArgs := turkishsearch.SQLFilter("businesses.description", params.Name)
+		typeSQL, typeArgs := turkishsearch.SQLFilter("bt.name", params.Name)
+		serviceSQL, serviceArgs := turkishsearch.SQLFilter("s.name", params.Name)
+
+		args := append(append(append(titleArgs, descArgs...), typeArgs...), serviceArgs...)
+		query = query.Where("("+titleSQL+" OR "+descSQL+
+			" OR EXISTS (SELECT 1 FROM business_types bt WHERE bt.id = businesses.business_type_id AND "+typeSQL+")"+
+			" OR EXISTS (SELECT 1 "+offeredServices+" AND EXISTS (SELECT 1 FROM services s WHERE s.id = ps.service_id AND "+serviceSQL+")))",
+			args...)
+	}
+
+	// Yakınlık: indeksli kutu ön elemesi, ardından kesin haversine mesafesi
+	var distanceSQL string
+	var distanceArgs []interface{}
+	if params.Near != nil {
+		box := geo.BoundingBox(*params.Near, float64(params.RadiusKm))
+		query = query.Where("businesses.latitude BETWEEN ? AND ? AND businesses.longitude BETWEEN ? AND ?",
+			box.MinLat, box.MaxLat, box.MinLng, box.MaxLng)
+		distanceSQL, distanceArgs = geo.DistanceSQL("businesses.latitude", "businesses.longitude", *params.Near)
+		query = query.Where(distanceSQL+" <= ?", append(distanceArgs, params.RadiusKm)...)
+	}
+
+	// Count
+	if err := query.Count(&totalCount).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if totalCount == 0 {
+		return []BusinessDirectoryRow{}, 0, nil
+	}
+
+	// Fiyat: hizmet seçiliyse o hizmetin, değilse tüm hizmetlerin en düşük fiyatı
+	priceSQL := "(SELECT MIN(ps.price) " + offeredServices + ")"
+	var priceArgs []interface{}
+	if params.ServiceID != 0 {
+		priceSQL = "(SELECT MIN(ps.price) " + offeredServices + " AND ps.service_id = ?)"
+		priceArgs = append(priceArgs, params.ServiceID)
+	}
+	selectSQL := "businesses.id, " + priceSQL + " AS min_price"
+	if distanceSQL != "" {
+		selectSQL += ", " + distanceSQL + " AS distance"
+		priceArgs = append(priceArgs, distanceArgs...)
+	}
+	query = query.Select(selectSQL, priceArgs...)
+
+	// Sorting: arama metnine ait parametreler olduğundan sıralama tek ifade olarak kurulur
+	orderSQL := "businesses.rating_average DESC, businesses.rating_count DESC"
+	var orderArgs []interface{}
+	switch params.SortBy {
+	case requests.DirectorySortRating:
+		// Varsayılan puan sıralaması kullanılır
+	case requests.DirectorySortPriceAsc:
+		orderSQL = "min_price ASC NULLS LAST"
+	case requests.DirectorySortPriceDesc:
+		orderSQL = "min_price DESC NULLS LAST"
+	case requests.DirectorySortNewest:
+		orderSQL = "businesses.created_at DESC"
+	case requests.DirectorySortDistance:
+		if distanceSQL != "" {
+			orderSQL = "distance ASC"
+		}
+	default:
+		if params.Name != "" {
+			prefixSQL, prefixArgs := turkishsearch.SQLPrefixFilter("businesses.title", params.Name)
+			containsSQL, containsArgs := turkishsearch.SQLFilter("businesses.title", params.Name)
+			orderSQL = "CASE WHEN " + prefixSQL + " THEN 0 WHEN " + containsSQL + " THEN 1 ELSE 2 END, " + orderSQL
+			orderArgs = append(prefixArgs, containsArgs...)
+		}
+	}
+	query = query.Order(clause.OrderBy{Expression: gorm.Expr(orderSQL+", businesses.id DESC", orderArgs...)})
+
+	// Pagination
+	query = query.Limit(params.PerPage).Offset(params.CalculateOffset())
+
+	if err := query.Find(&rows).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return rows, totalCount, nil
+}
+
+func (r *BusinessDirectoryRepository) GetBusinessesByIDs(ctx context.Context, ids []uint) ([]models.Business, error) {
+	var businesses []models.Business
+	if len(ids) == 0 {
+		return businesses, nil
+	}
+	err := r.db.WithContext(ctx).
+		Preload("BusinessType").
+		Preload("Address.City").
+		Preload("Address.District").
+		Preload("Galleries", "is_cover = ? AND type = ?", true, models.GalleryTypeGallery).
+		Where("id IN ?", ids).
+		Find(&businesses).Error
+	return businesses, err
+}
+
+// GetCitiesWithBusinesses, en az bir aktif işletmesi olan illeri döner; boş filtre seçenekleri gösterilmez.
+func (r *BusinessDirectoryRepository) GetCitiesWithBusinesses(ctx context.Context) ([]models.City, error) {
+	var cities []models.City
+	err := r.db.WithContext(ctx).
+		Where("id IN (?)", r.db.Table("addresses").
+			Select("addresses.city_id").
+			Joins("JOIN businesses ON businesses.address_id = addresses.id AND businesses.deleted_at IS NULL AND businesses.is_active = true")).
+		Order("name asc").
+		Find(&cities).Error
+	return cities, err
+}
+
+func (r *BusinessDirectoryRepository) GetDistrictsWithBusinesses(ctx context.Context, cityID uint) ([]models.District, error) {
+	var districts []models.District
+	err := r.db.WithContext(ctx).
+		Where("city_id = ?", cityID).
+		Where("id IN (?)", r.db.Table("addresses").
+			Select("addresses.district_id").
+			Joins("JOIN businesses ON businesses.address_id = addresses.id AND businesses.deleted_at IS NULL AND businesses.is_active = true")).
+		Order("name asc").
+		Find(&districts).Error
+	return districts, err
+}
+
+func (r *BusinessDirectoryRepository) GetActiveServices(ctx context.Context) ([]models.Service, error) {
+	var items []models.Service
+	err := r.db.WithContext(ctx).Where("is_active = ?", true).Order("name asc").Find(&items).Error
+	return items, err
+}
+
+var _ IBusinessDirectoryRepository = (*BusinessDirectoryRepository)(nil)
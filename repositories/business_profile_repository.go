@@ -0,0 +1,83 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+
+	"zatrano/configs/databaseconfig"
+	"zatrano/models"
+
+	"gorm.io/gorm"
+)
+
+// IBusinessProfileRepository, herkese açık işletme sayfaları için yalnızca okuma sorgularını tanımlar.
+type IBusinessProfileRepository interface {
+	GetActiveBusinessBySlug(ctx context.Context, slug string) (*models.Business, error)
+	GetProfessionalServices(ctx context.Context, professionalIDs []uint) ([]models.ProfessionalService, error)
+	FindCurrentSlug(ctx context.Context, oldSlug string) (string, error)
+}
+
+type BusinessProfileRepository struct {
+	db *gorm.DB
+}
+
+func NewBusinessProfileRepository() IBusinessProfileRepository {
+	return &BusinessProfileRepository{db: databaseconfig.GetDB()}
+}
+
+func (r *BusinessProfileRepository) GetActiveBusinessBySlug(ctx context.Context, slug string) (*models.Business, error) {
+	var business models.Business
+	err := r.db.WithContext(ctx).
+		Preload("BusinessType").
+		Preload("Address.Country").
+		Preload("Address.City").
+		Preload("Address.District").
+		Preload("Galleries", func(db *gorm.DB) *gorm.DB {
+			return db.Where("is_active = ? AND type = ?", true, models.GalleryTypeGallery).Order("position asc, id asc")
+		}).
+		Preload("Professionals", func(db *gorm.DB) *gorm.DB {
+			return db.Where("is_active = ?", true).Order("id asc")
+		}).
+		Where("slug = ? AND is_active = ?", slug, true).
+		First(&business).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &business, nil
+}
+
+func (r *BusinessProfileRepository) GetProfessionalServices(ctx context.Context, professionalIDs []uint) ([]models.ProfessionalService, error) {
+	var items []models.ProfessionalService
+	if len(professionalIDs) == 0 {
+		return items, nil
+	}
+	err := r.db.WithContext(ctx).
+		Preload("Service").
+		Where("professional_id IN ? AND is_active = ?", professionalIDs, true).
+		Order("price asc").
+		Find(&items).Error
+	return items, err
+}
+
+// FindCurrentSlug, eski bir bağlantı adının işaret ettiği işletmenin güncel adını döner.
+func (r *BusinessProfileRepository) FindCurrentSlug(ctx context.Context, oldSlug string) (string, error) {
+	var slug string
+	err := r.db.WithContext(ctx).Model(&models.BusinessSlugHistory{}).
+		Select("businesses.slug").
+		Joins("JOIN businesses ON businesses.id = business_slug_histories.business_id AND businesses.deleted_at IS NULL").
+		Where("business_slug_histories.slug = ? AND businesses.is_active = ?", oldSlug, true).
+		Limit(1).
+		Scan(&slug).Error
+	if err != nil {
+		return "", err
+	}
+	if slug == "" {
+		return "", ErrNotFound
+	}
+	return slug, nil
+}
+
+var _ IBusinessProfileRepository = (*BusinessProfileRepository)(nil)
@@ -0,0 +1,190 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+
+	"zatrano/configs/databaseconfig"
+	"zatrano/models"
+	"zatrano/pkg/currentuser"
+	"zatrano/requests"
+
+	"gorm.io/gorm"
+)
+
+type IBusinessRepository interface {
+	GetUserBusinesses(ctx context.Context, userID uint, params requests.BusinessListParams) ([]models.Business, int64, error)
+	GetUserBusinessByID(ctx context.Context, userID, id uint) (*models.Business, error)
+	SlugExists(ctx context.Context, slug string, excludeID uint) (bool, error)
+	CreateBusiness(ctx context.Context, business *models.Business, address *models.Address) error
+	UpdateBusiness(ctx context.Context, business *models.Business, businessData, addressData map[string]interface{}) error
+	DeleteBusiness(ctx context.Context, business *models.Business) error
+}
+
+type BusinessRepository struct {
+	db *gorm.DB
+}
+
+func NewBusinessRepository() IBusinessRepository {
+	return &BusinessRepository{db: databaseconfig.GetDB()}
+}
+
+func (r *BusinessRepository) GetUserBusinesses(ctx context.Context, userID uint, params requests.BusinessListParams) ([]models.Business, int64, error) {
+	var businesses []models.Business
+	var totalCount int64
+
+	query := r.db.WithContext(ctx).Model(&models.Business{}).Where("user_id = ?", userID)
+
+	// Filtreleme
+	if params.Name != "" {
+		query = query.Where("title ILIKE ?", "%"+params.Name+"%")
+	}
+
+	// Count
+	if err := query.Count(&totalCount).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if totalCount == 0 {
+		return []models.Business{}, 0, nil
+	}
+
+	// Sorting
+	query = query.Order(params.SortBy + " " + params.OrderBy)
+
+	// Pagination
+	offset := params.CalculateOffset()
+	query = query.Limit(params.PerPage).Offset(offset)
+
+	// Find
+	if err := query.Preload("BusinessType").
+		Preload("Address.City").
+		Preload("Address.District").
+		Find(&businesses).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return businesses, totalCount, nil
+}
+
+// GetUserBusinessByID, işletmeyi yalnızca sahibi için döner; başkasına ait kayıt bulunamadı sayılır.
+func (r *BusinessRepository) GetUserBusinessByID(ctx context.Context, userID, id uint) (*models.Business, error) {
+	var business models.Business
+	err := r.db.WithContext(ctx).
+		Preload("BusinessType").
+		Preload("Address").
+		Where("id = ? AND user_id = ?", id, userID).
+		First(&business).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &business, nil
+}
+
+// SlugExists, silinmiş kayıtlar dahil kontrol eder; slug benzersiz index'i soft delete'i dikkate almaz.
+// Başka bir işletmenin eski bağlantı adları da dolu sayılır, böylece yönlendirmeler bozulmaz.
+func (r *BusinessRepository) SlugExists(ctx context.Context, slug string, excludeID uint) (bool, error) {
+	var count int64
+	query := r.db.WithContext(ctx).Unscoped().Model(&models.Business{}).Where("slug = ?", slug)
+	if excludeID != 0 {
+		query = query.Where("id <> ?", excludeID)
+	}
+	if err := query.Count(&count).Error; err != nil {
+		return false, err
+	}
+	if count > 0 {
+		return true, nil
+	}
+
+	historyQuery := r.db.WithContext(ctx).Model(&models.BusinessSlugHistory{}).Where("slug = ?", slug)
+	if excludeID != 0 {
+		historyQuery = historyQuery.Where("business_id <> ?", excludeID)
+	}
+	if err := historyQuery.Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func (r *BusinessRepository) CreateBusiness(ctx context.Context, business *models.Business, address *models.Address) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(address).Error; err != nil {
+			return err
+		}
+		business.AddressID = address.ID
+		// Create callback'i IsActive=false değerini true'ya çevirir; pasif seçildiyse geri al
+		wantActive := business.IsActive
+		if err := tx.Omit("Address", "BusinessType", "User").Create(business).Error; err != nil {
+			return err
+		}
+		if !wantActive {
+			business.IsActive = false
+			return tx.Model(business).Update("is_active", false).Error
+		}
+		return nil
+	})
+}
+
+func (r *BusinessRepository) UpdateBusiness(ctx context.Context, business *models.Business, businessData, addressData map[string]interface{}) error {
+	if uid, ok := ctx.Value(currentuser.ContextUserIDKey).(uint); ok && uid > 0 {
+		businessData["updated_by"] = uid
+		addressData["updated_by"] = uid
+	}
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.Address{BaseModel: models.BaseModel{ID: business.AddressID}}).
+			Updates(addressData).Error; err != nil {
+			return err
+		}
+		result := tx.Model(&models.Business{}).
+			Where("id = ? AND user_id = ?", business.ID, business.UserID).
+			Updates(businessData)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrNotFound
+		}
+
+		// Bağlantı adı değiştiyse eskisini yönlendirme için sakla
+		if newSlug, ok := businessData["slug"].(string); ok && newSlug != business.Slug {
+			if err := tx.Unscoped().Where("slug = ?", newSlug).Delete(&models.BusinessSlugHistory{}).Error; err != nil {
+				return err
+			}
+			history := &models.BusinessSlugHistory{BusinessID: business.ID, Slug: business.Slug}
+			if err := tx.Create(history).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (r *BusinessRepository) DeleteBusiness(ctx context.Context, business *models.Business) error {
+	userID, ok := ctx.Value(currentuser.ContextUserIDKey).(uint)
+	if !ok || userID == 0 {
+		return ErrMissingUserID
+	}
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.Business{}).Where("id = ? AND user_id = ?", business.ID, business.UserID).
+			Update("deleted_by", userID).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&models.Address{}).Where("id = ?", business.AddressID).
+			Update("deleted_by", userID).Error; err != nil {
+			return err
+		}
+		result := tx.Where("id = ? AND user_id = ?", business.ID, business.UserID).Delete(&models.Business{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrNotFound
+		}
+		return tx.Delete(&models.Address{}, business.AddressID).Error
+	})
+}
+
+var _ IBusinessRepository = (*BusinessRepository)(nil)
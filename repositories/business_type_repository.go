@@ -0,0 +1,37 @@
+package repositories
+
+import (
+	"context"
+
+	"zatrano/configs/databaseconfig"
+	"zatrano/models"
+
+	"gorm.io/gorm"
+)
+
+type IBusinessTypeRepository interface {
+	GetActiveBusinessTypes(ctx context.Context) ([]models.BusinessType, error)
+	GetBusinessTypeByID(ctx context.Context, id uint) (*models.BusinessType, error)
+}
+
+type BusinessTypeRepository struct {
+	base IBaseRepository[models.BusinessType]
+	db   *gorm.DB
+}
+
+func NewBusinessTypeRepository() IBusinessTypeRepository {
+	base := NewBaseRepository[models.BusinessType](databaseconfig.GetDB())
+	return &BusinessTypeRepository{base: base, db: databaseconfig.GetDB()}
+}
+
+func (r *BusinessTypeRepository) GetActiveBusinessTypes(ctx context.Context) ([]models.BusinessType, error) {
+	var types []models.BusinessType
+	err := r.db.WithContext(ctx).Where("is_active = ?", true).Order("name asc").Find(&types).Error
+	return types, err
+}
+
+func (r *BusinessTypeRepository) GetBusinessTypeByID(ctx context.Context, id uint) (*models.BusinessType, error) {
+	return r.base.GetByID(ctx, id)
+}
+
+var _ IBusinessTypeRepository = (*BusinessTypeRepository)(nil)
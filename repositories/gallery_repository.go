@@ -0,0 +1,212 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+
+	"zatrano/configs/databaseconfig"
+	"zatrano/models"
+	"zatrano/pkg/currentuser"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrGalleryFull, eklenecek görsellerle galeri limiti aşılacaksa döner.
+var ErrGalleryFull = errors.New("galeri limiti dolu")
+
+type IGalleryRepository interface {
+	GetImages(ctx context.Context, businessID uint) ([]models.Gallery, error)
+	CountImages(ctx context.Context, businessID uint) (int64, error)
+	GetImage(ctx context.Context, businessID, id uint) (*models.Gallery, error)
+	// AddImages, limit > 0 ise sayımı ve eklemeyi işletme satırı kilitliyken yapar.
+	AddImages(ctx context.Context, businessID uint, images []models.Gallery, limit int) error
+	UpdateCaption(ctx context.Context, businessID, id uint, caption string) error
+	Reorder(ctx context.Context, businessID uint, ids []uint) error
+	SetCover(ctx context.Context, businessID, id uint) error
+	DeleteImage(ctx context.Context, image *models.Gallery) error
+	GetOwnerPlanName(ctx context.Context, ownerID uint) (string, error)
+}
+
+type GalleryRepository struct {
+	db *gorm.DB
+}
+
+func NewGalleryRepository() IGalleryRepository {
+	return &GalleryRepository{db: databaseconfig.GetDB()}
+}
+
+func (r *GalleryRepository) GetImages(ctx context.Context, businessID uint) ([]models.Gallery, error) {
+	var images []models.Gallery
+	err := r.db.WithContext(ctx).
+		Where("business_id = ? AND type = ?", businessID, models.GalleryTypeGallery).
+		Order("position asc, id asc").
+		Find(&images).Error
+	return images, err
+}
+
+func (r *GalleryRepository) CountImages(ctx context.Context, businessID uint) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.Gallery{}).
+		Where("business_id = ? AND type = ?", businessID, models.GalleryTypeGallery).
+		Count(&count).Error
+	return count, err
+}
+
+func (r *GalleryRepository) GetImage(ctx context.Context, businessID, id uint) (*models.Gallery, error) {
+	var image models.Gallery
+	err := r.db.WithContext(ctx).
+		Where("id = ? AND business_id = ? AND type = ?", id, businessID, models.GalleryTypeGallery).
+		First(&image).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &image, nil
+}
+
+func (r *GalleryRepository) AddImages(ctx context.Context, businessID uint, images []models.Gallery, limit int) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Aynı işletmeye eşzamanlı yüklemeler limiti birlikte aşamasın
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("id").First(&models.Business{}, businessID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrNotFound
+			}
+			return err
+		}
+
+		var stats struct {
+			Count       int64
+			MaxPosition int
+			HasCover    bool
+		}
+		if err := tx.Model(&models.Gallery{}).
+			Select("COUNT(*) AS count, COALESCE(MAX(position), 0) AS max_position, COALESCE(BOOL_OR(is_cover), false) AS has_cover").
+			Where("business_id = ? AND type = ?", businessID, models.GalleryTypeGallery).
+			Scan(&stats).Error; err != nil {
+			return err
+		}
+		if limit > 0 && int(stats.Count)+len(images) > limit {
+			return ErrGalleryFull
+		}
+
+		// Toplu Create yerine tek tek: BaseModel callback'i slice üzerinde çalışmaz
+		for i := range images {
+			images[i].BusinessID = businessID
+			images[i].Type = models.GalleryTypeGallery
+			images[i].Position = stats.MaxPosition + i + 1
+			images[i].IsCover = !stats.HasCover && i == 0
+			if err := tx.Omit(clause.Associations).Create(&images[i]).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (r *GalleryRepository) UpdateCaption(ctx context.Context, businessID, id uint, caption string) error {
+	data := map[string]interface{}{"caption": caption}
+	if uid, ok := ctx.Value(currentuser.ContextUserIDKey).(uint); ok && uid > 0 {
+		data["updated_by"] = uid
+	}
+	result := r.db.WithContext(ctx).Model(&models.Gallery{}).
+		Where("id = ? AND business_id = ? AND type = ?", id, businessID, models.GalleryTypeGallery).
+		Updates(data)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// Reorder, ids sırasını 1'den başlayan pozisyonlara yazar; listede olmayan görseller sona kalır.
+func (r *GalleryRepository) Reorder(ctx context.Context, businessID uint, ids []uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.Gallery{}).
+			Where("business_id = ? AND type = ? AND id NOT IN ?", businessID, models.GalleryTypeGallery, ids).
+			Update("position", gorm.Expr("position + ?", len(ids))).Error; err != nil {
+			return err
+		}
+		for i, id := range ids {
+			if err := tx.Model(&models.Gallery{}).
+				Where("id = ? AND business_id = ? AND type = ?", id, businessID, models.GalleryTypeGallery).
+				Update("position", i+1).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (r *GalleryRepository) SetCover(ctx context.Context, businessID, id uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.Gallery{}).
+			Where("business_id = ? AND type = ?", businessID, models.GalleryTypeGallery).
+			Update("is_cover", gorm.Expr("(id = ?)", id))
+		if result.Error != nil {
+			return result.Error
+		}
+		var exists int64
+		if err := tx.Model(&models.Gallery{}).
+			Where("id = ? AND business_id = ? AND type = ?", id, businessID, models.GalleryTypeGallery).
+			Count(&exists).Error; err != nil {
+			return err
+		}
+		if exists == 0 {
+			return ErrNotFound
+		}
+		return nil
+	})
+}
+
+// DeleteImage, görseli siler; kapak silindiyse sıradaki ilk görsel kapak olur.
+func (r *GalleryRepository) DeleteImage(ctx context.Context, image *models.Gallery) error {
+	userID, ok := ctx.Value(currentuser.ContextUserIDKey).(uint)
+	if !ok || userID == 0 {
+		return ErrMissingUserID
+	}
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(image).Update("deleted_by", userID).Error; err != nil {
+			return err
+		}
+		result := tx.Delete(image)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrNotFound
+		}
+		if !image.IsCover {
+			return nil
+		}
+		var next models.Gallery
+		err := tx.Where("business_id = ? AND type = ?", image.BusinessID, models.GalleryTypeGallery).
+			Order("position asc, id asc").First(&next).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		return tx.Model(&next).Update("is_cover", true).Error
+	})
+}
+
+// GetOwnerPlanName, işletme sahibinin plan olarak kullanılan kullanıcı tipi adını döner.
+func (r *GalleryRepository) GetOwnerPlanName(ctx context.Context, ownerID uint) (string, error) {
+	var name string
+	err := r.db.WithContext(ctx).Model(&models.User{}).
+		Select("user_types.name").
+		Joins("JOIN user_types ON user_types.id = users.user_type_id").
+		Where("users.id = ?", ownerID).
+		Limit(1).
+		Scan(&name).Error
+	return name, err
+}
+
+var _ IGalleryRepository = (*GalleryRepository)(nil)
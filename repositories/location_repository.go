@@ -0,0 +1,61 @@
+package repositories
+
+import (
+	"context"
+
+	"zatrano/configs/databaseconfig"
+	"zatrano/models"
+
+	"gorm.io/gorm"
+)
+
+type ILocationRepository interface {
+	GetCountries(ctx context.Context) ([]models.Country, error)
+	GetCitiesByCountry(ctx context.Context, countryID uint) ([]models.City, error)
+	GetDistrictsByCity(ctx context.Context, cityID uint) ([]models.District, error)
+	// DistrictBelongsTo, ilçenin ile, ilin de ülkeye bağlı olduğunu doğrular.
+	DistrictBelongsTo(ctx context.Context, countryID, cityID, districtID uint) (bool, error)
+}
+
+type LocationRepository struct {
+	db *gorm.DB
+}
+
+func NewLocationRepository() ILocationRepository {
+	return &LocationRepository{db: databaseconfig.GetDB()}
+}
+
+func (r *LocationRepository) GetCountries(ctx context.Context) ([]models.Country, error) {
+	var countries []models.Country
+	err := r.db.WithContext(ctx).Where("is_active = ?", true).Order("name asc").Find(&countries).Error
+	return countries, err
+}
+
+func (r *LocationRepository) GetCitiesByCountry(ctx context.Context, countryID uint) ([]models.City, error) {
+	var cities []models.City
+	err := r.db.WithContext(ctx).
+		Where("country_id = ? AND is_active = ?", countryID, true).
+		Order("name asc").
+		Find(&cities).Error
+	return cities, err
+}
+
+func (r *LocationRepository) GetDistrictsByCity(ctx context.Context, cityID uint) ([]models.District, error) {
+	var districts []models.District
+	err := r.db.WithContext(ctx).
+		Where("city_id = ? AND is_active = ?", cityID, true).
+		Order("name asc").
+		Find(&districts).Error
+	return districts, err
+}
+
+func (r *LocationRepository) DistrictBelongsTo(ctx context.Context, countryID, cityID, districtID uint) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.District{}).
+		Joins("JOIN cities ON cities.id = districts.city_id AND cities.deleted_at IS NULL").
+		Where("districts.id = ? AND districts.city_id = ? AND cities.country_id = ?", districtID, cityID, countryID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+var _ ILocationRepository = (*LocationRepository)(nil)
@@ -0,0 +1,91 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"zatrano/configs/databaseconfig"
+	"zatrano/models"
+	"zatrano/requests"
+
+	"gorm.io/gorm"
+)
+
+type ILoginLockoutRepository interface {
+	Create(ctx context.Context, lockout *models.LoginLockout) error
+	GetByID(ctx context.Context, id uint) (*models.LoginLockout, error)
+	List(ctx context.Context, params requests.LoginLockoutListParams) ([]models.LoginLockout, int64, error)
+	// MarkUnlocked, aynı kapsam ve konudaki süresi dolmamış tüm kilit kayıtlarını açılmış olarak işaretler.
+	MarkUnlocked(ctx context.Context, scope, subject string, by uint) error
+}
+
+type LoginLockoutRepository struct {
+	db *gorm.DB
+}
+
+func NewLoginLockoutRepository() ILoginLockoutRepository {
+	return &LoginLockoutRepository{db: databaseconfig.GetDB()}
+}
+
+func (r *LoginLockoutRepository) Create(ctx context.Context, lockout *models.LoginLockout) error {
+	return r.db.WithContext(ctx).Create(lockout).Error
+}
+
+func (r *LoginLockoutRepository) GetByID(ctx context.Context, id uint) (*models.LoginLockout, error) {
+	var lockout models.LoginLockout
+	err := r.db.WithContext(ctx).Preload("User").First(&lockout, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &lockout, nil
+}
+
+func (r *LoginLockoutRepository) List(ctx context.Context, params requests.LoginLockoutListParams) ([]models.LoginLockout, int64, error) {
+	var lockouts []models.LoginLockout
+	var totalCount int64
+
+	query := r.db.WithContext(ctx).Model(&models.LoginLockout{})
+
+	// Filtreleme
+	if params.Status == requests.LoginLockoutStatusActive {
+		query = query.Where("unlocked_at IS NULL AND locked_until > ?", time.Now())
+	}
+	if params.Search != "" {
+		query = query.Where("subject ILIKE ? OR ip ILIKE ?", "%"+strings.ToLower(params.Search)+"%", "%"+params.Search+"%")
+	}
+
+	// Count
+	if err := query.Count(&totalCount).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if totalCount == 0 {
+		return []models.LoginLockout{}, 0, nil
+	}
+
+	// Sorting & Pagination
+	if err := query.Order("created_at desc, id desc").
+		Limit(params.PerPage).Offset(params.CalculateOffset()).
+		Preload("User").
+		Find(&lockouts).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return lockouts, totalCount, nil
+}
+
+func (r *LoginLockoutRepository) MarkUnlocked(ctx context.Context, scope, subject string, by uint) error {
+	return r.db.WithContext(ctx).Model(&models.LoginLockout{}).
+		Where("scope = ? AND subject = ? AND unlocked_at IS NULL AND locked_until > ?", scope, subject, time.Now()).
+		Updates(map[string]interface{}{
+			"unlocked_at": time.Now(),
+			"unlocked_by": by,
+		}).Error
+}
+
+var _ ILoginLockoutRepository = (*LoginLockoutRepository)(nil)
@@ -0,0 +1,78 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"zatrano/configs/databaseconfig"
+	"zatrano/models"
+
+	"gorm.io/gorm"
+)
+
+type IPersonalAccessTokenRepository interface {
+	// FindByHash, anahtarı sahibi, rolü ve rol yetkileriyle birlikte getirir.
+	FindByHash(ctx context.Context, tokenHash string) (*models.PersonalAccessToken, error)
+	ListByUser(ctx context.Context, userID uint) ([]models.PersonalAccessToken, error)
+	Create(ctx context.Context, token *models.PersonalAccessToken) error
+	// Revoke, yalnızca kullanıcının henüz iptal edilmemiş anahtarını iptal eder.
+	Revoke(ctx context.Context, userID, id uint, at time.Time) error
+	TouchUse(ctx context.Context, id uint, ip string, at time.Time) error
+}
+
+type PersonalAccessTokenRepository struct {
+	db *gorm.DB
+}
+
+func NewPersonalAccessTokenRepository() IPersonalAccessTokenRepository {
+	return &PersonalAccessTokenRepository{db: databaseconfig.GetDB()}
+}
+
+func (r *PersonalAccessTokenRepository) FindByHash(ctx context.Context, tokenHash string) (*models.PersonalAccessToken, error) {
+	var token models.PersonalAccessToken
+	err := r.db.WithContext(ctx).
+		Preload("User.UserType.Permissions").
+		Where("token_hash = ?", tokenHash).
+		First(&token).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+func (r *PersonalAccessTokenRepository) ListByUser(ctx context.Context, userID uint) ([]models.PersonalAccessToken, error) {
+	var tokens []models.PersonalAccessToken
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("created_at desc").Find(&tokens).Error
+	return tokens, err
+}
+
+func (r *PersonalAccessTokenRepository) Create(ctx context.Context, token *models.PersonalAccessToken) error {
+	return r.db.WithContext(ctx).Create(token).Error
+}
+
+func (r *PersonalAccessTokenRepository) Revoke(ctx context.Context, userID, id uint, at time.Time) error {
+	result := r.db.WithContext(ctx).Model(&models.PersonalAccessToken{}).
+		Where("user_id = ? AND id = ? AND revoked_at IS NULL", userID, id).
+		Update("revoked_at", at)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *PersonalAccessTokenRepository) TouchUse(ctx context.Context, id uint, ip string, at time.Time) error {
+	return r.db.WithContext(ctx).Model(&models.PersonalAccessToken{}).Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"last_used_at": at,
+			"last_used_ip": ip,
+		}).Error
+}
+
+var _ IPersonalAccessTokenRepository = (*PersonalAccessTokenRepository)(nil)
@@ -0,0 +1,259 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"zatrano/configs/databaseconfig"
+	"zatrano/models"
+	"zatrano/pkg/currentuser"
+	"zatrano/requests"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrReviewExists, randevu için daha önce yorum bırakılmışsa döner.
+var ErrReviewExists = errors.New("bu randevu zaten değerlendirilmiş")
+
+// ratingSummary, onaylı yorumlardan ortalama ve adet üretir; işletme ve uzman satırlarında
+// saklanan özet alanlar her durum değişikliğinde bu sorguyla yeniden hesaplanır.
+const ratingSummary = `SELECT %s FROM reviews WHERE reviews.%s = ? AND reviews.status = 'approved' AND reviews.deleted_at IS NULL`
+
+type IReviewRepository interface {
+	FindByAppointment(ctx context.Context, appointmentID uint) (*models.Review, error)
+	Create(ctx context.Context, review *models.Review) error
+	GetByID(ctx context.Context, id uint) (*models.Review, error)
+	GetReviews(ctx context.Context, params requests.ReviewListParams) ([]models.Review, int64, error)
+	GetBusinessReviews(ctx context.Context, businessID uint, limit int) ([]models.Review, error)
+	GetOwnerReviews(ctx context.Context, ownerID uint, params requests.ReviewListParams) ([]models.Review, int64, error)
+	GetOwnerReviewByID(ctx context.Context, ownerID, id uint) (*models.Review, error)
+	UpdateReply(ctx context.Context, id uint, reply string, repliedAt *time.Time) error
+	// Moderate, yorumun durumunu değiştirir ve işletme/uzman puan özetini aynı transaction içinde günceller.
+	Moderate(ctx context.Context, review *models.Review, status, note string) error
+}
+
+type ReviewRepository struct {
+	db *gorm.DB
+}
+
+func NewReviewRepository() IReviewRepository {
+	return &ReviewRepository{db: databaseconfig.GetDB()}
+}
+
+func (r *ReviewRepository) FindByAppointment(ctx context.Context, appointmentID uint) (*models.Review, error) {
+	var review models.Review
+	err := r.db.WithContext(ctx).Where("appointment_id = ?", appointmentID).First(&review).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &review, nil
+}
+
+// Create, randevu satırını kilitleyerek aynı randevu için eşzamanlı ikinci yorumu engeller.
+// Yorum onaylı oluşturulursa puan özeti de güncellenir.
+func (r *ReviewRepository) Create(ctx context.Context, review *models.Review) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var appointment models.Appointment
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("id").
+			Where("id = ?", review.AppointmentID).
+			First(&appointment).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrNotFound
+			}
+			return err
+		}
+
+		var count int64
+		if err := tx.Unscoped().Model(&models.Review{}).
+			Where("appointment_id = ?", review.AppointmentID).
+			Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return ErrReviewExists
+		}
+
+		if err := tx.Omit(clause.Associations).Create(review).Error; err != nil {
+			return err
+		}
+		if review.Status == models.ReviewStatusApproved {
+			return refreshRatings(tx, review.BusinessID, review.ProfessionalID)
+		}
+		return nil
+	})
+}
+
+func (r *ReviewRepository) GetByID(ctx context.Context, id uint) (*models.Review, error) {
+	var review models.Review
+	err := r.reviewDetails(ctx).Where("reviews.id = ?", id).First(&review).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &review, nil
+}
+
+// GetReviews, moderasyon kuyruğu için tüm işletmelerin yorumlarını listeler.
+func (r *ReviewRepository) GetReviews(ctx context.Context, params requests.ReviewListParams) ([]models.Review, int64, error) {
+	return r.paginate(r.db.WithContext(ctx).Model(&models.Review{}), params)
+}
+
+// GetBusinessReviews, profil sayfasında gösterilecek en yeni onaylı yorumları döner.
+func (r *ReviewRepository) GetBusinessReviews(ctx context.Context, businessID uint, limit int) ([]models.Review, error) {
+	var reviews []models.Review
+	err := r.db.WithContext(ctx).
+		Preload("Professional").
+		Where("business_id = ? AND status = ?", businessID, models.ReviewStatusApproved).
+		Order("created_at desc, id desc").
+		Limit(limit).
+		Find(&reviews).Error
+	return reviews, err
+}
+
+func (r *ReviewRepository) GetOwnerReviews(ctx context.Context, ownerID uint, params requests.ReviewListParams) ([]models.Review, int64, error) {
+	query := r.db.WithContext(ctx).Model(&models.Review{}).
+		Joins("JOIN businesses ON businesses.id = reviews.business_id AND businesses.deleted_at IS NULL").
+		Where("businesses.user_id = ?", ownerID)
+	return r.paginate(query, params)
+}
+
+// GetOwnerReviewByID, yorumu yalnızca işletmenin sahibine döner.
+func (r *ReviewRepository) GetOwnerReviewByID(ctx context.Context, ownerID, id uint) (*models.Review, error) {
+	var review models.Review
+	err := r.reviewDetails(ctx).
+		Joins("JOIN businesses ON businesses.id = reviews.business_id AND businesses.deleted_at IS NULL").
+		Where("reviews.id = ? AND businesses.user_id = ?", id, ownerID).
+		First(&review).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &review, nil
+}
+
+func (r *ReviewRepository) UpdateReply(ctx context.Context, id uint, reply string, repliedAt *time.Time) error {
+	data := map[string]interface{}{
+		"reply":      reply,
+		"replied_at": repliedAt,
+	}
+	if uid, ok := ctx.Value(currentuser.ContextUserIDKey).(uint); ok && uid > 0 {
+		data["updated_by"] = uid
+	}
+	result := r.db.WithContext(ctx).Model(&models.Review{}).Where("id = ?", id).Updates(data)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *ReviewRepository) Moderate(ctx context.Context, review *models.Review, status, note string) error {
+	data := map[string]interface{}{
+		"status":          status,
+		"moderation_note": note,
+		"moderated_at":    time.Now(),
+	}
+	if uid, ok := ctx.Value(currentuser.ContextUserIDKey).(uint); ok && uid > 0 {
+		data["moderated_by"] = uid
+		data["updated_by"] = uid
+	}
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.Review{}).Where("id = ?", review.ID).Updates(data)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrNotFound
+		}
+		return refreshRatings(tx, review.BusinessID, review.ProfessionalID)
+	})
+}
+
+func (r *ReviewRepository) paginate(query *gorm.DB, params requests.ReviewListParams) ([]models.Review, int64, error) {
+	var reviews []models.Review
+	var totalCount int64
+
+	// Filtreleme
+	if params.BusinessID != 0 {
+		query = query.Where("reviews.business_id = ?", params.BusinessID)
+	}
+	if params.Status != "" {
+		query = query.Where("reviews.status = ?", params.Status)
+	}
+	if params.Rating != 0 {
+		query = query.Where("reviews.rating = ?", params.Rating)
+	}
+
+	// Count
+	if err := query.Count(&totalCount).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if totalCount == 0 {
+		return []models.Review{}, 0, nil
+	}
+
+	// Sorting & Pagination
+	query = query.Order("reviews.created_at desc, reviews.id desc").
+		Limit(params.PerPage).Offset(params.CalculateOffset())
+
+	if err := query.Select("reviews.*").
+		Preload("Business").
+		Preload("Professional").
+		Preload("Appointment.ProfessionalService.Service").
+		Find(&reviews).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return reviews, totalCount, nil
+}
+
+func (r *ReviewRepository) reviewDetails(ctx context.Context) *gorm.DB {
+	return r.db.WithContext(ctx).Model(&models.Review{}).
+		Select("reviews.*").
+		Preload("Business").
+		Preload("Professional").
+		Preload("Appointment.ProfessionalService.Service")
+}
+
+// refreshRatings, işletme ve uzmanın puan ortalamasını ve yorum sayısını onaylı yorumlardan yeniden hesaplar.
+// UpdateColumns kullanılır; özet alanların güncellenmesi kaydın düzenlenmesi sayılmaz.
+// Satırlar önce kilitlenir: READ COMMITTED altında UPDATE'in alt sorgusu ifadenin başladığı andaki
+// görüntüyü kullanır, kilit olmadan eş zamanlı iki yorumdan biri özete hiç yansımayabilirdi. Kilidi
+// bekleyen transaction, diğeri commit edildikten sonra yeni bir ifadeyle hesapladığı için onu da görür.
+func refreshRatings(tx *gorm.DB, businessID, professionalID uint) error {
+	if err := tx.Unscoped().Clauses(clause.Locking{Strength: "UPDATE"}).
+		Select("id").Where("id = ?", businessID).Find(&models.Business{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Unscoped().Clauses(clause.Locking{Strength: "UPDATE"}).
+		Select("id").Where("id = ?", professionalID).Find(&models.Professional{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Model(&models.Business{}).Where("id = ?", businessID).UpdateColumns(ratingColumns("business_id", businessID)).Error; err != nil {
+		return err
+	}
+	return tx.Model(&models.Professional{}).Where("id = ?", professionalID).UpdateColumns(ratingColumns("professional_id", professionalID)).Error
+}
+
+func ratingColumns(column string, id uint) map[string]interface{} {
+	return map[string]interface{}{
+		"rating_average": gorm.Expr("COALESCE(("+fmt.Sprintf(ratingSummary, "ROUND(AVG(reviews.rating)::numeric, 2)", column)+"), 0)", id),
+		"rating_count":   gorm.Expr("("+fmt.Sprintf(ratingSummary, "COUNT(*)", column)+")", id),
+	}
+}
+
+var _ IReviewRepository = (*ReviewRepository)(nil)
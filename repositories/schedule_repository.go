@@ -0,0 +1,129 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"zatrano/configs/databaseconfig"
+	"zatrano/models"
+	"zatrano/pkg/currentuser"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type IScheduleRepository interface {
+	GetOwnerProfessionals(ctx context.Context, ownerID uint) ([]models.Professional, error)
+	GetOwnerProfessional(ctx context.Context, ownerID, professionalID uint) (*models.Professional, error)
+	GetWorkingHours(ctx context.Context, professionalID uint) ([]models.ProfessionalWorkingHour, error)
+	ReplaceWorkingHours(ctx context.Context, professionalID uint, entries []models.ProfessionalWorkingHour, worksOnHolidays bool) error
+	GetTimeOffs(ctx context.Context, professionalID uint, from, to time.Time) ([]models.ProfessionalTimeOff, error)
+	CreateTimeOff(ctx context.Context, timeOff *models.ProfessionalTimeOff) error
+	DeleteTimeOff(ctx context.Context, professionalID, id uint) error
+}
+
+type ScheduleRepository struct {
+	db *gorm.DB
+}
+
+func NewScheduleRepository() IScheduleRepository {
+	return &ScheduleRepository{db: databaseconfig.GetDB()}
+}
+
+// GetOwnerProfessionals, kullanıcının işletmelerine bağlı uzmanları işletmesiyle birlikte döner.
+func (r *ScheduleRepository) GetOwnerProfessionals(ctx context.Context, ownerID uint) ([]models.Professional, error) {
+	var professionals []models.Professional
+	err := r.db.WithContext(ctx).
+		Joins("Business").
+		Where("\"Business\".user_id = ?", ownerID).
+		Order("\"Business\".title asc, professionals.title asc").
+		Find(&professionals).Error
+	return professionals, err
+}
+
+func (r *ScheduleRepository) GetOwnerProfessional(ctx context.Context, ownerID, professionalID uint) (*models.Professional, error) {
+	var professional models.Professional
+	err := r.db.WithContext(ctx).
+		Joins("Business").
+		Where("professionals.id = ? AND \"Business\".user_id = ?", professionalID, ownerID).
+		First(&professional).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &professional, nil
+}
+
+func (r *ScheduleRepository) GetWorkingHours(ctx context.Context, professionalID uint) ([]models.ProfessionalWorkingHour, error) {
+	var entries []models.ProfessionalWorkingHour
+	err := r.db.WithContext(ctx).
+		Where("professional_id = ?", professionalID).
+		Order("weekday asc, is_break asc, start_time asc").
+		Find(&entries).Error
+	return entries, err
+}
+
+// ReplaceWorkingHours, haftalık takvimi tek transaction içinde baştan yazar.
+// Eski satırlar geçmiş değeri taşımadığı için kalıcı olarak silinir.
+func (r *ScheduleRepository) ReplaceWorkingHours(ctx context.Context, professionalID uint, entries []models.ProfessionalWorkingHour, worksOnHolidays bool) error {
+	professionalData := map[string]interface{}{"works_on_holidays": worksOnHolidays}
+	if uid, ok := ctx.Value(currentuser.ContextUserIDKey).(uint); ok && uid > 0 {
+		professionalData["updated_by"] = uid
+	}
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Where("professional_id = ?", professionalID).
+			Delete(&models.ProfessionalWorkingHour{}).Error; err != nil {
+			return err
+		}
+		// Toplu Create yerine tek tek: BaseModel callback'i slice üzerinde çalışmaz
+		for i := range entries {
+			entries[i].ProfessionalID = professionalID
+			if err := tx.Omit(clause.Associations).Create(&entries[i]).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Model(&models.Professional{}).Where("id = ?", professionalID).
+			Updates(professionalData).Error
+	})
+}
+
+// GetTimeOffs, [from, to) aralığıyla kesişen izinleri döner.
+func (r *ScheduleRepository) GetTimeOffs(ctx context.Context, professionalID uint, from, to time.Time) ([]models.ProfessionalTimeOff, error) {
+	var items []models.ProfessionalTimeOff
+	err := r.db.WithContext(ctx).
+		Where("professional_id = ? AND starts_at < ? AND ends_at > ?", professionalID, to, from).
+		Order("starts_at asc").
+		Find(&items).Error
+	return items, err
+}
+
+func (r *ScheduleRepository) CreateTimeOff(ctx context.Context, timeOff *models.ProfessionalTimeOff) error {
+	return r.db.WithContext(ctx).Omit(clause.Associations).Create(timeOff).Error
+}
+
+func (r *ScheduleRepository) DeleteTimeOff(ctx context.Context, professionalID, id uint) error {
+	userID, ok := ctx.Value(currentuser.ContextUserIDKey).(uint)
+	if !ok || userID == 0 {
+		return ErrMissingUserID
+	}
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.ProfessionalTimeOff{}).
+			Where("id = ? AND professional_id = ?", id, professionalID).
+			Update("deleted_by", userID).Error; err != nil {
+			return err
+		}
+		result := tx.Where("id = ? AND professional_id = ?", id, professionalID).Delete(&models.ProfessionalTimeOff{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrNotFound
+		}
+		return nil
+	})
+}
+
+var _ IScheduleRepository = (*ScheduleRepository)(nil)
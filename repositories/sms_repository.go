@@ -0,0 +1,115 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"zatrano/configs/databaseconfig"
+	"zatrano/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrSmsQuotaExceeded, ayırılmak istenen segmentler kullanıcının aylık kotasını aştığında döner.
+var ErrSmsQuotaExceeded = errors.New("sms kotası aşıldı")
+
+type ISmsRepository interface {
+	CreateMessage(ctx context.Context, msg *models.SmsMessage) error
+	ReserveMessage(ctx context.Context, msg *models.SmsMessage, since time.Time, quota int) error
+	UpdateMessage(ctx context.Context, id uint, data map[string]interface{}) error
+	// AdvanceStatus, mesajı yalnızca durumu from listesindeyse günceller; koşul tek UPDATE içinde
+	// denetlendiği için eşzamanlı raporlar birbirini ezemez. Mesaj güncellenmediyse false döner.
+	AdvanceStatus(ctx context.Context, id uint, from []string, data map[string]interface{}) (bool, error)
+	FindByProviderMessageID(ctx context.Context, provider, providerMessageID string) (*models.SmsMessage, error)
+	SumUserSegmentsSince(ctx context.Context, userID uint, since time.Time) (int64, error)
+}
+
+type SmsRepository struct {
+	base IBaseRepository[models.SmsMessage]
+	db   *gorm.DB
+}
+
+func NewSmsRepository() ISmsRepository {
+	base := NewBaseRepository[models.SmsMessage](databaseconfig.GetDB())
+	return &SmsRepository{base: base, db: databaseconfig.GetDB()}
+}
+
+func (r *SmsRepository) CreateMessage(ctx context.Context, msg *models.SmsMessage) error {
+	return r.base.Create(ctx, msg)
+}
+
+// ReserveMessage, kullanıcı satırını FOR UPDATE ile kilitleyip kotayı aynı transaction içinde kontrol eder ve
+// mesajı kuyruğa yazar; kuyruktaki mesaj da harcanmış sayıldığından eşzamanlı gönderimler kotayı aşamaz.
+func (r *SmsRepository) ReserveMessage(ctx context.Context, msg *models.SmsMessage, since time.Time, quota int) error {
+	if msg.UserID == nil {
+		return r.CreateMessage(ctx, msg)
+	}
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var user models.User
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("id").
+			Where("id = ?", *msg.UserID).
+			First(&user).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrNotFound
+		}
+		if err != nil {
+			return err
+		}
+
+		used, err := sumUserSegmentsSince(tx, *msg.UserID, since)
+		if err != nil {
+			return err
+		}
+		if used+int64(msg.Segments) > int64(quota) {
+			return ErrSmsQuotaExceeded
+		}
+		return tx.Omit(clause.Associations).Create(msg).Error
+	})
+}
+
+func (r *SmsRepository) UpdateMessage(ctx context.Context, id uint, data map[string]interface{}) error {
+	return r.base.Update(ctx, id, data)
+}
+
+func (r *SmsRepository) AdvanceStatus(ctx context.Context, id uint, from []string, data map[string]interface{}) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&models.SmsMessage{}).
+		Where("id = ? AND status IN ?", id, from).
+		Updates(data)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *SmsRepository) FindByProviderMessageID(ctx context.Context, provider, providerMessageID string) (*models.SmsMessage, error) {
+	var msg models.SmsMessage
+	err := r.db.WithContext(ctx).
+		Where("provider = ? AND provider_message_id = ?", provider, providerMessageID).
+		First(&msg).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &msg, nil
+}
+
+// SumUserSegmentsSince — başarısız olanlar hariç, kullanıcının harcadığı segment sayısı
+func (r *SmsRepository) SumUserSegmentsSince(ctx context.Context, userID uint, since time.Time) (int64, error) {
+	return sumUserSegmentsSince(r.db.WithContext(ctx), userID, since)
+}
+
+func sumUserSegmentsSince(db *gorm.DB, userID uint, since time.Time) (int64, error) {
+	var total int64
+	err := db.Model(&models.SmsMessage{}).
+		Where("user_id = ? AND created_at >= ? AND status <> ?", userID, since, models.SmsStatusFailed).
+		Select("COALESCE(SUM(segments), 0)").
+		Scan(&total).Error
+	return total, err
+}
+
+var _ ISmsRepository = (*SmsRepository)(nil)
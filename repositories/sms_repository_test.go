@@ -0,0 +1,105 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"zatrano/models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func newSmsMockRepository(t *testing.T) (*SmsRepository, sqlmock.Sqlmock) {
+	t.Helper()
+	conn, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: conn}), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &SmsRepository{base: NewBaseRepository[models.SmsMessage](db), db: db}, mock
+}
+
+// expectQuotaCheck, kullanıcı satırının kilitlenmesini ve kota toplamının aynı transaction içinde okunmasını bekler.
+func expectQuotaCheck(mock sqlmock.Sqlmock, userID uint, used int64) {
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT "id" FROM "users" WHERE id = $1 AND "users"."deleted_at" IS NULL ORDER BY "users"."id" LIMIT $2 FOR UPDATE`)).
+		WithArgs(userID, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(userID))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT COALESCE(SUM(segments), 0) FROM "sms_messages" WHERE (user_id = $1 AND created_at >= $2 AND status <> $3)`)).
+		WithArgs(userID, sqlmock.AnyArg(), models.SmsStatusFailed).
+		WillReturnRows(sqlmock.NewRows([]string{"coalesce"}).AddRow(used))
+}
+
+func newQueuedSms(userID uint, segments uint) *models.SmsMessage {
+	return &models.SmsMessage{
+		UserID:   &userID,
+		To:       "+905321234567",
+		Body:     "Randevunuz onaylandı.",
+		Provider: "log",
+		Encoding: "GSM7",
+		Segments: segments,
+		Status:   models.SmsStatusQueued,
+	}
+}
+
+func TestReserveMessageLocksUserBeforeCheckingQuota(t *testing.T) {
+	repo, mock := newSmsMockRepository(t)
+	expectQuotaCheck(mock, 7, 8)
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "sms_messages"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectCommit()
+
+	msg := newQueuedSms(7, 2)
+	if err := repo.ReserveMessage(context.Background(), msg, time.Now().AddDate(0, -1, 0), 10); err != nil {
+		t.Fatalf("ReserveMessage: %v", err)
+	}
+	if msg.ID != 1 {
+		t.Fatalf("mesaj kimliği = %d", msg.ID)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestReserveMessageRejectsOverQuota(t *testing.T) {
+	repo, mock := newSmsMockRepository(t)
+	expectQuotaCheck(mock, 7, 9)
+	mock.ExpectRollback()
+
+	err := repo.ReserveMessage(context.Background(), newQueuedSms(7, 2), time.Now().AddDate(0, -1, 0), 10)
+	if !errors.Is(err, ErrSmsQuotaExceeded) {
+		t.Fatalf("hata = %v, beklenen %v", err, ErrSmsQuotaExceeded)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestAdvanceStatusOnlyFromEarlierStatuses(t *testing.T) {
+	repo, mock := newSmsMockRepository(t)
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "sms_messages" SET "status"=$1,"updated_at"=$2 WHERE (id = $3 AND status IN ($4,$5)) AND "sms_messages"."deleted_at" IS NULL`)).
+		WithArgs(models.SmsStatusSent, sqlmock.AnyArg(), 3, models.SmsStatusQueued, models.SmsStatusSent).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	advanced, err := repo.AdvanceStatus(context.Background(), 3,
+		[]string{models.SmsStatusQueued, models.SmsStatusSent},
+		map[string]interface{}{"status": models.SmsStatusSent})
+	if err != nil || advanced {
+		t.Fatalf("AdvanceStatus = %v, %v; satır güncellenmemişken false beklendi", advanced, err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
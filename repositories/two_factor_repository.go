@@ -0,0 +1,154 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"zatrano/configs/databaseconfig"
+	"zatrano/models"
+
+	"gorm.io/gorm"
+)
+
+type ITwoFactorRepository interface {
+	// Enable, anahtarı kaydeder ve kurtarma kodlarını aynı transaction içinde yeniler.
+	Enable(ctx context.Context, userID uint, secret string, step int64, codeHashes []string) error
+	// Disable, anahtarı, kurtarma kodlarını ve güvenilen cihazları siler.
+	Disable(ctx context.Context, userID uint) error
+	// AdvanceStep, son kabul edilen zaman adımını ileri alır; adım daha önce kullanıldıysa false döner.
+	AdvanceStep(ctx context.Context, userID uint, step int64) (bool, error)
+	ReplaceRecoveryCodes(ctx context.Context, userID uint, codeHashes []string) error
+	// UseRecoveryCode, kullanılmamış kodu tek seferlik olarak işaretler; kod yoksa false döner.
+	UseRecoveryCode(ctx context.Context, userID uint, codeHash string) (bool, error)
+	CountUnusedRecoveryCodes(ctx context.Context, userID uint) (int64, error)
+	CreateTrustedDevice(ctx context.Context, device *models.TrustedDevice) error
+	FindTrustedDevice(ctx context.Context, userID uint, tokenHash string) (*models.TrustedDevice, error)
+	TouchTrustedDevice(ctx context.Context, id uint) error
+	CountTrustedDevices(ctx context.Context, userID uint) (int64, error)
+	DeleteTrustedDevices(ctx context.Context, userID uint) error
+}
+
+type TwoFactorRepository struct {
+	db *gorm.DB
+}
+
+func NewTwoFactorRepository() ITwoFactorRepository {
+	return &TwoFactorRepository{db: databaseconfig.GetDB()}
+}
+
+func recoveryCodeRows(userID uint, codeHashes []string) []models.TwoFactorRecoveryCode {
+	rows := make([]models.TwoFactorRecoveryCode, 0, len(codeHashes))
+	for _, h := range codeHashes {
+		rows = append(rows, models.TwoFactorRecoveryCode{UserID: userID, CodeHash: h})
+	}
+	return rows
+}
+
+func (r *TwoFactorRepository) Enable(ctx context.Context, userID uint, secret string, step int64, codeHashes []string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		if err := tx.Model(&models.User{}).Where("id = ?", userID).Updates(map[string]interface{}{
+			"two_factor_secret":     secret,
+			"two_factor_enabled_at": now,
+			"two_factor_last_step":  step,
+		}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id = ?", userID).Delete(&models.TwoFactorRecoveryCode{}).Error; err != nil {
+			return err
+		}
+		return tx.Create(recoveryCodeRows(userID, codeHashes)).Error
+	})
+}
+
+func (r *TwoFactorRepository) Disable(ctx context.Context, userID uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.User{}).Where("id = ?", userID).Updates(map[string]interface{}{
+			"two_factor_secret":     "",
+			"two_factor_enabled_at": nil,
+			"two_factor_last_step":  0,
+		}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id = ?", userID).Delete(&models.TwoFactorRecoveryCode{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("user_id = ?", userID).Delete(&models.TrustedDevice{}).Error
+	})
+}
+
+func (r *TwoFactorRepository) AdvanceStep(ctx context.Context, userID uint, step int64) (bool, error) {
+	res := r.db.WithContext(ctx).Model(&models.User{}).
+		Where("id = ? AND two_factor_last_step < ?", userID, step).
+		Update("two_factor_last_step", step)
+	if res.Error != nil {
+		return false, res.Error
+	}
+	return res.RowsAffected == 1, nil
+}
+
+func (r *TwoFactorRepository) ReplaceRecoveryCodes(ctx context.Context, userID uint, codeHashes []string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&models.TwoFactorRecoveryCode{}).Error; err != nil {
+			return err
+		}
+		return tx.Create(recoveryCodeRows(userID, codeHashes)).Error
+	})
+}
+
+func (r *TwoFactorRepository) UseRecoveryCode(ctx context.Context, userID uint, codeHash string) (bool, error) {
+	res := r.db.WithContext(ctx).Model(&models.TwoFactorRecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, codeHash).
+		Update("used_at", time.Now())
+	if res.Error != nil {
+		return false, res.Error
+	}
+	return res.RowsAffected > 0, nil
+}
+
+func (r *TwoFactorRepository) CountUnusedRecoveryCodes(ctx context.Context, userID uint) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.TwoFactorRecoveryCode{}).
+		Where("user_id = ? AND used_at IS NULL", userID).
+		Count(&count).Error
+	return count, err
+}
+
+func (r *TwoFactorRepository) CreateTrustedDevice(ctx context.Context, device *models.TrustedDevice) error {
+	return r.db.WithContext(ctx).Create(device).Error
+}
+
+func (r *TwoFactorRepository) FindTrustedDevice(ctx context.Context, userID uint, tokenHash string) (*models.TrustedDevice, error) {
+	var device models.TrustedDevice
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND token_hash = ? AND expires_at > ?", userID, tokenHash, time.Now()).
+		First(&device).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &device, nil
+}
+
+func (r *TwoFactorRepository) TouchTrustedDevice(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Model(&models.TrustedDevice{}).
+		Where("id = ?", id).
+		Update("last_used_at", time.Now()).Error
+}
+
+func (r *TwoFactorRepository) CountTrustedDevices(ctx context.Context, userID uint) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.TrustedDevice{}).
+		Where("user_id = ? AND expires_at > ?", userID, time.Now()).
+		Count(&count).Error
+	return count, err
+}
+
+func (r *TwoFactorRepository) DeleteTrustedDevices(ctx context.Context, userID uint) error {
+	return r.db.WithContext(ctx).Where("user_id = ?", userID).Delete(&models.TrustedDevice{}).Error
+}
+
+var _ ITwoFactorRepository = (*TwoFactorRepository)(nil)
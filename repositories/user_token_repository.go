@@ -0,0 +1,84 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"zatrano/configs/databaseconfig"
+	"zatrano/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type IUserTokenRepository interface {
+	// Create, kullanıcının aynı amaçla verilmiş kullanılmamış anahtarlarını silip yenisini ekler;
+	// böylece yalnızca en son gönderilen bağlantı geçerli kalır.
+	Create(ctx context.Context, token *models.UserToken) error
+	// FindValid, kullanılmamış ve süresi dolmamış anahtarı döner.
+	FindValid(ctx context.Context, purpose, tokenHash string, now time.Time) (*models.UserToken, error)
+	// Consume, anahtarı tek sorguda kullanılmış olarak işaretler; anahtar geçersizse ya da browserHash
+	// anahtarın bağlı olduğu tarayıcıyla eşleşmiyorsa ErrNotFound döner.
+	// Aynı bağlantıyla eş zamanlı iki istekten yalnızca biri başarılı olur.
+	Consume(ctx context.Context, purpose, tokenHash, browserHash, userAgent, ip string, now time.Time) (*models.UserToken, error)
+	DeleteExpired(ctx context.Context, now time.Time) (int64, error)
+}
+
+type UserTokenRepository struct {
+	db *gorm.DB
+}
+
+func NewUserTokenRepository() IUserTokenRepository {
+	return &UserTokenRepository{db: databaseconfig.GetDB()}
+}
+
+func (r *UserTokenRepository) Create(ctx context.Context, token *models.UserToken) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ? AND purpose = ? AND used_at IS NULL", token.UserID, token.Purpose).
+			Delete(&models.UserToken{}).Error; err != nil {
+			return err
+		}
+		return tx.Create(token).Error
+	})
+}
+
+func (r *UserTokenRepository) FindValid(ctx context.Context, purpose, tokenHash string, now time.Time) (*models.UserToken, error) {
+	var token models.UserToken
+	err := r.db.WithContext(ctx).
+		Where("purpose = ? AND token_hash = ? AND used_at IS NULL AND expires_at > ?", purpose, tokenHash, now).
+		First(&token).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+func (r *UserTokenRepository) Consume(ctx context.Context, purpose, tokenHash, browserHash, userAgent, ip string, now time.Time) (*models.UserToken, error) {
+	var tokens []models.UserToken
+	result := r.db.WithContext(ctx).Model(&tokens).
+		Clauses(clause.Returning{}).
+		Where("purpose = ? AND token_hash = ? AND browser_hash = ? AND used_at IS NULL AND expires_at > ?", purpose, tokenHash, browserHash, now).
+		Updates(map[string]interface{}{
+			"used_at":         now,
+			"used_ip":         ip,
+			"used_user_agent": userAgent,
+		})
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 || len(tokens) == 0 {
+		return nil, ErrNotFound
+	}
+	return &tokens[0], nil
+}
+
+func (r *UserTokenRepository) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).Where("expires_at <= ?", now).Delete(&models.UserToken{})
+	return result.RowsAffected, result.Error
+}
+
+var _ IUserTokenRepository = (*UserTokenRepository)(nil)
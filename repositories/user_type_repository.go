@@ -2,9 +2,11 @@ package repositories
 
 import (
 	"context"
+	"errors"
 
 	"zatrano/configs/databaseconfig"
 	"zatrano/models"
+	"zatrano/pkg/currentuser"
 	"zatrano/requests"
 
 	"gorm.io/gorm"
@@ -13,8 +15,10 @@ import (
 type IUserTypeRepository interface {
 	GetAllUserTypes(ctx context.Context, params requests.UserTypeListParams) ([]models.UserType, int64, error)
 	GetUserTypeByID(ctx context.Context, id uint) (*models.UserType, error)
+	GetUserTypeByName(ctx context.Context, name string) (*models.UserType, error)
+	GetUserTypeOptions(ctx context.Context) ([]models.UserType, error)
 	CreateUserType(ctx context.Context, u *models.UserType) error
-	UpdateUserType(ctx context.Context, id uint, data map[string]interface{}) error
+	UpdateUserType(ctx context.Context, id uint, data map[string]interface{}, permissions []string) error
 	DeleteUserType(ctx context.Context, id uint) error
 }
 
@@ -26,6 +30,7 @@ type UserTypeRepository struct {
 func NewUserTypeRepository() IUserTypeRepository {
 	base := NewBaseRepository[models.UserType](databaseconfig.GetDB())
 	base.SetAllowedSortColumns([]string{"id", "name"})
+	base.SetPreloads("Permissions")
 	return &UserTypeRepository{base: base, db: databaseconfig.GetDB()}
 }
 
@@ -63,7 +68,7 @@ func (r *UserTypeRepository) GetAllUserTypes(ctx context.Context, params request
 
 	// Pagination
 	offset := params.CalculateOffset()
-	query = query.Limit(params.PerPage).Offset(offset)
+	query = query.Limit(params.PerPage).Offset(offset).Preload("Permissions")
 
 	// Find
 	if err := query.Find(&userTypes).Error; err != nil {
@@ -77,12 +82,54 @@ func (r *UserTypeRepository) GetUserTypeByID(ctx context.Context, id uint) (*mod
 	return r.base.GetByID(ctx, id)
 }
 
+func (r *UserTypeRepository) GetUserTypeByName(ctx context.Context, name string) (*models.UserType, error) {
+	var userType models.UserType
+	err := r.db.WithContext(ctx).Where("name = ?", name).First(&userType).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &userType, nil
+}
+
+func (r *UserTypeRepository) GetUserTypeOptions(ctx context.Context) ([]models.UserType, error) {
+	var userTypes []models.UserType
+	err := r.db.WithContext(ctx).Order("name ASC").Find(&userTypes).Error
+	return userTypes, err
+}
+
 func (r *UserTypeRepository) CreateUserType(ctx context.Context, u *models.UserType) error {
 	return r.base.Create(ctx, u)
 }
 
-func (r *UserTypeRepository) UpdateUserType(ctx context.Context, id uint, data map[string]interface{}) error {
-	return r.base.Update(ctx, id, data)
+// UpdateUserType, rol bilgilerini ve yetki listesini tek işlemde günceller; yetkiler verilen listeyle değiştirilir.
+func (r *UserTypeRepository) UpdateUserType(ctx context.Context, id uint, data map[string]interface{}, permissions []string) error {
+	if uid, ok := ctx.Value(currentuser.ContextUserIDKey).(uint); ok && uid > 0 {
+		data["updated_by"] = uid
+	}
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.UserType{}).Where("id = ?", id).Updates(data)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrNotFound
+		}
+
+		if err := tx.Where("user_type_id = ?", id).Delete(&models.UserTypePermission{}).Error; err != nil {
+			return err
+		}
+		for _, permission := range permissions {
+			row := models.UserTypePermission{UserTypeID: id, Permission: permission}
+			if err := tx.Create(&row).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
 }
 
 func (r *UserTypeRepository) DeleteUserType(ctx context.Context, id uint) error {
@@ -0,0 +1,167 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"zatrano/configs/databaseconfig"
+	"zatrano/models"
+	"zatrano/pkg/currentuser"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrVenueSessionTaken, seçilen tarih ve seans başka bir rezervasyon tarafından tutuluyorsa döner.
+var ErrVenueSessionTaken = errors.New("seçilen tarih ve seans dolu")
+
+type IVenueReservationRepository interface {
+	GetReservations(ctx context.Context, businessID uint, from, to time.Time) ([]models.VenueReservation, error)
+	GetOwnerReservation(ctx context.Context, ownerID, id uint) (*models.VenueReservation, error)
+	// Create, mekan satırını kilitleyip seans çakışmasını aynı transaction içinde kontrol eder.
+	Create(ctx context.Context, reservation *models.VenueReservation, now time.Time) error
+	// Confirm, süresi geçmiş bir opsiyon onaylanırken tarihin hâlâ boş olduğunu da doğrular.
+	Confirm(ctx context.Context, reservation *models.VenueReservation, now time.Time) error
+	UpdateStatus(ctx context.Context, id uint, fromStatuses []string, data map[string]interface{}) error
+	// ExpireOptions, süresi dolmuş opsiyonları expired durumuna çeker.
+	ExpireOptions(ctx context.Context, businessID uint, now time.Time) error
+}
+
+type VenueReservationRepository struct {
+	db *gorm.DB
+}
+
+func NewVenueReservationRepository() IVenueReservationRepository {
+	return &VenueReservationRepository{db: databaseconfig.GetDB()}
+}
+
+func (r *VenueReservationRepository) GetReservations(ctx context.Context, businessID uint, from, to time.Time) ([]models.VenueReservation, error) {
+	var items []models.VenueReservation
+	err := r.db.WithContext(ctx).
+		Where("business_id = ? AND date >= ? AND date < ?", businessID, from.Format("2006-01-02"), to.Format("2006-01-02")).
+		Order("date asc, session asc, id asc").
+		Find(&items).Error
+	return items, err
+}
+
+// GetOwnerReservation, rezervasyonu yalnızca mekanın sahibine döner.
+func (r *VenueReservationRepository) GetOwnerReservation(ctx context.Context, ownerID, id uint) (*models.VenueReservation, error) {
+	var reservation models.VenueReservation
+	err := r.db.WithContext(ctx).Model(&models.VenueReservation{}).
+		Select("venue_reservations.*").
+		Preload("Business.Address.Country").
+		Preload("Business.Address.City").
+		Preload("Business.Address.District").
+		Joins("JOIN businesses ON businesses.id = venue_reservations.business_id AND businesses.deleted_at IS NULL").
+		Where("venue_reservations.id = ? AND businesses.user_id = ?", id, ownerID).
+		First(&reservation).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &reservation, nil
+}
+
+func (r *VenueReservationRepository) Create(ctx context.Context, reservation *models.VenueReservation, now time.Time) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockBusiness(tx, reservation.BusinessID); err != nil {
+			return err
+		}
+		if err := ensureVenueFree(tx, reservation, now); err != nil {
+			return err
+		}
+		return tx.Omit(clause.Associations).Create(reservation).Error
+	})
+}
+
+func (r *VenueReservationRepository) Confirm(ctx context.Context, reservation *models.VenueReservation, now time.Time) error {
+	data := map[string]interface{}{
+		"status":            models.VenueReservationStatusConfirmed,
+		"confirmed_at":      now,
+		"option_expires_at": nil,
+	}
+	if uid, ok := ctx.Value(currentuser.ContextUserIDKey).(uint); ok && uid > 0 {
+		data["updated_by"] = uid
+	}
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockBusiness(tx, reservation.BusinessID); err != nil {
+			return err
+		}
+		if err := ensureVenueFree(tx, reservation, now); err != nil {
+			return err
+		}
+		result := tx.Model(&models.VenueReservation{}).
+			Where("id = ? AND status IN ?", reservation.ID, []string{models.VenueReservationStatusOption, models.VenueReservationStatusExpired}).
+			Updates(data)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrNotFound
+		}
+		return nil
+	})
+}
+
+func (r *VenueReservationRepository) UpdateStatus(ctx context.Context, id uint, fromStatuses []string, data map[string]interface{}) error {
+	if uid, ok := ctx.Value(currentuser.ContextUserIDKey).(uint); ok && uid > 0 {
+		data["updated_by"] = uid
+	}
+	result := r.db.WithContext(ctx).Model(&models.VenueReservation{}).
+		Where("id = ? AND status IN ?", id, fromStatuses).
+		Updates(data)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *VenueReservationRepository) ExpireOptions(ctx context.Context, businessID uint, now time.Time) error {
+	return r.db.WithContext(ctx).Model(&models.VenueReservation{}).
+		Where("business_id = ? AND status = ? AND option_expires_at <= ?", businessID, models.VenueReservationStatusOption, now).
+		UpdateColumn("status", models.VenueReservationStatusExpired).Error
+}
+
+func lockBusiness(tx *gorm.DB, businessID uint) error {
+	var business models.Business
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Select("id").
+		Where("id = ?", businessID).
+		First(&business).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrNotFound
+	}
+	return err
+}
+
+// ensureVenueFree, aynı gün ve seansı kapatan kesin rezervasyon ya da süresi dolmamış opsiyon varsa hata döner.
+// Tam gün rezervasyonu her iki seansla da çakışır.
+func ensureVenueFree(tx *gorm.DB, reservation *models.VenueReservation, now time.Time) error {
+	query := tx.Model(&models.VenueReservation{}).
+		Where("business_id = ? AND date = ?", reservation.BusinessID, reservation.Date.Format("2006-01-02")).
+		Where("(status = ? OR (status = ? AND option_expires_at > ?))",
+			models.VenueReservationStatusConfirmed, models.VenueReservationStatusOption, now)
+	if reservation.Session != models.VenueSessionFullDay {
+		query = query.Where("session IN ?", []string{reservation.Session, models.VenueSessionFullDay})
+	}
+	if reservation.ID != 0 {
+		query = query.Where("id <> ?", reservation.ID)
+	}
+
+	var count int64
+	if err := query.Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrVenueSessionTaken
+	}
+	return nil
+}
+
+var _ IVenueReservationRepository = (*VenueReservationRepository)(nil)
@@ -0,0 +1,74 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"zatrano/configs/databaseconfig"
+	"zatrano/models"
+
+	"gorm.io/gorm"
+)
+
+type IWebAuthnCredentialRepository interface {
+	ListByUser(ctx context.Context, userID uint) ([]models.WebAuthnCredential, error)
+	FindByCredentialID(ctx context.Context, credentialID []byte) (*models.WebAuthnCredential, error)
+	Create(ctx context.Context, credential *models.WebAuthnCredential) error
+	// RecordUse, başarılı girişten sonra imza sayacını, yedekleme durumunu ve kopya uyarısını kaydeder.
+	RecordUse(ctx context.Context, id uint, signCount uint32, backupState, cloneWarning bool, at time.Time) error
+	Delete(ctx context.Context, userID, id uint) error
+}
+
+type WebAuthnCredentialRepository struct {
+	db *gorm.DB
+}
+
+func NewWebAuthnCredentialRepository() IWebAuthnCredentialRepository {
+	return &WebAuthnCredentialRepository{db: databaseconfig.GetDB()}
+}
+
+func (r *WebAuthnCredentialRepository) ListByUser(ctx context.Context, userID uint) ([]models.WebAuthnCredential, error) {
+	var credentials []models.WebAuthnCredential
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("created_at asc").Find(&credentials).Error
+	return credentials, err
+}
+
+func (r *WebAuthnCredentialRepository) FindByCredentialID(ctx context.Context, credentialID []byte) (*models.WebAuthnCredential, error) {
+	var credential models.WebAuthnCredential
+	err := r.db.WithContext(ctx).Where("credential_id = ?", credentialID).First(&credential).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &credential, nil
+}
+
+func (r *WebAuthnCredentialRepository) Create(ctx context.Context, credential *models.WebAuthnCredential) error {
+	return r.db.WithContext(ctx).Create(credential).Error
+}
+
+func (r *WebAuthnCredentialRepository) RecordUse(ctx context.Context, id uint, signCount uint32, backupState, cloneWarning bool, at time.Time) error {
+	return r.db.WithContext(ctx).Model(&models.WebAuthnCredential{}).Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"sign_count":    signCount,
+			"backup_state":  backupState,
+			"clone_warning": cloneWarning,
+			"last_used_at":  at,
+		}).Error
+}
+
+func (r *WebAuthnCredentialRepository) Delete(ctx context.Context, userID, id uint) error {
+	result := r.db.WithContext(ctx).Where("user_id = ? AND id = ?", userID, id).Delete(&models.WebAuthnCredential{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+var _ IWebAuthnCredentialRepository = (*WebAuthnCredentialRepository)(nil)
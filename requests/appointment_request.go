@@ -0,0 +1,230 @@
+package requests
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// AppointmentStartLayout, formlarda ve slot listesinde kullanılan yerel saat biçimidir.
+const AppointmentStartLayout = "2006-01-02T15:04"
+
+type AppointmentRequest struct {
+	ProfessionalServiceID string `form:"professional_service_id" validate:"required,numeric"`
+	StartsAt              string `form:"starts_at" validate:"required,datetime=2006-01-02T15:04"`
+	CustomerName          string `form:"customer_name" validate:"required,min=2,max=100"`
+	CustomerPhone         string `form:"customer_phone" validate:"required,min=10,max=20"`
+	CustomerEmail         string `form:"customer_email" validate:"omitempty,email,max=100"`
+	Note                  string `form:"note" validate:"omitempty,max=500"`
+}
+
+func ParseAndValidateAppointmentRequest(c *fiber.Ctx) (AppointmentRequest, map[string]string, error) {
+	var req AppointmentRequest
+
+	if err := c.BodyParser(&req); err != nil {
+		return req, make(map[string]string), errors.New("geçersiz istek formatı")
+	}
+	for _, f := range []*string{&req.StartsAt, &req.CustomerName, &req.CustomerPhone, &req.CustomerEmail, &req.Note} {
+		*f = strings.TrimSpace(*f)
+	}
+
+	validate := newValidator()
+	if err := validate.Struct(req); err != nil {
+		validationErrors := GetAppointmentValidationErrors(err)
+		return req, validationErrors, errors.New("lütfen formdaki hataları düzeltin")
+	}
+
+	return req, make(map[string]string), nil
+}
+
+func (r *AppointmentRequest) ProfessionalServiceIDUint() uint {
+	return parseUint(r.ProfessionalServiceID)
+}
+
+type AppointmentRescheduleRequest struct {
+	StartsAt string `form:"starts_at" validate:"required,datetime=2006-01-02T15:04"`
+}
+
+func ParseAndValidateAppointmentReschedule(c *fiber.Ctx) (AppointmentRescheduleRequest, map[string]string, error) {
+	var req AppointmentRescheduleRequest
+
+	if err := c.BodyParser(&req); err != nil {
+		return req, make(map[string]string), errors.New("geçersiz istek formatı")
+	}
+	req.StartsAt = strings.TrimSpace(req.StartsAt)
+
+	validate := newValidator()
+	if err := validate.Struct(req); err != nil {
+		validationErrors := GetAppointmentValidationErrors(err)
+		return req, validationErrors, errors.New("lütfen yeni bir saat seçin")
+	}
+
+	return req, make(map[string]string), nil
+}
+
+type AppointmentCancelRequest struct {
+	Reason string `form:"reason" validate:"omitempty,max=255"`
+}
+
+func ParseAndValidateAppointmentCancel(c *fiber.Ctx) (AppointmentCancelRequest, map[string]string, error) {
+	var req AppointmentCancelRequest
+
+	if err := c.BodyParser(&req); err != nil {
+		return req, make(map[string]string), errors.New("geçersiz istek formatı")
+	}
+	req.Reason = strings.TrimSpace(req.Reason)
+
+	validate := newValidator()
+	if err := validate.Struct(req); err != nil {
+		validationErrors := GetAppointmentValidationErrors(err)
+		return req, validationErrors, errors.New("iptal nedeni en fazla 255 karakter olabilir")
+	}
+
+	return req, make(map[string]string), nil
+}
+
+type AppointmentSlotsRequest struct {
+	ProfessionalServiceID string `query:"professional_service_id" validate:"required,numeric"`
+	Date                  string `query:"date" validate:"required,datetime=2006-01-02"`
+}
+
+func ParseAndValidateAppointmentSlots(c *fiber.Ctx) (AppointmentSlotsRequest, map[string]string, error) {
+	var req AppointmentSlotsRequest
+
+	if err := c.QueryParser(&req); err != nil {
+		return req, make(map[string]string), errors.New("geçersiz sorgu parametreleri")
+	}
+
+	validate := newValidator()
+	if err := validate.Struct(req); err != nil {
+		validationErrors := GetAppointmentValidationErrors(err)
+		return req, validationErrors, errors.New("hizmet ve tarih seçilmelidir")
+	}
+
+	return req, make(map[string]string), nil
+}
+
+func (r *AppointmentSlotsRequest) ProfessionalServiceIDUint() uint {
+	return parseUint(r.ProfessionalServiceID)
+}
+
+type AppointmentListRequest struct {
+	BusinessID string `query:"business_id" validate:"omitempty,numeric"`
+	Status     string `query:"status" validate:"omitempty,oneof=pending confirmed cancelled"`
+	Date       string `query:"date" validate:"omitempty,datetime=2006-01-02"`
+	OrderBy    string `query:"orderBy" validate:"omitempty,oneof=asc desc"`
+	Page       string `query:"page" validate:"omitempty,numeric,min=1"`
+	PerPage    string `query:"perPage" validate:"omitempty,numeric,min=1,max=200"`
+}
+
+type AppointmentListParams struct {
+	BusinessID uint
+	Status     string
+	Date       string
+	From       time.Time
+	To         time.Time
+	OrderBy    string
+	Page       int
+	PerPage    int
+}
+
+// ToServiceParams, Date alanını verilen saat dilimindeki günün [From, To) aralığına çevirir.
+func (r *AppointmentListRequest) ToServiceParams(loc *time.Location) AppointmentListParams {
+	params := AppointmentListParams{
+		BusinessID: parseUint(r.BusinessID),
+		Status:     strings.TrimSpace(r.Status),
+		Date:       strings.TrimSpace(r.Date),
+		OrderBy:    strings.TrimSpace(r.OrderBy),
+	}
+
+	if params.Date != "" {
+		if day, err := time.ParseInLocation("2006-01-02", params.Date, loc); err == nil {
+			params.From = day
+			params.To = day.AddDate(0, 0, 1)
+		}
+	}
+
+	if r.Page != "" {
+		if page, err := strconv.Atoi(r.Page); err == nil && page > 0 {
+			params.Page = page
+		}
+	}
+
+	if r.PerPage != "" {
+		if perPage, err := strconv.Atoi(r.PerPage); err == nil && perPage > 0 {
+			params.PerPage = perPage
+		}
+	}
+
+	params.applyDefaults()
+
+	return params
+}
+
+func (p *AppointmentListParams) applyDefaults() {
+	if p.Page <= 0 {
+		p.Page = 1
+	}
+	if p.PerPage <= 0 {
+		p.PerPage = 20
+	}
+	if p.OrderBy == "" {
+		p.OrderBy = "asc"
+	}
+}
+
+func (p *AppointmentListParams) CalculateOffset() int {
+	if p.Page <= 0 {
+		return 0
+	}
+	return (p.Page - 1) * p.PerPage
+}
+
+func ParseAndValidateAppointmentList(c *fiber.Ctx, loc *time.Location) (AppointmentListParams, map[string]string, error) {
+	var req AppointmentListRequest
+
+	if err := c.QueryParser(&req); err != nil {
+		return AppointmentListParams{}, make(map[string]string), errors.New("geçersiz sorgu parametreleri")
+	}
+
+	validate := newValidator()
+	if err := validate.Struct(req); err != nil {
+		validationErrors := GetAppointmentValidationErrors(err)
+		return AppointmentListParams{}, validationErrors, errors.New("lütfen filtreleri kontrol edin")
+	}
+
+	return req.ToServiceParams(loc), make(map[string]string), nil
+}
+
+func GetAppointmentValidationErrors(err error) map[string]string {
+	errorMessages := map[string]string{
+		"ProfessionalServiceID_required": "Hizmet seçilmelidir.",
+		"ProfessionalServiceID_numeric":  "Geçerli bir hizmet seçiniz.",
+		"StartsAt_required":              "Randevu saati seçilmelidir.",
+		"StartsAt_datetime":              "Geçerli bir randevu saati seçiniz.",
+		"CustomerName_required":          "Ad soyad zorunludur.",
+		"CustomerName_min":               "Ad soyad en az 2 karakter olmalıdır.",
+		"CustomerName_max":               "Ad soyad en fazla 100 karakter olabilir.",
+		"CustomerPhone_required":         "Telefon numarası zorunludur.",
+		"CustomerPhone_min":              "Geçerli bir telefon numarası giriniz.",
+		"CustomerPhone_max":              "Telefon numarası en fazla 20 karakter olabilir.",
+		"CustomerEmail_email":            "Geçerli bir e-posta adresi giriniz.",
+		"Note_max":                       "Not en fazla 500 karakter olabilir.",
+		"Reason_max":                     "İptal nedeni en fazla 255 karakter olabilir.",
+		"Date_required":                  "Tarih seçilmelidir.",
+		"Date_datetime":                  "Geçerli bir tarih seçiniz.",
+		"BusinessID_numeric":             "Geçerli bir işletme seçiniz.",
+		"Status_oneof":                   "Geçerli bir durum seçiniz.",
+		"OrderBy_oneof":                  "Sıralama yönü sadece 'asc' veya 'desc' olabilir.",
+		"Page_numeric":                   "Sayfa numarası sayı olmalıdır.",
+		"Page_min":                       "Sayfa numarası en az 1 olmalıdır.",
+		"PerPage_numeric":                "Sayfa başı kayıt sayısı sayı olmalıdır.",
+		"PerPage_min":                    "Sayfa başı kayıt en az 1 olmalıdır.",
+		"PerPage_max":                    "Sayfa başı kayıt en fazla 200 olmalıdır.",
+	}
+
+	return CommonValidationErrors(err, errorMessages)
+}
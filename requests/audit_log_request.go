@@ -0,0 +1,138 @@
+package requests
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type AuditLogListRequest struct {
+	Search     string `query:"search" validate:"omitempty,max=255"`
+	EntityType string `query:"entity_type" validate:"omitempty,max=100"`
+	EntityID   string `query:"entity_id" validate:"omitempty,numeric"`
+	Action     string `query:"action" validate:"omitempty,oneof=create update delete"`
+	ActorID    string `query:"actor_id" validate:"omitempty,numeric"`
+	DateFrom   string `query:"date_from" validate:"omitempty,datetime=2006-01-02"`
+	DateTo     string `query:"date_to" validate:"omitempty,datetime=2006-01-02"`
+	Page       string `query:"page" validate:"omitempty,numeric,min=1"`
+	PerPage    string `query:"perPage" validate:"omitempty,numeric,min=1,max=200"`
+}
+
+type AuditLogListParams struct {
+	Search     string
+	EntityType string
+	EntityID   uint
+	Action     string
+	ActorID    uint
+	DateFrom   string
+	DateTo     string
+	From       time.Time
+	To         time.Time
+	Page       int
+	PerPage    int
+}
+
+// ToServiceParams; DateFrom gününün başından DateTo gününün sonuna kadar olan [From, To) aralığını
+// verilen saat diliminde hesaplar.
+func (r *AuditLogListRequest) ToServiceParams(loc *time.Location) AuditLogListParams {
+	params := AuditLogListParams{
+		Search:     strings.TrimSpace(r.Search),
+		EntityType: strings.TrimSpace(r.EntityType),
+		EntityID:   parseUint(r.EntityID),
+		Action:     strings.TrimSpace(r.Action),
+		ActorID:    parseUint(r.ActorID),
+		DateFrom:   strings.TrimSpace(r.DateFrom),
+		DateTo:     strings.TrimSpace(r.DateTo),
+	}
+
+	if params.DateFrom != "" {
+		if day, err := time.ParseInLocation("2006-01-02", params.DateFrom, loc); err == nil {
+			params.From = day
+		}
+	}
+	if params.DateTo != "" {
+		if day, err := time.ParseInLocation("2006-01-02", params.DateTo, loc); err == nil {
+			params.To = day.AddDate(0, 0, 1)
+		}
+	}
+
+	if r.Page != "" {
+		if page, err := strconv.Atoi(r.Page); err == nil && page > 0 {
+			params.Page = page
+		}
+	}
+
+	if r.PerPage != "" {
+		if perPage, err := strconv.Atoi(r.PerPage); err == nil && perPage > 0 {
+			params.PerPage = perPage
+		}
+	}
+
+	params.applyDefaults()
+
+	return params
+}
+
+func (p *AuditLogListParams) applyDefaults() {
+	if p.Page <= 0 {
+		p.Page = 1
+	}
+	if p.PerPage <= 0 {
+		p.PerPage = 50
+	}
+}
+
+func (p *AuditLogListParams) CalculateOffset() int {
+	if p.Page <= 0 {
+		return 0
+	}
+	return (p.Page - 1) * p.PerPage
+}
+
+func ParseAndValidateAuditLogList(c *fiber.Ctx, loc *time.Location) (AuditLogListParams, map[string]string, error) {
+	var req AuditLogListRequest
+
+	if err := c.QueryParser(&req); err != nil {
+		params := AuditLogListParams{}
+		params.applyDefaults()
+		return params, make(map[string]string), errors.New("geçersiz sorgu parametreleri")
+	}
+
+	validate := newValidator()
+	if err := validate.Struct(req); err != nil {
+		validationErrors := GetAuditLogValidationErrors(err)
+		params := AuditLogListParams{}
+		params.applyDefaults()
+		return params, validationErrors, errors.New("lütfen filtreleri kontrol edin")
+	}
+
+	params := req.ToServiceParams(loc)
+	if !params.From.IsZero() && !params.To.IsZero() && !params.From.Before(params.To) {
+		params = AuditLogListParams{}
+		params.applyDefaults()
+		return params, map[string]string{"date_to": "Bitiş tarihi başlangıç tarihinden önce olamaz."}, errors.New("lütfen filtreleri kontrol edin")
+	}
+	return params, make(map[string]string), nil
+}
+
+func GetAuditLogValidationErrors(err error) map[string]string {
+	errorMessages := map[string]string{
+		"Search_max":        "Arama metni en fazla 255 karakter olabilir.",
+		"EntityType_max":    "Geçerli bir kayıt türü seçiniz.",
+		"EntityID_numeric":  "Kayıt numarası sayı olmalıdır.",
+		"Action_oneof":      "Geçerli bir işlem seçiniz.",
+		"ActorID_numeric":   "Kullanıcı numarası sayı olmalıdır.",
+		"DateFrom_datetime": "Geçerli bir başlangıç tarihi giriniz.",
+		"DateTo_datetime":   "Geçerli bir bitiş tarihi giriniz.",
+		"Page_numeric":      "Sayfa numarası sayı olmalıdır.",
+		"Page_min":          "Sayfa numarası en az 1 olmalıdır.",
+		"PerPage_numeric":   "Sayfa başı kayıt sayısı sayı olmalıdır.",
+		"PerPage_min":       "Sayfa başı kayıt sayısı en az 1 olmalıdır.",
+		"PerPage_max":       "Sayfa başı kayıt sayısı en fazla 200 olabilir.",
+	}
+
+	return CommonValidationErrors(err, errorMessages)
+}
@@ -40,6 +40,10 @@ type (
 		Email string `form:"email" validate:"required,email"`
 	}
 
+	MagicLinkRequest struct {
+		Email string `form:"email" validate:"required,email"`
+	}
+
 	UpdateInfoRequest struct {
 		Name  string `form:"name" validate:"required,min=3"`
 		Email string `form:"email" validate:"required,email"`
@@ -48,6 +52,23 @@ type (
 	VerifyEmailRequest struct {
 		Token string `form:"token" validate:"required"`
 	}
+
+	// TwoFactorChallengeRequest, girişteki ikinci adımdır; kod 6 haneli TOTP ya da kurtarma kodu olabilir.
+	TwoFactorChallengeRequest struct {
+		Code        string `form:"code" validate:"required,min=6,max=32"`
+		TrustDevice bool   `form:"trust_device"`
+	}
+
+	TwoFactorCodeRequest struct {
+		Code string `form:"code" validate:"required,min=6,max=32"`
+	}
+
+	// AccessTokenRequest, profilden oluşturulan kişisel API erişim anahtarıdır; süre gün cinsindendir (0 = süresiz).
+	AccessTokenRequest struct {
+		Name          string   `form:"name" validate:"required,min=2,max=100"`
+		Scopes        []string `form:"scopes" validate:"required,min=1"`
+		ExpiresInDays string   `form:"expires_in_days" validate:"omitempty,oneof=0 30 90 365"`
+	}
 )
 
 func validateRequest(c *fiber.Ctx, req interface{}, errorMessages map[string]string, redirectPath string) error {
@@ -56,7 +77,7 @@ func validateRequest(c *fiber.Ctx, req interface{}, errorMessages map[string]str
 		return c.Redirect(redirectPath, fiber.StatusSeeOther)
 	}
 
-	validate := validator.New()
+	validate := newValidator()
 	if err := validate.Struct(req); err != nil {
 		err := err.(validator.ValidationErrors)[0]
 		if msg, ok := errorMessages[err.Field()+"_"+err.Tag()]; ok {
@@ -143,6 +164,40 @@ func ValidateForgotPasswordRequest(c *fiber.Ctx) error {
 	return c.Next()
 }
 
+func ValidateMagicLinkRequest(c *fiber.Ctx) error {
+	var req MagicLinkRequest
+	errorMessages := map[string]string{
+		"Email_required": "E-posta zorunludur",
+		"Email_email":    "Geçerli bir e-posta adresi giriniz",
+	}
+
+	if err := validateRequest(c, &req, errorMessages, "/auth/magic-link"); err != nil {
+		return err
+	}
+
+	c.Locals("magicLinkRequest", req)
+	return c.Next()
+}
+
+func ValidateAccessTokenRequest(c *fiber.Ctx) error {
+	var req AccessTokenRequest
+	errorMessages := map[string]string{
+		"Name_required":       "Anahtar adı zorunludur",
+		"Name_min":            "Anahtar adı en az 2 karakter olmalıdır",
+		"Name_max":            "Anahtar adı en fazla 100 karakter olabilir",
+		"Scopes_required":     "En az bir kapsam seçilmelidir",
+		"Scopes_min":          "En az bir kapsam seçilmelidir",
+		"ExpiresInDays_oneof": "Geçerli bir süre seçiniz",
+	}
+
+	if err := validateRequest(c, &req, errorMessages, "/auth/profile"); err != nil {
+		return err
+	}
+
+	c.Locals("accessTokenRequest", req)
+	return c.Next()
+}
+
 func ValidateResetPasswordRequest(c *fiber.Ctx) error {
 	var req ResetPasswordRequest
 	errorMessages := map[string]string{
@@ -206,3 +261,32 @@ func ValidateVerifyEmailRequest(c *fiber.Ctx) error {
 	c.Locals("verifyEmailRequest", req)
 	return c.Next()
 }
+
+var twoFactorCodeMessages = map[string]string{
+	"Code_required": "Doğrulama kodu zorunludur",
+	"Code_min":      "Doğrulama kodu en az 6 karakter olmalıdır",
+	"Code_max":      "Doğrulama kodu geçersiz",
+}
+
+func ValidateTwoFactorChallengeRequest(c *fiber.Ctx) error {
+	var req TwoFactorChallengeRequest
+	if err := validateRequest(c, &req, twoFactorCodeMessages, "/auth/two-factor"); err != nil {
+		return err
+	}
+
+	c.Locals("twoFactorChallengeRequest", req)
+	return c.Next()
+}
+
+// ValidateTwoFactorCodeRequest, profil/kurulum sayfasındaki kod isteyen işlemler içindir; hata olursa redirectPath'e döner.
+func ValidateTwoFactorCodeRequest(redirectPath string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var req TwoFactorCodeRequest
+		if err := validateRequest(c, &req, twoFactorCodeMessages, redirectPath); err != nil {
+			return err
+		}
+
+		c.Locals("twoFactorCodeRequest", req)
+		return c.Next()
+	}
+}
@@ -0,0 +1,185 @@
+package requests
+
+import (
+	"errors"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"zatrano/pkg/geo"
+	"zatrano/pkg/queryparams"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Dizin sıralama seçenekleri
+const (
+	DirectorySortRelevance = "relevance"
+	DirectorySortRating    = "rating"
+	DirectorySortPriceAsc  = "price_asc"
+	DirectorySortPriceDesc = "price_desc"
+	DirectorySortNewest    = "newest"
+	DirectorySortDistance  = "distance"
+)
+
+const directoryPerPage = 24
+
+// Yakınlık aramasında seçilebilen yarıçaplar (km)
+var DirectoryRadiusOptions = []int{1, 2, 5, 10, 25, 50}
+
+const defaultDirectoryRadius = 10
+
+type BusinessDirectoryRequest struct {
+	Q              string `query:"q" validate:"omitempty,max=100"`
+	CityID         string `query:"city_id" validate:"omitempty,numeric"`
+	DistrictID     string `query:"district_id" validate:"omitempty,numeric"`
+	BusinessTypeID string `query:"type_id" validate:"omitempty,numeric"`
+	ServiceID      string `query:"service_id" validate:"omitempty,numeric"`
+	Lat            string `query:"lat" validate:"omitempty,latitude,required_with=Lng"`
+	Lng            string `query:"lng" validate:"omitempty,longitude,required_with=Lat"`
+	Radius         string `query:"radius" validate:"omitempty,oneof=1 2 5 10 25 50"`
+	SortBy         string `query:"sortBy" validate:"omitempty,oneof=relevance rating price_asc price_desc newest distance"`
+	Page           string `query:"page" validate:"omitempty,numeric,min=1"`
+}
+
+// BusinessDirectoryParams, arama metni (Name), sıralama ve sayfalama için ortak ListParams'ı kullanır.
+type BusinessDirectoryParams struct {
+	queryparams.ListParams
+
+	CityID         uint
+	DistrictID     uint
+	BusinessTypeID uint
+	ServiceID      uint
+
+	// Near verildiğinde yalnızca RadiusKm içindeki konumu bilinen işletmeler listelenir
+	Near     *geo.Point
+	RadiusKm int
+}
+
+func (r *BusinessDirectoryRequest) ToServiceParams() BusinessDirectoryParams {
+	params := BusinessDirectoryParams{
+		ListParams: queryparams.ListParams{
+			Name:    strings.TrimSpace(r.Q),
+			SortBy:  strings.TrimSpace(r.SortBy),
+			PerPage: directoryPerPage,
+		},
+		CityID:         parseUint(r.CityID),
+		DistrictID:     parseUint(r.DistrictID),
+		BusinessTypeID: parseUint(r.BusinessTypeID),
+		ServiceID:      parseUint(r.ServiceID),
+	}
+
+	if page, err := strconv.Atoi(r.Page); err == nil && page > 0 {
+		params.Page = page
+	}
+	// İlçe, il seçilmeden anlamsızdır
+	if params.CityID == 0 {
+		params.DistrictID = 0
+	}
+	if p, ok := geo.Parse(r.Lat + "," + r.Lng); ok {
+		params.Near = &p
+		params.RadiusKm = defaultDirectoryRadius
+		if radius, err := strconv.Atoi(r.Radius); err == nil && radius > 0 {
+			params.RadiusKm = radius
+		}
+	}
+	switch {
+	case params.SortBy == "" && params.Near != nil:
+		params.SortBy = DirectorySortDistance
+	case params.SortBy == "", params.SortBy == DirectorySortDistance && params.Near == nil:
+		params.SortBy = DirectorySortRelevance
+	}
+	// Fiyat sıralaması hizmet seçiliyken o hizmetin, değilse işletmenin en düşük fiyatına göredir
+	params.OrderBy = "desc"
+	if params.SortBy == DirectorySortPriceAsc {
+		params.OrderBy = "asc"
+	}
+
+	params.ApplyDefaults()
+
+	return params
+}
+
+// Query, filtreleri paylaşılabilir bir sorgu dizesine çevirir; page=0 ise sayfa eklenmez.
+func (p BusinessDirectoryParams) Query(page int) string {
+	values := url.Values{}
+	if p.Name != "" {
+		values.Set("q", p.Name)
+	}
+	for key, id := range map[string]uint{
+		"city_id":     p.CityID,
+		"district_id": p.DistrictID,
+		"type_id":     p.BusinessTypeID,
+		"service_id":  p.ServiceID,
+	} {
+		if id != 0 {
+			values.Set(key, strconv.FormatUint(uint64(id), 10))
+		}
+	}
+	if p.Near != nil {
+		values.Set("lat", strconv.FormatFloat(p.Near.Lat, 'f', 5, 64))
+		values.Set("lng", strconv.FormatFloat(p.Near.Lng, 'f', 5, 64))
+		values.Set("radius", strconv.Itoa(p.RadiusKm))
+	}
+	if p.SortBy != "" && p.SortBy != DirectorySortRelevance {
+		values.Set("sortBy", p.SortBy)
+	}
+	if page > 1 {
+		values.Set("page", strconv.Itoa(page))
+	}
+	return values.Encode()
+}
+
+// WithoutNear, konum filtresi kaldırılmış kopyayı döner.
+func (p BusinessDirectoryParams) WithoutNear() BusinessDirectoryParams {
+	p.Near = nil
+	p.RadiusKm = 0
+	if p.SortBy == DirectorySortDistance {
+		p.SortBy = DirectorySortRelevance
+	}
+	return p
+}
+
+// URL, dizin sayfasının filtreleri korunmuş bağlantısını döner.
+func (p BusinessDirectoryParams) URL(page int) string {
+	if query := p.Query(page); query != "" {
+		return "/isletmeler?" + query
+	}
+	return "/isletmeler"
+}
+
+func ParseAndValidateBusinessDirectory(c *fiber.Ctx) (BusinessDirectoryParams, map[string]string, error) {
+	var req BusinessDirectoryRequest
+
+	if err := c.QueryParser(&req); err != nil {
+		return BusinessDirectoryParams{}, make(map[string]string), errors.New("geçersiz sorgu parametreleri")
+	}
+
+	validate := newValidator()
+	if err := validate.Struct(req); err != nil {
+		validationErrors := GetBusinessDirectoryValidationErrors(err)
+		return BusinessDirectoryParams{}, validationErrors, errors.New("lütfen filtreleri kontrol edin")
+	}
+
+	return req.ToServiceParams(), make(map[string]string), nil
+}
+
+func GetBusinessDirectoryValidationErrors(err error) map[string]string {
+	errorMessages := map[string]string{
+		"Q_max":                  "Arama metni en fazla 100 karakter olabilir.",
+		"CityID_numeric":         "Geçerli bir il seçiniz.",
+		"DistrictID_numeric":     "Geçerli bir ilçe seçiniz.",
+		"BusinessTypeID_numeric": "Geçerli bir işletme türü seçiniz.",
+		"ServiceID_numeric":      "Geçerli bir hizmet seçiniz.",
+		"Lat_latitude":           "Konum bilgisi geçersiz.",
+		"Lat_required_with":      "Konum bilgisi eksik.",
+		"Lng_longitude":          "Konum bilgisi geçersiz.",
+		"Lng_required_with":      "Konum bilgisi eksik.",
+		"Radius_oneof":           "Geçerli bir mesafe seçiniz.",
+		"SortBy_oneof":           "Geçerli bir sıralama seçiniz.",
+		"Page_numeric":           "Sayfa numarası sayı olmalıdır.",
+		"Page_min":               "Sayfa numarası en az 1 olmalıdır.",
+	}
+
+	return CommonValidationErrors(err, errorMessages)
+}
@@ -0,0 +1,361 @@
+package requests
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"zatrano/pkg/geo"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type BusinessRequest struct {
+	BusinessTypeID string `form:"business_type_id" validate:"required,numeric"`
+	Title          string `form:"title" validate:"required,min=2,max=255"`
+	Slug           string `form:"slug" validate:"omitempty,max=150"`
+	Description    string `form:"description" validate:"omitempty,max=5000"`
+	Capacity       string `form:"capacity" validate:"omitempty,numeric"`
+	Gsm            string `form:"gsm" validate:"omitempty,tr_mobile"`
+	Telephone      string `form:"telephone" validate:"omitempty,max=20"`
+	Email          string `form:"email" validate:"omitempty,email,max=100"`
+	Website        string `form:"website" validate:"omitempty,url,max=255"`
+
+	TaxOffice  string `form:"tax_office" validate:"omitempty,max=255"`
+	TaxNumber  string `form:"tax_number" validate:"omitempty,vkn|tckn"`
+	KEPAddress string `form:"kep_address" validate:"omitempty,email,max=255"`
+	MersisNo   string `form:"mersis_no" validate:"omitempty,mersis"`
+	IbanNo     string `form:"iban_no" validate:"omitempty,iban_tr"`
+
+	CountryID  string `form:"country_id" validate:"required,numeric"`
+	CityID     string `form:"city_id" validate:"required,numeric"`
+	DistrictID string `form:"district_id" validate:"required,numeric"`
+	Address    string `form:"address" validate:"required,min=5,max=255"`
+	Map        string `form:"map" validate:"omitempty,max=2000"`
+	Latitude   string `form:"latitude" validate:"omitempty,latitude,required_with=Longitude"`
+	Longitude  string `form:"longitude" validate:"omitempty,longitude,required_with=Latitude"`
+
+	Video     string `form:"video" validate:"omitempty,url,max=255"`
+	Whatapp   string `form:"whatapp" validate:"omitempty,tr_mobile"`
+	Instagram string `form:"instagram" validate:"omitempty,url,max=255"`
+	Facebook  string `form:"facebook" validate:"omitempty,url,max=255"`
+	Twitter   string `form:"twitter" validate:"omitempty,url,max=255"`
+	Linkedin  string `form:"linkedin" validate:"omitempty,url,max=255"`
+	Youtube   string `form:"youtube" validate:"omitempty,url,max=255"`
+	Tiktok    string `form:"tiktok" validate:"omitempty,url,max=255"`
+
+	IsActive string `form:"is_active" validate:"required,oneof=true false"`
+}
+
+type ConvertedBusinessRequest struct {
+	BusinessTypeID uint
+	Capacity       uint
+	CountryID      uint
+	CityID         uint
+	DistrictID     uint
+	IsActive       bool
+	Coordinates    *geo.Point
+}
+
+func (r *BusinessRequest) Convert() ConvertedBusinessRequest {
+	return ConvertedBusinessRequest{
+		BusinessTypeID: parseUint(r.BusinessTypeID),
+		Capacity:       parseUint(r.Capacity),
+		CountryID:      parseUint(r.CountryID),
+		CityID:         parseUint(r.CityID),
+		DistrictID:     parseUint(r.DistrictID),
+		IsActive:       r.IsActive == "true",
+		Coordinates:    r.coordinates(),
+	}
+}
+
+// coordinates, elle girilen enlem/boylamı, yoksa harita bağlantısından çıkarılan konumu döner.
+func (r *BusinessRequest) coordinates() *geo.Point {
+	if r.Latitude != "" && r.Longitude != "" {
+		if p, ok := geo.Parse(r.Latitude + "," + r.Longitude); ok {
+			return &p
+		}
+	}
+	if p, ok := geo.Parse(r.Map); ok {
+		return &p
+	}
+	return nil
+}
+
+// Trim, serbest metin alanlarındaki baştaki/sondaki boşlukları temizler.
+func (r *BusinessRequest) Trim() {
+	for _, f := range []*string{
+		&r.Title, &r.Slug, &r.Description, &r.Gsm, &r.Telephone, &r.Email, &r.Website,
+		&r.TaxOffice, &r.TaxNumber, &r.KEPAddress, &r.MersisNo, &r.IbanNo, &r.Address, &r.Map, &r.Latitude, &r.Longitude,
+		&r.Video, &r.Whatapp, &r.Instagram, &r.Facebook, &r.Twitter, &r.Linkedin, &r.Youtube, &r.Tiktok,
+	} {
+		*f = strings.TrimSpace(*f)
+	}
+	r.IbanNo = strings.ToUpper(strings.ReplaceAll(r.IbanNo, " ", ""))
+	r.MersisNo = strings.ReplaceAll(r.MersisNo, " ", "")
+}
+
+// NormalizePhones, doğrulanmış cep numaralarını E.164 (+905xxxxxxxxx) biçiminde saklanacak hale getirir.
+func (r *BusinessRequest) NormalizePhones() {
+	r.Gsm = normalizeMobile(r.Gsm)
+	r.Whatapp = normalizeMobile(r.Whatapp)
+}
+
+func ParseAndValidateBusinessRequest(c *fiber.Ctx) (BusinessRequest, map[string]string, error) {
+	var req BusinessRequest
+
+	if err := c.BodyParser(&req); err != nil {
+		return req, make(map[string]string), errors.New("geçersiz istek formatı")
+	}
+	return validateBusinessRequest(req)
+}
+
+func validateBusinessRequest(req BusinessRequest) (BusinessRequest, map[string]string, error) {
+	req.Trim()
+
+	validate := newValidator()
+	if err := validate.Struct(req); err != nil {
+		validationErrors := GetBusinessValidationErrors(err)
+		return req, validationErrors, errors.New("lütfen formdaki hataları düzeltin")
+	}
+	req.NormalizePhones()
+
+	return req, make(map[string]string), nil
+}
+
+// BusinessAPIRequest, /api/v1 üzerinden gelen JSON gövdesidir. Alan adları panel formuyla aynıdır;
+// sayısal alanlar sayı, is_active mantıksal değer olarak gönderilir ve verilmezse işletme aktif oluşturulur.
+type BusinessAPIRequest struct {
+	BusinessTypeID uint     `json:"business_type_id"`
+	Title          string   `json:"title"`
+	Slug           string   `json:"slug"`
+	Description    string   `json:"description"`
+	Capacity       uint     `json:"capacity"`
+	Gsm            string   `json:"gsm"`
+	Telephone      string   `json:"telephone"`
+	Email          string   `json:"email"`
+	Website        string   `json:"website"`
+	TaxOffice      string   `json:"tax_office"`
+	TaxNumber      string   `json:"tax_number"`
+	KEPAddress     string   `json:"kep_address"`
+	MersisNo       string   `json:"mersis_no"`
+	IbanNo         string   `json:"iban_no"`
+	CountryID      uint     `json:"country_id"`
+	CityID         uint     `json:"city_id"`
+	DistrictID     uint     `json:"district_id"`
+	Address        string   `json:"address"`
+	Map            string   `json:"map"`
+	Latitude       *float64 `json:"latitude"`
+	Longitude      *float64 `json:"longitude"`
+	Video          string   `json:"video"`
+	Whatapp        string   `json:"whatapp"`
+	Instagram      string   `json:"instagram"`
+	Facebook       string   `json:"facebook"`
+	Twitter        string   `json:"twitter"`
+	Linkedin       string   `json:"linkedin"`
+	Youtube        string   `json:"youtube"`
+	Tiktok         string   `json:"tiktok"`
+	IsActive       *bool    `json:"is_active"`
+}
+
+// ToBusinessRequest, JSON gövdesini panel formunun doğrulama kurallarından geçecek biçime çevirir.
+func (r BusinessAPIRequest) ToBusinessRequest() BusinessRequest {
+	formatID := func(v uint) string {
+		if v == 0 {
+			return ""
+		}
+		return strconv.FormatUint(uint64(v), 10)
+	}
+	formatCoordinate := func(v *float64) string {
+		if v == nil {
+			return ""
+		}
+		return strconv.FormatFloat(*v, 'f', -1, 64)
+	}
+	isActive := "true"
+	if r.IsActive != nil && !*r.IsActive {
+		isActive = "false"
+	}
+
+	return BusinessRequest{
+		BusinessTypeID: formatID(r.BusinessTypeID),
+		Title:          r.Title,
+		Slug:           r.Slug,
+		Description:    r.Description,
+		Capacity:       strconv.FormatUint(uint64(r.Capacity), 10),
+		Gsm:            r.Gsm,
+		Telephone:      r.Telephone,
+		Email:          r.Email,
+		Website:        r.Website,
+		TaxOffice:      r.TaxOffice,
+		TaxNumber:      r.TaxNumber,
+		KEPAddress:     r.KEPAddress,
+		MersisNo:       r.MersisNo,
+		IbanNo:         r.IbanNo,
+		CountryID:      formatID(r.CountryID),
+		CityID:         formatID(r.CityID),
+		DistrictID:     formatID(r.DistrictID),
+		Address:        r.Address,
+		Map:            r.Map,
+		Latitude:       formatCoordinate(r.Latitude),
+		Longitude:      formatCoordinate(r.Longitude),
+		Video:          r.Video,
+		Whatapp:        r.Whatapp,
+		Instagram:      r.Instagram,
+		Facebook:       r.Facebook,
+		Twitter:        r.Twitter,
+		Linkedin:       r.Linkedin,
+		Youtube:        r.Youtube,
+		Tiktok:         r.Tiktok,
+		IsActive:       isActive,
+	}
+}
+
+func ParseAndValidateBusinessAPIRequest(c *fiber.Ctx) (BusinessRequest, map[string]string, error) {
+	var req BusinessAPIRequest
+
+	if !strings.HasPrefix(c.Get(fiber.HeaderContentType), fiber.MIMEApplicationJSON) {
+		return BusinessRequest{}, make(map[string]string), errors.New("istek gövdesi application/json olmalıdır")
+	}
+	if err := c.BodyParser(&req); err != nil {
+		return BusinessRequest{}, make(map[string]string), errors.New("geçersiz JSON gövdesi")
+	}
+	return validateBusinessRequest(req.ToBusinessRequest())
+}
+
+type BusinessListRequest struct {
+	Name    string `query:"name"`
+	SortBy  string `query:"sortBy" validate:"omitempty,oneof=id title created_at"`
+	OrderBy string `query:"orderBy" validate:"omitempty,oneof=asc desc"`
+	Page    string `query:"page" validate:"omitempty,numeric,min=1"`
+	PerPage string `query:"perPage" validate:"omitempty,numeric,min=1,max=200"`
+}
+
+type BusinessListParams struct {
+	Name    string
+	SortBy  string
+	OrderBy string
+	Page    int
+	PerPage int
+}
+
+func (r *BusinessListRequest) ToServiceParams() BusinessListParams {
+	params := BusinessListParams{
+		Name:    strings.TrimSpace(r.Name),
+		SortBy:  strings.TrimSpace(r.SortBy),
+		OrderBy: strings.TrimSpace(r.OrderBy),
+	}
+
+	if r.Page != "" {
+		if page, err := strconv.Atoi(r.Page); err == nil && page > 0 {
+			params.Page = page
+		}
+	}
+
+	if r.PerPage != "" {
+		if perPage, err := strconv.Atoi(r.PerPage); err == nil && perPage > 0 {
+			params.PerPage = perPage
+		}
+	}
+
+	params.applyDefaults()
+
+	return params
+}
+
+func (p *BusinessListParams) applyDefaults() {
+	if p.Page <= 0 {
+		p.Page = 1
+	}
+	if p.PerPage <= 0 {
+		p.PerPage = 20
+	}
+	if p.SortBy == "" {
+		p.SortBy = "created_at"
+	}
+	if p.OrderBy == "" {
+		p.OrderBy = "desc"
+	}
+}
+
+func (p *BusinessListParams) CalculateOffset() int {
+	if p.Page <= 0 {
+		return 0
+	}
+	return (p.Page - 1) * p.PerPage
+}
+
+func ParseAndValidateBusinessList(c *fiber.Ctx) (BusinessListParams, map[string]string, error) {
+	var req BusinessListRequest
+
+	if err := c.QueryParser(&req); err != nil {
+		return BusinessListParams{}, make(map[string]string), errors.New("geçersiz sorgu parametreleri")
+	}
+
+	validate := newValidator()
+	if err := validate.Struct(req); err != nil {
+		validationErrors := GetBusinessListValidationErrors(err)
+		return BusinessListParams{}, validationErrors, errors.New("lütfen filtreleri kontrol edin")
+	}
+
+	return req.ToServiceParams(), make(map[string]string), nil
+}
+
+func GetBusinessValidationErrors(err error) map[string]string {
+	errorMessages := map[string]string{
+		"BusinessTypeID_required": "İşletme türü seçilmelidir.",
+		"BusinessTypeID_numeric":  "Geçerli bir işletme türü seçiniz.",
+		"Title_required":          "İşletme adı zorunludur.",
+		"Title_min":               "İşletme adı en az 2 karakter olmalıdır.",
+		"Title_max":               "İşletme adı en fazla 255 karakter olabilir.",
+		"Slug_max":                "Bağlantı adı en fazla 150 karakter olabilir.",
+		"Description_max":         "Açıklama en fazla 5000 karakter olabilir.",
+		"Capacity_numeric":        "Kapasite sayı olmalıdır.",
+		"Telephone_max":           "Telefon numarası en fazla 20 karakter olabilir.",
+		"Email_email":             "Geçerli bir e-posta adresi giriniz.",
+		"Website_url":             "Geçerli bir web sitesi adresi giriniz (https://...).",
+		"KEPAddress_email":        "Geçerli bir KEP adresi giriniz.",
+		"CountryID_required":      "Ülke seçilmelidir.",
+		"CityID_required":         "İl seçilmelidir.",
+		"DistrictID_required":     "İlçe seçilmelidir.",
+		"Address_required":        "Adres zorunludur.",
+		"Address_min":             "Adres en az 5 karakter olmalıdır.",
+		"Latitude_latitude":       "Geçerli bir enlem giriniz (örn. 41.0082).",
+		"Latitude_required_with":  "Boylam girildiyse enlem de girilmelidir.",
+		"Longitude_longitude":     "Geçerli bir boylam giriniz (örn. 28.9784).",
+		"Longitude_required_with": "Enlem girildiyse boylam da girilmelidir.",
+		"Video_url":               "Geçerli bir video adresi giriniz.",
+		"Instagram_url":           "Geçerli bir Instagram adresi giriniz.",
+		"Facebook_url":            "Geçerli bir Facebook adresi giriniz.",
+		"Twitter_url":             "Geçerli bir X (Twitter) adresi giriniz.",
+		"Linkedin_url":            "Geçerli bir LinkedIn adresi giriniz.",
+		"Youtube_url":             "Geçerli bir YouTube adresi giriniz.",
+		"Tiktok_url":              "Geçerli bir TikTok adresi giriniz.",
+		"IsActive_required":       "İşletme durumu seçilmelidir.",
+		"IsActive_oneof":          "Geçerli bir durum seçiniz (Aktif/Pasif).",
+	}
+
+	return CommonValidationErrors(err, errorMessages)
+}
+
+func GetBusinessListValidationErrors(err error) map[string]string {
+	errorMessages := map[string]string{
+		"SortBy_oneof":    "Sıralama alanı sadece 'id', 'title' veya 'created_at' olabilir.",
+		"OrderBy_oneof":   "Sıralama yönü sadece 'asc' veya 'desc' olabilir.",
+		"Page_numeric":    "Sayfa numarası sayı olmalıdır.",
+		"Page_min":        "Sayfa numarası en az 1 olmalıdır.",
+		"PerPage_numeric": "Sayfa başı kayıt sayısı sayı olmalıdır.",
+		"PerPage_min":     "Sayfa başı kayıt en az 1 olmalıdır.",
+		"PerPage_max":     "Sayfa başı kayıt en fazla 200 olmalıdır.",
+	}
+
+	return CommonValidationErrors(err, errorMessages)
+}
+
+func parseUint(s string) uint {
+	v, err := strconv.ParseUint(strings.TrimSpace(s), 10, 32)
+	if err != nil {
+		return 0
+	}
+	return uint(v)
+}
@@ -39,6 +39,8 @@ func CommonValidationErrors(err error, errorMessages map[string]string) map[stri
 
 			if msg, ok := errorMessages[key]; ok {
 				errors[fieldKey] = msg
+			} else if msg, ok := tagMessages[tag]; ok {
+				errors[fieldKey] = msg
 			} else {
 				errors[fieldKey] = "Geçersiz değer."
 			}
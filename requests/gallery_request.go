@@ -0,0 +1,62 @@
+package requests
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type GalleryCaptionRequest struct {
+	Caption string `form:"caption" json:"caption" validate:"omitempty,max=255"`
+}
+
+// GalleryOrderRequest, sürükle-bırak sonrası görsel ID'lerinin yeni sırasıdır.
+type GalleryOrderRequest struct {
+	IDs []uint `form:"ids" json:"ids" validate:"required,min=1,max=500,dive,gt=0"`
+}
+
+func ParseAndValidateGalleryCaption(c *fiber.Ctx) (GalleryCaptionRequest, map[string]string, error) {
+	var req GalleryCaptionRequest
+
+	if err := c.BodyParser(&req); err != nil {
+		return req, make(map[string]string), errors.New("geçersiz istek formatı")
+	}
+	req.Caption = strings.TrimSpace(req.Caption)
+
+	validate := newValidator()
+	if err := validate.Struct(req); err != nil {
+		validationErrors := GetGalleryValidationErrors(err)
+		return req, validationErrors, errors.New("açıklama en fazla 255 karakter olabilir")
+	}
+
+	return req, make(map[string]string), nil
+}
+
+func ParseAndValidateGalleryOrder(c *fiber.Ctx) (GalleryOrderRequest, map[string]string, error) {
+	var req GalleryOrderRequest
+
+	if err := c.BodyParser(&req); err != nil {
+		return req, make(map[string]string), errors.New("geçersiz istek formatı")
+	}
+
+	validate := newValidator()
+	if err := validate.Struct(req); err != nil {
+		validationErrors := GetGalleryValidationErrors(err)
+		return req, validationErrors, errors.New("görsel sırası geçersiz")
+	}
+
+	return req, make(map[string]string), nil
+}
+
+func GetGalleryValidationErrors(err error) map[string]string {
+	errorMessages := map[string]string{
+		"Caption_max":  "Açıklama en fazla 255 karakter olabilir.",
+		"IDs_required": "Görsel sırası boş olamaz.",
+		"IDs_min":      "Görsel sırası boş olamaz.",
+		"IDs_max":      "Tek seferde en fazla 500 görsel sıralanabilir.",
+		"IDs_gt":       "Geçersiz görsel numarası.",
+	}
+
+	return CommonValidationErrors(err, errorMessages)
+}
@@ -61,7 +61,7 @@ func ParseAndValidateInvitationRequest(c *fiber.Ctx) (InvitationRequest, error)
 		return req, errors.New("geçersiz istek formatı")
 	}
 
-	validate := validator.New()
+	validate := newValidator()
 	if err := validate.Struct(req); err != nil {
 		validationErrors := err.(validator.ValidationErrors)
 		field := validationErrors[0].Field()
@@ -0,0 +1,104 @@
+package requests
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const LoginLockoutStatusActive = "active"
+
+type LoginLockoutListRequest struct {
+	Status  string `query:"status" validate:"omitempty,oneof=active"`
+	Search  string `query:"search" validate:"omitempty,max=255"`
+	Page    string `query:"page" validate:"omitempty,numeric,min=1"`
+	PerPage string `query:"perPage" validate:"omitempty,numeric,min=1,max=200"`
+}
+
+type LoginLockoutListParams struct {
+	Status  string
+	Search  string
+	Page    int
+	PerPage int
+}
+
+func (r *LoginLockoutListRequest) ToServiceParams() LoginLockoutListParams {
+	params := LoginLockoutListParams{
+		Status: strings.TrimSpace(r.Status),
+		Search: strings.TrimSpace(r.Search),
+	}
+
+	if r.Page != "" {
+		if page, err := strconv.Atoi(r.Page); err == nil && page > 0 {
+			params.Page = page
+		}
+	}
+
+	if r.PerPage != "" {
+		if perPage, err := strconv.Atoi(r.PerPage); err == nil && perPage > 0 {
+			params.PerPage = perPage
+		}
+	}
+
+	params.applyDefaults()
+
+	return params
+}
+
+func (p *LoginLockoutListParams) applyDefaults() {
+	if p.Page <= 0 {
+		p.Page = 1
+	}
+	if p.PerPage <= 0 {
+		p.PerPage = 20
+	}
+}
+
+func (p *LoginLockoutListParams) CalculateOffset() int {
+	if p.Page <= 0 {
+		return 0
+	}
+	return (p.Page - 1) * p.PerPage
+}
+
+// ParseAndValidateLoginLockoutList; status sorguda hiç yoksa yalnızca etkin kilitler listelenir,
+// "Tüm kayıtlar" seçimi boş gönderir.
+func ParseAndValidateLoginLockoutList(c *fiber.Ctx) (LoginLockoutListParams, map[string]string, error) {
+	var req LoginLockoutListRequest
+
+	if err := c.QueryParser(&req); err != nil {
+		params := LoginLockoutListParams{Status: LoginLockoutStatusActive}
+		params.applyDefaults()
+		return params, make(map[string]string), errors.New("geçersiz sorgu parametreleri")
+	}
+
+	validate := newValidator()
+	if err := validate.Struct(req); err != nil {
+		validationErrors := GetLoginLockoutValidationErrors(err)
+		params := LoginLockoutListParams{Status: LoginLockoutStatusActive}
+		params.applyDefaults()
+		return params, validationErrors, errors.New("lütfen filtreleri kontrol edin")
+	}
+
+	params := req.ToServiceParams()
+	if !c.Context().QueryArgs().Has("status") {
+		params.Status = LoginLockoutStatusActive
+	}
+	return params, make(map[string]string), nil
+}
+
+func GetLoginLockoutValidationErrors(err error) map[string]string {
+	errorMessages := map[string]string{
+		"Status_oneof":    "Geçerli bir durum seçiniz.",
+		"Search_max":      "Arama metni en fazla 255 karakter olabilir.",
+		"Page_numeric":    "Sayfa numarası sayı olmalıdır.",
+		"Page_min":        "Sayfa numarası en az 1 olmalıdır.",
+		"PerPage_numeric": "Sayfa başı kayıt sayısı sayı olmalıdır.",
+		"PerPage_min":     "Sayfa başı kayıt sayısı en az 1 olmalıdır.",
+		"PerPage_max":     "Sayfa başı kayıt sayısı en fazla 200 olabilir.",
+	}
+
+	return CommonValidationErrors(err, errorMessages)
+}
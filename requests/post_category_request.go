@@ -21,7 +21,7 @@ func ParseAndValidatePostCategoryRequest(c *fiber.Ctx) (PostCategoryRequest, err
 		return req, errors.New("geçersiz istek formatı")
 	}
 
-	validate := validator.New()
+	validate := newValidator()
 	if err := validate.Struct(req); err != nil {
 		validationErrors := err.(validator.ValidationErrors)
 		field := validationErrors[0].Field()
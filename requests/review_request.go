@@ -0,0 +1,181 @@
+package requests
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type ReviewRequest struct {
+	Rating  string `form:"rating" validate:"required,oneof=1 2 3 4 5"`
+	Comment string `form:"comment" validate:"omitempty,min=10,max=2000"`
+}
+
+func ParseAndValidateReviewRequest(c *fiber.Ctx) (ReviewRequest, map[string]string, error) {
+	var req ReviewRequest
+
+	if err := c.BodyParser(&req); err != nil {
+		return req, make(map[string]string), errors.New("geçersiz istek formatı")
+	}
+	req.Rating = strings.TrimSpace(req.Rating)
+	req.Comment = strings.TrimSpace(req.Comment)
+
+	validate := newValidator()
+	if err := validate.Struct(req); err != nil {
+		validationErrors := GetReviewValidationErrors(err)
+		return req, validationErrors, errors.New("lütfen formdaki hataları düzeltin")
+	}
+
+	return req, make(map[string]string), nil
+}
+
+func (r *ReviewRequest) RatingValue() uint8 {
+	return uint8(parseUint(r.Rating))
+}
+
+type ReviewReplyRequest struct {
+	Reply string `form:"reply" json:"reply" validate:"max=2000"`
+}
+
+// ParseAndValidateReviewReply; boş yanıt, mevcut yanıtın kaldırılması anlamına gelir.
+func ParseAndValidateReviewReply(c *fiber.Ctx) (ReviewReplyRequest, map[string]string, error) {
+	var req ReviewReplyRequest
+
+	if err := c.BodyParser(&req); err != nil {
+		return req, make(map[string]string), errors.New("geçersiz istek formatı")
+	}
+	req.Reply = strings.TrimSpace(req.Reply)
+
+	validate := newValidator()
+	if err := validate.Struct(req); err != nil {
+		validationErrors := GetReviewValidationErrors(err)
+		return req, validationErrors, errors.New("yanıt en fazla 2000 karakter olabilir")
+	}
+
+	return req, make(map[string]string), nil
+}
+
+type ReviewModerationRequest struct {
+	Note string `form:"note" json:"note" validate:"omitempty,max=255"`
+}
+
+func ParseAndValidateReviewModeration(c *fiber.Ctx) (ReviewModerationRequest, map[string]string, error) {
+	var req ReviewModerationRequest
+
+	if err := c.BodyParser(&req); err != nil {
+		return req, make(map[string]string), errors.New("geçersiz istek formatı")
+	}
+	req.Note = strings.TrimSpace(req.Note)
+
+	validate := newValidator()
+	if err := validate.Struct(req); err != nil {
+		validationErrors := GetReviewValidationErrors(err)
+		return req, validationErrors, errors.New("not en fazla 255 karakter olabilir")
+	}
+
+	return req, make(map[string]string), nil
+}
+
+type ReviewListRequest struct {
+	BusinessID string `query:"business_id" validate:"omitempty,numeric"`
+	Status     string `query:"status" validate:"omitempty,oneof=pending approved rejected"`
+	Rating     string `query:"rating" validate:"omitempty,oneof=1 2 3 4 5"`
+	Page       string `query:"page" validate:"omitempty,numeric,min=1"`
+	PerPage    string `query:"perPage" validate:"omitempty,numeric,min=1,max=200"`
+}
+
+type ReviewListParams struct {
+	BusinessID uint
+	Status     string
+	Rating     uint8
+	Page       int
+	PerPage    int
+}
+
+func (r *ReviewListRequest) ToServiceParams() ReviewListParams {
+	params := ReviewListParams{
+		BusinessID: parseUint(r.BusinessID),
+		Status:     strings.TrimSpace(r.Status),
+		Rating:     uint8(parseUint(r.Rating)),
+	}
+
+	if r.Page != "" {
+		if page, err := strconv.Atoi(r.Page); err == nil && page > 0 {
+			params.Page = page
+		}
+	}
+
+	if r.PerPage != "" {
+		if perPage, err := strconv.Atoi(r.PerPage); err == nil && perPage > 0 {
+			params.PerPage = perPage
+		}
+	}
+
+	params.applyDefaults()
+
+	return params
+}
+
+func (p *ReviewListParams) applyDefaults() {
+	if p.Page <= 0 {
+		p.Page = 1
+	}
+	if p.PerPage <= 0 {
+		p.PerPage = 20
+	}
+}
+
+func (p *ReviewListParams) CalculateOffset() int {
+	if p.Page <= 0 {
+		return 0
+	}
+	return (p.Page - 1) * p.PerPage
+}
+
+// ParseAndValidateReviewList; defaultStatus, sorguda status hiç yoksa kullanılır
+// (ör. moderasyon kuyruğu ilk açılışta onay bekleyenleri listeler, "Tüm durumlar" seçimi boş gönderir).
+func ParseAndValidateReviewList(c *fiber.Ctx, defaultStatus string) (ReviewListParams, map[string]string, error) {
+	var req ReviewListRequest
+
+	if err := c.QueryParser(&req); err != nil {
+		params := ReviewListParams{Status: defaultStatus}
+		params.applyDefaults()
+		return params, make(map[string]string), errors.New("geçersiz sorgu parametreleri")
+	}
+
+	validate := newValidator()
+	if err := validate.Struct(req); err != nil {
+		validationErrors := GetReviewValidationErrors(err)
+		params := ReviewListParams{Status: defaultStatus}
+		params.applyDefaults()
+		return params, validationErrors, errors.New("lütfen filtreleri kontrol edin")
+	}
+
+	params := req.ToServiceParams()
+	if !c.Context().QueryArgs().Has("status") {
+		params.Status = defaultStatus
+	}
+	return params, make(map[string]string), nil
+}
+
+func GetReviewValidationErrors(err error) map[string]string {
+	errorMessages := map[string]string{
+		"Rating_required":    "Lütfen 1 ile 5 arasında bir puan verin.",
+		"Rating_oneof":       "Puan 1 ile 5 arasında olmalıdır.",
+		"Comment_min":        "Yorum en az 10 karakter olmalıdır.",
+		"Comment_max":        "Yorum en fazla 2000 karakter olabilir.",
+		"Reply_max":          "Yanıt en fazla 2000 karakter olabilir.",
+		"Note_max":           "Not en fazla 255 karakter olabilir.",
+		"BusinessID_numeric": "Geçerli bir işletme seçiniz.",
+		"Status_oneof":       "Geçerli bir durum seçiniz.",
+		"Page_numeric":       "Sayfa numarası sayı olmalıdır.",
+		"Page_min":           "Sayfa numarası en az 1 olmalıdır.",
+		"PerPage_numeric":    "Sayfa başı kayıt sayısı sayı olmalıdır.",
+		"PerPage_min":        "Sayfa başı kayıt en az 1 olmalıdır.",
+		"PerPage_max":        "Sayfa başı kayıt en fazla 200 olmalıdır.",
+	}
+
+	return CommonValidationErrors(err, errorMessages)
+}
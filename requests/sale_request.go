@@ -22,7 +22,7 @@ func ParseAndValidateSaleRequest(c *fiber.Ctx) (SaleRequest, error) {
 		return req, errors.New("geçersiz istek formatı")
 	}
 
-	validate := validator.New()
+	validate := newValidator()
 	if err := validate.Struct(req); err != nil {
 		validationErrors := err.(validator.ValidationErrors)
 		field := validationErrors[0].Field()
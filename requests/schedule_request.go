@@ -0,0 +1,128 @@
+package requests
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// WeeklyScheduleDay, haftalık takvim formundaki bir günün değerleridir.
+// Mola alanları boş bırakılabilir; saatler "15:04" biçimindedir.
+type WeeklyScheduleDay struct {
+	Open       bool
+	Start      string
+	End        string
+	BreakStart string
+	BreakEnd   string
+}
+
+// WeeklyScheduleRequest, Days[0] Pazar olacak şekilde time.Weekday sırasını izler.
+type WeeklyScheduleRequest struct {
+	Days            [7]WeeklyScheduleDay
+	WorksOnHolidays bool
+}
+
+// ParseAndValidateWeeklySchedule, day_<n>_open, day_<n>_start, day_<n>_end, day_<n>_break_start
+// ve day_<n>_break_end alanlarını okur. Hata anahtarları aynı alan adlarıdır.
+func ParseAndValidateWeeklySchedule(c *fiber.Ctx) (WeeklyScheduleRequest, map[string]string, error) {
+	var req WeeklyScheduleRequest
+	fieldErrors := make(map[string]string)
+
+	req.WorksOnHolidays = c.FormValue("works_on_holidays") == "true"
+
+	for d := 0; d < 7; d++ {
+		key := func(name string) string { return fmt.Sprintf("day_%d_%s", d, name) }
+		day := WeeklyScheduleDay{
+			Open:       c.FormValue(key("open")) == "true",
+			Start:      strings.TrimSpace(c.FormValue(key("start"))),
+			End:        strings.TrimSpace(c.FormValue(key("end"))),
+			BreakStart: strings.TrimSpace(c.FormValue(key("break_start"))),
+			BreakEnd:   strings.TrimSpace(c.FormValue(key("break_end"))),
+		}
+		req.Days[d] = day
+		if !day.Open {
+			continue
+		}
+
+		start, okStart := parseClockValue(day.Start)
+		end, okEnd := parseClockValue(day.End)
+		switch {
+		case !okStart:
+			fieldErrors[key("start")] = "Geçerli bir başlangıç saati giriniz."
+			continue
+		case !okEnd:
+			fieldErrors[key("end")] = "Geçerli bir bitiş saati giriniz."
+			continue
+		case !start.Before(end):
+			fieldErrors[key("end")] = "Bitiş saati başlangıçtan sonra olmalıdır."
+			continue
+		}
+
+		if day.BreakStart == "" && day.BreakEnd == "" {
+			continue
+		}
+		breakStart, okBreakStart := parseClockValue(day.BreakStart)
+		breakEnd, okBreakEnd := parseClockValue(day.BreakEnd)
+		switch {
+		case !okBreakStart || !okBreakEnd:
+			fieldErrors[key("break_start")] = "Mola için başlangıç ve bitiş saati giriniz."
+		case !breakStart.Before(breakEnd):
+			fieldErrors[key("break_end")] = "Mola bitişi başlangıcından sonra olmalıdır."
+		case breakStart.Before(start) || breakEnd.After(end):
+			fieldErrors[key("break_start")] = "Mola çalışma saatleri içinde olmalıdır."
+		}
+	}
+
+	if len(fieldErrors) > 0 {
+		return req, fieldErrors, errors.New("lütfen formdaki hataları düzeltin")
+	}
+	return req, fieldErrors, nil
+}
+
+func parseClockValue(raw string) (time.Time, bool) {
+	t, err := time.Parse("15:04", raw)
+	return t, err == nil
+}
+
+type TimeOffRequest struct {
+	StartsAt string `form:"starts_at" validate:"required,datetime=2006-01-02T15:04"`
+	EndsAt   string `form:"ends_at" validate:"required,datetime=2006-01-02T15:04"`
+	Reason   string `form:"reason" validate:"omitempty,max=255"`
+}
+
+func ParseAndValidateTimeOffRequest(c *fiber.Ctx) (TimeOffRequest, map[string]string, error) {
+	var req TimeOffRequest
+
+	if err := c.BodyParser(&req); err != nil {
+		return req, make(map[string]string), errors.New("geçersiz istek formatı")
+	}
+	req.StartsAt = strings.TrimSpace(req.StartsAt)
+	req.EndsAt = strings.TrimSpace(req.EndsAt)
+	req.Reason = strings.TrimSpace(req.Reason)
+
+	validate := newValidator()
+	if err := validate.Struct(req); err != nil {
+		validationErrors := GetTimeOffValidationErrors(err)
+		return req, validationErrors, errors.New("lütfen formdaki hataları düzeltin")
+	}
+	if req.EndsAt <= req.StartsAt {
+		return req, map[string]string{"ends_at": "İzin bitişi başlangıcından sonra olmalıdır."}, errors.New("lütfen formdaki hataları düzeltin")
+	}
+
+	return req, make(map[string]string), nil
+}
+
+func GetTimeOffValidationErrors(err error) map[string]string {
+	errorMessages := map[string]string{
+		"StartsAt_required": "İzin başlangıcı zorunludur.",
+		"StartsAt_datetime": "Geçerli bir başlangıç tarihi giriniz.",
+		"EndsAt_required":   "İzin bitişi zorunludur.",
+		"EndsAt_datetime":   "Geçerli bir bitiş tarihi giriniz.",
+		"Reason_max":        "Açıklama en fazla 255 karakter olabilir.",
+	}
+
+	return CommonValidationErrors(err, errorMessages)
+}
@@ -5,32 +5,23 @@ import (
 	"strconv"
 	"strings"
 
-	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
 )
 
 type BaseUserRequest struct {
-	Name              string `form:"name" validate:"required,min=3"`
-	Email             string `form:"email" validate:"required,email"`
-	IsActive          string `form:"is_active" validate:"required,oneof=true false"`
-	UserTypeID        string `form:"user_type_id" validate:"required"`
-	EmailVerified     string `form:"email_verified" validate:"required,oneof=true false"`
-	ResetToken        string `form:"reset_token"`
-	VerificationToken string `form:"verification_token"`
-	Provider          string `form:"provider"`
-	ProviderID        string `form:"provider_id"`
+	Name          string `form:"name" validate:"required,min=3"`
+	Email         string `form:"email" validate:"required,email"`
+	IsActive      string `form:"is_active" validate:"required,oneof=true false"`
+	UserTypeID    string `form:"user_type_id" validate:"required"`
+	EmailVerified string `form:"email_verified" validate:"required,oneof=true false"`
 }
 
 type ConvertedBaseUserRequest struct {
-	Name              string
-	Email             string
-	IsActive          *bool
-	UserTypeID        *uint
-	EmailVerified     *bool
-	ResetToken        string
-	VerificationToken string
-	Provider          string
-	ProviderID        string
+	Name          string
+	Email         string
+	IsActive      *bool
+	UserTypeID    *uint
+	EmailVerified *bool
 }
 
 func (r *BaseUserRequest) Convert() ConvertedBaseUserRequest {
@@ -55,15 +46,11 @@ func (r *BaseUserRequest) Convert() ConvertedBaseUserRequest {
 	}
 
 	return ConvertedBaseUserRequest{
-		Name:              r.Name,
-		Email:             r.Email,
-		IsActive:          isActivePtr,
-		UserTypeID:        userTypeIDPtr,
-		EmailVerified:     emailVerifiedPtr,
-		ResetToken:        r.ResetToken,
-		VerificationToken: r.VerificationToken,
-		Provider:          r.Provider,
-		ProviderID:        r.ProviderID,
+		Name:          r.Name,
+		Email:         r.Email,
+		IsActive:      isActivePtr,
+		UserTypeID:    userTypeIDPtr,
+		EmailVerified: emailVerifiedPtr,
 	}
 }
 
@@ -165,7 +152,7 @@ func ParseAndValidateCreateRequest(c *fiber.Ctx) (CreateUserRequest, map[string]
 		return req, make(map[string]string), errors.New("geçersiz istek formatı")
 	}
 
-	validate := validator.New()
+	validate := newValidator()
 	if err := validate.Struct(req); err != nil {
 		validationErrors := GetUserValidationErrors(err)
 		return req, validationErrors, errors.New("lütfen formdaki hataları düzeltin")
@@ -181,7 +168,7 @@ func ParseAndValidateUpdateRequest(c *fiber.Ctx) (UpdateUserRequest, map[string]
 		return req, make(map[string]string), errors.New("geçersiz istek formatı")
 	}
 
-	validate := validator.New()
+	validate := newValidator()
 	if err := validate.Struct(req); err != nil {
 		validationErrors := GetUserValidationErrors(err)
 		return req, validationErrors, errors.New("lütfen formdaki hataları düzeltin")
@@ -197,7 +184,7 @@ func ParseAndValidateUserList(c *fiber.Ctx) (UserListParams, map[string]string,
 		return UserListParams{}, make(map[string]string), errors.New("geçersiz sorgu parametreleri")
 	}
 
-	validate := validator.New()
+	validate := newValidator()
 	if err := validate.Struct(req); err != nil {
 		validationErrors := GetUserListValidationErrors(err)
 		return UserListParams{}, validationErrors, errors.New("lütfen filtreleri kontrol edin")
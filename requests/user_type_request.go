@@ -5,18 +5,23 @@ import (
 	"strconv"
 	"strings"
 
-	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
 )
 
 type BaseUserTypeRequest struct {
-	Name     string `form:"name" validate:"required,min=2"`
-	IsActive string `form:"is_active" validate:"required,oneof=true false"`
+	Name             string   `form:"name" validate:"required,min=2"`
+	IsActive         string   `form:"is_active" validate:"required,oneof=true false"`
+	LandingPage      string   `form:"landing_page" validate:"omitempty,startswith=/,startsnotwith=//,max=255"`
+	Permissions      []string `form:"permissions"`
+	RequireTwoFactor bool     `form:"require_two_factor"` // onay kutusu işaretliyse "true" gelir
 }
 
 type ConvertedBaseUserTypeRequest struct {
-	Name     string
-	IsActive *bool
+	Name             string
+	IsActive         *bool
+	LandingPage      string
+	Permissions      []string
+	RequireTwoFactor bool
 }
 
 func (r *BaseUserTypeRequest) Convert() ConvertedBaseUserTypeRequest {
@@ -26,9 +31,22 @@ func (r *BaseUserTypeRequest) Convert() ConvertedBaseUserTypeRequest {
 		isActivePtr = &val
 	}
 
+	permissions := make([]string, 0, len(r.Permissions))
+	seen := make(map[string]bool, len(r.Permissions))
+	for _, p := range r.Permissions {
+		p = strings.TrimSpace(p)
+		if p != "" && !seen[p] {
+			seen[p] = true
+			permissions = append(permissions, p)
+		}
+	}
+
 	return ConvertedBaseUserTypeRequest{
-		Name:     r.Name,
-		IsActive: isActivePtr,
+		Name:             r.Name,
+		IsActive:         isActivePtr,
+		LandingPage:      strings.TrimSpace(r.LandingPage),
+		Permissions:      permissions,
+		RequireTwoFactor: r.RequireTwoFactor,
 	}
 }
 
@@ -43,7 +61,7 @@ func ParseAndValidateUserTypeRequest(c *fiber.Ctx) (UserTypeRequest, map[string]
 		return req, make(map[string]string), errors.New("geçersiz istek formatı")
 	}
 
-	validate := validator.New()
+	validate := newValidator()
 	if err := validate.Struct(req); err != nil {
 		validationErrors := GetUserTypeValidationErrors(err)
 		return req, validationErrors, errors.New("lütfen formdaki hataları düzeltin")
@@ -124,7 +142,7 @@ func ParseAndValidateUserTypeList(c *fiber.Ctx) (UserTypeListParams, map[string]
 		return UserTypeListParams{}, make(map[string]string), errors.New("geçersiz sorgu parametreleri")
 	}
 
-	validate := validator.New()
+	validate := newValidator()
 	if err := validate.Struct(req); err != nil {
 		validationErrors := GetUserTypeListValidationErrors(err)
 		return UserTypeListParams{}, validationErrors, errors.New("lütfen filtreleri kontrol edin")
@@ -135,10 +153,13 @@ func ParseAndValidateUserTypeList(c *fiber.Ctx) (UserTypeListParams, map[string]
 
 func GetUserTypeValidationErrors(err error) map[string]string {
 	errorMessages := map[string]string{
-		"Name_required":     "Kullanıcı tipi adı zorunludur.",
-		"Name_min":          "Kullanıcı tipi adı en az 2 karakter olmalıdır.",
-		"IsActive_required": "Kullanıcı tipi durumu seçilmelidir.",
-		"IsActive_oneof":    "Geçerli bir durum seçiniz (Aktif/Pasif).",
+		"Name_required":             "Kullanıcı tipi adı zorunludur.",
+		"Name_min":                  "Kullanıcı tipi adı en az 2 karakter olmalıdır.",
+		"IsActive_required":         "Kullanıcı tipi durumu seçilmelidir.",
+		"IsActive_oneof":            "Geçerli bir durum seçiniz (Aktif/Pasif).",
+		"LandingPage_startswith":    "Açılış sayfası / ile başlayan bir site içi adres olmalıdır.",
+		"LandingPage_startsnotwith": "Açılış sayfası site içi bir adres olmalıdır.",
+		"LandingPage_max":           "Açılış sayfası en fazla 255 karakter olabilir.",
 	}
 
 	return CommonValidationErrors(err, errorMessages)
@@ -0,0 +1,53 @@
+package requests
+
+import (
+	"zatrano/pkg/trvalidators"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// Türkiye'ye özgü doğrulama etiketleri. Boş değerler için omitempty ile birlikte kullanılmalıdır.
+const (
+	TagTCKN     = "tckn"
+	TagVKN      = "vkn"
+	TagIBAN     = "iban_tr"
+	TagMERSIS   = "mersis"
+	TagTRMobile = "tr_mobile"
+)
+
+// tagMessages, alana özel mesaj tanımlanmamış özel etiketler için CommonValidationErrors'ın kullandığı mesajlardır.
+var tagMessages = map[string]string{
+	TagTCKN:                "Geçerli bir T.C. kimlik numarası giriniz.",
+	TagVKN:                 "Geçerli bir vergi kimlik numarası giriniz.",
+	TagVKN + "|" + TagTCKN: "Geçerli bir vergi kimlik numarası (10 hane) veya T.C. kimlik numarası (11 hane) giriniz.",
+	TagIBAN:                "Geçerli bir TR IBAN giriniz (TR ile başlayan 26 karakter).",
+	TagMERSIS:              "MERSIS numarası 16 haneli olmalıdır.",
+	TagTRMobile:            "Geçerli bir cep telefonu numarası giriniz (05xx xxx xx xx).",
+}
+
+// newValidator, özel etiketleri kayıtlı bir validator döner. Request'ler validator.New() yerine bunu kullanır.
+func newValidator() *validator.Validate {
+	v := validator.New()
+	register := func(tag string, check func(string) bool) {
+		_ = v.RegisterValidation(tag, func(fl validator.FieldLevel) bool {
+			return check(fl.Field().String())
+		})
+	}
+	register(TagTCKN, trvalidators.TCKN)
+	register(TagVKN, trvalidators.VKN)
+	register(TagIBAN, trvalidators.IBAN)
+	register(TagMERSIS, trvalidators.MERSIS)
+	register(TagTRMobile, func(s string) bool {
+		_, ok := trvalidators.NormalizeMobile(s)
+		return ok
+	})
+	return v
+}
+
+// normalizeMobile, doğrulanmış bir cep numarasını E.164 biçimine çevirir; geçersizse değeri olduğu gibi bırakır.
+func normalizeMobile(s string) string {
+	if phone, ok := trvalidators.NormalizeMobile(s); ok {
+		return phone
+	}
+	return s
+}
@@ -0,0 +1,183 @@
+package requests
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// VenueMonthLayout, takvim görünümünde ay seçimi için kullanılan biçimdir.
+const VenueMonthLayout = "2006-01"
+
+type VenueReservationRequest struct {
+	Date          string `form:"date" validate:"required,datetime=2006-01-02"`
+	Session       string `form:"session" validate:"required,oneof=morning evening full_day"`
+	Status        string `form:"status" validate:"required,oneof=option confirmed"`
+	EventType     string `form:"event_type" validate:"omitempty,max=50"`
+	GuestCount    string `form:"guest_count" validate:"required,numeric"`
+	CustomerName  string `form:"customer_name" validate:"required,min=2,max=100"`
+	CustomerPhone string `form:"customer_phone" validate:"required,min=10,max=20"`
+	CustomerEmail string `form:"customer_email" validate:"omitempty,email,max=100"`
+	Note          string `form:"note" validate:"omitempty,max=500"`
+	TotalPrice    string `form:"total_price" validate:"omitempty,numeric"`
+	DepositAmount string `form:"deposit_amount" validate:"omitempty,numeric"`
+}
+
+func ParseAndValidateVenueReservation(c *fiber.Ctx) (VenueReservationRequest, map[string]string, error) {
+	var req VenueReservationRequest
+
+	if err := c.BodyParser(&req); err != nil {
+		return req, make(map[string]string), errors.New("geçersiz istek formatı")
+	}
+	for _, f := range []*string{&req.Date, &req.Session, &req.Status, &req.EventType, &req.GuestCount,
+		&req.CustomerName, &req.CustomerPhone, &req.CustomerEmail, &req.Note} {
+		*f = strings.TrimSpace(*f)
+	}
+	req.TotalPrice = normalizeAmount(req.TotalPrice)
+	req.DepositAmount = normalizeAmount(req.DepositAmount)
+
+	validate := newValidator()
+	if err := validate.Struct(req); err != nil {
+		validationErrors := GetVenueReservationValidationErrors(err)
+		return req, validationErrors, errors.New("lütfen formdaki hataları düzeltin")
+	}
+
+	return req, make(map[string]string), nil
+}
+
+func (r *VenueReservationRequest) GuestCountUint() uint {
+	return parseUint(r.GuestCount)
+}
+
+func (r *VenueReservationRequest) TotalPriceValue() float64 {
+	return parseAmount(r.TotalPrice)
+}
+
+func (r *VenueReservationRequest) DepositAmountValue() float64 {
+	return parseAmount(r.DepositAmount)
+}
+
+type VenueDepositRequest struct {
+	Amount string `form:"amount" json:"amount" validate:"required,numeric"`
+}
+
+func ParseAndValidateVenueDeposit(c *fiber.Ctx) (VenueDepositRequest, map[string]string, error) {
+	var req VenueDepositRequest
+
+	if err := c.BodyParser(&req); err != nil {
+		return req, make(map[string]string), errors.New("geçersiz istek formatı")
+	}
+	req.Amount = normalizeAmount(req.Amount)
+
+	validate := newValidator()
+	if err := validate.Struct(req); err != nil {
+		validationErrors := GetVenueReservationValidationErrors(err)
+		return req, validationErrors, errors.New("geçerli bir kapora tutarı giriniz")
+	}
+
+	return req, make(map[string]string), nil
+}
+
+func (r *VenueDepositRequest) AmountValue() float64 {
+	return parseAmount(r.Amount)
+}
+
+type VenueCalendarRequest struct {
+	BusinessID string `query:"business_id" validate:"omitempty,numeric"`
+	Month      string `query:"month" validate:"omitempty,datetime=2006-01"`
+}
+
+type VenueCalendarParams struct {
+	BusinessID uint
+	// Month, seçilen ayın ilk günüdür (yerel saat diliminde)
+	Month time.Time
+}
+
+func (p VenueCalendarParams) MonthValue() string {
+	return p.Month.Format(VenueMonthLayout)
+}
+
+func (p VenueCalendarParams) PrevMonth() string {
+	return p.Month.AddDate(0, -1, 0).Format(VenueMonthLayout)
+}
+
+func (p VenueCalendarParams) NextMonth() string {
+	return p.Month.AddDate(0, 1, 0).Format(VenueMonthLayout)
+}
+
+// ParseAndValidateVenueCalendar; ay verilmezse içinde bulunulan ay, hatalı girişte de aynı varsayılanlar döner.
+func ParseAndValidateVenueCalendar(c *fiber.Ctx, loc *time.Location) (VenueCalendarParams, map[string]string, error) {
+	now := time.Now().In(loc)
+	params := VenueCalendarParams{Month: time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, loc)}
+
+	var req VenueCalendarRequest
+	if err := c.QueryParser(&req); err != nil {
+		return params, make(map[string]string), errors.New("geçersiz sorgu parametreleri")
+	}
+
+	validate := newValidator()
+	if err := validate.Struct(req); err != nil {
+		validationErrors := GetVenueReservationValidationErrors(err)
+		return params, validationErrors, errors.New("lütfen filtreleri kontrol edin")
+	}
+
+	params.BusinessID = parseUint(req.BusinessID)
+	if req.Month != "" {
+		if month, err := time.ParseInLocation(VenueMonthLayout, req.Month, loc); err == nil {
+			params.Month = month
+		}
+	}
+	return params, make(map[string]string), nil
+}
+
+// normalizeAmount, "1.500,50" gibi Türkçe yazılmış tutarları "1500.50" biçimine çevirir.
+func normalizeAmount(raw string) string {
+	raw = strings.ReplaceAll(strings.TrimSpace(raw), " ", "")
+	if strings.Contains(raw, ",") {
+		raw = strings.ReplaceAll(raw, ".", "")
+		raw = strings.ReplaceAll(raw, ",", ".")
+	}
+	return raw
+}
+
+func parseAmount(s string) float64 {
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil || v < 0 {
+		return 0
+	}
+	return v
+}
+
+func GetVenueReservationValidationErrors(err error) map[string]string {
+	errorMessages := map[string]string{
+		"Date_required":          "Tarih seçilmelidir.",
+		"Date_datetime":          "Geçerli bir tarih seçiniz.",
+		"Session_required":       "Seans seçilmelidir.",
+		"Session_oneof":          "Geçerli bir seans seçiniz.",
+		"Status_required":        "Rezervasyon türü seçilmelidir.",
+		"Status_oneof":           "Rezervasyon türü opsiyon veya kesin olmalıdır.",
+		"EventType_max":          "Etkinlik türü en fazla 50 karakter olabilir.",
+		"GuestCount_required":    "Davetli sayısı zorunludur.",
+		"GuestCount_numeric":     "Davetli sayısı sayı olmalıdır.",
+		"CustomerName_required":  "Ad soyad zorunludur.",
+		"CustomerName_min":       "Ad soyad en az 2 karakter olmalıdır.",
+		"CustomerName_max":       "Ad soyad en fazla 100 karakter olabilir.",
+		"CustomerPhone_required": "Telefon numarası zorunludur.",
+		"CustomerPhone_min":      "Geçerli bir telefon numarası giriniz.",
+		"CustomerPhone_max":      "Telefon numarası en fazla 20 karakter olabilir.",
+		"CustomerEmail_email":    "Geçerli bir e-posta adresi giriniz.",
+		"CustomerEmail_max":      "E-posta en fazla 100 karakter olabilir.",
+		"Note_max":               "Not en fazla 500 karakter olabilir.",
+		"TotalPrice_numeric":     "Toplam ücret sayı olmalıdır.",
+		"DepositAmount_numeric":  "Kapora tutarı sayı olmalıdır.",
+		"Amount_required":        "Kapora tutarı zorunludur.",
+		"Amount_numeric":         "Kapora tutarı sayı olmalıdır.",
+		"BusinessID_numeric":     "Geçerli bir mekan seçiniz.",
+		"Month_datetime":         "Geçerli bir ay seçiniz.",
+	}
+
+	return CommonValidationErrors(err, errorMessages)
+}
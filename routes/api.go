@@ -0,0 +1,36 @@
+package routes
+
+import (
+	handlers "zatrano/handlers/api"
+	"zatrano/middlewares"
+	"zatrano/models"
+	"zatrano/services"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func registerAPIRoutes(app *fiber.App) {
+	apiGroup := app.Group(handlers.APIVersionPrefix,
+		middlewares.APIErrors(),
+		middlewares.APITokenAuth(services.NewPersonalAccessTokenService()),
+	)
+
+	tokenHandler := handlers.NewAPITokenHandler()
+	apiGroup.Get("/me", tokenHandler.Me)
+
+	// İşletmeler: anahtar sahibinin işletmeleri; panel erişimi olmayan rollerin anahtarları kullanamaz
+	businessHandler := handlers.NewAPIBusinessHandler()
+	canRead := middlewares.RequireTokenScope(models.TokenScopeBusinessesRead, models.PermissionPanelAccess)
+	canWrite := middlewares.RequireTokenScope(models.TokenScopeBusinessesWrite, models.PermissionPanelAccess)
+	apiGroup.Get("/business-types", canRead, businessHandler.ListBusinessTypes)
+	apiGroup.Get("/businesses", canRead, businessHandler.ListBusinesses)
+	apiGroup.Get("/businesses/:id", canRead, businessHandler.GetBusiness)
+	apiGroup.Post("/businesses", canWrite, businessHandler.CreateBusiness)
+	apiGroup.Put("/businesses/:id", canWrite, businessHandler.UpdateBusiness)
+	apiGroup.Delete("/businesses/:id", canWrite, businessHandler.DeleteBusiness)
+
+	// Tanımsız API yolları da JSON hata zarfıyla döner; istek web rotalarına düşmez
+	apiGroup.Use(func(c *fiber.Ctx) error {
+		return fiber.NewError(fiber.StatusNotFound, "İstenen API uç noktası bulunamadı.")
+	})
+}
@@ -21,10 +21,28 @@ func registerAuthRoutes(app *fiber.App) {
 		authHandler.Login,
 	)
 
+	// İki adımlı doğrulama: parola doğrulandıktan sonraki ikinci adım (login limiter'ı ile aynı sayaç)
+	authGroup.Get("/two-factor", middlewares.GuestMiddleware, authHandler.ShowTwoFactorChallenge)
+	authGroup.Post("/two-factor",
+		middlewares.GuestMiddleware,
+		middlewares.LoginRateLimit(),
+		requests.ValidateTwoFactorChallengeRequest,
+		authHandler.TwoFactorChallenge,
+	)
+	authGroup.Get("/two-factor/setup", middlewares.AuthMiddleware, authHandler.ShowTwoFactorSetup)
+	authGroup.Post("/two-factor/enable", middlewares.AuthMiddleware, requests.ValidateTwoFactorCodeRequest("/auth/two-factor/setup"), authHandler.EnableTwoFactor)
+	authGroup.Post("/two-factor/disable", middlewares.AuthMiddleware, requests.ValidateTwoFactorCodeRequest("/auth/profile"), authHandler.DisableTwoFactor)
+	authGroup.Post("/two-factor/recovery-codes", middlewares.AuthMiddleware, requests.ValidateTwoFactorCodeRequest("/auth/profile"), authHandler.RegenerateRecoveryCodes)
+	authGroup.Post("/two-factor/forget-devices", middlewares.AuthMiddleware, authHandler.ForgetTrustedDevices)
+
 	authGroup.Get("/logout", middlewares.AuthMiddleware, authHandler.Logout)
 	authGroup.Get("/profile", middlewares.AuthMiddleware, authHandler.Profile)
 	authGroup.Post("/profile/update-password", middlewares.AuthMiddleware, requests.ValidateUpdatePasswordRequest, authHandler.UpdatePassword)
 	authGroup.Post("/profile/update-info", middlewares.AuthMiddleware, requests.ValidateUpdateInfoRequest, authHandler.UpdateInfo)
+	authGroup.Post("/sessions/revoke-others", middlewares.AuthMiddleware, authHandler.RevokeOtherSessions)
+	authGroup.Post("/sessions/:handle/revoke", middlewares.AuthMiddleware, authHandler.RevokeSession)
+	authGroup.Post("/access-tokens", middlewares.AuthMiddleware, requests.ValidateAccessTokenRequest, authHandler.CreateAccessToken)
+	authGroup.Post("/access-tokens/:id/revoke", middlewares.AuthMiddleware, authHandler.RevokeAccessToken)
 
 	authGroup.Get("/register", middlewares.GuestMiddleware, authHandler.ShowRegister)
 	authGroup.Post("/register", middlewares.GuestMiddleware, requests.ValidateRegisterRequest, authHandler.Register)
@@ -35,10 +53,36 @@ func registerAuthRoutes(app *fiber.App) {
 	authGroup.Get("/reset-password", middlewares.GuestMiddleware, authHandler.ShowResetPassword)
 	authGroup.Post("/reset-password", middlewares.GuestMiddleware, requests.ValidateResetPasswordRequest, authHandler.ResetPassword)
 
+	// Parolasız giriş: e-postayla gönderilen, isteyen tarayıcıya bağlı tek kullanımlık bağlantı
+	authGroup.Get("/magic-link", middlewares.GuestMiddleware, authHandler.ShowMagicLink)
+	authGroup.Post("/magic-link",
+		middlewares.GuestMiddleware,
+		middlewares.LoginRateLimit(),
+		requests.ValidateMagicLinkRequest,
+		authHandler.SendMagicLink,
+	)
+	authGroup.Get("/magic-link/verify", middlewares.GuestMiddleware, authHandler.MagicLinkLogin)
+
+	// Geçiş anahtarları (WebAuthn): tarayıcıdaki betik JSON ile begin/finish törenlerini yürütür
+	authGroup.Post("/passkey/login/begin", middlewares.GuestMiddleware, authHandler.BeginPasskeyLogin)
+	authGroup.Post("/passkey/login/finish",
+		middlewares.GuestMiddleware,
+		middlewares.LoginRateLimit(),
+		authHandler.FinishPasskeyLogin,
+	)
+	authGroup.Post("/passkey/register/begin", middlewares.AuthMiddleware, authHandler.BeginPasskeyRegistration)
+	authGroup.Post("/passkey/register/finish", middlewares.AuthMiddleware, authHandler.FinishPasskeyRegistration)
+	authGroup.Post("/passkey/:id/delete", middlewares.AuthMiddleware, authHandler.DeletePasskey)
+
 	authGroup.Get("/verify-email", middlewares.GuestMiddleware, authHandler.VerifyEmail)
 	authGroup.Get("/resend-verification", middlewares.GuestMiddleware, authHandler.ShowResendVerification)
 	authGroup.Post("/resend-verification", middlewares.GuestMiddleware, requests.ValidateResendVerificationRequest, authHandler.ResendVerification)
 
-	authGroup.Get("/google/login", middlewares.GuestMiddleware, handlers.GoogleLogin)
-	authGroup.Get("/google/callback", middlewares.GuestMiddleware, handlers.GoogleCallback)
+	// Dış kimlik sağlayıcıları (Google, Microsoft, Facebook, Apple, genel OIDC). Geri dönüş hem giriş
+	// hem hesap bağlama için kullanıldığından misafir kontrolü yoktur; Apple form POST ile döner.
+	authGroup.Get("/:provider/login", middlewares.GuestMiddleware, authHandler.OAuthLogin)
+	authGroup.Get("/:provider/callback", authHandler.OAuthCallback)
+	authGroup.Post("/:provider/callback", authHandler.OAuthCallback)
+	authGroup.Post("/:provider/link", middlewares.AuthMiddleware, authHandler.LinkOAuth)
+	authGroup.Post("/:provider/unlink", middlewares.AuthMiddleware, authHandler.UnlinkOAuth)
 }
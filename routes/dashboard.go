@@ -3,6 +3,7 @@ package routes
 import (
 	handlers "zatrano/handlers/dashboard"
 	"zatrano/middlewares"
+	"zatrano/models"
 
 	"github.com/gofiber/fiber/v2"
 )
@@ -11,7 +12,7 @@ func registerDashboardRoutes(app *fiber.App) {
 	dashboardGroup := app.Group("/dashboard")
 	dashboardGroup.Use(
 		middlewares.AuthMiddleware,
-		middlewares.UserTypeMiddleware(1),
+		middlewares.RequirePermission(models.PermissionDashboardAccess),
 	)
 
 	// Dashboard anasayfa
@@ -21,19 +22,41 @@ func registerDashboardRoutes(app *fiber.App) {
 
 	// Kullanıcı türleri yönetimi
 	userTypeHandler := handlers.NewDashboardUserTypeHandler()
-	dashboardGroup.Get("/user-types", userTypeHandler.ListUserTypes)
-	dashboardGroup.Get("/user-types/create", userTypeHandler.ShowCreateUserType)
-	dashboardGroup.Post("/user-types/create", userTypeHandler.CreateUserType)
-	dashboardGroup.Get("/user-types/update/:id", userTypeHandler.ShowUpdateUserType)
-	dashboardGroup.Post("/user-types/update/:id", userTypeHandler.UpdateUserType)
-	dashboardGroup.Delete("/user-types/delete/:id", userTypeHandler.DeleteUserType)
+	manageUserTypes := middlewares.RequirePermission(models.PermissionUserTypesManage)
+	dashboardGroup.Get("/user-types", manageUserTypes, userTypeHandler.ListUserTypes)
+	dashboardGroup.Get("/user-types/create", manageUserTypes, userTypeHandler.ShowCreateUserType)
+	dashboardGroup.Post("/user-types/create", manageUserTypes, userTypeHandler.CreateUserType)
+	dashboardGroup.Get("/user-types/update/:id", manageUserTypes, userTypeHandler.ShowUpdateUserType)
+	dashboardGroup.Post("/user-types/update/:id", manageUserTypes, userTypeHandler.UpdateUserType)
+	dashboardGroup.Delete("/user-types/delete/:id", manageUserTypes, userTypeHandler.DeleteUserType)
 
 	// Kullanıcı yönetimi
 	userHandler := handlers.NewDashboardUserHandler()
-	dashboardGroup.Get("/users", userHandler.ListUsers)
-	dashboardGroup.Get("/users/create", userHandler.ShowCreateUser)
-	dashboardGroup.Post("/users/create", userHandler.CreateUser)
-	dashboardGroup.Get("/users/update/:id", userHandler.ShowUpdateUser)
-	dashboardGroup.Post("/users/update/:id", userHandler.UpdateUser)
-	dashboardGroup.Delete("/users/delete/:id", userHandler.DeleteUser)
+	manageUsers := middlewares.RequirePermission(models.PermissionUsersManage)
+	dashboardGroup.Get("/users", manageUsers, userHandler.ListUsers)
+	dashboardGroup.Get("/users/create", manageUsers, userHandler.ShowCreateUser)
+	dashboardGroup.Post("/users/create", manageUsers, userHandler.CreateUser)
+	dashboardGroup.Get("/users/update/:id", manageUsers, userHandler.ShowUpdateUser)
+	dashboardGroup.Post("/users/update/:id", manageUsers, userHandler.UpdateUser)
+	dashboardGroup.Post("/users/reset-two-factor/:id", manageUsers, userHandler.ResetTwoFactor)
+	dashboardGroup.Delete("/users/delete/:id", manageUsers, userHandler.DeleteUser)
+
+	// Başarısız girişler nedeniyle kilitlenen hesap ve IP adresleri
+	lockoutHandler := handlers.NewDashboardLoginLockoutHandler()
+	dashboardGroup.Get("/login-lockouts", manageUsers, lockoutHandler.ListLockouts)
+	dashboardGroup.Post("/login-lockouts/unlock/:id", manageUsers, lockoutHandler.Unlock)
+
+	// Yorum moderasyonu
+	reviewHandler := handlers.NewDashboardReviewHandler()
+	moderateReviews := middlewares.RequirePermission(models.PermissionReviewsModerate)
+	dashboardGroup.Get("/reviews", moderateReviews, reviewHandler.ListReviews)
+	dashboardGroup.Post("/reviews/approve/:id", moderateReviews, reviewHandler.ApproveReview)
+	dashboardGroup.Post("/reviews/reject/:id", moderateReviews, reviewHandler.RejectReview)
+
+	// Denetim kayıtları (yalnızca okunur)
+	auditLogHandler := handlers.NewDashboardAuditLogHandler()
+	viewAuditLogs := middlewares.RequirePermission(models.PermissionAuditLogsView)
+	dashboardGroup.Get("/audit-logs", viewAuditLogs, auditLogHandler.ListAuditLogs)
+	dashboardGroup.Get("/audit-logs/history/:entity/:id", viewAuditLogs, auditLogHandler.EntityHistory)
+	dashboardGroup.Get("/audit-logs/:id", viewAuditLogs, auditLogHandler.ShowAuditLog)
 }
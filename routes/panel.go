@@ -3,6 +3,7 @@ package routes
 import (
 	handlers "zatrano/handlers/panel"
 	"zatrano/middlewares"
+	"zatrano/models"
 
 	"github.com/gofiber/fiber/v2"
 )
@@ -11,10 +12,63 @@ func registerPanelRoutes(app *fiber.App) {
 	panelGroup := app.Group("/panel")
 	panelGroup.Use(
 		middlewares.AuthMiddleware,
-		middlewares.UserTypeMiddleware(2),
+		middlewares.RequirePermission(models.PermissionPanelAccess),
 	)
 
 	panelHomeHandler := handlers.NewPanelHomeHandler()
 	panelGroup.Get("/", panelHomeHandler.HomePage)
 	panelGroup.Get("/anasayfa", panelHomeHandler.HomePage)
+
+	// İşletme yönetimi
+	businessHandler := handlers.NewPanelBusinessHandler()
+	panelGroup.Get("/isletmeler", businessHandler.ListBusinesses)
+	panelGroup.Get("/isletmeler/olustur", businessHandler.ShowCreateBusiness)
+	panelGroup.Post("/isletmeler/olustur", businessHandler.CreateBusiness)
+	panelGroup.Get("/isletmeler/guncelle/:id", businessHandler.ShowUpdateBusiness)
+	panelGroup.Post("/isletmeler/guncelle/:id", businessHandler.UpdateBusiness)
+	panelGroup.Delete("/isletmeler/sil/:id", businessHandler.DeleteBusiness)
+
+	// İşletme galerisi
+	galleryHandler := handlers.NewPanelGalleryHandler()
+	panelGroup.Get("/isletmeler/:id/galeri", galleryHandler.ShowGallery)
+	panelGroup.Post("/isletmeler/:id/galeri", galleryHandler.UploadImages)
+	panelGroup.Post("/isletmeler/:id/galeri/sirala", galleryHandler.ReorderImages)
+	panelGroup.Post("/isletmeler/:id/galeri/:imageId/aciklama", galleryHandler.UpdateCaption)
+	panelGroup.Post("/isletmeler/:id/galeri/:imageId/kapak", galleryHandler.SetCover)
+	panelGroup.Delete("/isletmeler/:id/galeri/:imageId", galleryHandler.DeleteImage)
+
+	// Randevu yönetimi
+	appointmentHandler := handlers.NewPanelAppointmentHandler()
+	panelGroup.Get("/randevular", appointmentHandler.ListAppointments)
+	panelGroup.Post("/randevular/onayla/:id", appointmentHandler.ConfirmAppointment)
+	panelGroup.Post("/randevular/iptal/:id", appointmentHandler.CancelAppointment)
+	panelGroup.Post("/randevular/ertele/:id", appointmentHandler.RescheduleAppointment)
+	panelGroup.Get("/randevular/saatler/:id", appointmentHandler.RescheduleSlots)
+
+	// Salon rezervasyonları (kapasitesi tanımlı işletmeler)
+	reservationHandler := handlers.NewPanelVenueReservationHandler()
+	panelGroup.Get("/rezervasyonlar", reservationHandler.ShowCalendar)
+	panelGroup.Get("/rezervasyonlar/:id/olustur", reservationHandler.ShowCreateReservation)
+	panelGroup.Post("/rezervasyonlar/:id/olustur", reservationHandler.CreateReservation)
+	panelGroup.Post("/rezervasyonlar/kesinlestir/:id", reservationHandler.ConfirmReservation)
+	panelGroup.Post("/rezervasyonlar/kapora/:id", reservationHandler.RecordDeposit)
+	panelGroup.Post("/rezervasyonlar/iptal/:id", reservationHandler.CancelReservation)
+
+	// Müşteri yorumları ve yanıtlar
+	reviewHandler := handlers.NewPanelReviewHandler()
+	panelGroup.Get("/yorumlar", reviewHandler.ListReviews)
+	panelGroup.Post("/yorumlar/yanitla/:id", reviewHandler.ReplyReview)
+
+	// Uzman çalışma saatleri ve izinler
+	scheduleHandler := handlers.NewPanelScheduleHandler()
+	panelGroup.Get("/calisma-saatleri", scheduleHandler.ListProfessionals)
+	panelGroup.Get("/calisma-saatleri/:id", scheduleHandler.ShowSchedule)
+	panelGroup.Post("/calisma-saatleri/:id", scheduleHandler.SaveSchedule)
+	panelGroup.Post("/calisma-saatleri/:id/izin", scheduleHandler.AddTimeOff)
+	panelGroup.Delete("/calisma-saatleri/:id/izin/:timeOffId", scheduleHandler.DeleteTimeOff)
+
+	// Adres seçicisi (ülke -> il -> ilçe)
+	locationHandler := handlers.NewPanelLocationHandler()
+	panelGroup.Get("/konum/iller/:countryId", locationHandler.Cities)
+	panelGroup.Get("/konum/ilceler/:cityId", locationHandler.Districts)
 }
@@ -8,6 +8,7 @@ import (
 
 func SetupRoutes(app *fiber.App) {
 	// global limiter & middleware’lar
+	app.Use(middlewares.RequestMeta())
 	app.Use(middlewares.GlobalRateLimit())
 	app.Use(middlewares.FormPostRateLimit())
 
@@ -19,6 +20,12 @@ func SetupRoutes(app *fiber.App) {
 	registerDashboardRoutes(app)
 	registerPanelRoutes(app)
 
+	// sağlayıcı geri çağrıları (CSRF muaf)
+	registerWebhookRoutes(app)
+
+	// kişisel erişim anahtarıyla kullanılan JSON API (CSRF muaf)
+	registerAPIRoutes(app)
+
 	// web/public area
 	registerWebsiteRoutes(app)
 }
@@ -0,0 +1,15 @@
+package routes
+
+import (
+	handlers "zatrano/handlers/webhook"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func registerWebhookRoutes(app *fiber.App) {
+	smsWebhookHandler := handlers.NewSmsWebhookHandler()
+
+	webhookGroup := app.Group("/webhooks")
+	webhookGroup.Get("/sms/:provider", smsWebhookHandler.DeliveryReport)
+	webhookGroup.Post("/sms/:provider", smsWebhookHandler.DeliveryReport)
+}
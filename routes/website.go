@@ -10,6 +10,24 @@ func registerWebsiteRoutes(app *fiber.App) {
 	websiteHandler := handlers.NewWebsiteHandler()
 	app.Get("/", websiteHandler.HomePage)
 	app.Get("/kullanim-sartlari", websiteHandler.KullanimSartlari)
+
+	businessDirectoryHandler := handlers.NewBusinessDirectoryHandler()
+	app.Get("/isletmeler", businessDirectoryHandler.ListBusinesses)
+
+	businessProfileHandler := handlers.NewBusinessProfileHandler()
+	app.Get("/isletme/:slug", businessProfileHandler.ShowBusiness)
+
+	// Randevu alma ve müşterinin randevusunu yönetmesi
+	appointmentHandler := handlers.NewAppointmentHandler()
+	app.Get("/isletme/:slug/randevu", appointmentHandler.ShowBooking)
+	app.Get("/isletme/:slug/randevu/saatler", appointmentHandler.Slots)
+	app.Post("/isletme/:slug/randevu", appointmentHandler.Book)
+	app.Get("/randevu/:token", appointmentHandler.ShowAppointment)
+	app.Get("/randevu/:token/saatler", appointmentHandler.RescheduleSlots)
+	app.Post("/randevu/:token/ertele", appointmentHandler.Reschedule)
+	app.Post("/randevu/:token/iptal", appointmentHandler.Cancel)
+	app.Post("/randevu/:token/degerlendir", appointmentHandler.Review)
+
 	app.Get("/dijital-acilis-davetiyesi", websiteHandler.Acilis)
 	app.Get("/dijital-after-party-davetiyesi", websiteHandler.AfterParty)
 	app.Get("/dijital-anitkabir-ziyareti-davetiyesi", websiteHandler.AnitkabirZiyareti)
@@ -0,0 +1,128 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	_ "time/tzdata"
+
+	"zatrano/configs/envconfig"
+	"zatrano/configs/logconfig"
+	"zatrano/models"
+	"zatrano/pkg/slots"
+
+	"go.uber.org/zap"
+)
+
+var appointmentLocation = loadAppointmentLocation()
+
+// AppointmentLocation, randevu saatlerinin yorumlandığı saat dilimidir (APP_TIMEZONE, varsayılan Europe/Istanbul).
+func AppointmentLocation() *time.Location {
+	return appointmentLocation
+}
+
+func loadAppointmentLocation() *time.Location {
+	name := envconfig.String("APP_TIMEZONE", "Europe/Istanbul")
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		// Türkiye 2016'dan beri yaz saati uygulamıyor; sabit +03:00 güvenli bir yedektir
+		return time.FixedZone("TRT", 3*60*60)
+	}
+	return loc
+}
+
+// IWorkingHoursProvider, bir uzmanın [from, to) aralığındaki her gün için çalışma pencerelerini
+// (molalar, tatiller ve izinler düşülmüş) "2006-01-02" anahtarıyla döner.
+// from ve to, AppointmentLocation saat diliminde gün başlangıçlarıdır; ay görünümü tek çağrıda hesaplanır.
+type IWorkingHoursProvider interface {
+	Windows(ctx context.Context, professional *models.Professional, from, to time.Time) (map[string][]slots.Interval, error)
+}
+
+// envWorkingHours, haftalık takvimi girilmemiş uzmanlar için ortam değişkenlerinden okunan varsayılan saatlerdir.
+type envWorkingHours struct {
+	start      clockTime
+	end        clockTime
+	breaks     [][2]clockTime
+	closedDays map[time.Weekday]bool
+}
+
+// newEnvWorkingHours, APPOINTMENT_DAY_START/END (ss:dd), APPOINTMENT_BREAKS (ss:dd-ss:dd,...) ve
+// APPOINTMENT_CLOSED_DAYS (0=Pazar ... 6=Cumartesi, virgülle) değişkenlerini okur.
+func newEnvWorkingHours() *envWorkingHours {
+	h := &envWorkingHours{closedDays: make(map[time.Weekday]bool)}
+
+	var ok bool
+	if h.start, ok = parseClock(envconfig.String("APPOINTMENT_DAY_START", "09:00")); !ok {
+		h.start = clockTime{9, 0}
+	}
+	if h.end, ok = parseClock(envconfig.String("APPOINTMENT_DAY_END", "18:00")); !ok {
+		h.end = clockTime{18, 0}
+	}
+
+	for _, part := range strings.Split(envconfig.String("APPOINTMENT_BREAKS", "12:30-13:30"), ",") {
+		bounds := strings.SplitN(strings.TrimSpace(part), "-", 2)
+		if len(bounds) != 2 {
+			continue
+		}
+		from, okFrom := parseClock(bounds[0])
+		to, okTo := parseClock(bounds[1])
+		if !okFrom || !okTo {
+			logconfig.Log.Warn("Geçersiz mola tanımı atlandı", zap.String("break", part))
+			continue
+		}
+		h.breaks = append(h.breaks, [2]clockTime{from, to})
+	}
+
+	for _, part := range strings.Split(envconfig.String("APPOINTMENT_CLOSED_DAYS", "0"), ",") {
+		if d, err := strconv.Atoi(strings.TrimSpace(part)); err == nil && d >= 0 && d <= 6 {
+			h.closedDays[time.Weekday(d)] = true
+		}
+	}
+	return h
+}
+
+func (h *envWorkingHours) Windows(_ context.Context, _ *models.Professional, from, to time.Time) (map[string][]slots.Interval, error) {
+	result := make(map[string][]slots.Interval)
+	for day := from; day.Before(to); day = day.AddDate(0, 0, 1) {
+		result[day.Format("2006-01-02")] = h.day(day)
+	}
+	return result, nil
+}
+
+func (h *envWorkingHours) day(day time.Time) []slots.Interval {
+	if h.closedDays[day.Weekday()] {
+		return nil
+	}
+	windows := []slots.Interval{{Start: h.start.On(day), End: h.end.On(day)}}
+	var cuts []slots.Interval
+	for _, b := range h.breaks {
+		cuts = append(cuts, slots.Interval{Start: b[0].On(day), End: b[1].On(day)})
+	}
+	return slots.Subtract(windows, cuts)
+}
+
+// clockTime, gün içi saat:dakika değeridir.
+type clockTime struct {
+	Hour   int
+	Minute int
+}
+
+func (c clockTime) On(day time.Time) time.Time {
+	return time.Date(day.Year(), day.Month(), day.Day(), c.Hour, c.Minute, 0, 0, day.Location())
+}
+
+func (c clockTime) String() string {
+	return fmt.Sprintf("%02d:%02d", c.Hour, c.Minute)
+}
+
+func parseClock(raw string) (clockTime, bool) {
+	t, err := time.Parse("15:04", strings.TrimSpace(raw))
+	if err != nil {
+		return clockTime{}, false
+	}
+	return clockTime{Hour: t.Hour(), Minute: t.Minute()}, true
+}
+
+var _ IWorkingHoursProvider = (*envWorkingHours)(nil)
@@ -0,0 +1,417 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"zatrano/configs/envconfig"
+	"zatrano/configs/logconfig"
+	"zatrano/models"
+	"zatrano/pkg/slots"
+	"zatrano/repositories"
+	"zatrano/requests"
+
+	"go.uber.org/zap"
+)
+
+var (
+	ErrAppointmentNotFound        = errors.New("randevu bulunamadı")
+	ErrAppointmentServiceNotFound = errors.New("hizmet bulunamadı")
+	ErrAppointmentSlotTaken       = errors.New("seçilen saat az önce doldu, lütfen başka bir saat seçin")
+	ErrAppointmentSlotUnavailable = errors.New("seçilen saat randevuya uygun değil")
+	ErrAppointmentInvalidDate     = errors.New("geçersiz tarih")
+	ErrAppointmentTooLate         = errors.New("randevuya çok az süre kaldığı için değişiklik yapılamaz")
+	ErrAppointmentNotPending      = errors.New("yalnızca onay bekleyen randevular onaylanabilir")
+	ErrAppointmentInvalidPhone    = errors.New("geçerli bir cep telefonu numarası giriniz")
+)
+
+// AppointmentSlots, bir hizmet için seçilen gündeki boş başlangıç saatlerini taşır.
+type AppointmentSlots struct {
+	Service *models.ProfessionalService
+	Day     time.Time
+	Starts  []time.Time
+}
+
+// IAppointmentService, randevu alma, uygun saat hesaplama ve
+// müşteri/işletme tarafındaki onay, iptal ve erteleme akışlarını tanımlar.
+type IAppointmentService interface {
+	AvailableSlots(ctx context.Context, businessID, professionalServiceID uint, date string) (*AppointmentSlots, error)
+	Book(ctx context.Context, businessID uint, req requests.AppointmentRequest) (*models.Appointment, error)
+	// RescheduleSlots, mevcut randevunun kendi aralığını dolu saymadan boş saatleri hesaplar.
+	RescheduleSlots(ctx context.Context, appointment *models.Appointment, date string) (*AppointmentSlots, error)
+
+	// Müşteri akışları; randevu, oluştururken verilen yönetim bağlantısındaki token ile bulunur
+	GetByToken(ctx context.Context, token string) (*models.Appointment, error)
+	CancelByCustomer(ctx context.Context, token, reason string) error
+	RescheduleByCustomer(ctx context.Context, token, startsAt string) error
+
+	// İşletme akışları; yalnızca işletme sahibinin randevularına erişilebilir
+	GetOwnerAppointments(ctx context.Context, ownerID uint, params requests.AppointmentListParams) (*requests.PaginatedResult, error)
+	GetOwnerAppointment(ctx context.Context, ownerID, id uint) (*models.Appointment, error)
+	Confirm(ctx context.Context, ownerID, id uint) error
+	CancelByBusiness(ctx context.Context, ownerID, id uint, reason string) error
+	RescheduleByBusiness(ctx context.Context, ownerID, id uint, startsAt string) error
+}
+
+type AppointmentService struct {
+	repo         repositories.IAppointmentRepository
+	hours        IWorkingHoursProvider
+	step         time.Duration
+	minNotice    time.Duration
+	cancelNotice time.Duration
+	maxDaysAhead int
+	autoConfirm  bool
+	now          func() time.Time
+}
+
+// NewAppointmentService; APPOINTMENT_SLOT_STEP_MINUTES slot aralığını, APPOINTMENT_MIN_NOTICE_MINUTES en erken
+// randevu süresini, APPOINTMENT_CANCEL_NOTICE_MINUTES müşterinin son iptal/erteleme süresini,
+// APPOINTMENT_MAX_DAYS_AHEAD ileri tarih sınırını belirler. APPOINTMENT_AUTO_CONFIRM=true ise randevular onaylı oluşur.
+func NewAppointmentService() IAppointmentService {
+	return &AppointmentService{
+		repo:         repositories.NewAppointmentRepository(),
+		hours:        NewScheduleWorkingHours(),
+		step:         time.Duration(envconfig.Int("APPOINTMENT_SLOT_STEP_MINUTES", 15)) * time.Minute,
+		minNotice:    time.Duration(envconfig.Int("APPOINTMENT_MIN_NOTICE_MINUTES", 60)) * time.Minute,
+		cancelNotice: time.Duration(envconfig.Int("APPOINTMENT_CANCEL_NOTICE_MINUTES", 120)) * time.Minute,
+		maxDaysAhead: envconfig.Int("APPOINTMENT_MAX_DAYS_AHEAD", 60),
+		autoConfirm:  envconfig.String("APPOINTMENT_AUTO_CONFIRM", "false") == "true",
+		now:          time.Now,
+	}
+}
+
+func (s *AppointmentService) AvailableSlots(ctx context.Context, businessID, professionalServiceID uint, date string) (*AppointmentSlots, error) {
+	day, err := time.ParseInLocation("2006-01-02", date, appointmentLocation)
+	if err != nil {
+		return nil, ErrAppointmentInvalidDate
+	}
+	item, err := s.bookableService(ctx, businessID, professionalServiceID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &AppointmentSlots{Service: item, Day: day}
+	if !s.withinBookingRange(day) {
+		return result, nil
+	}
+
+	free, err := s.freeSlots(ctx, item, day, 0)
+	if err != nil {
+		return nil, err
+	}
+	result.Starts = free
+	return result, nil
+}
+
+func (s *AppointmentService) RescheduleSlots(ctx context.Context, appointment *models.Appointment, date string) (*AppointmentSlots, error) {
+	day, err := time.ParseInLocation("2006-01-02", date, appointmentLocation)
+	if err != nil {
+		return nil, ErrAppointmentInvalidDate
+	}
+	item := appointmentSnapshot(appointment)
+	if item == nil {
+		return nil, ErrAppointmentServiceNotFound
+	}
+
+	result := &AppointmentSlots{Service: item, Day: day}
+	if !appointment.Blocks() || !s.withinBookingRange(day) {
+		return result, nil
+	}
+
+	free, err := s.freeSlots(ctx, item, day, appointment.ID)
+	if err != nil {
+		return nil, err
+	}
+	result.Starts = free
+	return result, nil
+}
+
+func (s *AppointmentService) Book(ctx context.Context, businessID uint, req requests.AppointmentRequest) (*models.Appointment, error) {
+	phone, ok := NormalizeTRPhone(req.CustomerPhone)
+	if !ok {
+		return nil, ErrAppointmentInvalidPhone
+	}
+	item, err := s.bookableService(ctx, businessID, req.ProfessionalServiceIDUint())
+	if err != nil {
+		return nil, err
+	}
+	startsAt, err := s.checkStart(ctx, item, req.StartsAt, 0)
+	if err != nil {
+		return nil, err
+	}
+	token, err := newAppointmentToken()
+	if err != nil {
+		return nil, err
+	}
+
+	status := models.AppointmentStatusPending
+	if s.autoConfirm {
+		status = models.AppointmentStatusConfirmed
+	}
+	appointment := &models.Appointment{
+		BusinessID:            businessID,
+		ProfessionalID:        item.ProfessionalID,
+		ProfessionalServiceID: item.ID,
+		StartsAt:              startsAt,
+		EndsAt:                startsAt.Add(time.Duration(item.Duration) * time.Minute),
+		Duration:              item.Duration,
+		Price:                 item.Price,
+		CustomerName:          req.CustomerName,
+		CustomerPhone:         phone,
+		CustomerEmail:         req.CustomerEmail,
+		Note:                  req.Note,
+		Status:                status,
+		ManageToken:           token,
+	}
+
+	if err := s.repo.Book(ctx, appointment); err != nil {
+		if errors.Is(err, repositories.ErrSlotTaken) {
+			return nil, ErrAppointmentSlotTaken
+		}
+		logconfig.Log.Error("Randevu oluşturulamadı", zap.Uint("professional_service_id", item.ID), zap.Error(err))
+		return nil, err
+	}
+	return appointment, nil
+}
+
+func (s *AppointmentService) GetByToken(ctx context.Context, token string) (*models.Appointment, error) {
+	if token == "" {
+		return nil, ErrAppointmentNotFound
+	}
+	appointment, err := s.repo.FindByToken(ctx, token)
+	if err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return nil, ErrAppointmentNotFound
+		}
+		return nil, err
+	}
+	return appointment, nil
+}
+
+func (s *AppointmentService) CancelByCustomer(ctx context.Context, token, reason string) error {
+	appointment, err := s.GetByToken(ctx, token)
+	if err != nil {
+		return err
+	}
+	if err := s.checkCustomerChangeAllowed(appointment); err != nil {
+		return err
+	}
+	return s.cancel(ctx, appointment, models.AppointmentActorCustomer, reason)
+}
+
+// RescheduleByCustomer, randevuyu yeni saate taşır ve işletmenin yeniden onayına düşürür.
+func (s *AppointmentService) RescheduleByCustomer(ctx context.Context, token, startsAt string) error {
+	appointment, err := s.GetByToken(ctx, token)
+	if err != nil {
+		return err
+	}
+	if err := s.checkCustomerChangeAllowed(appointment); err != nil {
+		return err
+	}
+	status := models.AppointmentStatusPending
+	if s.autoConfirm {
+		status = models.AppointmentStatusConfirmed
+	}
+	return s.reschedule(ctx, appointment, startsAt, status)
+}
+
+func (s *AppointmentService) GetOwnerAppointments(ctx context.Context, ownerID uint, params requests.AppointmentListParams) (*requests.PaginatedResult, error) {
+	appointments, total, err := s.repo.GetOwnerAppointments(ctx, ownerID, params)
+	if err != nil {
+		logconfig.Log.Error("Randevular getirilemedi", zap.Uint("owner_id", ownerID), zap.Error(err))
+		return nil, err
+	}
+	return requests.CreatePaginatedResult(appointments, total, params.Page, params.PerPage), nil
+}
+
+func (s *AppointmentService) GetOwnerAppointment(ctx context.Context, ownerID, id uint) (*models.Appointment, error) {
+	appointment, err := s.repo.GetOwnerAppointmentByID(ctx, ownerID, id)
+	if err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return nil, ErrAppointmentNotFound
+		}
+		return nil, err
+	}
+	return appointment, nil
+}
+
+func (s *AppointmentService) Confirm(ctx context.Context, ownerID, id uint) error {
+	appointment, err := s.GetOwnerAppointment(ctx, ownerID, id)
+	if err != nil {
+		return err
+	}
+	if appointment.Status != models.AppointmentStatusPending {
+		return ErrAppointmentNotPending
+	}
+	return s.updateStatus(ctx, appointment, map[string]interface{}{"status": models.AppointmentStatusConfirmed})
+}
+
+func (s *AppointmentService) CancelByBusiness(ctx context.Context, ownerID, id uint, reason string) error {
+	appointment, err := s.GetOwnerAppointment(ctx, ownerID, id)
+	if err != nil {
+		return err
+	}
+	return s.cancel(ctx, appointment, models.AppointmentActorBusiness, reason)
+}
+
+// RescheduleByBusiness, işletmenin önerdiği saat onaylı kabul edilir.
+func (s *AppointmentService) RescheduleByBusiness(ctx context.Context, ownerID, id uint, startsAt string) error {
+	appointment, err := s.GetOwnerAppointment(ctx, ownerID, id)
+	if err != nil {
+		return err
+	}
+	return s.reschedule(ctx, appointment, startsAt, models.AppointmentStatusConfirmed)
+}
+
+func (s *AppointmentService) cancel(ctx context.Context, appointment *models.Appointment, actor, reason string) error {
+	if !appointment.Blocks() {
+		return ErrAppointmentNotFound
+	}
+	return s.updateStatus(ctx, appointment, map[string]interface{}{
+		"status":        models.AppointmentStatusCancelled,
+		"cancelled_by":  actor,
+		"cancel_reason": reason,
+	})
+}
+
+func (s *AppointmentService) reschedule(ctx context.Context, appointment *models.Appointment, raw, status string) error {
+	if !appointment.Blocks() {
+		return ErrAppointmentNotFound
+	}
+	item := appointmentSnapshot(appointment)
+	if item == nil {
+		return ErrAppointmentServiceNotFound
+	}
+
+	startsAt, err := s.checkStart(ctx, item, raw, appointment.ID)
+	if err != nil {
+		return err
+	}
+	endsAt := startsAt.Add(time.Duration(appointment.Duration) * time.Minute)
+	if err := s.repo.Reschedule(ctx, appointment, startsAt, endsAt, status); err != nil {
+		switch {
+		case errors.Is(err, repositories.ErrSlotTaken):
+			return ErrAppointmentSlotTaken
+		case errors.Is(err, repositories.ErrNotFound):
+			return ErrAppointmentNotFound
+		}
+		logconfig.Log.Error("Randevu ertelenemedi", zap.Uint("appointment_id", appointment.ID), zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+func (s *AppointmentService) updateStatus(ctx context.Context, appointment *models.Appointment, data map[string]interface{}) error {
+	if err := s.repo.UpdateStatus(ctx, appointment.ID, data); err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return ErrAppointmentNotFound
+		}
+		logconfig.Log.Error("Randevu durumu güncellenemedi", zap.Uint("appointment_id", appointment.ID), zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+func (s *AppointmentService) bookableService(ctx context.Context, businessID, professionalServiceID uint) (*models.ProfessionalService, error) {
+	item, err := s.repo.GetBookableService(ctx, businessID, professionalServiceID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return nil, ErrAppointmentServiceNotFound
+		}
+		logconfig.Log.Error("Randevu hizmeti getirilemedi", zap.Uint("professional_service_id", professionalServiceID), zap.Error(err))
+		return nil, err
+	}
+	if item.Duration == 0 {
+		return nil, ErrAppointmentServiceNotFound
+	}
+	return item, nil
+}
+
+// checkStart, istenen başlangıcın o gün hesaplanan boş slotlardan biri olduğunu doğrular.
+// Son çakışma kontrolü yine de repository'deki kilitli transaction içinde yapılır.
+func (s *AppointmentService) checkStart(ctx context.Context, item *models.ProfessionalService, raw string, excludeID uint) (time.Time, error) {
+	startsAt, err := time.ParseInLocation(requests.AppointmentStartLayout, raw, appointmentLocation)
+	if err != nil {
+		return time.Time{}, ErrAppointmentSlotUnavailable
+	}
+	day := time.Date(startsAt.Year(), startsAt.Month(), startsAt.Day(), 0, 0, 0, 0, appointmentLocation)
+	if !s.withinBookingRange(day) {
+		return time.Time{}, ErrAppointmentSlotUnavailable
+	}
+	free, err := s.freeSlots(ctx, item, day, excludeID)
+	if err != nil {
+		return time.Time{}, err
+	}
+	for _, t := range free {
+		if t.Equal(startsAt) {
+			return startsAt, nil
+		}
+	}
+	return time.Time{}, ErrAppointmentSlotUnavailable
+}
+
+func (s *AppointmentService) freeSlots(ctx context.Context, item *models.ProfessionalService, day time.Time, excludeID uint) ([]time.Time, error) {
+	days, err := s.hours.Windows(ctx, item.Professional, day, day.AddDate(0, 0, 1))
+	if err != nil {
+		logconfig.Log.Error("Çalışma saatleri getirilemedi", zap.Uint("professional_id", item.ProfessionalID), zap.Error(err))
+		return nil, err
+	}
+	windows := days[day.Format("2006-01-02")]
+	if len(windows) == 0 {
+		return nil, nil
+	}
+
+	busyAppointments, err := s.repo.GetBusyAppointments(ctx, item.ProfessionalID, day, day.AddDate(0, 0, 1), excludeID)
+	if err != nil {
+		logconfig.Log.Error("Dolu randevular getirilemedi", zap.Uint("professional_id", item.ProfessionalID), zap.Error(err))
+		return nil, err
+	}
+	busy := make([]slots.Interval, 0, len(busyAppointments))
+	for _, a := range busyAppointments {
+		busy = append(busy, slots.Interval{Start: a.StartsAt.In(appointmentLocation), End: a.EndsAt.In(appointmentLocation)})
+	}
+
+	duration := time.Duration(item.Duration) * time.Minute
+	return slots.Free(windows, busy, duration, s.step, s.now().Add(s.minNotice)), nil
+}
+
+func (s *AppointmentService) withinBookingRange(day time.Time) bool {
+	now := s.now().In(appointmentLocation)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, appointmentLocation)
+	return !day.Before(today) && day.Before(today.AddDate(0, 0, s.maxDaysAhead+1))
+}
+
+func (s *AppointmentService) checkCustomerChangeAllowed(appointment *models.Appointment) error {
+	if !appointment.Blocks() {
+		return ErrAppointmentNotFound
+	}
+	if s.now().Add(s.cancelNotice).After(appointment.StartsAt) {
+		return ErrAppointmentTooLate
+	}
+	return nil
+}
+
+// appointmentSnapshot, slot hesabı için randevunun hizmetini randevu anındaki süreyle döner;
+// hizmetin süresi sonradan değişse de mevcut randevu kendi süresiyle taşınır.
+func appointmentSnapshot(appointment *models.Appointment) *models.ProfessionalService {
+	if appointment.ProfessionalService == nil {
+		return nil
+	}
+	snapshot := *appointment.ProfessionalService
+	snapshot.Duration = appointment.Duration
+	snapshot.Professional = appointment.Professional
+	return &snapshot
+}
+
+func newAppointmentToken() (string, error) {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+var _ IAppointmentService = (*AppointmentService)(nil)
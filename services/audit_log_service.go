@@ -0,0 +1,62 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"zatrano/configs/logconfig"
+	"zatrano/models"
+	"zatrano/repositories"
+	"zatrano/requests"
+
+	"go.uber.org/zap"
+)
+
+var ErrAuditLogNotFound = errors.New("denetim kaydı bulunamadı")
+
+// IAuditLogService, denetim kayıtlarını yönetim paneli için okur; kayıtlar değiştirilemez.
+type IAuditLogService interface {
+	List(ctx context.Context, params requests.AuditLogListParams) (*requests.PaginatedResult, error)
+	GetByID(ctx context.Context, id uint) (*models.AuditLog, error)
+	EntityTypes(ctx context.Context) ([]string, error)
+}
+
+type AuditLogService struct {
+	repo repositories.IAuditLogRepository
+}
+
+func NewAuditLogService() IAuditLogService {
+	return &AuditLogService{repo: repositories.NewAuditLogRepository()}
+}
+
+func (s *AuditLogService) List(ctx context.Context, params requests.AuditLogListParams) (*requests.PaginatedResult, error) {
+	entries, total, err := s.repo.List(ctx, params)
+	if err != nil {
+		logconfig.Log.Error("Denetim kayıtları listelenemedi", zap.Error(err))
+		return nil, err
+	}
+	return requests.CreatePaginatedResult(entries, total, params.Page, params.PerPage), nil
+}
+
+func (s *AuditLogService) GetByID(ctx context.Context, id uint) (*models.AuditLog, error) {
+	entry, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return nil, ErrAuditLogNotFound
+		}
+		logconfig.Log.Error("Denetim kaydı getirilemedi", zap.Uint("audit_log_id", id), zap.Error(err))
+		return nil, err
+	}
+	return entry, nil
+}
+
+func (s *AuditLogService) EntityTypes(ctx context.Context) ([]string, error) {
+	types, err := s.repo.EntityTypes(ctx)
+	if err != nil {
+		logconfig.Log.Error("Denetim kaydı türleri getirilemedi", zap.Error(err))
+		return nil, err
+	}
+	return types, nil
+}
+
+var _ IAuditLogService = (*AuditLogService)(nil)
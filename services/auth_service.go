@@ -2,8 +2,6 @@ package services
 
 import (
 	"context"
-	"crypto/rand"
-	"encoding/hex"
 	"errors"
 	"fmt"
 	"os"
@@ -40,21 +38,44 @@ type IAuthService interface {
 	GetUserProfile(id uint) (*models.User, error)
 	UpdatePassword(ctx context.Context, userID uint, currentPass, newPassword string) error
 	CreateUser(ctx context.Context, user *models.User) error
-	SendPasswordResetLink(email string) error
-	ResetPassword(token, newPassword string) error
-	VerifyEmail(token string) error
-	ResendVerificationLink(email string) error
-	// Eski: FindOrCreateUser(user models.User)
-	// Yeni: Google/OAuth akışı için minimal imza
-	FindOrCreateOAuthUser(providerID, email, name string) (*models.User, error)
+	// Parola sıfırlama ve e-posta doğrulama bağlantıları tek kullanımlıktır ve süreleri dolar;
+	// userAgent ve ip, bağlantıyı isteyen ve kullanan istemci olarak kaydedilir.
+	SendPasswordResetLink(ctx context.Context, email, userAgent, ip string) error
+	// CheckResetToken, sıfırlama formunu göstermeden önce bağlantının hâlâ geçerli olduğunu denetler.
+	CheckResetToken(ctx context.Context, token string) error
+	ResetPassword(ctx context.Context, token, newPassword, userAgent, ip string) error
+	VerifyEmail(ctx context.Context, token, userAgent, ip string) error
+	SendVerificationLink(ctx context.Context, user *models.User, userAgent, ip string) error
+	ResendVerificationLink(ctx context.Context, email, userAgent, ip string) error
 	UpdateUserInfo(ctx context.Context, userID uint, name, email string) error
+	LandingPath(user *models.User) string
 }
 
 type AuthService struct {
-	repo repositories.IAuthRepository
+	repo       repositories.IAuthRepository
+	identities repositories.IUserIdentityRepository
+	sessions   ISessionService
+	tokens     IUserTokenService
+	userTypes  IUserTypeService
 }
 
-func NewAuthService() IAuthService { return &AuthService{repo: repositories.NewAuthRepository()} }
+func NewAuthService() IAuthService {
+	return &AuthService{
+		repo:       repositories.NewAuthRepository(),
+		identities: repositories.NewUserIdentityRepository(),
+		sessions:   NewSessionService(),
+		tokens:     NewUserTokenService(),
+		userTypes:  NewUserTypeService(),
+	}
+}
+
+// revokeSessions, parola değiştiğinde kullanıcının açık tüm oturumlarını kapatır.
+// Parola zaten güncellendiği için hata yalnızca loglanır.
+func (s *AuthService) revokeSessions(ctx context.Context, userID uint) {
+	if err := s.sessions.RevokeAll(ctx, userID); err != nil {
+		logconfig.Log.Error("Parola değişikliği sonrası oturumlar kapatılamadı", zap.Uint("user_id", userID), zap.Error(err))
+	}
+}
 
 func (s *AuthService) logAuthSuccess(email string, userID uint) {
 	logconfig.Log.Info("Kimlik doğrulama başarılı", zap.String("email", email), zap.Uint("user_id", userID))
@@ -128,6 +149,17 @@ func (s *AuthService) GetUserProfile(id uint) (*models.User, error) {
 	return s.getUserByID(id)
 }
 
+// LandingPath, kullanıcının rolüne göre giriş sonrası açılacak sayfayı döner.
+// Yeni oluşturulmuş (rolü yüklenmemiş) kullanıcılar için rol veritabanından okunur.
+func (s *AuthService) LandingPath(user *models.User) string {
+	if user.UserType.ID != user.UserTypeID {
+		if fresh, err := s.getUserByID(user.ID); err == nil {
+			user = fresh
+		}
+	}
+	return user.UserType.LandingPath()
+}
+
 func (s *AuthService) UpdatePassword(ctx context.Context, userID uint, currentPass, newPassword string) error {
 	user, err := s.getUserByID(userID)
 	if err != nil {
@@ -136,9 +168,14 @@ func (s *AuthService) UpdatePassword(ctx context.Context, userID uint, currentPa
 
 	// Sosyal giriş ile oluşmuş ve şifresi boş kullanıcı için set-flow
 	if user.Password == "" {
-		if user.Provider == "" {
-			s.logWarn("Şifre boş ama provider yok", zap.Uint("user_id", userID))
-			return errors.New("provider bilgisi olmayan kullanıcı için şifre boş olamaz")
+		linked, err := s.identities.CountByUser(ctx, userID)
+		if err != nil {
+			s.logDBError("Bağlı hesap sorgulama", err, zap.Uint("user_id", userID))
+			return ErrUpdatePasswordGeneric
+		}
+		if linked == 0 {
+			s.logWarn("Şifre boş ama bağlı hesap yok", zap.Uint("user_id", userID))
+			return errors.New("bağlı hesabı olmayan kullanıcı için şifre boş olamaz")
 		}
 		if len(newPassword) < 6 {
 			s.logWarn("Yeni parola çok kısa", zap.Uint("user_id", userID))
@@ -155,6 +192,7 @@ func (s *AuthService) UpdatePassword(ctx context.Context, userID uint, currentPa
 			return ErrDatabaseUpdateFailed
 		}
 		logconfig.Log.Info("Sosyal giriş kullanıcısının parolası güncellendi", zap.Uint("user_id", userID))
+		s.revokeSessions(ctx, userID)
 		return nil
 	}
 
@@ -182,6 +220,7 @@ func (s *AuthService) UpdatePassword(ctx context.Context, userID uint, currentPa
 		return ErrDatabaseUpdateFailed
 	}
 	logconfig.Log.Info("Parola güncellendi", zap.Uint("user_id", userID))
+	s.revokeSessions(ctx, userID)
 	return nil
 }
 
@@ -193,11 +232,18 @@ func (s *AuthService) CreateUser(ctx context.Context, user *models.User) error {
 		logconfig.Log.Error("Şifre oluşturulamadı", zap.Error(err))
 		return errors.New("şifre oluşturulurken hata oluştu")
 	}
-	// IsAdmin alanı zaten models.User içinde; ekstra ayar gerekmiyor
+	// Rol verilmemişse kayıt olan kullanıcı varsayılan role alınır
+	if user.UserTypeID == 0 {
+		userTypeID, err := s.userTypes.DefaultUserTypeID(ctx)
+		if err != nil {
+			return err
+		}
+		user.UserTypeID = userTypeID
+	}
 	return s.repo.CreateUser(ctx, user)
 }
 
-func (s *AuthService) SendPasswordResetLink(email string) error {
+func (s *AuthService) SendPasswordResetLink(ctx context.Context, email, userAgent, ip string) error {
 	user, err := s.repo.FindUserByEmail(email)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -206,9 +252,8 @@ func (s *AuthService) SendPasswordResetLink(email string) error {
 		return ErrAuthGeneric
 	}
 
-	resetToken := generateToken()
-	user.ResetToken = resetToken
-	if err := s.repo.UpdateUser(context.Background(), user); err != nil {
+	resetToken, err := s.tokens.Issue(ctx, user.ID, models.UserTokenPurposePasswordReset, userAgent, ip)
+	if err != nil {
 		return ErrDatabaseUpdateFailed
 	}
 
@@ -222,54 +267,58 @@ func (s *AuthService) SendPasswordResetLink(email string) error {
 	return nil
 }
 
-func (s *AuthService) ResetPassword(token, newPassword string) error {
-	user, err := s.repo.FindUserByResetToken(token)
+func (s *AuthService) CheckResetToken(ctx context.Context, token string) error {
+	_, err := s.tokens.Check(ctx, models.UserTokenPurposePasswordReset, token)
+	return err
+}
+
+// ResetPassword; anahtar parola güncellenmeden önce kullanılmış sayılır, böylece aynı bağlantıyla
+// eş zamanlı gönderilen iki istekten yalnızca biri parolayı değiştirebilir.
+func (s *AuthService) ResetPassword(ctx context.Context, token, newPassword, userAgent, ip string) error {
+	used, err := s.tokens.Consume(ctx, models.UserTokenPurposePasswordReset, token, userAgent, ip)
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return ErrUserNotFound
+		if errors.Is(err, ErrUserTokenInvalid) {
+			return err
 		}
 		return ErrAuthGeneric
 	}
+	user, err := s.getUserByID(used.UserID)
+	if err != nil {
+		return err
+	}
 	if err := user.SetPassword(newPassword); err != nil {
 		return ErrHashingFailed
 	}
-	user.ResetToken = ""
-	if err := s.repo.UpdateUser(context.Background(), user); err != nil {
+	if err := s.repo.UpdateUser(ctx, user); err != nil {
 		return ErrDatabaseUpdateFailed
 	}
+	logconfig.Log.Info("Parola sıfırlandı", zap.Uint("user_id", user.ID), zap.String("ip", ip))
+	s.revokeSessions(ctx, user.ID)
 	return nil
 }
 
-func (s *AuthService) VerifyEmail(token string) error {
-	user, err := s.repo.FindUserByVerificationToken(token)
+func (s *AuthService) VerifyEmail(ctx context.Context, token, userAgent, ip string) error {
+	used, err := s.tokens.Consume(ctx, models.UserTokenPurposeEmailVerification, token, userAgent, ip)
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return ErrUserNotFound
+		if errors.Is(err, ErrUserTokenInvalid) {
+			return err
 		}
 		return ErrAuthGeneric
 	}
+	user, err := s.getUserByID(used.UserID)
+	if err != nil {
+		return err
+	}
 	user.EmailVerified = true
-	user.VerificationToken = ""
-	if err := s.repo.UpdateUser(context.Background(), user); err != nil {
+	if err := s.repo.UpdateUser(ctx, user); err != nil {
 		return ErrDatabaseUpdateFailed
 	}
 	return nil
 }
 
-func (s *AuthService) ResendVerificationLink(email string) error {
-	user, err := s.repo.FindUserByEmail(email)
+func (s *AuthService) SendVerificationLink(ctx context.Context, user *models.User, userAgent, ip string) error {
+	verificationToken, err := s.tokens.Issue(ctx, user.ID, models.UserTokenPurposeEmailVerification, userAgent, ip)
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return ErrUserNotFound
-		}
-		return ErrAuthGeneric
-	}
-	if user.EmailVerified {
-		return nil
-	}
-	verificationToken := generateToken()
-	user.VerificationToken = verificationToken
-	if err := s.repo.UpdateUser(context.Background(), user); err != nil {
 		return ErrDatabaseUpdateFailed
 	}
 	mailService := NewMailService()
@@ -279,56 +328,18 @@ func (s *AuthService) ResendVerificationLink(email string) error {
 	return mailService.SendMail(user.Email, "Email Doğrulama", emailBody)
 }
 
-func generateToken() string {
-	tokenBytes := make([]byte, 16)
-	if _, err := rand.Read(tokenBytes); err != nil {
-		logconfig.Log.Error("Token oluşturulamadı", zap.Error(err))
-		return ""
-	}
-	return hex.EncodeToString(tokenBytes)
-}
-
-// Yeni, sade OAuth bul/oluştur akışı
-func (s *AuthService) FindOrCreateOAuthUser(providerID, email, name string) (*models.User, error) {
-	// Önce provider bilgileri ile ara
-	existingUser, err := s.repo.FindByProviderAndID("google", providerID)
-	if err == nil {
-		return existingUser, nil
-	}
-
-	// Yoksa email ile kontrol (aynı email varsa provider alanlarını doldur)
-	if errors.Is(err, gorm.ErrRecordNotFound) {
-		if byEmail, e2 := s.repo.FindUserByEmail(email); e2 == nil {
-			byEmail.Provider = "google"
-			byEmail.ProviderID = providerID
-			byEmail.EmailVerified = true
-
-			if err := s.repo.UpdateUser(context.Background(), byEmail); err != nil {
-				return nil, err
-			}
-			return byEmail, nil
+func (s *AuthService) ResendVerificationLink(ctx context.Context, email, userAgent, ip string) error {
+	user, err := s.repo.FindUserByEmail(email)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrUserNotFound
 		}
+		return ErrAuthGeneric
 	}
-
-	// Tamamen yeni kullanıcı: admin değildir
-	userTypeID := uint(2) // User
-	u := &models.User{
-		Name:          name,
-		Email:         email,
-		UserTypeID:    userTypeID,
-		EmailVerified: true,
-		BaseModel: models.BaseModel{
-			IsActive: true,
-		},
-		Provider:   "google",
-		ProviderID: providerID,
-		// Password boş bırakılır (sosyal giriş)
-	}
-
-	if err := s.repo.CreateUser(context.Background(), u); err != nil {
-		return nil, err
+	if user.EmailVerified {
+		return nil
 	}
-	return u, nil
+	return s.SendVerificationLink(ctx, user, userAgent, ip)
 }
 
 func (s *AuthService) UpdateUserInfo(ctx context.Context, userID uint, name, email string) error {
@@ -0,0 +1,134 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+
+	"zatrano/configs/logconfig"
+	"zatrano/models"
+	"zatrano/repositories"
+	"zatrano/requests"
+
+	"go.uber.org/zap"
+)
+
+// DirectoryItem, dizin kartında gösterilen işletmeyi başlangıç fiyatıyla birlikte taşır.
+type DirectoryItem struct {
+	Business models.Business
+	MinPrice *float64
+	// Distance yalnızca yakınlık aramasında dolar (km)
+	Distance *float64
+}
+
+// HasPrice, işletmenin fiyatı girilmiş en az bir hizmeti olup olmadığını söyler.
+func (i DirectoryItem) HasPrice() bool {
+	return i.MinPrice != nil
+}
+
+// StartingPrice, şablonlarda işaretçi açmadan kullanılabilen başlangıç fiyatıdır.
+func (i DirectoryItem) StartingPrice() float64 {
+	if i.MinPrice == nil {
+		return 0
+	}
+	return *i.MinPrice
+}
+
+// DistanceLabel, mesafeyi 1 km altında metre, üstünde km olarak yazar.
+func (i DirectoryItem) DistanceLabel() string {
+	if i.Distance == nil {
+		return ""
+	}
+	if *i.Distance < 1 {
+		return fmt.Sprintf("%d m", int(math.Round(*i.Distance*1000/10))*10)
+	}
+	return strings.Replace(fmt.Sprintf("%.1f km", *i.Distance), ".", ",", 1)
+}
+
+// DirectoryFilters, arama formundaki seçim kutularının seçeneklerini taşır.
+type DirectoryFilters struct {
+	Cities        []models.City
+	Districts     []models.District
+	BusinessTypes []models.BusinessType
+	Services      []models.Service
+}
+
+type IBusinessDirectoryService interface {
+	Search(ctx context.Context, params requests.BusinessDirectoryParams) (*requests.PaginatedResult, error)
+	GetFilters(ctx context.Context, cityID uint) (*DirectoryFilters, error)
+}
+
+type BusinessDirectoryService struct {
+	repo     repositories.IBusinessDirectoryRepository
+	typeRepo repositories.IBusinessTypeRepository
+}
+
+func NewBusinessDirectoryService() IBusinessDirectoryService {
+	return &BusinessDirectoryService{
+		repo:     repositories.NewBusinessDirectoryRepository(),
+		typeRepo: repositories.NewBusinessTypeRepository(),
+	}
+}
+
+func (s *BusinessDirectoryService) Search(ctx context.Context, params requests.BusinessDirectoryParams) (*requests.PaginatedResult, error) {
+	rows, totalCount, err := s.repo.Search(ctx, params)
+	if err != nil {
+		logconfig.Log.Error("İşletme dizini aranamadı", zap.String("q", params.Name), zap.Error(err))
+		return nil, err
+	}
+
+	ids := make([]uint, 0, len(rows))
+	for _, row := range rows {
+		ids = append(ids, row.ID)
+	}
+	businesses, err := s.repo.GetBusinessesByIDs(ctx, ids)
+	if err != nil {
+		logconfig.Log.Error("Dizin işletmeleri yüklenemedi", zap.Error(err))
+		return nil, err
+	}
+
+	// Yükleme sırası garanti olmadığından arama sırası korunur
+	byID := make(map[uint]models.Business, len(businesses))
+	for _, b := range businesses {
+		byID[b.ID] = b
+	}
+	items := make([]DirectoryItem, 0, len(rows))
+	for _, row := range rows {
+		b, ok := byID[row.ID]
+		if !ok {
+			continue
+		}
+		items = append(items, DirectoryItem{Business: b, MinPrice: row.MinPrice, Distance: row.Distance})
+	}
+
+	return requests.CreatePaginatedResult(items, totalCount, params.Page, params.PerPage), nil
+}
+
+func (s *BusinessDirectoryService) GetFilters(ctx context.Context, cityID uint) (*DirectoryFilters, error) {
+	filters := &DirectoryFilters{}
+	var err error
+
+	if filters.Cities, err = s.repo.GetCitiesWithBusinesses(ctx); err != nil {
+		logconfig.Log.Error("Dizin illeri getirilemedi", zap.Error(err))
+		return nil, err
+	}
+	if cityID != 0 {
+		if filters.Districts, err = s.repo.GetDistrictsWithBusinesses(ctx, cityID); err != nil {
+			logconfig.Log.Error("Dizin ilçeleri getirilemedi", zap.Uint("city_id", cityID), zap.Error(err))
+			return nil, err
+		}
+	}
+	if filters.BusinessTypes, err = s.typeRepo.GetActiveBusinessTypes(ctx); err != nil {
+		logconfig.Log.Error("İşletme türleri getirilemedi", zap.Error(err))
+		return nil, err
+	}
+	if filters.Services, err = s.repo.GetActiveServices(ctx); err != nil {
+		logconfig.Log.Error("Hizmetler getirilemedi", zap.Error(err))
+		return nil, err
+	}
+
+	return filters, nil
+}
+
+var _ IBusinessDirectoryService = (*BusinessDirectoryService)(nil)
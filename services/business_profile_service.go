@@ -0,0 +1,314 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"zatrano/configs/envconfig"
+	"zatrano/configs/logconfig"
+	"zatrano/models"
+	"zatrano/repositories"
+
+	"go.uber.org/zap"
+)
+
+var ErrBusinessProfileNotFound = errors.New("işletme bulunamadı")
+
+// profileReviewLimit, profil sayfasında gösterilen en yeni onaylı yorum sayısıdır.
+const profileReviewLimit = 10
+
+// ProfessionalWithServices, profil sayfasında bir uzmanı sunduğu hizmetlerle birlikte taşır.
+type ProfessionalWithServices struct {
+	Professional models.Professional
+	Services     []models.ProfessionalService
+}
+
+// BusinessProfile, herkese açık işletme sayfasının ihtiyaç duyduğu tüm veriyi taşır.
+type BusinessProfile struct {
+	Business      *models.Business
+	Professionals []ProfessionalWithServices
+	Reviews       []models.Review
+	CanonicalURL  string
+	MapEmbedURL   string
+	WhatsAppURL   string
+	SocialLinks   map[string]string
+	JSONLD        map[string]interface{}
+}
+
+type IBusinessProfileService interface {
+	// GetProfile, işletme bulunamazsa eski bağlantı adlarına bakar;
+	// eşleşme varsa profil yerine yönlendirilecek güncel slug döner.
+	GetProfile(ctx context.Context, slug string) (profile *BusinessProfile, redirectSlug string, err error)
+}
+
+type BusinessProfileService struct {
+	repo       repositories.IBusinessProfileRepository
+	reviewRepo repositories.IReviewRepository
+	baseURL    string
+}
+
+func NewBusinessProfileService() IBusinessProfileService {
+	return &BusinessProfileService{
+		repo:       repositories.NewBusinessProfileRepository(),
+		reviewRepo: repositories.NewReviewRepository(),
+		baseURL:    strings.TrimRight(envconfig.String("APP_BASE_URL", ""), "/"),
+	}
+}
+
+func (s *BusinessProfileService) GetProfile(ctx context.Context, slug string) (*BusinessProfile, string, error) {
+	business, err := s.repo.GetActiveBusinessBySlug(ctx, slug)
+	if err != nil {
+		if !errors.Is(err, repositories.ErrNotFound) {
+			logconfig.Log.Error("İşletme profili getirilemedi", zap.String("slug", slug), zap.Error(err))
+			return nil, "", err
+		}
+		current, findErr := s.repo.FindCurrentSlug(ctx, slug)
+		if findErr == nil && current != slug {
+			return nil, current, nil
+		}
+		return nil, "", ErrBusinessProfileNotFound
+	}
+
+	professionalIDs := make([]uint, 0, len(business.Professionals))
+	for _, p := range business.Professionals {
+		professionalIDs = append(professionalIDs, p.ID)
+	}
+	items, err := s.repo.GetProfessionalServices(ctx, professionalIDs)
+	if err != nil {
+		logconfig.Log.Error("Uzman hizmetleri getirilemedi", zap.Uint("business_id", business.ID), zap.Error(err))
+		return nil, "", err
+	}
+	business.ProfessionalServices = items
+
+	byProfessional := make(map[uint][]models.ProfessionalService)
+	for _, item := range items {
+		byProfessional[item.ProfessionalID] = append(byProfessional[item.ProfessionalID], item)
+	}
+	professionals := make([]ProfessionalWithServices, 0, len(business.Professionals))
+	for _, p := range business.Professionals {
+		professionals = append(professionals, ProfessionalWithServices{Professional: p, Services: byProfessional[p.ID]})
+	}
+
+	// Yorumlar sayfanın ana içeriği değil; getirilemezse profil yorumsuz gösterilir
+	reviews, err := s.reviewRepo.GetBusinessReviews(ctx, business.ID, profileReviewLimit)
+	if err != nil {
+		logconfig.Log.Warn("İşletme yorumları getirilemedi", zap.Uint("business_id", business.ID), zap.Error(err))
+	}
+
+	profile := &BusinessProfile{
+		Business:      business,
+		Professionals: professionals,
+		Reviews:       reviews,
+		CanonicalURL:  s.baseURL + "/isletme/" + business.Slug,
+		MapEmbedURL:   mapEmbedURL(business),
+		SocialLinks:   socialLinks(business),
+	}
+	if phone, ok := NormalizeTRPhone(firstNonEmpty(business.Whatapp, business.Gsm)); ok {
+		profile.WhatsAppURL = "https://wa.me/" + strings.TrimPrefix(phone, "+")
+	}
+	profile.JSONLD = s.localBusinessJSONLD(profile)
+
+	return profile, "", nil
+}
+
+// localBusinessJSONLD, schema.org LocalBusiness yapısını üretir.
+func (s *BusinessProfileService) localBusinessJSONLD(p *BusinessProfile) map[string]interface{} {
+	b := p.Business
+	ld := map[string]interface{}{
+		"@context": "https://schema.org",
+		"@type":    "LocalBusiness",
+		"name":     b.Title,
+		"url":      p.CanonicalURL,
+	}
+	if b.Description != "" {
+		ld["description"] = b.Description
+	}
+	if b.Telephone != "" || b.Gsm != "" {
+		ld["telephone"] = firstNonEmpty(b.Telephone, b.Gsm)
+	}
+	if b.Email != "" {
+		ld["email"] = b.Email
+	}
+
+	var images []string
+	for _, img := range []string{b.Banner, b.CoverImage(), b.Logo} {
+		if img != "" {
+			images = append(images, s.baseURL+"/uploads/businesses/"+img)
+		}
+	}
+	if len(images) > 0 {
+		ld["image"] = images
+	}
+	if b.Logo != "" {
+		ld["logo"] = s.baseURL + "/uploads/businesses/" + b.Logo
+	}
+
+	if b.Address != nil {
+		address := map[string]interface{}{
+			"@type":         "PostalAddress",
+			"streetAddress": b.Address.Address,
+		}
+		if b.Address.District != nil {
+			address["addressLocality"] = b.Address.District.Name
+		}
+		if b.Address.City != nil {
+			address["addressRegion"] = b.Address.City.Name
+		}
+		if b.Address.Country != nil {
+			address["addressCountry"] = b.Address.Country.Name
+		}
+		ld["address"] = address
+	}
+	if b.HasCoordinates() {
+		ld["geo"] = map[string]interface{}{
+			"@type":     "GeoCoordinates",
+			"latitude":  *b.Latitude,
+			"longitude": *b.Longitude,
+		}
+	}
+
+	if len(p.SocialLinks) > 0 {
+		sameAs := make([]string, 0, len(p.SocialLinks))
+		for _, key := range socialOrder {
+			if link, ok := p.SocialLinks[key]; ok {
+				sameAs = append(sameAs, link)
+			}
+		}
+		ld["sameAs"] = sameAs
+	}
+
+	var offers []map[string]interface{}
+	for _, item := range b.ProfessionalServices {
+		if item.Service == nil {
+			continue
+		}
+		offers = append(offers, map[string]interface{}{
+			"@type":         "Offer",
+			"price":         item.Price,
+			"priceCurrency": "TRY",
+			"itemOffered": map[string]interface{}{
+				"@type": "Service",
+				"name":  item.Service.Name,
+			},
+		})
+	}
+	if len(offers) > 0 {
+		ld["makesOffer"] = offers
+	}
+
+	if b.RatingCount > 0 {
+		ld["aggregateRating"] = map[string]interface{}{
+			"@type":       "AggregateRating",
+			"ratingValue": b.RatingAverage,
+			"reviewCount": b.RatingCount,
+			"bestRating":  5,
+			"worstRating": 1,
+		}
+	}
+	var reviews []map[string]interface{}
+	for _, r := range p.Reviews {
+		review := map[string]interface{}{
+			"@type":         "Review",
+			"author":        map[string]interface{}{"@type": "Person", "name": r.DisplayName()},
+			"datePublished": r.CreatedAt.Format("2006-01-02"),
+			"reviewRating": map[string]interface{}{
+				"@type":       "Rating",
+				"ratingValue": r.Rating,
+				"bestRating":  5,
+				"worstRating": 1,
+			},
+		}
+		if r.Comment != "" {
+			review["reviewBody"] = r.Comment
+		}
+		reviews = append(reviews, review)
+	}
+	if len(reviews) > 0 {
+		ld["review"] = reviews
+	}
+	return ld
+}
+
+var socialOrder = []string{"website", "instagram", "facebook", "twitter", "linkedin", "youtube", "tiktok"}
+
+func socialLinks(b *models.Business) map[string]string {
+	links := make(map[string]string)
+	for key, link := range map[string]string{
+		"website":   b.Website,
+		"instagram": b.Instagram,
+		"facebook":  b.Facebook,
+		"twitter":   b.Twitter,
+		"linkedin":  b.Linkedin,
+		"youtube":   b.Youtube,
+		"tiktok":    b.Tiktok,
+	} {
+		if isHTTPURL(link) {
+			links[key] = link
+		}
+	}
+	return links
+}
+
+var iframeSrcPattern = regexp.MustCompile(`(?i)<iframe[^>]+src=["']([^"']+)["']`)
+
+// mapEmbedURL, Map alanına yapıştırılmış iframe kodundan ya da Google Maps embed linkinden
+// güvenli bir iframe adresi çıkarır. Geçerli bir embed yoksa koordinattan, o da yoksa adresten arama haritası üretir.
+func mapEmbedURL(b *models.Business) string {
+	raw := strings.TrimSpace(b.Map)
+	if m := iframeSrcPattern.FindStringSubmatch(raw); len(m) == 2 {
+		raw = m[1]
+	}
+	if u, err := url.Parse(raw); err == nil && u.Scheme == "https" &&
+		(u.Host == "www.google.com" || u.Host == "maps.google.com") &&
+		strings.HasPrefix(u.Path, "/maps/embed") {
+		return u.String()
+	}
+	if b.HasCoordinates() {
+		return "https://maps.google.com/maps?output=embed&q=" + url.QueryEscape(b.LatitudeText()+","+b.LongitudeText())
+	}
+
+	var parts []string
+	if b.Address != nil {
+		parts = append(parts, b.Address.Address)
+		if b.Address.District != nil {
+			parts = append(parts, b.Address.District.Name)
+		}
+		if b.Address.City != nil {
+			parts = append(parts, b.Address.City.Name)
+		}
+	}
+	query := strings.TrimSpace(strings.Join(nonEmpty(parts...), ", "))
+	if query == "" {
+		return ""
+	}
+	return "https://maps.google.com/maps?output=embed&q=" + url.QueryEscape(b.Title+", "+query)
+}
+
+func isHTTPURL(raw string) bool {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	return err == nil && (u.Scheme == "https" || u.Scheme == "http") && u.Host != ""
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if strings.TrimSpace(v) != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+func nonEmpty(values ...string) []string {
+	out := make([]string, 0, len(values))
+	for _, v := range values {
+		if strings.TrimSpace(v) != "" {
+			out = append(out, strings.TrimSpace(v))
+		}
+	}
+	return out
+}
+
+var _ IBusinessProfileService = (*BusinessProfileService)(nil)
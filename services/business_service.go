@@ -0,0 +1,275 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"zatrano/configs/logconfig"
+	"zatrano/models"
+	"zatrano/pkg/slugify"
+	"zatrano/repositories"
+	"zatrano/requests"
+
+	"go.uber.org/zap"
+)
+
+var (
+	ErrBusinessNotFound     = errors.New("işletme bulunamadı")
+	ErrBusinessSlugTaken    = errors.New("bu bağlantı adı başka bir işletme tarafından kullanılıyor")
+	ErrBusinessSlugInvalid  = errors.New("bağlantı adı geçerli karakter içermiyor")
+	ErrBusinessTypeNotFound = errors.New("işletme türü bulunamadı")
+	ErrBusinessAddress      = errors.New("seçilen il/ilçe bilgisi tutarsız")
+)
+
+// BusinessMedia, yüklenen logo/banner dosya adlarını taşır; boş alanlar mevcut değeri korur.
+type BusinessMedia struct {
+	Logo   string
+	Banner string
+}
+
+type IBusinessService interface {
+	GetUserBusinesses(ctx context.Context, userID uint, params requests.BusinessListParams) (*requests.PaginatedResult, error)
+	GetUserBusinessByID(ctx context.Context, userID, id uint) (*models.Business, error)
+	CreateBusiness(ctx context.Context, userID uint, req requests.BusinessRequest, media BusinessMedia) (*models.Business, error)
+	UpdateBusiness(ctx context.Context, userID, id uint, req requests.BusinessRequest, media BusinessMedia) error
+	DeleteBusiness(ctx context.Context, userID, id uint) (*models.Business, error)
+	GetBusinessTypes(ctx context.Context) ([]models.BusinessType, error)
+}
+
+type BusinessService struct {
+	repo         repositories.IBusinessRepository
+	typeRepo     repositories.IBusinessTypeRepository
+	locationRepo repositories.ILocationRepository
+}
+
+func NewBusinessService() IBusinessService {
+	return &BusinessService{
+		repo:         repositories.NewBusinessRepository(),
+		typeRepo:     repositories.NewBusinessTypeRepository(),
+		locationRepo: repositories.NewLocationRepository(),
+	}
+}
+
+func (s *BusinessService) GetUserBusinesses(ctx context.Context, userID uint, params requests.BusinessListParams) (*requests.PaginatedResult, error) {
+	businesses, totalCount, err := s.repo.GetUserBusinesses(ctx, userID, params)
+	if err != nil {
+		logconfig.Log.Error("İşletmeler getirilemedi", zap.Uint("user_id", userID), zap.Error(err))
+		return nil, err
+	}
+	return requests.CreatePaginatedResult(businesses, totalCount, params.Page, params.PerPage), nil
+}
+
+func (s *BusinessService) GetUserBusinessByID(ctx context.Context, userID, id uint) (*models.Business, error) {
+	business, err := s.repo.GetUserBusinessByID(ctx, userID, id)
+	if err != nil {
+		if !errors.Is(err, repositories.ErrNotFound) {
+			logconfig.Log.Error("İşletme getirilemedi", zap.Uint("business_id", id), zap.Error(err))
+		}
+		return nil, ErrBusinessNotFound
+	}
+	return business, nil
+}
+
+func (s *BusinessService) CreateBusiness(ctx context.Context, userID uint, req requests.BusinessRequest, media BusinessMedia) (*models.Business, error) {
+	converted := req.Convert()
+	if err := s.checkReferences(ctx, converted); err != nil {
+		return nil, err
+	}
+
+	slug, err := s.resolveSlug(ctx, req.Slug, req.Title, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	address := &models.Address{
+		CountryID:  converted.CountryID,
+		CityID:     converted.CityID,
+		DistrictID: converted.DistrictID,
+		Address:    req.Address,
+	}
+	business := &models.Business{
+		UserID:         userID,
+		BusinessTypeID: converted.BusinessTypeID,
+		Capacity:       converted.Capacity,
+		Slug:           slug,
+		Title:          req.Title,
+		Description:    req.Description,
+		Gsm:            req.Gsm,
+		Telephone:      req.Telephone,
+		Email:          req.Email,
+		Website:        req.Website,
+		TaxOffice:      req.TaxOffice,
+		TaxNumber:      req.TaxNumber,
+		KEPAddress:     req.KEPAddress,
+		MersisNo:       req.MersisNo,
+		IbanNo:         req.IbanNo,
+		Map:            req.Map,
+		Logo:           media.Logo,
+		Banner:         media.Banner,
+		Video:          req.Video,
+		Whatapp:        req.Whatapp,
+		Instagram:      req.Instagram,
+		Facebook:       req.Facebook,
+		Twitter:        req.Twitter,
+		Linkedin:       req.Linkedin,
+		Youtube:        req.Youtube,
+		Tiktok:         req.Tiktok,
+	}
+	business.IsActive = converted.IsActive
+	if converted.Coordinates != nil {
+		business.Latitude = &converted.Coordinates.Lat
+		business.Longitude = &converted.Coordinates.Lng
+	}
+
+	if err := s.repo.CreateBusiness(ctx, business, address); err != nil {
+		logconfig.Log.Error("İşletme oluşturulamadı", zap.Uint("user_id", userID), zap.Error(err))
+		return nil, errors.New("işletme kaydedilemedi")
+	}
+	return business, nil
+}
+
+func (s *BusinessService) UpdateBusiness(ctx context.Context, userID, id uint, req requests.BusinessRequest, media BusinessMedia) error {
+	business, err := s.GetUserBusinessByID(ctx, userID, id)
+	if err != nil {
+		return err
+	}
+
+	converted := req.Convert()
+	if err := s.checkReferences(ctx, converted); err != nil {
+		return err
+	}
+
+	// Bağlantı adı boş bırakılırsa mevcut adres korunur; paylaşılmış linkler kırılmaz
+	slug := business.Slug
+	if req.Slug != "" && slugify.Make(req.Slug) != business.Slug {
+		if slug, err = s.resolveSlug(ctx, req.Slug, req.Title, business.ID); err != nil {
+			return err
+		}
+	}
+
+	businessData := map[string]interface{}{
+		"business_type_id": converted.BusinessTypeID,
+		"capacity":         converted.Capacity,
+		"slug":             slug,
+		"title":            req.Title,
+		"description":      req.Description,
+		"gsm":              req.Gsm,
+		"telephone":        req.Telephone,
+		"email":            req.Email,
+		"website":          req.Website,
+		"tax_office":       req.TaxOffice,
+		"tax_number":       req.TaxNumber,
+		"kep_address":      req.KEPAddress,
+		"mersis_no":        req.MersisNo,
+		"iban_no":          req.IbanNo,
+		"map":              req.Map,
+		"video":            req.Video,
+		"whatapp":          req.Whatapp,
+		"instagram":        req.Instagram,
+		"facebook":         req.Facebook,
+		"twitter":          req.Twitter,
+		"linkedin":         req.Linkedin,
+		"youtube":          req.Youtube,
+		"tiktok":           req.Tiktok,
+		"is_active":        converted.IsActive,
+	}
+	// Konum bulunamazsa eski koordinat silinir; harita linki değiştiyse yanlış yerde görünmesin
+	businessData["latitude"], businessData["longitude"] = nil, nil
+	if converted.Coordinates != nil {
+		businessData["latitude"] = converted.Coordinates.Lat
+		businessData["longitude"] = converted.Coordinates.Lng
+	}
+	if media.Logo != "" {
+		businessData["logo"] = media.Logo
+	}
+	if media.Banner != "" {
+		businessData["banner"] = media.Banner
+	}
+	addressData := map[string]interface{}{
+		"country_id":  converted.CountryID,
+		"city_id":     converted.CityID,
+		"district_id": converted.DistrictID,
+		"address":     req.Address,
+	}
+
+	if err := s.repo.UpdateBusiness(ctx, business, businessData, addressData); err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return ErrBusinessNotFound
+		}
+		logconfig.Log.Error("İşletme güncellenemedi", zap.Uint("business_id", id), zap.Error(err))
+		return errors.New("işletme güncellenemedi")
+	}
+	return nil
+}
+
+// DeleteBusiness, silinen işletmeyi döner; çağıran taraf logo/banner dosyalarını temizler.
+func (s *BusinessService) DeleteBusiness(ctx context.Context, userID, id uint) (*models.Business, error) {
+	business, err := s.GetUserBusinessByID(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.DeleteBusiness(ctx, business); err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return nil, ErrBusinessNotFound
+		}
+		logconfig.Log.Error("İşletme silinemedi", zap.Uint("business_id", id), zap.Error(err))
+		return nil, errors.New("işletme silinemedi")
+	}
+	return business, nil
+}
+
+func (s *BusinessService) GetBusinessTypes(ctx context.Context) ([]models.BusinessType, error) {
+	return s.typeRepo.GetActiveBusinessTypes(ctx)
+}
+
+func (s *BusinessService) checkReferences(ctx context.Context, converted requests.ConvertedBusinessRequest) error {
+	if _, err := s.typeRepo.GetBusinessTypeByID(ctx, converted.BusinessTypeID); err != nil {
+		return ErrBusinessTypeNotFound
+	}
+	ok, err := s.locationRepo.DistrictBelongsTo(ctx, converted.CountryID, converted.CityID, converted.DistrictID)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrBusinessAddress
+	}
+	return nil
+}
+
+// resolveSlug, kullanıcının verdiği bağlantı adını aynen kullanır (çakışırsa hata verir);
+// boşsa işletme adından üretir ve çakışmada -2, -3 ... ekler.
+func (s *BusinessService) resolveSlug(ctx context.Context, requested, title string, excludeID uint) (string, error) {
+	if requested != "" {
+		slug := slugify.Make(requested)
+		if slug == "" {
+			return "", ErrBusinessSlugInvalid
+		}
+		exists, err := s.repo.SlugExists(ctx, slug, excludeID)
+		if err != nil {
+			return "", err
+		}
+		if exists {
+			return "", ErrBusinessSlugTaken
+		}
+		return slug, nil
+	}
+
+	base := slugify.Make(title)
+	if base == "" {
+		return "", ErrBusinessSlugInvalid
+	}
+	slug := base
+	for i := 2; ; i++ {
+		exists, err := s.repo.SlugExists(ctx, slug, excludeID)
+		if err != nil {
+			return "", err
+		}
+		if !exists {
+			return slug, nil
+		}
+		slug = fmt.Sprintf("%s-%d", base, i)
+	}
+}
+
+var _ IBusinessService = (*BusinessService)(nil)
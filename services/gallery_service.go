@@ -0,0 +1,259 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+
+	"zatrano/configs/envconfig"
+	"zatrano/configs/logconfig"
+	"zatrano/models"
+	"zatrano/repositories"
+
+	"go.uber.org/zap"
+)
+
+var (
+	ErrGalleryImageNotFound = errors.New("görsel bulunamadı")
+	ErrGalleryLimitReached  = errors.New("planınızın galeri görsel limiti doldu")
+	ErrGalleryInvalidOrder  = errors.New("görsel sırası geçersiz")
+)
+
+// GalleryState, galeri sayfasının görselleri ve plan limitini taşır. Limit 0 ise sınırsızdır.
+type GalleryState struct {
+	Business *models.Business
+	Images   []models.Gallery
+	Plan     string
+	Limit    int
+}
+
+// Remaining, eklenebilecek görsel sayısıdır; sınırsız planda -1 döner.
+func (g GalleryState) Remaining() int {
+	if g.Limit <= 0 {
+		return -1
+	}
+	if left := g.Limit - len(g.Images); left > 0 {
+		return left
+	}
+	return 0
+}
+
+type IGalleryService interface {
+	GetGallery(ctx context.Context, ownerID, businessID uint) (*GalleryState, error)
+	// CheckCapacity, dosyalar diske yazılmadan önce count görselin sığıp sığmadığını kontrol eder.
+	CheckCapacity(ctx context.Context, ownerID, businessID uint, count int) error
+	AddImages(ctx context.Context, ownerID, businessID uint, fileNames []string) ([]models.Gallery, error)
+	UpdateCaption(ctx context.Context, ownerID, businessID, imageID uint, caption string) error
+	Reorder(ctx context.Context, ownerID, businessID uint, ids []uint) error
+	SetCover(ctx context.Context, ownerID, businessID, imageID uint) error
+	// DeleteImage, silinen görseli döner; çağıran taraf dosyayı temizler.
+	DeleteImage(ctx context.Context, ownerID, businessID, imageID uint) (*models.Gallery, error)
+}
+
+type GalleryService struct {
+	repo         repositories.IGalleryRepository
+	businessRepo repositories.IBusinessRepository
+	defaultLimit int
+	planLimits   map[string]int
+}
+
+// NewGalleryService, plan limitlerini GALLERY_IMAGE_LIMITS ("Business:30,Specialist:12") ile,
+// listede olmayan planlar için GALLERY_IMAGE_LIMIT ile belirler. Plan, sahibin kullanıcı tipidir.
+func NewGalleryService() IGalleryService {
+	return &GalleryService{
+		repo:         repositories.NewGalleryRepository(),
+		businessRepo: repositories.NewBusinessRepository(),
+		defaultLimit: envconfig.Int("GALLERY_IMAGE_LIMIT", 10),
+		planLimits:   parsePlanLimits(envconfig.String("GALLERY_IMAGE_LIMITS", "")),
+	}
+}
+
+func parsePlanLimits(raw string) map[string]int {
+	limits := make(map[string]int)
+	for _, part := range strings.Split(raw, ",") {
+		name, value, ok := strings.Cut(part, ":")
+		if !ok {
+			continue
+		}
+		limit, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil || limit < 0 {
+			logconfig.Log.Warn("Geçersiz galeri plan limiti yok sayıldı", zap.String("value", part))
+			continue
+		}
+		limits[strings.ToLower(strings.TrimSpace(name))] = limit
+	}
+	return limits
+}
+
+func (s *GalleryService) GetGallery(ctx context.Context, ownerID, businessID uint) (*GalleryState, error) {
+	business, err := s.ownerBusiness(ctx, ownerID, businessID)
+	if err != nil {
+		return nil, err
+	}
+	images, err := s.repo.GetImages(ctx, business.ID)
+	if err != nil {
+		logconfig.Log.Error("Galeri getirilemedi", zap.Uint("business_id", business.ID), zap.Error(err))
+		return nil, err
+	}
+	plan, limit, err := s.planLimit(ctx, ownerID)
+	if err != nil {
+		return nil, err
+	}
+	return &GalleryState{Business: business, Images: images, Plan: plan, Limit: limit}, nil
+}
+
+func (s *GalleryService) CheckCapacity(ctx context.Context, ownerID, businessID uint, count int) error {
+	business, err := s.ownerBusiness(ctx, ownerID, businessID)
+	if err != nil {
+		return err
+	}
+	_, limit, err := s.planLimit(ctx, ownerID)
+	if err != nil || limit == 0 {
+		return err
+	}
+	current, err := s.repo.CountImages(ctx, business.ID)
+	if err != nil {
+		logconfig.Log.Error("Galeri sayılamadı", zap.Uint("business_id", business.ID), zap.Error(err))
+		return err
+	}
+	if int(current)+count > limit {
+		return ErrGalleryLimitReached
+	}
+	return nil
+}
+
+func (s *GalleryService) AddImages(ctx context.Context, ownerID, businessID uint, fileNames []string) ([]models.Gallery, error) {
+	business, err := s.ownerBusiness(ctx, ownerID, businessID)
+	if err != nil {
+		return nil, err
+	}
+	_, limit, err := s.planLimit(ctx, ownerID)
+	if err != nil {
+		return nil, err
+	}
+
+	images := make([]models.Gallery, 0, len(fileNames))
+	for _, name := range fileNames {
+		images = append(images, models.Gallery{Image: name})
+	}
+	if err := s.repo.AddImages(ctx, business.ID, images, limit); err != nil {
+		if errors.Is(err, repositories.ErrGalleryFull) {
+			return nil, ErrGalleryLimitReached
+		}
+		logconfig.Log.Error("Galeri görselleri eklenemedi", zap.Uint("business_id", business.ID), zap.Error(err))
+		return nil, errors.New("görseller kaydedilemedi")
+	}
+	return images, nil
+}
+
+func (s *GalleryService) UpdateCaption(ctx context.Context, ownerID, businessID, imageID uint, caption string) error {
+	business, err := s.ownerBusiness(ctx, ownerID, businessID)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.UpdateCaption(ctx, business.ID, imageID, caption); err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return ErrGalleryImageNotFound
+		}
+		logconfig.Log.Error("Görsel açıklaması güncellenemedi", zap.Uint("image_id", imageID), zap.Error(err))
+		return errors.New("açıklama kaydedilemedi")
+	}
+	return nil
+}
+
+// Reorder, yalnızca işletmenin tüm görsellerini birer kez içeren sıralamayı kabul eder;
+// eski bir sekmeden gelen eksik liste sırayı bozmasın.
+func (s *GalleryService) Reorder(ctx context.Context, ownerID, businessID uint, ids []uint) error {
+	business, err := s.ownerBusiness(ctx, ownerID, businessID)
+	if err != nil {
+		return err
+	}
+	images, err := s.repo.GetImages(ctx, business.ID)
+	if err != nil {
+		logconfig.Log.Error("Galeri getirilemedi", zap.Uint("business_id", business.ID), zap.Error(err))
+		return err
+	}
+	if len(ids) != len(images) {
+		return ErrGalleryInvalidOrder
+	}
+	known := make(map[uint]bool, len(images))
+	for _, img := range images {
+		known[img.ID] = true
+	}
+	for _, id := range ids {
+		if !known[id] {
+			return ErrGalleryInvalidOrder
+		}
+		delete(known, id)
+	}
+
+	if err := s.repo.Reorder(ctx, business.ID, ids); err != nil {
+		logconfig.Log.Error("Galeri sırası kaydedilemedi", zap.Uint("business_id", business.ID), zap.Error(err))
+		return errors.New("sıralama kaydedilemedi")
+	}
+	return nil
+}
+
+func (s *GalleryService) SetCover(ctx context.Context, ownerID, businessID, imageID uint) error {
+	business, err := s.ownerBusiness(ctx, ownerID, businessID)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.SetCover(ctx, business.ID, imageID); err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return ErrGalleryImageNotFound
+		}
+		logconfig.Log.Error("Kapak görseli ayarlanamadı", zap.Uint("image_id", imageID), zap.Error(err))
+		return errors.New("kapak görseli ayarlanamadı")
+	}
+	return nil
+}
+
+func (s *GalleryService) DeleteImage(ctx context.Context, ownerID, businessID, imageID uint) (*models.Gallery, error) {
+	business, err := s.ownerBusiness(ctx, ownerID, businessID)
+	if err != nil {
+		return nil, err
+	}
+	image, err := s.repo.GetImage(ctx, business.ID, imageID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return nil, ErrGalleryImageNotFound
+		}
+		logconfig.Log.Error("Görsel getirilemedi", zap.Uint("image_id", imageID), zap.Error(err))
+		return nil, err
+	}
+	if err := s.repo.DeleteImage(ctx, image); err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return nil, ErrGalleryImageNotFound
+		}
+		logconfig.Log.Error("Görsel silinemedi", zap.Uint("image_id", imageID), zap.Error(err))
+		return nil, errors.New("görsel silinemedi")
+	}
+	return image, nil
+}
+
+func (s *GalleryService) ownerBusiness(ctx context.Context, ownerID, businessID uint) (*models.Business, error) {
+	business, err := s.businessRepo.GetUserBusinessByID(ctx, ownerID, businessID)
+	if err != nil {
+		if !errors.Is(err, repositories.ErrNotFound) {
+			logconfig.Log.Error("İşletme getirilemedi", zap.Uint("business_id", businessID), zap.Error(err))
+		}
+		return nil, ErrBusinessNotFound
+	}
+	return business, nil
+}
+
+func (s *GalleryService) planLimit(ctx context.Context, ownerID uint) (string, int, error) {
+	plan, err := s.repo.GetOwnerPlanName(ctx, ownerID)
+	if err != nil {
+		logconfig.Log.Error("Kullanıcı planı getirilemedi", zap.Uint("user_id", ownerID), zap.Error(err))
+		return "", 0, err
+	}
+	if limit, ok := s.planLimits[strings.ToLower(plan)]; ok {
+		return plan, limit, nil
+	}
+	return plan, s.defaultLimit, nil
+}
+
+var _ IGalleryService = (*GalleryService)(nil)
@@ -0,0 +1,37 @@
+package services
+
+import (
+	"context"
+
+	"zatrano/models"
+	"zatrano/repositories"
+)
+
+// ILocationService, adres formlarındaki ülke/il/ilçe seçicileri için veri sağlar.
+type ILocationService interface {
+	GetCountries(ctx context.Context) ([]models.Country, error)
+	GetCities(ctx context.Context, countryID uint) ([]models.City, error)
+	GetDistricts(ctx context.Context, cityID uint) ([]models.District, error)
+}
+
+type LocationService struct {
+	repo repositories.ILocationRepository
+}
+
+func NewLocationService() ILocationService {
+	return &LocationService{repo: repositories.NewLocationRepository()}
+}
+
+func (s *LocationService) GetCountries(ctx context.Context) ([]models.Country, error) {
+	return s.repo.GetCountries(ctx)
+}
+
+func (s *LocationService) GetCities(ctx context.Context, countryID uint) ([]models.City, error) {
+	return s.repo.GetCitiesByCountry(ctx, countryID)
+}
+
+func (s *LocationService) GetDistricts(ctx context.Context, cityID uint) ([]models.District, error) {
+	return s.repo.GetDistrictsByCity(ctx, cityID)
+}
+
+var _ ILocationService = (*LocationService)(nil)
@@ -0,0 +1,251 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"zatrano/configs/envconfig"
+	"zatrano/configs/logconfig"
+	"zatrano/configs/redisconfig"
+	"zatrano/models"
+	"zatrano/repositories"
+	"zatrano/requests"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrLoginLockoutNotFound = errors.New("kilit kaydı bulunamadı")
+	ErrLoginLockoutInactive = errors.New("kilidin süresi dolmuş ya da kilit zaten açılmış")
+)
+
+const (
+	loginThrottlePrefix = "login_throttle"
+	// loginLevelTTL, art arda kilit sayısının ne kadar süre hatırlanacağıdır; bu süre içinde
+	// yeniden kilitlenen hesap ya da IP bir öncekinin iki katı süre bekler.
+	loginLevelTTL = 24 * time.Hour
+)
+
+// ILoginThrottleService, başarısız girişleri hesap (e-posta) ve IP adresi bazında Redis'te sayar.
+// Eşik aşıldığında konu geçici olarak kilitlenir; kilit süresi her yeni kilitte iki katına çıkar.
+// Sayaçlar Redis'te tutulduğu için birden fazla uygulama örneği aynı sınırları paylaşır.
+type ILoginThrottleService interface {
+	// Check, hesap ya da IP kilitliyse kalan süreyi döner; kilit yoksa sıfır döner.
+	Check(ctx context.Context, email, ip string) (time.Duration, error)
+	// RecordFailure, başarısız denemeyi sayar; deneme bir kilidi tetiklediyse kilit süresini döner.
+	RecordFailure(ctx context.Context, email, ip string, userID *uint) (time.Duration, error)
+	// RecordSuccess, başarılı girişten sonra hesabın sayaçlarını sıfırlar. IP sayacı korunur;
+	// aksi halde tek bir geçerli hesapla diğer hesaplara yönelik denemeler sıfırlanabilirdi.
+	RecordSuccess(ctx context.Context, email string) error
+	List(ctx context.Context, params requests.LoginLockoutListParams) (*requests.PaginatedResult, error)
+	// Unlock, yöneticinin kilidi süresi dolmadan açmasını sağlar.
+	Unlock(ctx context.Context, id uint, adminID uint) error
+}
+
+type LoginThrottleService struct {
+	redis          *redis.Client
+	repo           repositories.ILoginLockoutRepository
+	accountMax     int
+	ipMax          int
+	window         time.Duration
+	lockoutBase    time.Duration
+	lockoutMaximum time.Duration
+	now            func() time.Time
+}
+
+// NewLoginThrottleService; LOGIN_ACCOUNT_MAX_FAILURES ya da LOGIN_IP_MAX_FAILURES sıfır verilirse
+// ilgili kapsam devre dışı kalır.
+func NewLoginThrottleService() ILoginThrottleService {
+	return &LoginThrottleService{
+		redis:          redisconfig.GetClient(),
+		repo:           repositories.NewLoginLockoutRepository(),
+		accountMax:     envconfig.Int("LOGIN_ACCOUNT_MAX_FAILURES", 5),
+		ipMax:          envconfig.Int("LOGIN_IP_MAX_FAILURES", 20),
+		window:         time.Duration(envconfig.Int("LOGIN_FAILURE_WINDOW_MINUTES", 15)) * time.Minute,
+		lockoutBase:    time.Duration(envconfig.Int("LOGIN_LOCKOUT_SECONDS", 60)) * time.Second,
+		lockoutMaximum: time.Duration(envconfig.Int("LOGIN_LOCKOUT_MAX_MINUTES", 60)) * time.Minute,
+		now:            time.Now,
+	}
+}
+
+// throttleSubject, kilitlenebilecek bir hesap ya da IP adresidir.
+type throttleSubject struct {
+	scope string
+	value string
+	max   int
+}
+
+func (t throttleSubject) key(kind string) string {
+	return redisconfig.GetPrefixedKey(loginThrottlePrefix, kind+":"+t.scope+":"+t.value)
+}
+
+func normalizeLoginEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+func (s *LoginThrottleService) subjects(email, ip string) []throttleSubject {
+	var subjects []throttleSubject
+	if email = normalizeLoginEmail(email); email != "" && s.accountMax > 0 {
+		subjects = append(subjects, throttleSubject{scope: models.LockoutScopeAccount, value: email, max: s.accountMax})
+	}
+	if ip != "" && s.ipMax > 0 {
+		subjects = append(subjects, throttleSubject{scope: models.LockoutScopeIP, value: ip, max: s.ipMax})
+	}
+	return subjects
+}
+
+// lockoutDuration, level. kilidin süresini hesaplar: taban süre × 2^(level-1), üst sınırla.
+func (s *LoginThrottleService) lockoutDuration(level int64) time.Duration {
+	d := s.lockoutBase
+	for i := int64(1); i < level && d < s.lockoutMaximum; i++ {
+		d *= 2
+	}
+	if d > s.lockoutMaximum {
+		return s.lockoutMaximum
+	}
+	return d
+}
+
+// Check; hata dönerse çağıran girişi engellememelidir, Redis'e ulaşılamaması girişi kapatmaz.
+func (s *LoginThrottleService) Check(ctx context.Context, email, ip string) (time.Duration, error) {
+	var remaining time.Duration
+	for _, subject := range s.subjects(email, ip) {
+		ttl, err := s.redis.PTTL(ctx, subject.key("lock")).Result()
+		if err != nil {
+			logconfig.Log.Error("Giriş kilidi okunamadı", zap.String("scope", subject.scope), zap.Error(err))
+			return 0, err
+		}
+		if ttl > remaining {
+			remaining = ttl
+		}
+	}
+	return remaining, nil
+}
+
+func (s *LoginThrottleService) RecordFailure(ctx context.Context, email, ip string, userID *uint) (time.Duration, error) {
+	var locked time.Duration
+	for _, subject := range s.subjects(email, ip) {
+		d, err := s.recordFailure(ctx, subject, ip, userID)
+		if err != nil {
+			logconfig.Log.Error("Başarısız giriş sayılamadı", zap.String("scope", subject.scope), zap.Error(err))
+			return locked, err
+		}
+		if d > locked {
+			locked = d
+		}
+	}
+	return locked, nil
+}
+
+func (s *LoginThrottleService) recordFailure(ctx context.Context, subject throttleSubject, ip string, userID *uint) (time.Duration, error) {
+	failKey := subject.key("fail")
+	failures, err := s.redis.Incr(ctx, failKey).Result()
+	if err != nil {
+		return 0, err
+	}
+	if failures == 1 {
+		if err := s.redis.Expire(ctx, failKey, s.window).Err(); err != nil {
+			return 0, err
+		}
+	}
+	if failures < int64(subject.max) {
+		return 0, nil
+	}
+
+	levelKey := subject.key("level")
+	pipe := s.redis.TxPipeline()
+	levelCmd := pipe.Incr(ctx, levelKey)
+	pipe.Expire(ctx, levelKey, loginLevelTTL)
+	pipe.Del(ctx, failKey)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, err
+	}
+
+	level := levelCmd.Val()
+	duration := s.lockoutDuration(level)
+	if err := s.redis.Set(ctx, subject.key("lock"), level, duration).Err(); err != nil {
+		return 0, err
+	}
+
+	lockout := &models.LoginLockout{
+		Scope:       subject.scope,
+		Subject:     subject.value,
+		IP:          ip,
+		Failures:    int(failures),
+		Level:       int(level),
+		LockedUntil: s.now().Add(duration),
+	}
+	if subject.scope == models.LockoutScopeAccount {
+		lockout.UserID = userID
+	}
+	if err := s.repo.Create(ctx, lockout); err != nil {
+		// Kilit Redis'te uygulandı; kaydın yazılamaması girişi engellemeye devam etmesini etkilemez
+		logconfig.Log.Error("Giriş kilidi kaydedilemedi", zap.String("scope", subject.scope), zap.String("subject", subject.value), zap.Error(err))
+	}
+
+	logconfig.Log.Warn("Giriş kilidi uygulandı",
+		zap.String("scope", subject.scope),
+		zap.String("subject", subject.value),
+		zap.String("ip", ip),
+		zap.Int64("failures", failures),
+		zap.Int64("level", level),
+		zap.Duration("duration", duration))
+	return duration, nil
+}
+
+func (s *LoginThrottleService) RecordSuccess(ctx context.Context, email string) error {
+	email = normalizeLoginEmail(email)
+	if email == "" {
+		return nil
+	}
+	subject := throttleSubject{scope: models.LockoutScopeAccount, value: email}
+	if err := s.redis.Del(ctx, subject.key("fail"), subject.key("level")).Err(); err != nil {
+		logconfig.Log.Error("Giriş sayacı sıfırlanamadı", zap.String("email", email), zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+func (s *LoginThrottleService) List(ctx context.Context, params requests.LoginLockoutListParams) (*requests.PaginatedResult, error) {
+	lockouts, total, err := s.repo.List(ctx, params)
+	if err != nil {
+		logconfig.Log.Error("Giriş kilitleri listelenemedi", zap.Error(err))
+		return nil, err
+	}
+	return requests.CreatePaginatedResult(lockouts, total, params.Page, params.PerPage), nil
+}
+
+func (s *LoginThrottleService) Unlock(ctx context.Context, id uint, adminID uint) error {
+	lockout, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return ErrLoginLockoutNotFound
+		}
+		return err
+	}
+	if !lockout.Active(s.now()) {
+		return ErrLoginLockoutInactive
+	}
+
+	subject := throttleSubject{scope: lockout.Scope, value: lockout.Subject}
+	if err := s.redis.Del(ctx, subject.key("lock"), subject.key("fail"), subject.key("level")).Err(); err != nil {
+		logconfig.Log.Error("Giriş kilidi açılamadı", zap.Uint("lockout_id", id), zap.Error(err))
+		return err
+	}
+	if err := s.repo.MarkUnlocked(ctx, lockout.Scope, lockout.Subject, adminID); err != nil {
+		logconfig.Log.Error("Giriş kilidi kaydı güncellenemedi", zap.Uint("lockout_id", id), zap.Error(err))
+		return err
+	}
+
+	logconfig.Log.Warn("Giriş kilidi yönetici tarafından açıldı",
+		zap.Uint("lockout_id", id),
+		zap.String("scope", lockout.Scope),
+		zap.String("subject", lockout.Subject),
+		zap.Uint("admin_id", adminID))
+	return nil
+}
+
+var _ ILoginThrottleService = (*LoginThrottleService)(nil)
@@ -0,0 +1,181 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"zatrano/configs/envconfig"
+	"zatrano/configs/logconfig"
+	"zatrano/configs/redisconfig"
+	"zatrano/models"
+	"zatrano/repositories"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+var ErrMagicLinkRateLimited = errors.New("çok fazla giriş bağlantısı istendi")
+
+const magicLinkRatePrefix = "magic_link"
+
+// IMagicLinkService, parolasız giriş için e-postayla tek kullanımlık giriş bağlantısı gönderir.
+// Bağlantı isteyen tarayıcıya bağlıdır; başka bir tarayıcıda açılırsa giriş yapılmaz.
+type IMagicLinkService interface {
+	// Send, adres kayıtlı ve aktifse bağlantıyı gönderir ve tarayıcıya yazılacak bağlama değerini döner.
+	// Hesabın varlığı ele verilmesin diye kayıtlı olmayan adreslerde de değer üretilir ve hata dönmez;
+	// adres başına istek sınırı her adres için aynı şekilde işler.
+	Send(ctx context.Context, email, userAgent, ip string) (string, error)
+	// Login, bağlantıyı kullanır ve giriş yapacak kullanıcıyı döner.
+	Login(ctx context.Context, token, browser, userAgent, ip string) (*MagicLinkLogin, error)
+	// LinkTTL, bağlantının geçerlilik süresidir; tarayıcı çerezi de bu süre kadar tutulur.
+	LinkTTL() time.Duration
+}
+
+// MagicLinkLogin, giriş bağlantısıyla doğrulanan kullanıcıdır. PasswordCleared, adres bu girişle ilk kez
+// doğrulandığı için hesapta önceden belirlenmiş parolanın kaldırıldığını belirtir; kullanıcıya bildirilmelidir.
+type MagicLinkLogin struct {
+	User            *models.User
+	PasswordCleared bool
+}
+
+type MagicLinkService struct {
+	redis    *redis.Client
+	users    repositories.IAuthRepository
+	tokens   IUserTokenService
+	sessions ISessionService
+	mail     IMailService
+	ttl      time.Duration
+	max      int
+	window   time.Duration
+}
+
+// NewMagicLinkService; MAGIC_LINK_MAX_PER_WINDOW sıfır verilirse adres başına sınır uygulanmaz.
+func NewMagicLinkService() IMagicLinkService {
+	return &MagicLinkService{
+		redis:    redisconfig.GetClient(),
+		users:    repositories.NewAuthRepository(),
+		tokens:   NewUserTokenService(),
+		sessions: NewSessionService(),
+		mail:     NewMailService(),
+		ttl:      time.Duration(envconfig.Int("MAGIC_LINK_TOKEN_MINUTES", 15)) * time.Minute,
+		max:      envconfig.Int("MAGIC_LINK_MAX_PER_WINDOW", 3),
+		window:   time.Duration(envconfig.Int("MAGIC_LINK_WINDOW_MINUTES", 15)) * time.Minute,
+	}
+}
+
+// allow, adres için pencere içindeki istek sayısını artırır; Redis'e ulaşılamazsa istek engellenmez.
+func (s *MagicLinkService) allow(ctx context.Context, email string) bool {
+	if s.max <= 0 {
+		return true
+	}
+	key := redisconfig.GetPrefixedKey(magicLinkRatePrefix, "rate:"+email)
+	count, err := s.redis.Incr(ctx, key).Result()
+	if err != nil {
+		logconfig.Log.Error("Giriş bağlantısı sayacı güncellenemedi", zap.Error(err))
+		return true
+	}
+	if count == 1 {
+		if err := s.redis.Expire(ctx, key, s.window).Err(); err != nil {
+			logconfig.Log.Error("Giriş bağlantısı sayacının süresi ayarlanamadı", zap.Error(err))
+		}
+	}
+	return count <= int64(s.max)
+}
+
+func (s *MagicLinkService) Send(ctx context.Context, email, userAgent, ip string) (string, error) {
+	email = strings.TrimSpace(email)
+	if !s.allow(ctx, normalizeLoginEmail(email)) {
+		logconfig.Log.Warn("Giriş bağlantısı istek sınırı aşıldı", zap.String("email", email), zap.String("ip", ip))
+		return "", ErrMagicLinkRateLimited
+	}
+
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	browser := hex.EncodeToString(buf)
+
+	user, err := s.users.FindUserByEmail(email)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return browser, nil
+		}
+		return "", ErrAuthGeneric
+	}
+	if !user.IsActive {
+		logconfig.Log.Warn("Aktif olmayan hesap için giriş bağlantısı istendi", zap.Uint("user_id", user.ID))
+		return browser, nil
+	}
+
+	token, err := s.tokens.IssueForBrowser(ctx, user.ID, models.UserTokenPurposeMagicLogin, browser, userAgent, ip)
+	if err != nil {
+		return "", ErrDatabaseUpdateFailed
+	}
+
+	link := envconfig.String("APP_BASE_URL", "") + "/auth/magic-link/verify?token=" + token
+	body := "Giriş yapmak için aşağıdaki bağlantıya tıklayın: " + link +
+		"\n\nBağlantı tek kullanımlıktır ve yalnızca isteği yaptığınız tarayıcıda çalışır." +
+		"\nBu isteği siz yapmadıysanız bu e-postayı dikkate almayın."
+	if err := s.mail.SendMail(user.Email, "Giriş Bağlantısı", body); err != nil {
+		return "", fmt.Errorf("giriş bağlantısı e-postası gönderilemedi: %w", err)
+	}
+	logconfig.Log.Info("Giriş bağlantısı gönderildi", zap.Uint("user_id", user.ID), zap.String("ip", ip))
+	return browser, nil
+}
+
+// Login; bağlantı e-posta adresine gönderildiği için kullanılması adresin doğrulandığını da gösterir.
+// Adres ilk kez böyle doğrulanıyorsa hesabı adresin sahibinden önce kaydeden biri olabilir; onun
+// belirleyebildiği tek giriş yolu olan parola kaldırılır ve açık oturumlar kapatılır.
+func (s *MagicLinkService) Login(ctx context.Context, token, browser, userAgent, ip string) (*MagicLinkLogin, error) {
+	used, err := s.tokens.ConsumeForBrowser(ctx, models.UserTokenPurposeMagicLogin, token, browser, userAgent, ip)
+	if err != nil {
+		if errors.Is(err, ErrUserTokenInvalid) || errors.Is(err, ErrUserTokenWrongBrowser) {
+			return nil, err
+		}
+		return nil, ErrAuthGeneric
+	}
+
+	user, err := s.users.FindUserByID(used.UserID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, ErrAuthGeneric
+	}
+	if !user.IsActive {
+		return nil, ErrUserInactive
+	}
+	result := &MagicLinkLogin{User: user}
+	if !user.EmailVerified {
+		claimed, err := s.users.ClaimUnverifiedEmail(ctx, user.ID)
+		if err != nil {
+			return nil, ErrDatabaseUpdateFailed
+		}
+		if claimed {
+			result.PasswordCleared = user.Password != ""
+			logconfig.Log.Warn("Doğrulanmamış hesap giriş bağlantısıyla doğrulandı; önceden belirlenmiş parola kaldırıldı",
+				zap.Uint("user_id", user.ID), zap.Bool("password_cleared", result.PasswordCleared), zap.String("ip", ip))
+			// Hesap değişikliği kaydedildiği için hata yalnızca loglanır
+			if err := s.sessions.RevokeAll(ctx, user.ID); err != nil {
+				logconfig.Log.Error("Sahiplenilen hesabın oturumları kapatılamadı", zap.Uint("user_id", user.ID), zap.Error(err))
+			}
+		}
+		if result.User, err = s.users.FindUserByID(user.ID); err != nil {
+			return nil, ErrAuthGeneric
+		}
+	}
+	logconfig.Log.Info("Giriş bağlantısıyla kimlik doğrulandı", zap.Uint("user_id", user.ID), zap.String("ip", ip))
+	return result, nil
+}
+
+func (s *MagicLinkService) LinkTTL() time.Duration {
+	return s.ttl
+}
+
+var _ IMagicLinkService = (*MagicLinkService)(nil)
@@ -0,0 +1,226 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"zatrano/models"
+	"zatrano/repositories"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+// fakeTokenRepo, anahtarları bellekte tutan IUserTokenRepository'dir; sorgu koşulları gerçek deponunkiyle aynıdır.
+type fakeTokenRepo struct {
+	tokens []models.UserToken
+}
+
+func (r *fakeTokenRepo) Create(ctx context.Context, token *models.UserToken) error {
+	kept := r.tokens[:0]
+	for _, t := range r.tokens {
+		if t.UserID != token.UserID || t.Purpose != token.Purpose || t.UsedAt != nil {
+			kept = append(kept, t)
+		}
+	}
+	token.ID = uint(len(kept) + 1)
+	r.tokens = append(kept, *token)
+	return nil
+}
+
+func (r *fakeTokenRepo) FindValid(ctx context.Context, purpose, tokenHash string, now time.Time) (*models.UserToken, error) {
+	for _, t := range r.tokens {
+		if t.Purpose == purpose && t.TokenHash == tokenHash && t.UsedAt == nil && t.ExpiresAt.After(now) {
+			return &t, nil
+		}
+	}
+	return nil, repositories.ErrNotFound
+}
+
+func (r *fakeTokenRepo) Consume(ctx context.Context, purpose, tokenHash, browserHash, userAgent, ip string, now time.Time) (*models.UserToken, error) {
+	for i := range r.tokens {
+		t := &r.tokens[i]
+		if t.Purpose == purpose && t.TokenHash == tokenHash && t.BrowserHash == browserHash && t.UsedAt == nil && t.ExpiresAt.After(now) {
+			t.UsedAt, t.UsedIP, t.UsedUserAgent = &now, ip, userAgent
+			used := *t
+			return &used, nil
+		}
+	}
+	return nil, repositories.ErrNotFound
+}
+
+func (r *fakeTokenRepo) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	return 0, nil
+}
+
+// fakeMail, gönderilen son e-postanın gövdesini tutar.
+type fakeMail struct {
+	body string
+}
+
+func (m *fakeMail) SendMail(to, subject, body string) error {
+	m.body = body
+	return nil
+}
+
+// fakeSessions, RevokeAll çağrılarını sayan ISessionService'tir.
+type fakeSessions struct {
+	ISessionService
+	revoked []uint
+}
+
+func (s *fakeSessions) RevokeAll(ctx context.Context, userID uint) error {
+	s.revoked = append(s.revoked, userID)
+	return nil
+}
+
+type magicLinkTestEnv struct {
+	service  *MagicLinkService
+	tokens   *UserTokenService
+	users    *fakeAuthRepo
+	mail     *fakeMail
+	sessions *fakeSessions
+	now      time.Time
+}
+
+func newMagicLinkTestEnv(t *testing.T) *magicLinkTestEnv {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+
+	e := &magicLinkTestEnv{
+		users:    &fakeAuthRepo{users: make(map[uint]*models.User)},
+		mail:     &fakeMail{},
+		sessions: &fakeSessions{},
+		now:      time.Now(),
+	}
+	e.users.users[1] = &models.User{BaseModel: models.BaseModel{ID: 1, IsActive: true}, Email: "ayse@example.com", Password: "hash", EmailVerified: true}
+	e.tokens = &UserTokenService{
+		repo: &fakeTokenRepo{},
+		ttl:  map[string]time.Duration{models.UserTokenPurposeMagicLogin: 15 * time.Minute},
+		now:  func() time.Time { return e.now },
+	}
+	e.service = &MagicLinkService{
+		redis:    client,
+		users:    e.users,
+		tokens:   e.tokens,
+		sessions: e.sessions,
+		mail:     e.mail,
+		ttl:      15 * time.Minute,
+		max:      3,
+		window:   15 * time.Minute,
+	}
+	return e
+}
+
+// send, bağlantıyı ister ve e-postadaki anahtarla tarayıcıya yazılacak bağlama değerini döner.
+func (e *magicLinkTestEnv) send(t *testing.T) (token, browser string) {
+	t.Helper()
+	browser, err := e.service.Send(context.Background(), "ayse@example.com", "ua", "10.0.0.1")
+	if err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	i := strings.Index(e.mail.body, "token=")
+	if i < 0 {
+		t.Fatalf("e-postada bağlantı yok: %q", e.mail.body)
+	}
+	return strings.Fields(e.mail.body[i+len("token="):])[0], browser
+}
+
+func (e *magicLinkTestEnv) login(token, browser string) (*MagicLinkLogin, error) {
+	return e.service.Login(context.Background(), token, browser, "ua", "10.0.0.1")
+}
+
+func TestMagicLinkLogin(t *testing.T) {
+	e := newMagicLinkTestEnv(t)
+	token, browser := e.send(t)
+
+	result, err := e.login(token, browser)
+	if err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+	if result.User.ID != 1 || result.PasswordCleared || result.User.Password != "hash" {
+		t.Fatalf("beklenmeyen sonuç: %+v", result)
+	}
+	if len(e.sessions.revoked) != 0 {
+		t.Fatal("doğrulanmış hesabın oturumları kapatıldı")
+	}
+}
+
+func TestMagicLinkLoginRequiresRequestingBrowser(t *testing.T) {
+	e := newMagicLinkTestEnv(t)
+	token, browser := e.send(t)
+
+	for name, other := range map[string]string{"çerezsiz": "", "başka tarayıcı": "another-browser"} {
+		if _, err := e.login(token, other); !errors.Is(err, ErrUserTokenWrongBrowser) {
+			t.Fatalf("%s: hata = %v, beklenen %v", name, err, ErrUserTokenWrongBrowser)
+		}
+	}
+	// Yanlış tarayıcıdaki denemeler bağlantıyı harcamamalı
+	if _, err := e.login(token, browser); err != nil {
+		t.Fatalf("isteyen tarayıcıda Login: %v", err)
+	}
+}
+
+func TestMagicLinkLoginIsSingleUse(t *testing.T) {
+	e := newMagicLinkTestEnv(t)
+	token, browser := e.send(t)
+
+	if _, err := e.login(token, browser); err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+	if _, err := e.login(token, browser); !errors.Is(err, ErrUserTokenInvalid) {
+		t.Fatalf("hata = %v, beklenen %v", err, ErrUserTokenInvalid)
+	}
+}
+
+func TestMagicLinkLoginRejectsExpiredOrReplacedLink(t *testing.T) {
+	e := newMagicLinkTestEnv(t)
+	first, _ := e.send(t)
+	second, browser := e.send(t)
+
+	if _, err := e.login(first, browser); !errors.Is(err, ErrUserTokenInvalid) {
+		t.Fatalf("yenisi istenen bağlantı: hata = %v", err)
+	}
+	e.now = e.now.Add(15*time.Minute + time.Second)
+	if _, err := e.login(second, browser); !errors.Is(err, ErrUserTokenInvalid) {
+		t.Fatalf("süresi dolmuş bağlantı: hata = %v", err)
+	}
+}
+
+func TestMagicLinkLoginClaimsUnverifiedAccount(t *testing.T) {
+	e := newMagicLinkTestEnv(t)
+	// Adresi sahibinden önce kaydeden birinin belirlediği parola kaldırılmalı
+	e.users.users[1].EmailVerified = false
+	token, browser := e.send(t)
+
+	result, err := e.login(token, browser)
+	if err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+	if !result.PasswordCleared || !result.User.EmailVerified || result.User.Password != "" {
+		t.Fatalf("hesap sahiplenilmedi: %+v", result)
+	}
+	if len(e.sessions.revoked) != 1 || e.sessions.revoked[0] != 1 {
+		t.Fatalf("oturumlar kapatılmadı: %v", e.sessions.revoked)
+	}
+}
+
+func TestMagicLinkLoginClaimWithoutPassword(t *testing.T) {
+	e := newMagicLinkTestEnv(t)
+	e.users.users[1].EmailVerified = false
+	e.users.users[1].Password = ""
+	token, browser := e.send(t)
+
+	result, err := e.login(token, browser)
+	if err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+	if result.PasswordCleared || !result.User.EmailVerified {
+		t.Fatalf("parolasız hesapta parola kaldırıldı bildirildi: %+v", result)
+	}
+}
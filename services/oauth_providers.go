@@ -0,0 +1,474 @@
+package services
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/subtle"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"zatrano/configs/envconfig"
+	"zatrano/configs/logconfig"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	"github.com/go-jose/go-jose/v4"
+	"go.uber.org/zap"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/facebook"
+)
+
+// OAuthProfile, sağlayıcının doğruladığı kullanıcı kimliğidir.
+type OAuthProfile struct {
+	Subject string
+	Email   string
+	// EmailVerified, sağlayıcının e-posta adresinin sahipliğini doğruladığını belirtir; yalnızca bu
+	// durumda kimlik aynı e-postalı mevcut hesaba kendiliğinden bağlanır.
+	EmailVerified bool
+	Name          string
+}
+
+// IOAuthProvider, bir OAuth2/OIDC kimlik sağlayıcısıyla konuşan sürücüyü tanımlar.
+type IOAuthProvider interface {
+	Name() string
+	DisplayName() string
+	// Icon, Font Awesome sınıfıdır (ör. "fab fa-google").
+	Icon() string
+	// AuthCodeURL, kullanıcının yönlendirileceği yetkilendirme adresini üretir. nonce ID token'a,
+	// verifier PKCE kod doğrulayıcısına karşılık gelir; sağlayıcı desteklemiyorsa yok sayılır.
+	AuthCodeURL(ctx context.Context, state, nonce, verifier string) (string, error)
+	// Exchange, yetkilendirme kodunu token'la değiştirir ve kimliği doğrular. form, geri dönüş
+	// isteğinin alanlarıdır (Apple ad bilgisini yalnızca ilk girişte formla gönderir).
+	Exchange(ctx context.Context, code, nonce, verifier string, form func(key string) string) (*OAuthProfile, error)
+}
+
+var (
+	oauthProvidersOnce sync.Once
+	oauthProviders     []IOAuthProvider
+)
+
+// registeredOAuthProviders, istemci kimliği tanımlı sağlayıcıları döner. Keşif belgesi ve imza
+// anahtarları sağlayıcı nesnesinde önbelleğe alındığı için liste süreç boyunca bir kez kurulur.
+func registeredOAuthProviders() []IOAuthProvider {
+	oauthProvidersOnce.Do(func() {
+		oauthProviders = newOAuthProviders()
+	})
+	return oauthProviders
+}
+
+// newOAuthProviders, ortam değişkenlerinden sağlayıcıları kurar; <SAĞLAYICI>_CLIENT_ID boşsa
+// sağlayıcı devre dışıdır. Geri dönüş adresi verilmezse APP_BASE_URL/auth/<ad>/callback kullanılır.
+func newOAuthProviders() []IOAuthProvider {
+	client := &http.Client{Timeout: 15 * time.Second}
+	var providers []IOAuthProvider
+
+	if id := envconfig.String("GOOGLE_CLIENT_ID", ""); id != "" {
+		providers = append(providers, &oidcProvider{
+			name:         "google",
+			displayName:  "Google",
+			icon:         "fab fa-google",
+			issuer:       "https://accounts.google.com",
+			clientID:     id,
+			clientSecret: envconfig.String("GOOGLE_CLIENT_SECRET", ""),
+			redirectURL:  oauthRedirectURL("google"),
+			scopes:       []string{"openid", "email", "profile"},
+			pkce:         true,
+			client:       client,
+		})
+	}
+
+	if id := envconfig.String("MICROSOFT_CLIENT_ID", ""); id != "" {
+		tenant := envconfig.String("MICROSOFT_TENANT", "common")
+		var issuerTemplate string
+		switch tenant {
+		case "common", "organizations", "consumers":
+			// Çok kiracılı uçların keşif belgesi yayıncıyı {tenantid} yer tutucusuyla verir
+			issuerTemplate = "https://login.microsoftonline.com/" + oidcTenantPlaceholder + "/v2.0"
+		}
+		providers = append(providers, &oidcProvider{
+			name:           "microsoft",
+			displayName:    "Microsoft",
+			icon:           "fab fa-microsoft",
+			issuer:         "https://login.microsoftonline.com/" + tenant + "/v2.0",
+			issuerTemplate: issuerTemplate,
+			clientID:       id,
+			clientSecret:   envconfig.String("MICROSOFT_CLIENT_SECRET", ""),
+			redirectURL:    oauthRedirectURL("microsoft"),
+			scopes:         []string{"openid", "email", "profile"},
+			pkce:           true,
+			client:         client,
+		})
+	}
+
+	if id := envconfig.String("FACEBOOK_CLIENT_ID", ""); id != "" {
+		providers = append(providers, &facebookProvider{
+			config: &oauth2.Config{
+				ClientID:     id,
+				ClientSecret: envconfig.String("FACEBOOK_CLIENT_SECRET", ""),
+				RedirectURL:  oauthRedirectURL("facebook"),
+				Scopes:       []string{"email", "public_profile"},
+				Endpoint:     facebook.Endpoint,
+			},
+			graphURL: envconfig.String("FACEBOOK_GRAPH_URL", "https://graph.facebook.com"),
+			client:   client,
+		})
+	}
+
+	if id := envconfig.String("APPLE_CLIENT_ID", ""); id != "" {
+		secret, err := newAppleClientSecret(id,
+			envconfig.String("APPLE_TEAM_ID", ""),
+			envconfig.String("APPLE_KEY_ID", ""),
+			envconfig.String("APPLE_PRIVATE_KEY_PATH", ""))
+		if err != nil {
+			logconfig.Log.Error("Apple ile giriş devre dışı: özel anahtar okunamadı", zap.Error(err))
+		} else {
+			providers = append(providers, &oidcProvider{
+				name:        "apple",
+				displayName: "Apple",
+				icon:        "fab fa-apple",
+				issuer:      "https://appleid.apple.com",
+				clientID:    id,
+				secretFunc:  secret,
+				redirectURL: oauthRedirectURL("apple"),
+				scopes:      []string{"openid", "name", "email"},
+				// Apple ad ve e-posta istendiğinde yanıtı yalnızca form POST ile döner ve PKCE desteklemez
+				formPost: true,
+				client:   client,
+			})
+		}
+	}
+
+	if id := envconfig.String("OIDC_CLIENT_ID", ""); id != "" {
+		issuer := envconfig.String("OIDC_ISSUER", "")
+		if issuer == "" {
+			logconfig.Log.Error("Genel OIDC sağlayıcısı devre dışı: OIDC_ISSUER tanımlı değil")
+		} else {
+			providers = append(providers, &oidcProvider{
+				name:         "oidc",
+				displayName:  envconfig.String("OIDC_DISPLAY_NAME", "Kurumsal Hesap"),
+				icon:         "fas fa-id-badge",
+				issuer:       issuer,
+				clientID:     id,
+				clientSecret: envconfig.String("OIDC_CLIENT_SECRET", ""),
+				redirectURL:  oauthRedirectURL("oidc"),
+				scopes:       strings.Fields(envconfig.String("OIDC_SCOPES", "openid email profile")),
+				pkce:         true,
+				client:       client,
+			})
+		}
+	}
+
+	return providers
+}
+
+func oauthRedirectURL(name string) string {
+	if v := envconfig.String(strings.ToUpper(name)+"_REDIRECT_URI", ""); v != "" {
+		return v
+	}
+	return strings.TrimSuffix(envconfig.String("APP_BASE_URL", ""), "/") + "/auth/" + name + "/callback"
+}
+
+// ---------------------------------------------------------------------
+// oidc: Google, Microsoft, Apple ve genel OpenID Connect sağlayıcıları
+// ---------------------------------------------------------------------
+
+// oidcTenantPlaceholder, çok kiracılı sağlayıcıların yayıncı adresinde token'daki tid ile değişen kısımdır.
+const oidcTenantPlaceholder = "{tenantid}"
+
+var (
+	errOIDCNoIDToken = errors.New("sağlayıcı ID token döndürmedi")
+	errOIDCNonce     = errors.New("oidc: nonce eşleşmiyor")
+	errOIDCAudience  = errors.New("oidc: ID token bu istemci için verilmemiş (azp)")
+	errOIDCIssuer    = errors.New("oidc: ID token yayıncısı (iss) kiracıyla eşleşmiyor")
+	errOIDCSubject   = errors.New("oidc: ID token kullanıcı kimliği (sub) içermiyor")
+)
+
+// oidcClaims, doğrulanmış ID token'dan go-oidc'nin çözmediği alanlardır.
+type oidcClaims struct {
+	AuthorizedBy string `json:"azp"`
+	Email        string `json:"email"`
+	// EmailVerified; bazı sağlayıcılar (ör. Apple) alanı "true" dizesi olarak gönderir.
+	EmailVerified json.RawMessage `json:"email_verified"`
+	Name          string          `json:"name"`
+	TenantID      string          `json:"tid"`
+}
+
+func (c oidcClaims) emailVerified() bool {
+	var b bool
+	if err := json.Unmarshal(c.EmailVerified, &b); err == nil {
+		return b
+	}
+	var s string
+	if err := json.Unmarshal(c.EmailVerified, &s); err == nil {
+		return strings.EqualFold(s, "true")
+	}
+	return false
+}
+
+type oidcProvider struct {
+	name        string
+	displayName string
+	icon        string
+	issuer      string
+	// issuerTemplate, keşif belgesindeki yayıncı adresi yer tutucu içerdiğinde (Microsoft common) doludur;
+	// yayıncı bu durumda token'daki tid ile tamamlanarak denetlenir.
+	issuerTemplate string
+	clientID       string
+	clientSecret   string
+	// secretFunc, istemci gizli anahtarı her istekte üretilen sağlayıcılar içindir (Apple).
+	secretFunc  func() (string, error)
+	redirectURL string
+	scopes      []string
+	pkce        bool
+	formPost    bool
+	client      *http.Client
+	// now, token süresinin denetlendiği saattir; boşsa time.Now kullanılır.
+	now func() time.Time
+
+	mu       sync.Mutex
+	provider *oidc.Provider
+	verifier *oidc.IDTokenVerifier
+}
+
+func (p *oidcProvider) Name() string        { return p.name }
+func (p *oidcProvider) DisplayName() string { return p.displayName }
+func (p *oidcProvider) Icon() string        { return p.icon }
+
+// discover, keşif belgesini ilk kullanımda indirir; hata durumunda sonraki istekte yeniden dener.
+// İmza anahtarları (JWKS) go-oidc tarafından önbelleğe alınır ve bilinmeyen kid geldiğinde yenilenir.
+func (p *oidcProvider) discover(ctx context.Context) (*oidc.Provider, *oidc.IDTokenVerifier, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.provider != nil {
+		return p.provider, p.verifier, nil
+	}
+	discoveryCtx := oidc.ClientContext(ctx, p.client)
+	if p.issuerTemplate != "" {
+		discoveryCtx = oidc.InsecureIssuerURLContext(discoveryCtx, p.issuerTemplate)
+	}
+	provider, err := oidc.NewProvider(discoveryCtx, p.issuer)
+	if err != nil {
+		return nil, nil, fmt.Errorf("oidc: keşif belgesi alınamadı: %w", err)
+	}
+	p.provider = provider
+	// Anahtar kümesi istekten uzun yaşar; isteğin bağlamı yerine yalnızca HTTP istemcisi taşınır
+	p.verifier = provider.VerifierContext(oidc.ClientContext(context.Background(), p.client), &oidc.Config{
+		ClientID:        p.clientID,
+		SkipIssuerCheck: p.issuerTemplate != "",
+		Now:             p.now,
+	})
+	return p.provider, p.verifier, nil
+}
+
+func (p *oidcProvider) config(provider *oidc.Provider, secret string) *oauth2.Config {
+	endpoint := provider.Endpoint()
+	endpoint.AuthStyle = oauth2.AuthStyleInParams
+	return &oauth2.Config{
+		ClientID:     p.clientID,
+		ClientSecret: secret,
+		RedirectURL:  p.redirectURL,
+		Scopes:       p.scopes,
+		Endpoint:     endpoint,
+	}
+}
+
+func (p *oidcProvider) AuthCodeURL(ctx context.Context, state, nonce, verifier string) (string, error) {
+	provider, _, err := p.discover(ctx)
+	if err != nil {
+		return "", err
+	}
+	opts := []oauth2.AuthCodeOption{oidc.Nonce(nonce)}
+	if p.pkce {
+		opts = append(opts, oauth2.S256ChallengeOption(verifier))
+	}
+	if p.formPost {
+		opts = append(opts, oauth2.SetAuthURLParam("response_mode", "form_post"))
+	}
+	return p.config(provider, p.clientSecret).AuthCodeURL(state, opts...), nil
+}
+
+func (p *oidcProvider) Exchange(ctx context.Context, code, nonce, verifier string, form func(key string) string) (*OAuthProfile, error) {
+	provider, idVerifier, err := p.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+	secret := p.clientSecret
+	if p.secretFunc != nil {
+		if secret, err = p.secretFunc(); err != nil {
+			return nil, err
+		}
+	}
+
+	var opts []oauth2.AuthCodeOption
+	if p.pkce {
+		opts = append(opts, oauth2.VerifierOption(verifier))
+	}
+	tok, err := p.config(provider, secret).Exchange(context.WithValue(ctx, oauth2.HTTPClient, p.client), code, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("token değişimi başarısız: %w", err)
+	}
+	rawIDToken, _ := tok.Extra("id_token").(string)
+	if rawIDToken == "" {
+		return nil, errOIDCNoIDToken
+	}
+
+	// İmza, yayıncı, hedef kitle (aud) ve süre go-oidc tarafından denetlenir
+	idToken, err := idVerifier.Verify(ctx, rawIDToken)
+	if err != nil {
+		return nil, err
+	}
+	var claims oidcClaims
+	if err := idToken.Claims(&claims); err != nil {
+		return nil, err
+	}
+	if nonce == "" || subtle.ConstantTimeCompare([]byte(idToken.Nonce), []byte(nonce)) != 1 {
+		return nil, errOIDCNonce
+	}
+	if len(idToken.Audience) > 1 && claims.AuthorizedBy != "" && claims.AuthorizedBy != p.clientID {
+		return nil, errOIDCAudience
+	}
+	if p.issuerTemplate != "" &&
+		(claims.TenantID == "" || idToken.Issuer != strings.ReplaceAll(p.issuerTemplate, oidcTenantPlaceholder, claims.TenantID)) {
+		return nil, errOIDCIssuer
+	}
+	if idToken.Subject == "" {
+		return nil, errOIDCSubject
+	}
+
+	profile := &OAuthProfile{
+		Subject:       idToken.Subject,
+		Email:         strings.ToLower(strings.TrimSpace(claims.Email)),
+		EmailVerified: claims.emailVerified(),
+		Name:          claims.Name,
+	}
+	if profile.Name == "" && form != nil {
+		profile.Name = appleUserName(form("user"))
+	}
+	return profile, nil
+}
+
+// appleUserName, Apple'ın yalnızca ilk girişte gönderdiği user alanındaki adı çıkarır.
+func appleUserName(raw string) string {
+	if raw == "" {
+		return ""
+	}
+	var user struct {
+		Name struct {
+			FirstName string `json:"firstName"`
+			LastName  string `json:"lastName"`
+		} `json:"name"`
+	}
+	if err := json.Unmarshal([]byte(raw), &user); err != nil {
+		return ""
+	}
+	return strings.TrimSpace(user.Name.FirstName + " " + user.Name.LastName)
+}
+
+// newAppleClientSecret, Apple'ın istemci gizli anahtarı olarak beklediği ES256 imzalı JWT'yi
+// üreten fonksiyonu döner. JWT kısa ömürlüdür ve her token değişiminde yeniden imzalanır.
+func newAppleClientSecret(clientID, teamID, keyID, keyPath string) (func() (string, error), error) {
+	if teamID == "" || keyID == "" || keyPath == "" {
+		return nil, errors.New("APPLE_TEAM_ID, APPLE_KEY_ID ve APPLE_PRIVATE_KEY_PATH zorunludur")
+	}
+	raw, err := os.ReadFile(keyPath)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(raw)
+	if block == nil {
+		return nil, errors.New("özel anahtar PEM biçiminde değil")
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	key, ok := parsed.(*ecdsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("özel anahtar ECDSA değil")
+	}
+	signer, err := jose.NewSigner(jose.SigningKey{Algorithm: jose.ES256, Key: key},
+		(&jose.SignerOptions{}).WithType("JWT").WithHeader("kid", keyID))
+	if err != nil {
+		return nil, err
+	}
+	return func() (string, error) {
+		now := time.Now()
+		payload, err := json.Marshal(map[string]interface{}{
+			"iss": teamID,
+			"iat": now.Unix(),
+			"exp": now.Add(5 * time.Minute).Unix(),
+			"aud": "https://appleid.apple.com",
+			"sub": clientID,
+		})
+		if err != nil {
+			return "", err
+		}
+		signed, err := signer.Sign(payload)
+		if err != nil {
+			return "", err
+		}
+		return signed.CompactSerialize()
+	}, nil
+}
+
+// ---------------------------------------------------------------------
+// facebook: OIDC değil, düz OAuth2; kimlik Graph API'den okunur
+// ---------------------------------------------------------------------
+
+type facebookProvider struct {
+	config   *oauth2.Config
+	graphURL string
+	client   *http.Client
+}
+
+func (p *facebookProvider) Name() string        { return "facebook" }
+func (p *facebookProvider) DisplayName() string { return "Facebook" }
+func (p *facebookProvider) Icon() string        { return "fab fa-facebook" }
+
+func (p *facebookProvider) AuthCodeURL(ctx context.Context, state, nonce, verifier string) (string, error) {
+	return p.config.AuthCodeURL(state, oauth2.S256ChallengeOption(verifier)), nil
+}
+
+func (p *facebookProvider) Exchange(ctx context.Context, code, nonce, verifier string, form func(key string) string) (*OAuthProfile, error) {
+	ctx = context.WithValue(ctx, oauth2.HTTPClient, p.client)
+	tok, err := p.config.Exchange(ctx, code, oauth2.VerifierOption(verifier))
+	if err != nil {
+		return nil, fmt.Errorf("token değişimi başarısız: %w", err)
+	}
+
+	resp, err := p.config.Client(ctx, tok).Get(p.graphURL + "/me?fields=" + url.QueryEscape("id,name,email"))
+	if err != nil {
+		return nil, fmt.Errorf("kullanıcı bilgileri alınamadı: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("kullanıcı bilgileri alınamadı: beklenmeyen yanıt %d", resp.StatusCode)
+	}
+
+	var me struct {
+		ID    string `json:"id"`
+		Name  string `json:"name"`
+		Email string `json:"email"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&me); err != nil {
+		return nil, fmt.Errorf("kullanıcı bilgileri çözümlenemedi: %w", err)
+	}
+	if me.ID == "" {
+		return nil, errors.New("sağlayıcı kullanıcı kimliği döndürmedi")
+	}
+	// Facebook yalnızca onaylanmış e-posta adreslerini paylaşır
+	return &OAuthProfile{
+		Subject:       me.ID,
+		Email:         strings.ToLower(strings.TrimSpace(me.Email)),
+		EmailVerified: me.Email != "",
+		Name:          me.Name,
+	}, nil
+}
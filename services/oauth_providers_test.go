@@ -0,0 +1,427 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	"github.com/go-jose/go-jose/v4"
+)
+
+const (
+	testOIDCClientID = "zatrano-test"
+	testOIDCKeyID    = "test-key"
+)
+
+// fakeOIDCGrant, sahte yetkilendirme ucunun verdiği kodun bağlı olduğu PKCE ve nonce değerleridir.
+type fakeOIDCGrant struct {
+	challenge string
+	nonce     string
+}
+
+// fakeOIDC; keşif, JWKS ve token uçlarını sunan httptest tabanlı sahte OpenID Connect sağlayıcısıdır.
+type fakeOIDC struct {
+	t      *testing.T
+	server *httptest.Server
+	key    *rsa.PrivateKey
+	// issuer, keşif belgesinde yayınlanan yayıncıdır; boşsa sunucu adresi kullanılır.
+	issuer string
+	// claims, token ucunun imzaladığı ID token alanlarını testin istediği gibi değiştirir.
+	claims func(claims map[string]interface{})
+	// sign, ID token'ı sağlayıcının anahtarı yerine testin istediği biçimde imzalar.
+	sign func(claims map[string]interface{}) string
+
+	mu     sync.Mutex
+	grants map[string]fakeOIDCGrant
+}
+
+func newFakeOIDC(t *testing.T) *fakeOIDC {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := &fakeOIDC{t: t, key: key, grants: make(map[string]fakeOIDCGrant)}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		writeTestJSON(w, http.StatusOK, map[string]interface{}{
+			"issuer":                                f.issuerURL(),
+			"authorization_endpoint":                f.server.URL + "/authorize",
+			"token_endpoint":                        f.server.URL + "/token",
+			"jwks_uri":                              f.server.URL + "/jwks",
+			"id_token_signing_alg_values_supported": []string{"RS256"},
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		writeTestJSON(w, http.StatusOK, jose.JSONWebKeySet{Keys: []jose.JSONWebKey{
+			{Key: &f.key.PublicKey, KeyID: testOIDCKeyID, Algorithm: string(jose.RS256), Use: "sig"},
+		}})
+	})
+	mux.HandleFunc("/token", f.token)
+	f.server = httptest.NewServer(mux)
+	t.Cleanup(f.server.Close)
+	return f
+}
+
+func writeTestJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func (f *fakeOIDC) issuerURL() string {
+	if f.issuer != "" {
+		return f.issuer
+	}
+	return f.server.URL
+}
+
+// authorize, kullanıcının onay verdiğini varsayar ve yetkilendirme adresindeki PKCE ile nonce'a bağlı bir kod üretir.
+func (f *fakeOIDC) authorize(authURL string) string {
+	f.t.Helper()
+	u, err := url.Parse(authURL)
+	if err != nil {
+		f.t.Fatal(err)
+	}
+	q := u.Query()
+	if q.Get("client_id") != testOIDCClientID {
+		f.t.Fatalf("client_id = %q", q.Get("client_id"))
+	}
+	if m := q.Get("code_challenge_method"); q.Get("code_challenge") != "" && m != "S256" {
+		f.t.Fatalf("code_challenge_method = %q, S256 bekleniyordu", m)
+	}
+	code := "code-" + q.Get("state")
+	f.mu.Lock()
+	f.grants[code] = fakeOIDCGrant{challenge: q.Get("code_challenge"), nonce: q.Get("nonce")}
+	f.mu.Unlock()
+	return code
+}
+
+func (f *fakeOIDC) token(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		writeTestJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_request"})
+		return
+	}
+	f.mu.Lock()
+	grant, ok := f.grants[r.PostForm.Get("code")]
+	delete(f.grants, r.PostForm.Get("code"))
+	f.mu.Unlock()
+	if !ok || r.PostForm.Get("client_id") != testOIDCClientID {
+		writeTestJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant"})
+		return
+	}
+	if grant.challenge != "" {
+		sum := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+		if base64.RawURLEncoding.EncodeToString(sum[:]) != grant.challenge {
+			writeTestJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant", "error_description": "PKCE doğrulaması başarısız"})
+			return
+		}
+	}
+
+	now := time.Now()
+	claims := map[string]interface{}{
+		"iss":            f.issuerURL(),
+		"sub":            "subject-1",
+		"aud":            testOIDCClientID,
+		"iat":            now.Unix(),
+		"exp":            now.Add(time.Hour).Unix(),
+		"nonce":          grant.nonce,
+		"email":          "Ayse@Example.com",
+		"email_verified": true,
+		"name":           "Ayşe Yılmaz",
+	}
+	if f.claims != nil {
+		f.claims(claims)
+	}
+	sign := f.signRS256
+	if f.sign != nil {
+		sign = f.sign
+	}
+	writeTestJSON(w, http.StatusOK, map[string]interface{}{
+		"access_token": "access-token",
+		"token_type":   "Bearer",
+		"expires_in":   3600,
+		"id_token":     sign(claims),
+	})
+}
+
+func (f *fakeOIDC) signRS256(claims map[string]interface{}) string {
+	return signTestJWT(f.t, jose.SigningKey{Algorithm: jose.RS256, Key: f.key}, claims)
+}
+
+func signTestJWT(t *testing.T, key jose.SigningKey, claims map[string]interface{}) string {
+	t.Helper()
+	signer, err := jose.NewSigner(key, (&jose.SignerOptions{}).WithType("JWT").WithHeader("kid", testOIDCKeyID))
+	if err != nil {
+		t.Fatal(err)
+	}
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signed, err := signer.Sign(payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw, err := signed.CompactSerialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return raw
+}
+
+func (f *fakeOIDC) provider() *oidcProvider {
+	return &oidcProvider{
+		name:         "oidc",
+		displayName:  "Test",
+		icon:         "fas fa-id-badge",
+		issuer:       f.server.URL,
+		clientID:     testOIDCClientID,
+		clientSecret: "secret",
+		redirectURL:  "https://zatrano.test/auth/oidc/callback",
+		scopes:       []string{"openid", "email", "profile"},
+		pkce:         true,
+		client:       f.server.Client(),
+	}
+}
+
+// login, yetkilendirmeden token değişimine kadar tüm akışı çalıştırır; exchangeNonce boşsa istekteki nonce kullanılır.
+func (f *fakeOIDC) login(p *oidcProvider, exchangeNonce string) (*OAuthProfile, error) {
+	f.t.Helper()
+	ctx := context.Background()
+	verifier := "verifier-0123456789-0123456789-0123456789"
+	authURL, err := p.AuthCodeURL(ctx, "state-1", "nonce-1", verifier)
+	if err != nil {
+		f.t.Fatal(err)
+	}
+	code := f.authorize(authURL)
+	if exchangeNonce == "" {
+		exchangeNonce = "nonce-1"
+	}
+	return p.Exchange(ctx, code, exchangeNonce, verifier, nil)
+}
+
+func TestOIDCExchangeReturnsVerifiedProfile(t *testing.T) {
+	f := newFakeOIDC(t)
+	profile, err := f.login(f.provider(), "")
+	if err != nil {
+		t.Fatalf("Exchange: %v", err)
+	}
+	if profile.Subject != "subject-1" || profile.Email != "ayse@example.com" || !profile.EmailVerified || profile.Name != "Ayşe Yılmaz" {
+		t.Fatalf("beklenmeyen profil: %+v", profile)
+	}
+}
+
+func TestOIDCExchangeAcceptsStringEmailVerified(t *testing.T) {
+	f := newFakeOIDC(t)
+	f.claims = func(c map[string]interface{}) { c["email_verified"] = "true" }
+	profile, err := f.login(f.provider(), "")
+	if err != nil {
+		t.Fatalf("Exchange: %v", err)
+	}
+	if !profile.EmailVerified {
+		t.Fatal(`email_verified "true" dizesi doğrulanmış sayılmalı`)
+	}
+
+	f.claims = func(c map[string]interface{}) { c["email_verified"] = "false" }
+	profile, err = f.login(f.provider(), "")
+	if err != nil {
+		t.Fatalf("Exchange: %v", err)
+	}
+	if profile.EmailVerified {
+		t.Fatal(`email_verified "false" dizesi doğrulanmamış sayılmalı`)
+	}
+}
+
+func TestOIDCExchangeRejectsInvalidTokens(t *testing.T) {
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		claims func(c map[string]interface{})
+		sign   func(f *fakeOIDC, c map[string]interface{}) string
+		nonce  string
+		want   error
+	}{
+		{
+			name: "başka anahtarla imza",
+			sign: func(f *fakeOIDC, c map[string]interface{}) string {
+				return signTestJWT(t, jose.SigningKey{Algorithm: jose.RS256, Key: otherKey}, c)
+			},
+		},
+		{
+			name: "değiştirilmiş içerik",
+			sign: func(f *fakeOIDC, c map[string]interface{}) string {
+				parts := strings.Split(f.signRS256(c), ".")
+				c["sub"] = "attacker"
+				payload, _ := json.Marshal(c)
+				return parts[0] + "." + base64.RawURLEncoding.EncodeToString(payload) + "." + parts[2]
+			},
+		},
+		{
+			name:   "yanlış yayıncı",
+			claims: func(c map[string]interface{}) { c["iss"] = "https://evil.example.com" },
+		},
+		{
+			name:   "yanlış hedef kitle",
+			claims: func(c map[string]interface{}) { c["aud"] = "another-client" },
+		},
+		{
+			name: "başka istemciye yetkilendirilmiş (azp)",
+			claims: func(c map[string]interface{}) {
+				c["aud"] = []string{testOIDCClientID, "another-client"}
+				c["azp"] = "another-client"
+			},
+			want: errOIDCAudience,
+		},
+		{
+			name:  "nonce eşleşmiyor",
+			nonce: "another-nonce",
+			want:  errOIDCNonce,
+		},
+		{
+			name:   "nonce yok",
+			claims: func(c map[string]interface{}) { delete(c, "nonce") },
+			want:   errOIDCNonce,
+		},
+		{
+			name:   "süresi dolmuş",
+			claims: func(c map[string]interface{}) { c["exp"] = time.Now().Add(-time.Hour).Unix() },
+		},
+		{
+			name:   "kullanıcı kimliği yok",
+			claims: func(c map[string]interface{}) { delete(c, "sub") },
+			want:   errOIDCSubject,
+		},
+		{
+			// Açık RSA anahtarı HMAC sırrı gibi kullanılarak imzalanmış token kabul edilmemeli
+			name: "algoritma karışıklığı (HS256)",
+			sign: func(f *fakeOIDC, c map[string]interface{}) string {
+				der, err := x509.MarshalPKIXPublicKey(&f.key.PublicKey)
+				if err != nil {
+					t.Fatal(err)
+				}
+				return signTestJWT(t, jose.SigningKey{Algorithm: jose.HS256, Key: der}, c)
+			},
+		},
+		{
+			name: "imzasız token (none)",
+			sign: func(f *fakeOIDC, c map[string]interface{}) string {
+				header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none","typ":"JWT"}`))
+				payload, _ := json.Marshal(c)
+				return header + "." + base64.RawURLEncoding.EncodeToString(payload) + "."
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFakeOIDC(t)
+			f.claims = tt.claims
+			if tt.sign != nil {
+				f.sign = func(c map[string]interface{}) string { return tt.sign(f, c) }
+			}
+			profile, err := f.login(f.provider(), tt.nonce)
+			if err == nil {
+				t.Fatalf("token kabul edildi: %+v", profile)
+			}
+			if tt.want != nil && !errors.Is(err, tt.want) {
+				t.Fatalf("hata = %v, beklenen %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestOIDCExchangeReportsExpiredToken(t *testing.T) {
+	f := newFakeOIDC(t)
+	f.claims = func(c map[string]interface{}) { c["exp"] = time.Now().Add(-time.Minute).Unix() }
+	_, err := f.login(f.provider(), "")
+	var expired *oidc.TokenExpiredError
+	if !errors.As(err, &expired) {
+		t.Fatalf("hata = %v, TokenExpiredError bekleniyordu", err)
+	}
+}
+
+func TestOIDCExchangeRequiresPKCEVerifier(t *testing.T) {
+	f := newFakeOIDC(t)
+	p := f.provider()
+	ctx := context.Background()
+
+	authURL, err := p.AuthCodeURL(ctx, "state-1", "nonce-1", "verifier-0123456789-0123456789-0123456789")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u, _ := url.Parse(authURL); u.Query().Get("code_challenge") == "" {
+		t.Fatal("yetkilendirme adresinde code_challenge yok")
+	}
+	code := f.authorize(authURL)
+	if _, err := p.Exchange(ctx, code, "nonce-1", "another-verifier-0123456789-0123456789", nil); err == nil {
+		t.Fatal("yanlış PKCE doğrulayıcısıyla token alındı")
+	}
+}
+
+func TestOIDCRejectsDiscoveryIssuerMismatch(t *testing.T) {
+	f := newFakeOIDC(t)
+	f.issuer = "https://evil.example.com"
+	if _, err := f.provider().AuthCodeURL(context.Background(), "state-1", "nonce-1", "verifier"); err == nil {
+		t.Fatal("keşif belgesindeki farklı yayıncı kabul edildi")
+	}
+}
+
+func TestOIDCMultiTenantIssuer(t *testing.T) {
+	f := newFakeOIDC(t)
+	template := f.server.URL + "/" + oidcTenantPlaceholder + "/v2.0"
+	f.issuer = template
+
+	newProvider := func() *oidcProvider {
+		p := f.provider()
+		p.issuerTemplate = template
+		return p
+	}
+
+	f.claims = func(c map[string]interface{}) {
+		c["tid"] = "tenant-1"
+		c["iss"] = f.server.URL + "/tenant-1/v2.0"
+	}
+	if _, err := f.login(newProvider(), ""); err != nil {
+		t.Fatalf("kiracı yayıncısı kabul edilmedi: %v", err)
+	}
+
+	for name, claims := range map[string]func(c map[string]interface{}){
+		"tid ile eşleşmeyen yayıncı": func(c map[string]interface{}) {
+			c["tid"] = "tenant-1"
+			c["iss"] = f.server.URL + "/tenant-2/v2.0"
+		},
+		"tid yok": func(c map[string]interface{}) {
+			c["iss"] = f.server.URL + "/tenant-1/v2.0"
+		},
+		"başka sağlayıcının yayıncısı": func(c map[string]interface{}) {
+			c["tid"] = "tenant-1"
+			c["iss"] = "https://evil.example.com/tenant-1/v2.0"
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			f.claims = claims
+			if _, err := f.login(newProvider(), ""); !errors.Is(err, errOIDCIssuer) {
+				t.Fatalf("hata = %v, beklenen %v", err, errOIDCIssuer)
+			}
+		})
+	}
+}
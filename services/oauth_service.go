@@ -0,0 +1,450 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"zatrano/configs/logconfig"
+	"zatrano/configs/redisconfig"
+	"zatrano/models"
+	"zatrano/repositories"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+	"golang.org/x/oauth2"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrOAuthUnknownProvider   = errors.New("bilinmeyen ya da etkin olmayan giriş sağlayıcısı")
+	ErrOAuthInvalidState      = errors.New("giriş isteği geçersiz ya da süresi dolmuş")
+	ErrOAuthEmailMissing      = errors.New("sağlayıcı e-posta adresini paylaşmadı")
+	ErrOAuthEmailUnverified   = errors.New("bu e-posta adresiyle kayıtlı bir hesap var; sağlayıcı adresi doğrulamadığı için hesaplar otomatik bağlanamaz")
+	ErrOAuthAccountUnverified = errors.New("bu e-posta adresiyle doğrulanmamış bir hesap var; hesaplar otomatik bağlanamaz")
+	ErrOAuthIdentityTaken     = errors.New("bu sağlayıcı hesabı başka bir kullanıcıya bağlı")
+	ErrOAuthAlreadyLinked     = errors.New("bu sağlayıcıya zaten bir hesap bağlı")
+	ErrOAuthLastLoginMethod   = errors.New("şifre belirlemeden son giriş yöntemi kaldırılamaz")
+	ErrOAuthIdentityNotFound  = errors.New("bağlı hesap bulunamadı")
+)
+
+const (
+	OAuthModeLogin = "login"
+	OAuthModeLink  = "link"
+
+	oauthStatePrefix = "oauth_state"
+	oauthStateTTL    = 10 * time.Minute
+)
+
+// OAuthProviderInfo, giriş ve profil sayfalarında gösterilen sağlayıcı bilgisidir.
+type OAuthProviderInfo struct {
+	Name        string
+	DisplayName string
+	Icon        string
+}
+
+// LinkedAccount, profil sayfasında sağlayıcının bağlı olup olmadığını gösterir.
+type LinkedAccount struct {
+	OAuthProviderInfo
+	Identity *models.UserIdentity
+}
+
+// OAuthStart, yetkilendirme adresi ve tarayıcıya yazılacak bağlama değeridir.
+type OAuthStart struct {
+	URL     string
+	Binding string
+}
+
+// OAuthResult, geri dönüşte doğrulanan kullanıcı ve akışın türüdür.
+type OAuthResult struct {
+	User     *models.User
+	Mode     string
+	Provider OAuthProviderInfo
+}
+
+// oauthPending, yetkilendirme başlarken Redis'e yazılan ve geri dönüşte bir kez okunan bekleyen istektir.
+// Oturum çerezi prod'da SameSite=Strict olduğundan sağlayıcıdan dönen istek oturumu taşımayabilir;
+// bu yüzden bilgiler oturumda değil state anahtarı altında tutulur ve tarayıcıya bağlama çereziyle bağlanır.
+type oauthPending struct {
+	Provider    string `json:"provider"`
+	Mode        string `json:"mode"`
+	UserID      uint   `json:"user_id,omitempty"`
+	Nonce       string `json:"nonce"`
+	Verifier    string `json:"verifier"`
+	BindingHash string `json:"binding"`
+}
+
+// IOAuthService, dış kimlik sağlayıcılarıyla giriş, kayıt ve hesap bağlama akışını yönetir.
+type IOAuthService interface {
+	Providers() []OAuthProviderInfo
+	Provider(name string) (OAuthProviderInfo, bool)
+	// Begin, state, nonce ve PKCE doğrulayıcısını üretip saklar; mode link ise userID bağlanacak hesaptır.
+	Begin(ctx context.Context, provider, mode string, userID uint) (*OAuthStart, error)
+	// Complete, state'i tüketir, kodu token'la değiştirir ve kimliği kullanıcıya eşler.
+	// currentUserID, isteği yapan oturumun kullanıcısıdır (oturum görünmüyorsa sıfır).
+	Complete(ctx context.Context, provider, state, binding, code string, form func(key string) string, currentUserID uint) (*OAuthResult, error)
+	LinkedAccounts(ctx context.Context, userID uint) ([]LinkedAccount, error)
+	Unlink(ctx context.Context, userID uint, provider string) error
+}
+
+type OAuthService struct {
+	providers  []IOAuthProvider
+	redis      *redis.Client
+	identities repositories.IUserIdentityRepository
+	users      repositories.IAuthRepository
+	userTypes  IUserTypeService
+	now        func() time.Time
+}
+
+func NewOAuthService() IOAuthService {
+	return &OAuthService{
+		providers:  registeredOAuthProviders(),
+		redis:      redisconfig.GetClient(),
+		identities: repositories.NewUserIdentityRepository(),
+		users:      repositories.NewAuthRepository(),
+		userTypes:  NewUserTypeService(),
+		now:        time.Now,
+	}
+}
+
+func oauthStateKey(state string) string {
+	return redisconfig.GetPrefixedKey(oauthStatePrefix, state)
+}
+
+func randomOAuthValue() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+func providerInfo(p IOAuthProvider) OAuthProviderInfo {
+	return OAuthProviderInfo{Name: p.Name(), DisplayName: p.DisplayName(), Icon: p.Icon()}
+}
+
+func (s *OAuthService) find(name string) (IOAuthProvider, error) {
+	for _, p := range s.providers {
+		if p.Name() == name {
+			return p, nil
+		}
+	}
+	return nil, ErrOAuthUnknownProvider
+}
+
+func (s *OAuthService) Providers() []OAuthProviderInfo {
+	infos := make([]OAuthProviderInfo, 0, len(s.providers))
+	for _, p := range s.providers {
+		infos = append(infos, providerInfo(p))
+	}
+	return infos
+}
+
+func (s *OAuthService) Provider(name string) (OAuthProviderInfo, bool) {
+	p, err := s.find(name)
+	if err != nil {
+		return OAuthProviderInfo{}, false
+	}
+	return providerInfo(p), true
+}
+
+func (s *OAuthService) Begin(ctx context.Context, provider, mode string, userID uint) (*OAuthStart, error) {
+	p, err := s.find(provider)
+	if err != nil {
+		return nil, err
+	}
+	if mode != OAuthModeLink {
+		mode, userID = OAuthModeLogin, 0
+	}
+
+	state, err := randomOAuthValue()
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := randomOAuthValue()
+	if err != nil {
+		return nil, err
+	}
+	binding, err := randomOAuthValue()
+	if err != nil {
+		return nil, err
+	}
+	pending := oauthPending{
+		Provider:    provider,
+		Mode:        mode,
+		UserID:      userID,
+		Nonce:       nonce,
+		Verifier:    oauth2.GenerateVerifier(),
+		BindingHash: hashSecretToken(binding),
+	}
+
+	authURL, err := p.AuthCodeURL(ctx, state, pending.Nonce, pending.Verifier)
+	if err != nil {
+		logconfig.Log.Error("OAuth yetkilendirme adresi oluşturulamadı", zap.String("provider", provider), zap.Error(err))
+		return nil, err
+	}
+
+	raw, err := json.Marshal(pending)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.redis.Set(ctx, oauthStateKey(state), raw, oauthStateTTL).Err(); err != nil {
+		logconfig.Log.Error("OAuth state kaydedilemedi", zap.String("provider", provider), zap.Error(err))
+		return nil, err
+	}
+	return &OAuthStart{URL: authURL, Binding: binding}, nil
+}
+
+// takePending, state'i Redis'ten silerek okur; aynı geri dönüş adresi ikinci kez kullanılamaz.
+func (s *OAuthService) takePending(ctx context.Context, provider, state, binding string) (*oauthPending, error) {
+	if state == "" || binding == "" {
+		return nil, ErrOAuthInvalidState
+	}
+	raw, err := s.redis.GetDel(ctx, oauthStateKey(state)).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, ErrOAuthInvalidState
+	}
+	if err != nil {
+		logconfig.Log.Error("OAuth state okunamadı", zap.String("provider", provider), zap.Error(err))
+		return nil, err
+	}
+
+	var pending oauthPending
+	if err := json.Unmarshal(raw, &pending); err != nil {
+		return nil, ErrOAuthInvalidState
+	}
+	if pending.Provider != provider ||
+		subtle.ConstantTimeCompare([]byte(pending.BindingHash), []byte(hashSecretToken(binding))) != 1 {
+		logconfig.Log.Warn("OAuth state başka bir tarayıcıya ya da sağlayıcıya ait", zap.String("provider", provider))
+		return nil, ErrOAuthInvalidState
+	}
+	return &pending, nil
+}
+
+func (s *OAuthService) Complete(ctx context.Context, provider, state, binding, code string, form func(key string) string, currentUserID uint) (*OAuthResult, error) {
+	p, err := s.find(provider)
+	if err != nil {
+		return nil, err
+	}
+	pending, err := s.takePending(ctx, provider, state, binding)
+	if err != nil {
+		return nil, err
+	}
+	// Prod'da oturum çerezi Strict olduğundan sağlayıcıdan dönen istekte oturum görünmeyebilir; bu durumda
+	// bağlama çerezi isteğin bağlamayı başlatan tarayıcıdan geldiğini zaten kanıtlar.
+	if pending.Mode == OAuthModeLink && currentUserID != 0 && pending.UserID != currentUserID {
+		logconfig.Log.Warn("OAuth bağlama isteği farklı bir oturumdan tamamlanmaya çalışıldı",
+			zap.String("provider", provider), zap.Uint("user_id", pending.UserID), zap.Uint("current_user_id", currentUserID))
+		return nil, ErrOAuthInvalidState
+	}
+	if code == "" {
+		return nil, ErrOAuthInvalidState
+	}
+
+	profile, err := p.Exchange(ctx, code, pending.Nonce, pending.Verifier, form)
+	if err != nil {
+		logconfig.Log.Warn("OAuth kimliği doğrulanamadı", zap.String("provider", provider), zap.Error(err))
+		return nil, err
+	}
+
+	result := &OAuthResult{Mode: pending.Mode, Provider: providerInfo(p)}
+	if pending.Mode == OAuthModeLink {
+		result.User, err = s.link(ctx, provider, pending.UserID, profile)
+	} else {
+		result.User, err = s.login(ctx, provider, profile)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (s *OAuthService) link(ctx context.Context, provider string, userID uint, profile *OAuthProfile) (*models.User, error) {
+	existing, err := s.identities.FindBySubject(ctx, provider, profile.Subject)
+	if err == nil {
+		if existing.UserID != userID {
+			return nil, ErrOAuthIdentityTaken
+		}
+		return s.loadUser(existing.UserID)
+	}
+	if !errors.Is(err, repositories.ErrNotFound) {
+		logconfig.Log.Error("OAuth kimliği sorgulanamadı", zap.String("provider", provider), zap.Error(err))
+		return nil, err
+	}
+
+	user, err := s.loadUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.ensureNotLinked(ctx, userID, provider); err != nil {
+		return nil, err
+	}
+	now := s.now()
+	identity := &models.UserIdentity{UserID: userID, Provider: provider, Subject: profile.Subject, Email: profile.Email, LastLoginAt: &now}
+	if err := s.identities.Create(ctx, identity); err != nil {
+		logconfig.Log.Error("OAuth kimliği bağlanamadı", zap.Uint("user_id", userID), zap.String("provider", provider), zap.Error(err))
+		return nil, err
+	}
+	logconfig.Log.Info("Dış hesap bağlandı", zap.Uint("user_id", userID), zap.String("provider", provider))
+	return user, nil
+}
+
+func (s *OAuthService) login(ctx context.Context, provider string, profile *OAuthProfile) (*models.User, error) {
+	now := s.now()
+
+	identity, err := s.identities.FindBySubject(ctx, provider, profile.Subject)
+	if err == nil {
+		user, err := s.loadUser(identity.UserID)
+		if err != nil {
+			return nil, err
+		}
+		if !user.IsActive {
+			return nil, ErrUserInactive
+		}
+		if err := s.identities.TouchLogin(ctx, identity.ID, now); err != nil {
+			logconfig.Log.Warn("OAuth son giriş zamanı güncellenemedi", zap.Uint("identity_id", identity.ID), zap.Error(err))
+		}
+		return user, nil
+	}
+	if !errors.Is(err, repositories.ErrNotFound) {
+		logconfig.Log.Error("OAuth kimliği sorgulanamadı", zap.String("provider", provider), zap.Error(err))
+		return nil, err
+	}
+
+	if profile.Email == "" {
+		return nil, ErrOAuthEmailMissing
+	}
+	identity = &models.UserIdentity{Provider: provider, Subject: profile.Subject, Email: profile.Email, LastLoginAt: &now}
+
+	// Aynı e-postayla kayıtlı hesap yalnızca hem sağlayıcı hem uygulama adresi doğruladıysa bağlanır.
+	// Sağlayıcı doğrulamadıysa başkasının adresini kullanan biri o hesaba girebilirdi; hesap doğrulanmamışsa
+	// adresi sahibinden önce kaydedip parola belirleyen biri, bağlamadan sonra da o parolayla girebilirdi.
+	existing, err := s.users.FindUserByEmail(profile.Email)
+	if err == nil {
+		if !profile.EmailVerified {
+			return nil, ErrOAuthEmailUnverified
+		}
+		if !existing.EmailVerified {
+			logconfig.Log.Warn("Doğrulanmamış hesaba e-posta eşleşmesiyle bağlama reddedildi", zap.Uint("user_id", existing.ID), zap.String("provider", provider))
+			return nil, ErrOAuthAccountUnverified
+		}
+		if !existing.IsActive {
+			return nil, ErrUserInactive
+		}
+		if err := s.ensureNotLinked(ctx, existing.ID, provider); err != nil {
+			return nil, err
+		}
+		identity.UserID = existing.ID
+		if err := s.identities.Create(ctx, identity); err != nil {
+			logconfig.Log.Error("OAuth kimliği bağlanamadı", zap.Uint("user_id", existing.ID), zap.String("provider", provider), zap.Error(err))
+			return nil, err
+		}
+		logconfig.Log.Info("Dış hesap e-posta eşleşmesiyle bağlandı", zap.Uint("user_id", existing.ID), zap.String("provider", provider))
+		return existing, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrAuthGeneric
+	}
+
+	name := strings.TrimSpace(profile.Name)
+	if name == "" {
+		name = strings.SplitN(profile.Email, "@", 2)[0]
+	}
+	userTypeID, err := s.userTypes.DefaultUserTypeID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	// Yeni kullanıcı varsayılan rolle ve parolasız oluşturulur (sosyal giriş)
+	user := &models.User{
+		Name:          name,
+		Email:         profile.Email,
+		UserTypeID:    userTypeID,
+		EmailVerified: profile.EmailVerified,
+		BaseModel: models.BaseModel{
+			IsActive: true,
+		},
+	}
+	if err := s.identities.CreateWithUser(ctx, user, identity); err != nil {
+		logconfig.Log.Error("Sosyal giriş kullanıcısı oluşturulamadı", zap.String("provider", provider), zap.String("email", profile.Email), zap.Error(err))
+		return nil, err
+	}
+	logconfig.Log.Info("Sosyal girişle yeni kullanıcı oluşturuldu", zap.Uint("user_id", user.ID), zap.String("provider", provider))
+	return user, nil
+}
+
+// ensureNotLinked, kullanıcının aynı sağlayıcıya başka bir hesapla bağlı olmadığını denetler.
+func (s *OAuthService) ensureNotLinked(ctx context.Context, userID uint, provider string) error {
+	identities, err := s.identities.ListByUser(ctx, userID)
+	if err != nil {
+		logconfig.Log.Error("Bağlı hesaplar sorgulanamadı", zap.Uint("user_id", userID), zap.Error(err))
+		return err
+	}
+	for _, identity := range identities {
+		if identity.Provider == provider {
+			return ErrOAuthAlreadyLinked
+		}
+	}
+	return nil
+}
+
+func (s *OAuthService) loadUser(id uint) (*models.User, error) {
+	user, err := s.users.FindUserByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, ErrAuthGeneric
+	}
+	return user, nil
+}
+
+func (s *OAuthService) LinkedAccounts(ctx context.Context, userID uint) ([]LinkedAccount, error) {
+	identities, err := s.identities.ListByUser(ctx, userID)
+	if err != nil {
+		logconfig.Log.Error("Bağlı hesaplar listelenemedi", zap.Uint("user_id", userID), zap.Error(err))
+		return nil, err
+	}
+	byProvider := make(map[string]*models.UserIdentity, len(identities))
+	for i := range identities {
+		byProvider[identities[i].Provider] = &identities[i]
+	}
+
+	accounts := make([]LinkedAccount, 0, len(s.providers))
+	for _, p := range s.providers {
+		accounts = append(accounts, LinkedAccount{OAuthProviderInfo: providerInfo(p), Identity: byProvider[p.Name()]})
+	}
+	return accounts, nil
+}
+
+// Unlink; parolası olmayan kullanıcının son bağlı hesabı kaldırılamaz, aksi halde hesaba giriş yolu kalmazdı.
+func (s *OAuthService) Unlink(ctx context.Context, userID uint, provider string) error {
+	user, err := s.loadUser(userID)
+	if err != nil {
+		return err
+	}
+	if user.Password == "" {
+		count, err := s.identities.CountByUser(ctx, userID)
+		if err != nil {
+			return err
+		}
+		if count <= 1 {
+			return ErrOAuthLastLoginMethod
+		}
+	}
+	if err := s.identities.Delete(ctx, userID, provider); err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return ErrOAuthIdentityNotFound
+		}
+		logconfig.Log.Error("Bağlı hesap kaldırılamadı", zap.Uint("user_id", userID), zap.String("provider", provider), zap.Error(err))
+		return err
+	}
+	logconfig.Log.Info("Dış hesap bağlantısı kaldırıldı", zap.Uint("user_id", userID), zap.String("provider", provider))
+	return nil
+}
+
+var _ IOAuthService = (*OAuthService)(nil)
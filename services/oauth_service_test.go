@@ -0,0 +1,307 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"zatrano/configs/logconfig"
+	"zatrano/models"
+	"zatrano/repositories"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+func TestMain(m *testing.M) {
+	logconfig.Log = zap.NewNop()
+	os.Exit(m.Run())
+}
+
+// fakeIdentityRepo, kimlikleri bellekte tutan IUserIdentityRepository'dir.
+type fakeIdentityRepo struct {
+	users      *fakeAuthRepo
+	identities []models.UserIdentity
+}
+
+func (r *fakeIdentityRepo) FindBySubject(ctx context.Context, provider, subject string) (*models.UserIdentity, error) {
+	for i := range r.identities {
+		if r.identities[i].Provider == provider && r.identities[i].Subject == subject {
+			identity := r.identities[i]
+			return &identity, nil
+		}
+	}
+	return nil, repositories.ErrNotFound
+}
+
+func (r *fakeIdentityRepo) ListByUser(ctx context.Context, userID uint) ([]models.UserIdentity, error) {
+	var list []models.UserIdentity
+	for _, identity := range r.identities {
+		if identity.UserID == userID {
+			list = append(list, identity)
+		}
+	}
+	return list, nil
+}
+
+func (r *fakeIdentityRepo) CountByUser(ctx context.Context, userID uint) (int64, error) {
+	list, _ := r.ListByUser(ctx, userID)
+	return int64(len(list)), nil
+}
+
+func (r *fakeIdentityRepo) Create(ctx context.Context, identity *models.UserIdentity) error {
+	identity.ID = uint(len(r.identities) + 1)
+	r.identities = append(r.identities, *identity)
+	return nil
+}
+
+func (r *fakeIdentityRepo) CreateWithUser(ctx context.Context, user *models.User, identity *models.UserIdentity) error {
+	if err := r.users.CreateUser(ctx, user); err != nil {
+		return err
+	}
+	identity.UserID = user.ID
+	return r.Create(ctx, identity)
+}
+
+func (r *fakeIdentityRepo) Delete(ctx context.Context, userID uint, provider string) error {
+	for i, identity := range r.identities {
+		if identity.UserID == userID && identity.Provider == provider {
+			r.identities = append(r.identities[:i], r.identities[i+1:]...)
+			return nil
+		}
+	}
+	return repositories.ErrNotFound
+}
+
+func (r *fakeIdentityRepo) TouchLogin(ctx context.Context, id uint, at time.Time) error {
+	return nil
+}
+
+// fakeAuthRepo, kullanıcıları bellekte tutan IAuthRepository'dir.
+type fakeAuthRepo struct {
+	users map[uint]*models.User
+}
+
+func (r *fakeAuthRepo) FindUserByEmail(email string) (*models.User, error) {
+	for _, u := range r.users {
+		if u.Email == email {
+			user := *u
+			return &user, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *fakeAuthRepo) FindUserByID(id uint) (*models.User, error) {
+	u, ok := r.users[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	user := *u
+	return &user, nil
+}
+
+func (r *fakeAuthRepo) UpdateUser(ctx context.Context, user *models.User) error {
+	u := *user
+	r.users[user.ID] = &u
+	return nil
+}
+
+func (r *fakeAuthRepo) CreateUser(ctx context.Context, user *models.User) error {
+	user.ID = uint(len(r.users) + 1)
+	u := *user
+	r.users[user.ID] = &u
+	return nil
+}
+
+func (r *fakeAuthRepo) ClaimUnverifiedEmail(ctx context.Context, userID uint) (bool, error) {
+	u, ok := r.users[userID]
+	if !ok || u.EmailVerified {
+		return false, nil
+	}
+	u.EmailVerified, u.Password = true, ""
+	return true, nil
+}
+
+// fakeUserTypes, yalnızca varsayılan rolü bilen IUserTypeService'tir.
+type fakeUserTypes struct {
+	IUserTypeService
+}
+
+func (fakeUserTypes) DefaultUserTypeID(ctx context.Context) (uint, error) { return 7, nil }
+
+type oauthTestEnv struct {
+	oidc    *fakeOIDC
+	service *OAuthService
+	users   *fakeAuthRepo
+	idents  *fakeIdentityRepo
+	redis   *miniredis.Miniredis
+}
+
+func newOAuthTestEnv(t *testing.T) *oauthTestEnv {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+
+	f := newFakeOIDC(t)
+	users := &fakeAuthRepo{users: make(map[uint]*models.User)}
+	idents := &fakeIdentityRepo{users: users}
+	return &oauthTestEnv{
+		oidc:   f,
+		users:  users,
+		idents: idents,
+		redis:  mr,
+		service: &OAuthService{
+			providers:  []IOAuthProvider{f.provider()},
+			redis:      client,
+			identities: idents,
+			users:      users,
+			userTypes:  fakeUserTypes{},
+			now:        time.Now,
+		},
+	}
+}
+
+// begin, giriş akışını başlatır ve sağlayıcının verdiği kodu, state'i ve bağlama değerini döner.
+func (e *oauthTestEnv) begin(t *testing.T, mode string, userID uint) (code, state, binding string) {
+	t.Helper()
+	start, err := e.service.Begin(context.Background(), "oidc", mode, userID)
+	if err != nil {
+		t.Fatalf("Begin: %v", err)
+	}
+	code = e.oidc.authorize(start.URL)
+	return code, code[len("code-"):], start.Binding
+}
+
+func (e *oauthTestEnv) complete(code, state, binding string, currentUserID uint) (*OAuthResult, error) {
+	return e.service.Complete(context.Background(), "oidc", state, binding, code, nil, currentUserID)
+}
+
+func TestOAuthCompleteCreatesUser(t *testing.T) {
+	e := newOAuthTestEnv(t)
+	code, state, binding := e.begin(t, OAuthModeLogin, 0)
+
+	result, err := e.complete(code, state, binding, 0)
+	if err != nil {
+		t.Fatalf("Complete: %v", err)
+	}
+	if result.Mode != OAuthModeLogin || result.User.Email != "ayse@example.com" || result.User.UserTypeID != 7 || result.User.Password != "" {
+		t.Fatalf("beklenmeyen sonuç: %+v", result.User)
+	}
+	if len(e.idents.identities) != 1 || e.idents.identities[0].Subject != "subject-1" {
+		t.Fatalf("kimlik kaydedilmedi: %+v", e.idents.identities)
+	}
+}
+
+func TestOAuthCompleteRejectsReplayedState(t *testing.T) {
+	e := newOAuthTestEnv(t)
+	code, state, binding := e.begin(t, OAuthModeLogin, 0)
+	if _, err := e.complete(code, state, binding, 0); err != nil {
+		t.Fatalf("Complete: %v", err)
+	}
+	if _, err := e.complete(code, state, binding, 0); !errors.Is(err, ErrOAuthInvalidState) {
+		t.Fatalf("hata = %v, beklenen %v", err, ErrOAuthInvalidState)
+	}
+}
+
+func TestOAuthCompleteRejectsForeignBrowser(t *testing.T) {
+	e := newOAuthTestEnv(t)
+	code, state, _ := e.begin(t, OAuthModeLogin, 0)
+	if _, err := e.complete(code, state, "another-browser", 0); !errors.Is(err, ErrOAuthInvalidState) {
+		t.Fatalf("hata = %v, beklenen %v", err, ErrOAuthInvalidState)
+	}
+	if len(e.redis.Keys()) != 0 {
+		t.Fatalf("başarısız denemeden sonra state silinmedi: %v", e.redis.Keys())
+	}
+}
+
+func TestOAuthCompleteRejectsUnknownOrExpiredState(t *testing.T) {
+	e := newOAuthTestEnv(t)
+	code, state, binding := e.begin(t, OAuthModeLogin, 0)
+
+	if _, err := e.complete(code, "unknown-state", binding, 0); !errors.Is(err, ErrOAuthInvalidState) {
+		t.Fatalf("bilinmeyen state: hata = %v", err)
+	}
+	if _, err := e.complete(code, "", binding, 0); !errors.Is(err, ErrOAuthInvalidState) {
+		t.Fatalf("boş state: hata = %v", err)
+	}
+
+	e.redis.FastForward(oauthStateTTL + time.Second)
+	if _, err := e.complete(code, state, binding, 0); !errors.Is(err, ErrOAuthInvalidState) {
+		t.Fatalf("süresi dolmuş state: hata = %v", err)
+	}
+}
+
+func TestOAuthCompleteRejectsInjectedCode(t *testing.T) {
+	e := newOAuthTestEnv(t)
+	// Saldırganın kendi akışında aldığı kod, kurbanın state'i ve PKCE doğrulayıcısıyla kullanılamamalı
+	attackerCode, _, _ := e.begin(t, OAuthModeLogin, 0)
+	_, state, binding := e.begin(t, OAuthModeLogin, 0)
+
+	if _, err := e.complete(attackerCode, state, binding, 0); err == nil {
+		t.Fatal("başka bir PKCE doğrulayıcısına bağlı kod kabul edildi")
+	}
+	if len(e.users.users) != 0 {
+		t.Fatal("başarısız girişte kullanıcı oluşturuldu")
+	}
+}
+
+func TestOAuthLinkRejectsOtherSession(t *testing.T) {
+	e := newOAuthTestEnv(t)
+	e.users.users[1] = &models.User{BaseModel: models.BaseModel{ID: 1, IsActive: true}, Email: "owner@example.com", EmailVerified: true}
+	code, state, binding := e.begin(t, OAuthModeLink, 1)
+
+	if _, err := e.complete(code, state, binding, 2); !errors.Is(err, ErrOAuthInvalidState) {
+		t.Fatalf("hata = %v, beklenen %v", err, ErrOAuthInvalidState)
+	}
+	if len(e.idents.identities) != 0 {
+		t.Fatal("başka oturumdan gelen bağlama isteği kimlik ekledi")
+	}
+}
+
+func TestOAuthLoginLinksVerifiedAccountByEmail(t *testing.T) {
+	e := newOAuthTestEnv(t)
+	e.users.users[1] = &models.User{BaseModel: models.BaseModel{ID: 1, IsActive: true}, Email: "ayse@example.com", Password: "hash", EmailVerified: true}
+	code, state, binding := e.begin(t, OAuthModeLogin, 0)
+
+	result, err := e.complete(code, state, binding, 0)
+	if err != nil {
+		t.Fatalf("Complete: %v", err)
+	}
+	if result.User.ID != 1 || len(e.idents.identities) != 1 || e.idents.identities[0].UserID != 1 {
+		t.Fatalf("kimlik mevcut hesaba bağlanmadı: %+v", e.idents.identities)
+	}
+}
+
+func TestOAuthLoginRefusesUnverifiedAccount(t *testing.T) {
+	e := newOAuthTestEnv(t)
+	// Adresi sahibinden önce kaydedip parola belirleyen biri, bağlamadan sonra da o parolayla girebilirdi
+	e.users.users[1] = &models.User{BaseModel: models.BaseModel{ID: 1, IsActive: true}, Email: "ayse@example.com", Password: "attacker-hash"}
+	code, state, binding := e.begin(t, OAuthModeLogin, 0)
+
+	if _, err := e.complete(code, state, binding, 0); !errors.Is(err, ErrOAuthAccountUnverified) {
+		t.Fatalf("hata = %v, beklenen %v", err, ErrOAuthAccountUnverified)
+	}
+	if len(e.idents.identities) != 0 {
+		t.Fatal("doğrulanmamış hesaba kimlik bağlandı")
+	}
+	if u := e.users.users[1]; u.EmailVerified || u.Password != "attacker-hash" {
+		t.Fatalf("hesap değiştirildi: %+v", u)
+	}
+}
+
+func TestOAuthLoginRefusesUnverifiedProviderEmail(t *testing.T) {
+	e := newOAuthTestEnv(t)
+	e.users.users[1] = &models.User{BaseModel: models.BaseModel{ID: 1, IsActive: true}, Email: "ayse@example.com", EmailVerified: true}
+	e.oidc.claims = func(c map[string]interface{}) { c["email_verified"] = false }
+	code, state, binding := e.begin(t, OAuthModeLogin, 0)
+
+	if _, err := e.complete(code, state, binding, 0); !errors.Is(err, ErrOAuthEmailUnverified) {
+		t.Fatalf("hata = %v, beklenen %v", err, ErrOAuthEmailUnverified)
+	}
+}
@@ -0,0 +1,380 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"zatrano/configs/envconfig"
+	"zatrano/configs/logconfig"
+	"zatrano/models"
+	"zatrano/repositories"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/webauthn"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrPasskeyUnavailable = errors.New("geçiş anahtarı desteği yapılandırılmamış")
+	ErrPasskeyInvalid     = errors.New("geçiş anahtarı doğrulanamadı")
+	ErrPasskeyCloned      = errors.New("geçiş anahtarının imza sayacı geriye gitti; anahtar kopyalanmış olabilir")
+	ErrPasskeyNotFound    = errors.New("geçiş anahtarı bulunamadı")
+)
+
+const passkeyNameMaxLength = 100
+
+// PasskeyLogin, doğrulanan geçiş anahtarının sahibidir. UserVerified, cihazın kullanıcıyı PIN ya da
+// biyometriyle doğruladığını gösterir; bu durumda giriş tek başına iki aşamalı sayılır.
+type PasskeyLogin struct {
+	User         *models.User
+	UserVerified bool
+}
+
+// IPasskeyService, WebAuthn kayıt ve doğrulama törenlerini yürütür. Begin metodlarının döndüğü durum
+// (state) istemciye gönderilmez; oturumda saklanıp Finish metodlarına aynen geri verilir.
+type IPasskeyService interface {
+	BeginRegistration(ctx context.Context, user *models.User) (*protocol.CredentialCreation, string, error)
+	// FinishRegistration, tarayıcının navigator.credentials.create yanıtını doğrular ve anahtarı kaydeder.
+	FinishRegistration(ctx context.Context, user *models.User, state, name string, response io.Reader) (*models.WebAuthnCredential, error)
+	// BeginLogin, kullanıcı adı sorulmadan (cihazda bulunan anahtarla) giriş için seçenekleri üretir.
+	BeginLogin(ctx context.Context) (*protocol.CredentialAssertion, string, error)
+	FinishLogin(ctx context.Context, state string, response io.Reader) (*PasskeyLogin, error)
+	List(ctx context.Context, userID uint) ([]models.WebAuthnCredential, error)
+	Delete(ctx context.Context, userID, id uint) error
+}
+
+// PasskeyService; rp boşsa bağlı taraf ayarları ilk kullanımda ortamdan okunur.
+type PasskeyService struct {
+	repo  repositories.IWebAuthnCredentialRepository
+	users repositories.IAuthRepository
+	rp    *webauthn.WebAuthn
+	now   func() time.Time
+}
+
+func NewPasskeyService() IPasskeyService {
+	return &PasskeyService{
+		repo:  repositories.NewWebAuthnCredentialRepository(),
+		users: repositories.NewAuthRepository(),
+		now:   time.Now,
+	}
+}
+
+var (
+	relyingPartyOnce sync.Once
+	relyingParty     *webauthn.WebAuthn
+	relyingPartyErr  error
+)
+
+// newRelyingParty, bağlı taraf (RP) ayarlarını ortamdan okur. WEBAUTHN_RP_ID verilmezse APP_BASE_URL'in
+// alan adı, WEBAUTHN_ORIGINS verilmezse APP_BASE_URL kullanılır; tarayıcı yalnızca bu kökenlerden
+// gelen törenleri kabul eder.
+func newRelyingParty() (*webauthn.WebAuthn, error) {
+	base, err := url.Parse(envconfig.String("APP_BASE_URL", ""))
+	if err != nil {
+		return nil, err
+	}
+	rpID := envconfig.String("WEBAUTHN_RP_ID", base.Hostname())
+	origins := strings.Split(envconfig.String("WEBAUTHN_ORIGINS", base.Scheme+"://"+base.Host), ",")
+	for i := range origins {
+		origins[i] = strings.TrimSuffix(strings.TrimSpace(origins[i]), "/")
+	}
+
+	return webauthn.New(&webauthn.Config{
+		RPID:          rpID,
+		RPDisplayName: envconfig.String("WEBAUTHN_RP_NAME", "ZATRANO"),
+		RPOrigins:     origins,
+		AuthenticatorSelection: protocol.AuthenticatorSelection{
+			ResidentKey:      protocol.ResidentKeyRequirementRequired,
+			UserVerification: protocol.VerificationPreferred,
+		},
+		AttestationPreference: protocol.PreferNoAttestation,
+		Timeouts: webauthn.TimeoutsConfig{
+			Login:        webauthn.TimeoutConfig{Enforce: true, Timeout: 5 * time.Minute, TimeoutUVD: 5 * time.Minute},
+			Registration: webauthn.TimeoutConfig{Enforce: true, Timeout: 5 * time.Minute, TimeoutUVD: 5 * time.Minute},
+		},
+	})
+}
+
+func (s *PasskeyService) relyingParty() (*webauthn.WebAuthn, error) {
+	if s.rp != nil {
+		return s.rp, nil
+	}
+	relyingPartyOnce.Do(func() {
+		relyingParty, relyingPartyErr = newRelyingParty()
+		if relyingPartyErr != nil {
+			logconfig.Log.Error("WebAuthn yapılandırması geçersiz", zap.Error(relyingPartyErr))
+		}
+	})
+	if relyingPartyErr != nil {
+		return nil, ErrPasskeyUnavailable
+	}
+	return relyingParty, nil
+}
+
+// passkeyUserHandle, WebAuthn kullanıcı tanıtıcısıdır. Cihaza e-posta gibi kişisel bir bilgi yazılmaz;
+// kullanıcı ID'si 8 baytlık sayı olarak saklanır.
+func passkeyUserHandle(userID uint) []byte {
+	handle := make([]byte, 8)
+	binary.BigEndian.PutUint64(handle, uint64(userID))
+	return handle
+}
+
+// webauthnUser, kullanıcıyı ve kayıtlı anahtarlarını kütüphanenin beklediği arayüze uyarlar.
+type webauthnUser struct {
+	user        *models.User
+	credentials []models.WebAuthnCredential
+}
+
+func (u *webauthnUser) WebAuthnID() []byte          { return passkeyUserHandle(u.user.ID) }
+func (u *webauthnUser) WebAuthnName() string        { return u.user.Email }
+func (u *webauthnUser) WebAuthnDisplayName() string { return u.user.Name }
+
+func (u *webauthnUser) WebAuthnCredentials() []webauthn.Credential {
+	list := make([]webauthn.Credential, 0, len(u.credentials))
+	for _, c := range u.credentials {
+		transports := make([]protocol.AuthenticatorTransport, 0)
+		for _, t := range c.TransportList() {
+			transports = append(transports, protocol.AuthenticatorTransport(t))
+		}
+		list = append(list, webauthn.Credential{
+			ID:              c.CredentialID,
+			PublicKey:       c.PublicKey,
+			AttestationType: c.AttestationType,
+			Transport:       transports,
+			Flags: webauthn.CredentialFlags{
+				BackupEligible: c.BackupEligible,
+				BackupState:    c.BackupState,
+			},
+			Authenticator: webauthn.Authenticator{
+				AAGUID:       c.AAGUID,
+				SignCount:    c.SignCount,
+				CloneWarning: c.CloneWarning,
+			},
+		})
+	}
+	return list
+}
+
+func (s *PasskeyService) loadUser(ctx context.Context, user *models.User) (*webauthnUser, error) {
+	credentials, err := s.repo.ListByUser(ctx, user.ID)
+	if err != nil {
+		logconfig.Log.Error("Geçiş anahtarları listelenemedi", zap.Uint("user_id", user.ID), zap.Error(err))
+		return nil, err
+	}
+	return &webauthnUser{user: user, credentials: credentials}, nil
+}
+
+func encodePasskeyState(session *webauthn.SessionData) (string, error) {
+	raw, err := json.Marshal(session)
+	if err != nil {
+		return "", err
+	}
+	return string(raw), nil
+}
+
+func decodePasskeyState(state string) (*webauthn.SessionData, error) {
+	if state == "" {
+		return nil, ErrPasskeyInvalid
+	}
+	var session webauthn.SessionData
+	if err := json.Unmarshal([]byte(state), &session); err != nil {
+		return nil, ErrPasskeyInvalid
+	}
+	return &session, nil
+}
+
+func (s *PasskeyService) BeginRegistration(ctx context.Context, user *models.User) (*protocol.CredentialCreation, string, error) {
+	rp, err := s.relyingParty()
+	if err != nil {
+		return nil, "", err
+	}
+	wu, err := s.loadUser(ctx, user)
+	if err != nil {
+		return nil, "", err
+	}
+
+	// Aynı cihazın ikinci kez kaydedilmesini engellemek için mevcut anahtarlar dışarıda bırakılır
+	exclusions := make([]protocol.CredentialDescriptor, 0, len(wu.credentials))
+	for _, c := range wu.WebAuthnCredentials() {
+		exclusions = append(exclusions, c.Descriptor())
+	}
+	creation, session, err := rp.BeginRegistration(wu, webauthn.WithExclusions(exclusions))
+	if err != nil {
+		logconfig.Log.Error("Geçiş anahtarı kaydı başlatılamadı", zap.Uint("user_id", user.ID), zap.Error(err))
+		return nil, "", err
+	}
+	state, err := encodePasskeyState(session)
+	if err != nil {
+		return nil, "", err
+	}
+	return creation, state, nil
+}
+
+func (s *PasskeyService) FinishRegistration(ctx context.Context, user *models.User, state, name string, response io.Reader) (*models.WebAuthnCredential, error) {
+	rp, err := s.relyingParty()
+	if err != nil {
+		return nil, err
+	}
+	session, err := decodePasskeyState(state)
+	if err != nil {
+		return nil, err
+	}
+	wu, err := s.loadUser(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+
+	parsed, err := protocol.ParseCredentialCreationResponseBody(response)
+	if err != nil {
+		logconfig.Log.Warn("Geçiş anahtarı kayıt yanıtı çözümlenemedi", zap.Uint("user_id", user.ID), zap.Error(err))
+		return nil, ErrPasskeyInvalid
+	}
+	credential, err := rp.CreateCredential(wu, *session, parsed)
+	if err != nil {
+		logconfig.Log.Warn("Geçiş anahtarı kaydı doğrulanamadı", zap.Uint("user_id", user.ID), zap.Error(err))
+		return nil, ErrPasskeyInvalid
+	}
+
+	transports := make([]string, 0, len(credential.Transport))
+	for _, t := range credential.Transport {
+		transports = append(transports, string(t))
+	}
+	name = strings.TrimSpace(name)
+	if name == "" {
+		name = "Geçiş anahtarı " + s.now().Format("02.01.2006")
+	}
+	if r := []rune(name); len(r) > passkeyNameMaxLength {
+		name = string(r[:passkeyNameMaxLength])
+	}
+
+	record := &models.WebAuthnCredential{
+		UserID:          user.ID,
+		Name:            name,
+		CredentialID:    credential.ID,
+		PublicKey:       credential.PublicKey,
+		AttestationType: credential.AttestationType,
+		Transports:      strings.Join(transports, ","),
+		AAGUID:          credential.Authenticator.AAGUID,
+		SignCount:       credential.Authenticator.SignCount,
+		BackupEligible:  credential.Flags.BackupEligible,
+		BackupState:     credential.Flags.BackupState,
+	}
+	if err := s.repo.Create(ctx, record); err != nil {
+		logconfig.Log.Error("Geçiş anahtarı kaydedilemedi", zap.Uint("user_id", user.ID), zap.Error(err))
+		return nil, err
+	}
+	logconfig.Log.Info("Geçiş anahtarı eklendi", zap.Uint("user_id", user.ID), zap.Uint("credential_id", record.ID))
+	return record, nil
+}
+
+func (s *PasskeyService) BeginLogin(ctx context.Context) (*protocol.CredentialAssertion, string, error) {
+	rp, err := s.relyingParty()
+	if err != nil {
+		return nil, "", err
+	}
+	assertion, session, err := rp.BeginDiscoverableLogin()
+	if err != nil {
+		logconfig.Log.Error("Geçiş anahtarıyla giriş başlatılamadı", zap.Error(err))
+		return nil, "", err
+	}
+	state, err := encodePasskeyState(session)
+	if err != nil {
+		return nil, "", err
+	}
+	return assertion, state, nil
+}
+
+func (s *PasskeyService) FinishLogin(ctx context.Context, state string, response io.Reader) (*PasskeyLogin, error) {
+	rp, err := s.relyingParty()
+	if err != nil {
+		return nil, err
+	}
+	session, err := decodePasskeyState(state)
+	if err != nil {
+		return nil, err
+	}
+	parsed, err := protocol.ParseCredentialRequestResponseBody(response)
+	if err != nil {
+		logconfig.Log.Warn("Geçiş anahtarı giriş yanıtı çözümlenemedi", zap.Error(err))
+		return nil, ErrPasskeyInvalid
+	}
+
+	// Anahtar, tarayıcının döndürdüğü kimlikle bulunur; kullanıcı tanıtıcısının bu anahtarın sahibini
+	// göstermesi zorunludur, aksi halde başka bir kullanıcının anahtarıyla giriş denenebilirdi.
+	var stored *models.WebAuthnCredential
+	var owner *webauthnUser
+	handler := func(rawID, userHandle []byte) (webauthn.User, error) {
+		record, err := s.repo.FindByCredentialID(ctx, rawID)
+		if err != nil {
+			return nil, err
+		}
+		if !bytes.Equal(userHandle, passkeyUserHandle(record.UserID)) {
+			return nil, ErrPasskeyInvalid
+		}
+		user, err := s.users.FindUserByID(record.UserID)
+		if err != nil {
+			return nil, err
+		}
+		wu, err := s.loadUser(ctx, user)
+		if err != nil {
+			return nil, err
+		}
+		stored, owner = record, wu
+		return wu, nil
+	}
+
+	credential, err := rp.ValidateDiscoverableLogin(handler, *session, parsed)
+	if err != nil {
+		logconfig.Log.Warn("Geçiş anahtarıyla giriş doğrulanamadı", zap.Error(err))
+		return nil, ErrPasskeyInvalid
+	}
+	if !owner.user.IsActive {
+		return nil, ErrUserInactive
+	}
+
+	// Sayaç geriye gittiyse kopya uyarısı kaydedilir ve giriş reddedilir; sayaç tutmayan (hep sıfır
+	// gönderen) eşitlenen anahtarlar kütüphane tarafından uyarı almaz.
+	if err := s.repo.RecordUse(ctx, stored.ID, credential.Authenticator.SignCount, credential.Flags.BackupState,
+		credential.Authenticator.CloneWarning || stored.CloneWarning, s.now()); err != nil {
+		logconfig.Log.Error("Geçiş anahtarı kullanımı kaydedilemedi", zap.Uint("credential_id", stored.ID), zap.Error(err))
+		return nil, err
+	}
+	if credential.Authenticator.CloneWarning {
+		logconfig.Log.Warn("Geçiş anahtarının imza sayacı geriye gitti", zap.Uint("user_id", owner.user.ID), zap.Uint("credential_id", stored.ID))
+		return nil, ErrPasskeyCloned
+	}
+
+	logconfig.Log.Info("Geçiş anahtarıyla kimlik doğrulandı", zap.Uint("user_id", owner.user.ID), zap.Uint("credential_id", stored.ID))
+	return &PasskeyLogin{User: owner.user, UserVerified: credential.Flags.UserVerified}, nil
+}
+
+func (s *PasskeyService) List(ctx context.Context, userID uint) ([]models.WebAuthnCredential, error) {
+	credentials, err := s.repo.ListByUser(ctx, userID)
+	if err != nil {
+		logconfig.Log.Error("Geçiş anahtarları listelenemedi", zap.Uint("user_id", userID), zap.Error(err))
+		return nil, err
+	}
+	return credentials, nil
+}
+
+func (s *PasskeyService) Delete(ctx context.Context, userID, id uint) error {
+	if err := s.repo.Delete(ctx, userID, id); err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return ErrPasskeyNotFound
+		}
+		logconfig.Log.Error("Geçiş anahtarı silinemedi", zap.Uint("user_id", userID), zap.Uint("credential_id", id), zap.Error(err))
+		return err
+	}
+	logconfig.Log.Info("Geçiş anahtarı silindi", zap.Uint("user_id", userID), zap.Uint("credential_id", id))
+	return nil
+}
+
+var _ IPasskeyService = (*PasskeyService)(nil)
@@ -0,0 +1,419 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"zatrano/models"
+	"zatrano/repositories"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/protocol/webauthncbor"
+	"github.com/go-webauthn/webauthn/protocol/webauthncose"
+	"github.com/go-webauthn/webauthn/webauthn"
+)
+
+const (
+	testPasskeyRPID   = "zatrano.test"
+	testPasskeyOrigin = "https://zatrano.test"
+)
+
+// fakeCredentialRepo, geçiş anahtarlarını bellekte tutan IWebAuthnCredentialRepository'dir.
+type fakeCredentialRepo struct {
+	credentials []models.WebAuthnCredential
+}
+
+func (r *fakeCredentialRepo) ListByUser(ctx context.Context, userID uint) ([]models.WebAuthnCredential, error) {
+	var list []models.WebAuthnCredential
+	for _, c := range r.credentials {
+		if c.UserID == userID {
+			list = append(list, c)
+		}
+	}
+	return list, nil
+}
+
+func (r *fakeCredentialRepo) FindByCredentialID(ctx context.Context, credentialID []byte) (*models.WebAuthnCredential, error) {
+	for _, c := range r.credentials {
+		if bytes.Equal(c.CredentialID, credentialID) {
+			credential := c
+			return &credential, nil
+		}
+	}
+	return nil, repositories.ErrNotFound
+}
+
+func (r *fakeCredentialRepo) Create(ctx context.Context, credential *models.WebAuthnCredential) error {
+	credential.ID = uint(len(r.credentials) + 1)
+	r.credentials = append(r.credentials, *credential)
+	return nil
+}
+
+func (r *fakeCredentialRepo) RecordUse(ctx context.Context, id uint, signCount uint32, backupState, cloneWarning bool, at time.Time) error {
+	for i := range r.credentials {
+		if r.credentials[i].ID == id {
+			r.credentials[i].SignCount = signCount
+			r.credentials[i].BackupState = backupState
+			r.credentials[i].CloneWarning = cloneWarning
+			r.credentials[i].LastUsedAt = &at
+			return nil
+		}
+	}
+	return repositories.ErrNotFound
+}
+
+func (r *fakeCredentialRepo) Delete(ctx context.Context, userID, id uint) error {
+	for i, c := range r.credentials {
+		if c.UserID == userID && c.ID == id {
+			r.credentials = append(r.credentials[:i], r.credentials[i+1:]...)
+			return nil
+		}
+	}
+	return repositories.ErrNotFound
+}
+
+// softAuthenticator, ES256 anahtarıyla kayıt ve imza üreten yazılım doğrulayıcısıdır (authenticator).
+type softAuthenticator struct {
+	t            *testing.T
+	key          *ecdsa.PrivateKey
+	credentialID []byte
+	userHandle   []byte
+	// rpID ve origin, doğrulayıcının ve tarayıcının bildirdiği bağlı taraf ile kökendir.
+	rpID   string
+	origin string
+	// counter, bir sonraki imzada gönderilecek sayaçtır.
+	counter uint32
+}
+
+func newSoftAuthenticator(t *testing.T) *softAuthenticator {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		t.Fatal(err)
+	}
+	return &softAuthenticator{t: t, key: key, credentialID: id, rpID: testPasskeyRPID, origin: testPasskeyOrigin}
+}
+
+func b64url(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func (a *softAuthenticator) clientData(ceremony protocol.CeremonyType, challenge string) []byte {
+	raw, err := json.Marshal(map[string]interface{}{
+		"type":        ceremony,
+		"challenge":   challenge,
+		"origin":      a.origin,
+		"crossOrigin": false,
+	})
+	if err != nil {
+		a.t.Fatal(err)
+	}
+	return raw
+}
+
+// authData, doğrulayıcı verisini üretir; attested true ise kayıt için anahtar bilgisini de ekler.
+func (a *softAuthenticator) authData(attested bool) []byte {
+	rpIDHash := sha256.Sum256([]byte(a.rpID))
+	flags := protocol.FlagUserPresent | protocol.FlagUserVerified
+	if attested {
+		flags |= protocol.FlagAttestedCredentialData
+	}
+	data := append([]byte{}, rpIDHash[:]...)
+	data = append(data, byte(flags))
+	data = binary.BigEndian.AppendUint32(data, a.counter)
+	if !attested {
+		return data
+	}
+
+	publicKey, err := webauthncbor.Marshal(webauthncose.EC2PublicKeyData{
+		PublicKeyData: webauthncose.PublicKeyData{
+			KeyType:   int64(webauthncose.EllipticKey),
+			Algorithm: int64(webauthncose.AlgES256),
+		},
+		Curve:  1, // P-256
+		XCoord: a.key.PublicKey.X.FillBytes(make([]byte, 32)),
+		YCoord: a.key.PublicKey.Y.FillBytes(make([]byte, 32)),
+	})
+	if err != nil {
+		a.t.Fatal(err)
+	}
+	data = append(data, make([]byte, 16)...) // AAGUID
+	data = binary.BigEndian.AppendUint16(data, uint16(len(a.credentialID)))
+	data = append(data, a.credentialID...)
+	return append(data, publicKey...)
+}
+
+// register, navigator.credentials.create yanıtını üretir.
+func (a *softAuthenticator) register(creation *protocol.CredentialCreation) []byte {
+	a.userHandle = creation.Response.User.ID.(protocol.URLEncodedBase64)
+	attestation, err := webauthncbor.Marshal(map[string]interface{}{
+		"fmt":      "none",
+		"attStmt":  map[string]interface{}{},
+		"authData": a.authData(true),
+	})
+	if err != nil {
+		a.t.Fatal(err)
+	}
+	return a.marshalResponse(map[string]interface{}{
+		"clientDataJSON":    b64url(a.clientData(protocol.CreateCeremony, creation.Response.Challenge.String())),
+		"attestationObject": b64url(attestation),
+		"transports":        []string{"internal"},
+	})
+}
+
+// assert, navigator.credentials.get yanıtını üretir ve sayacı ilerletir.
+func (a *softAuthenticator) assert(assertion *protocol.CredentialAssertion) []byte {
+	clientData := a.clientData(protocol.AssertCeremony, assertion.Response.Challenge.String())
+	authData := a.authData(false)
+	clientDataHash := sha256.Sum256(clientData)
+	digest := sha256.Sum256(append(append([]byte{}, authData...), clientDataHash[:]...))
+	signature, err := ecdsa.SignASN1(rand.Reader, a.key, digest[:])
+	if err != nil {
+		a.t.Fatal(err)
+	}
+	a.counter++
+	return a.marshalResponse(map[string]interface{}{
+		"clientDataJSON":    b64url(clientData),
+		"authenticatorData": b64url(authData),
+		"signature":         b64url(signature),
+		"userHandle":        b64url(a.userHandle),
+	})
+}
+
+func (a *softAuthenticator) marshalResponse(response map[string]interface{}) []byte {
+	raw, err := json.Marshal(map[string]interface{}{
+		"id":       b64url(a.credentialID),
+		"rawId":    b64url(a.credentialID),
+		"type":     "public-key",
+		"response": response,
+	})
+	if err != nil {
+		a.t.Fatal(err)
+	}
+	return raw
+}
+
+type passkeyTestEnv struct {
+	service *PasskeyService
+	repo    *fakeCredentialRepo
+	user    *models.User
+}
+
+func newPasskeyTestEnv(t *testing.T) *passkeyTestEnv {
+	t.Helper()
+	rp, err := webauthn.New(&webauthn.Config{
+		RPID:          testPasskeyRPID,
+		RPDisplayName: "ZATRANO",
+		RPOrigins:     []string{testPasskeyOrigin},
+		AuthenticatorSelection: protocol.AuthenticatorSelection{
+			ResidentKey:      protocol.ResidentKeyRequirementRequired,
+			UserVerification: protocol.VerificationPreferred,
+		},
+		AttestationPreference: protocol.PreferNoAttestation,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	user := &models.User{BaseModel: models.BaseModel{ID: 42, IsActive: true}, Name: "Ayşe", Email: "ayse@example.com", EmailVerified: true}
+	repo := &fakeCredentialRepo{}
+	return &passkeyTestEnv{
+		user: user,
+		repo: repo,
+		service: &PasskeyService{
+			repo:  repo,
+			users: &fakeAuthRepo{users: map[uint]*models.User{user.ID: user}},
+			rp:    rp,
+			now:   time.Now,
+		},
+	}
+}
+
+// register, anahtarı kayıt törenini baştan sona çalıştırarak ekler.
+func (e *passkeyTestEnv) register(t *testing.T, a *softAuthenticator) (*models.WebAuthnCredential, error) {
+	t.Helper()
+	creation, state, err := e.service.BeginRegistration(context.Background(), e.user)
+	if err != nil {
+		t.Fatalf("BeginRegistration: %v", err)
+	}
+	return e.service.FinishRegistration(context.Background(), e.user, state, "Test", bytes.NewReader(a.register(creation)))
+}
+
+func (e *passkeyTestEnv) login(t *testing.T, a *softAuthenticator) (*PasskeyLogin, error) {
+	t.Helper()
+	assertion, state, err := e.service.BeginLogin(context.Background())
+	if err != nil {
+		t.Fatalf("BeginLogin: %v", err)
+	}
+	return e.service.FinishLogin(context.Background(), state, bytes.NewReader(a.assert(assertion)))
+}
+
+func TestPasskeyRegistrationAndLoginRoundTrip(t *testing.T) {
+	e := newPasskeyTestEnv(t)
+	a := newSoftAuthenticator(t)
+
+	record, err := e.register(t, a)
+	if err != nil {
+		t.Fatalf("FinishRegistration: %v", err)
+	}
+	if !bytes.Equal(record.CredentialID, a.credentialID) || record.UserID != e.user.ID || record.Transports != "internal" {
+		t.Fatalf("beklenmeyen kayıt: %+v", record)
+	}
+
+	a.counter = 1
+	for i := 0; i < 2; i++ {
+		result, err := e.login(t, a)
+		if err != nil {
+			t.Fatalf("FinishLogin: %v", err)
+		}
+		if result.User.ID != e.user.ID || !result.UserVerified {
+			t.Fatalf("beklenmeyen giriş: %+v", result)
+		}
+	}
+	if got := e.repo.credentials[0].SignCount; got != 2 {
+		t.Fatalf("imza sayacı = %d, beklenen 2", got)
+	}
+}
+
+func TestPasskeyRejectsChallengeMismatch(t *testing.T) {
+	e := newPasskeyTestEnv(t)
+	a := newSoftAuthenticator(t)
+	ctx := context.Background()
+
+	// Yanıt başka bir törenin challenge'ıyla imzalanmış
+	creation, _, err := e.service.BeginRegistration(ctx, e.user)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, state, err := e.service.BeginRegistration(ctx, e.user)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := e.service.FinishRegistration(ctx, e.user, state, "", bytes.NewReader(a.register(creation))); !errors.Is(err, ErrPasskeyInvalid) {
+		t.Fatalf("kayıt: hata = %v, beklenen %v", err, ErrPasskeyInvalid)
+	}
+
+	if _, err := e.register(t, a); err != nil {
+		t.Fatalf("FinishRegistration: %v", err)
+	}
+	assertion, _, err := e.service.BeginLogin(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, state, err = e.service.BeginLogin(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := e.service.FinishLogin(ctx, state, bytes.NewReader(a.assert(assertion))); !errors.Is(err, ErrPasskeyInvalid) {
+		t.Fatalf("giriş: hata = %v, beklenen %v", err, ErrPasskeyInvalid)
+	}
+}
+
+func TestPasskeyRejectsWrongOrigin(t *testing.T) {
+	e := newPasskeyTestEnv(t)
+	a := newSoftAuthenticator(t)
+
+	a.origin = "https://evil.example.com"
+	if _, err := e.register(t, a); !errors.Is(err, ErrPasskeyInvalid) {
+		t.Fatalf("kayıt: hata = %v, beklenen %v", err, ErrPasskeyInvalid)
+	}
+
+	a.origin = testPasskeyOrigin
+	if _, err := e.register(t, a); err != nil {
+		t.Fatalf("FinishRegistration: %v", err)
+	}
+	a.origin = "https://evil.example.com"
+	if _, err := e.login(t, a); !errors.Is(err, ErrPasskeyInvalid) {
+		t.Fatalf("giriş: hata = %v, beklenen %v", err, ErrPasskeyInvalid)
+	}
+}
+
+func TestPasskeyRejectsWrongRPID(t *testing.T) {
+	e := newPasskeyTestEnv(t)
+	a := newSoftAuthenticator(t)
+
+	a.rpID = "evil.example.com"
+	if _, err := e.register(t, a); !errors.Is(err, ErrPasskeyInvalid) {
+		t.Fatalf("kayıt: hata = %v, beklenen %v", err, ErrPasskeyInvalid)
+	}
+
+	a.rpID = testPasskeyRPID
+	if _, err := e.register(t, a); err != nil {
+		t.Fatalf("FinishRegistration: %v", err)
+	}
+	a.rpID = "evil.example.com"
+	if _, err := e.login(t, a); !errors.Is(err, ErrPasskeyInvalid) {
+		t.Fatalf("giriş: hata = %v, beklenen %v", err, ErrPasskeyInvalid)
+	}
+}
+
+func TestPasskeyRejectsForeignUserHandle(t *testing.T) {
+	e := newPasskeyTestEnv(t)
+	a := newSoftAuthenticator(t)
+	if _, err := e.register(t, a); err != nil {
+		t.Fatalf("FinishRegistration: %v", err)
+	}
+
+	a.userHandle = passkeyUserHandle(e.user.ID + 1)
+	if _, err := e.login(t, a); !errors.Is(err, ErrPasskeyInvalid) {
+		t.Fatalf("hata = %v, beklenen %v", err, ErrPasskeyInvalid)
+	}
+}
+
+func TestPasskeyRejectsSignCountRegression(t *testing.T) {
+	e := newPasskeyTestEnv(t)
+	a := newSoftAuthenticator(t)
+	if _, err := e.register(t, a); err != nil {
+		t.Fatalf("FinishRegistration: %v", err)
+	}
+
+	a.counter = 5
+	if _, err := e.login(t, a); err != nil {
+		t.Fatalf("FinishLogin: %v", err)
+	}
+
+	// Kopyalanmış anahtar eski bir sayaçla imzalıyor
+	a.counter = 3
+	if _, err := e.login(t, a); !errors.Is(err, ErrPasskeyCloned) {
+		t.Fatalf("hata = %v, beklenen %v", err, ErrPasskeyCloned)
+	}
+	stored := e.repo.credentials[0]
+	if !stored.CloneWarning || stored.SignCount != 5 {
+		t.Fatalf("kopya uyarısı kaydedilmedi: %+v", stored)
+	}
+
+	// Uyarı kalıcıdır; sayaç yeniden ilerlese de anahtarla giriş yapılamaz
+	a.counter = 10
+	if _, err := e.login(t, a); !errors.Is(err, ErrPasskeyCloned) {
+		t.Fatalf("uyarı sonrası giriş: hata = %v, beklenen %v", err, ErrPasskeyCloned)
+	}
+}
+
+func TestPasskeyAllowsZeroCounterAuthenticators(t *testing.T) {
+	e := newPasskeyTestEnv(t)
+	a := newSoftAuthenticator(t)
+	if _, err := e.register(t, a); err != nil {
+		t.Fatalf("FinishRegistration: %v", err)
+	}
+
+	// Eşitlenen anahtarlar sayaç tutmaz ve her imzada sıfır gönderir
+	for i := 0; i < 2; i++ {
+		a.counter = 0
+		if _, err := e.login(t, a); err != nil {
+			t.Fatalf("FinishLogin: %v", err)
+		}
+	}
+}
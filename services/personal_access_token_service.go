@@ -0,0 +1,173 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+
+	"zatrano/configs/envconfig"
+	"zatrano/configs/logconfig"
+	"zatrano/models"
+	"zatrano/repositories"
+
+	"go.uber.org/zap"
+)
+
+var (
+	ErrAccessTokenInvalid  = errors.New("erişim anahtarı geçersiz, süresi dolmuş ya da iptal edilmiş")
+	ErrAccessTokenNotFound = errors.New("erişim anahtarı bulunamadı")
+	ErrAccessTokenScope    = errors.New("en az bir geçerli kapsam seçilmelidir")
+	ErrAccessTokenLimit    = errors.New("etkin erişim anahtarı sınırına ulaşıldı")
+)
+
+// accessTokenPrefix, anahtarın bu uygulamaya ait olduğunu gösterir; kaynak kodda ya da günlüklerde
+// sızan anahtarların taranarak bulunmasını kolaylaştırır.
+const accessTokenPrefix = "zat_"
+
+// accessTokenTouchInterval, son kullanım bilgisinin en fazla bu sıklıkla yazılmasını sağlar;
+// her API isteğinde veritabanına yazılmaz.
+const accessTokenTouchInterval = time.Minute
+
+// IPersonalAccessTokenService, kullanıcıların /api/v1 için ürettiği kişisel erişim anahtarlarını yönetir.
+type IPersonalAccessTokenService interface {
+	// Create, anahtarı üretir ve düz metin halini döner; düz metin bir daha elde edilemez.
+	// expiresInDays sıfırsa anahtar iptal edilene kadar geçerlidir.
+	Create(ctx context.Context, userID uint, name string, scopes []string, expiresInDays int) (string, *models.PersonalAccessToken, error)
+	List(ctx context.Context, userID uint) ([]models.PersonalAccessToken, error)
+	Revoke(ctx context.Context, userID, id uint) error
+	// Authenticate, Bearer başlığındaki anahtarı doğrular ve sahibiyle birlikte döner.
+	Authenticate(ctx context.Context, raw, ip string) (*models.PersonalAccessToken, error)
+}
+
+type PersonalAccessTokenService struct {
+	repo      repositories.IPersonalAccessTokenRepository
+	maxActive int
+	now       func() time.Time
+}
+
+// NewPersonalAccessTokenService; PERSONAL_ACCESS_TOKEN_MAX sıfır verilirse kullanıcı başına sınır uygulanmaz.
+func NewPersonalAccessTokenService() IPersonalAccessTokenService {
+	return &PersonalAccessTokenService{
+		repo:      repositories.NewPersonalAccessTokenRepository(),
+		maxActive: envconfig.Int("PERSONAL_ACCESS_TOKEN_MAX", 10),
+		now:       time.Now,
+	}
+}
+
+func (s *PersonalAccessTokenService) Create(ctx context.Context, userID uint, name string, scopes []string, expiresInDays int) (string, *models.PersonalAccessToken, error) {
+	selected := make([]string, 0, len(scopes))
+	seen := make(map[string]bool, len(scopes))
+	for _, scope := range scopes {
+		scope = strings.TrimSpace(scope)
+		if !models.IsKnownTokenScope(scope) {
+			return "", nil, ErrAccessTokenScope
+		}
+		if !seen[scope] {
+			seen[scope] = true
+			selected = append(selected, scope)
+		}
+	}
+	if len(selected) == 0 {
+		return "", nil, ErrAccessTokenScope
+	}
+
+	if s.maxActive > 0 {
+		existing, err := s.List(ctx, userID)
+		if err != nil {
+			return "", nil, err
+		}
+		active := 0
+		for i := range existing {
+			if existing[i].Active(s.now()) {
+				active++
+			}
+		}
+		if active >= s.maxActive {
+			return "", nil, ErrAccessTokenLimit
+		}
+	}
+
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		logconfig.Log.Error("Erişim anahtarı oluşturulamadı", zap.Error(err))
+		return "", nil, err
+	}
+	raw := accessTokenPrefix + hex.EncodeToString(buf)
+
+	token := &models.PersonalAccessToken{
+		UserID:    userID,
+		Name:      strings.TrimSpace(name),
+		TokenHash: hashSecretToken(raw),
+		Prefix:    raw[:len(accessTokenPrefix)+8],
+		Scopes:    strings.Join(selected, ","),
+	}
+	if expiresInDays > 0 {
+		expires := s.now().AddDate(0, 0, expiresInDays)
+		token.ExpiresAt = &expires
+	}
+	if err := s.repo.Create(ctx, token); err != nil {
+		logconfig.Log.Error("Erişim anahtarı kaydedilemedi", zap.Uint("user_id", userID), zap.Error(err))
+		return "", nil, ErrDatabaseUpdateFailed
+	}
+	logconfig.Log.Info("Erişim anahtarı oluşturuldu",
+		zap.Uint("user_id", userID),
+		zap.Uint("token_id", token.ID),
+		zap.Strings("scopes", selected))
+	return raw, token, nil
+}
+
+func (s *PersonalAccessTokenService) List(ctx context.Context, userID uint) ([]models.PersonalAccessToken, error) {
+	tokens, err := s.repo.ListByUser(ctx, userID)
+	if err != nil {
+		logconfig.Log.Error("Erişim anahtarları listelenemedi", zap.Uint("user_id", userID), zap.Error(err))
+		return nil, err
+	}
+	return tokens, nil
+}
+
+func (s *PersonalAccessTokenService) Revoke(ctx context.Context, userID, id uint) error {
+	if err := s.repo.Revoke(ctx, userID, id, s.now()); err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return ErrAccessTokenNotFound
+		}
+		logconfig.Log.Error("Erişim anahtarı iptal edilemedi", zap.Uint("user_id", userID), zap.Uint("token_id", id), zap.Error(err))
+		return ErrDatabaseUpdateFailed
+	}
+	logconfig.Log.Info("Erişim anahtarı iptal edildi", zap.Uint("user_id", userID), zap.Uint("token_id", id))
+	return nil
+}
+
+func (s *PersonalAccessTokenService) Authenticate(ctx context.Context, raw, ip string) (*models.PersonalAccessToken, error) {
+	if !strings.HasPrefix(raw, accessTokenPrefix) {
+		return nil, ErrAccessTokenInvalid
+	}
+	token, err := s.repo.FindByHash(ctx, hashSecretToken(raw))
+	if err != nil {
+		if !errors.Is(err, repositories.ErrNotFound) {
+			logconfig.Log.Error("Erişim anahtarı sorgulanamadı", zap.Error(err))
+			return nil, ErrAuthGeneric
+		}
+		return nil, ErrAccessTokenInvalid
+	}
+
+	now := s.now()
+	if !token.Active(now) {
+		return nil, ErrAccessTokenInvalid
+	}
+	if token.User == nil || !token.User.IsActive {
+		return nil, ErrUserInactive
+	}
+
+	// Kullanım bilgisi yazılamazsa istek yine de işlenir
+	if token.LastUsedAt == nil || now.Sub(*token.LastUsedAt) >= accessTokenTouchInterval || token.LastUsedIP != ip {
+		if err := s.repo.TouchUse(ctx, token.ID, ip, now); err != nil {
+			logconfig.Log.Warn("Erişim anahtarı kullanım bilgisi güncellenemedi", zap.Uint("token_id", token.ID), zap.Error(err))
+		}
+	}
+	return token, nil
+}
+
+var _ IPersonalAccessTokenService = (*PersonalAccessTokenService)(nil)
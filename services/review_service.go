@@ -0,0 +1,230 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"zatrano/configs/envconfig"
+	"zatrano/configs/logconfig"
+	"zatrano/models"
+	"zatrano/repositories"
+	"zatrano/requests"
+
+	"go.uber.org/zap"
+)
+
+var (
+	ErrReviewNotFound         = errors.New("yorum bulunamadı")
+	ErrReviewNotAllowed       = errors.New("yalnızca tamamlanmış randevular değerlendirilebilir")
+	ErrReviewAlreadyExists    = errors.New("bu randevu zaten değerlendirilmiş")
+	ErrReviewPeriodExpired    = errors.New("bu randevu için değerlendirme süresi doldu")
+	ErrReviewAlreadyModerated = errors.New("yorum zaten bu durumda")
+	ErrReviewReplyNotAllowed  = errors.New("reddedilen yorumlara yanıt verilemez")
+)
+
+// AppointmentReview, müşterinin randevu sayfasında değerlendirme bölümünün durumunu taşır.
+type AppointmentReview struct {
+	Review *models.Review
+	// Completed, onaylı randevunun bitiş saati geçtiyse true olur
+	Completed bool
+	CanReview bool
+	Expired   bool
+}
+
+// IReviewService, randevuya bağlı doğrulanmış yorumları, işletme yanıtlarını ve
+// yönetici moderasyonunu tanımlar.
+type IReviewService interface {
+	// Müşteri akışları; randevu yönetim bağlantısındaki token ile çalışır
+	GetAppointmentReview(ctx context.Context, appointment *models.Appointment) (*AppointmentReview, error)
+	Submit(ctx context.Context, token string, req requests.ReviewRequest) (*models.Review, error)
+
+	// GetBusinessReviews, profil sayfası için en yeni onaylı yorumları döner.
+	GetBusinessReviews(ctx context.Context, businessID uint, limit int) ([]models.Review, error)
+
+	// İşletme akışları; yalnızca işletme sahibinin yorumlarına erişilebilir
+	GetOwnerReviews(ctx context.Context, ownerID uint, params requests.ReviewListParams) (*requests.PaginatedResult, error)
+	Reply(ctx context.Context, ownerID, id uint, reply string) error
+
+	// Yönetici moderasyonu
+	GetReviews(ctx context.Context, params requests.ReviewListParams) (*requests.PaginatedResult, error)
+	Approve(ctx context.Context, id uint, note string) error
+	Reject(ctx context.Context, id uint, note string) error
+}
+
+type ReviewService struct {
+	repo            repositories.IReviewRepository
+	appointmentRepo repositories.IAppointmentRepository
+	autoApprove     bool
+	windowDays      int
+	now             func() time.Time
+}
+
+// NewReviewService; REVIEW_AUTO_APPROVE=true ise yorumlar moderasyonsuz yayınlanır,
+// REVIEW_WINDOW_DAYS randevu bitiminden sonra kaç gün içinde yorum bırakılabileceğini belirler (0: süresiz).
+func NewReviewService() IReviewService {
+	return &ReviewService{
+		repo:            repositories.NewReviewRepository(),
+		appointmentRepo: repositories.NewAppointmentRepository(),
+		autoApprove:     envconfig.String("REVIEW_AUTO_APPROVE", "false") == "true",
+		windowDays:      envconfig.Int("REVIEW_WINDOW_DAYS", 30),
+		now:             time.Now,
+	}
+}
+
+func (s *ReviewService) GetAppointmentReview(ctx context.Context, appointment *models.Appointment) (*AppointmentReview, error) {
+	state := &AppointmentReview{Completed: s.completed(appointment)}
+
+	review, err := s.repo.FindByAppointment(ctx, appointment.ID)
+	switch {
+	case err == nil:
+		state.Review = review
+	case errors.Is(err, repositories.ErrNotFound):
+		state.Expired = state.Completed && s.expired(appointment)
+		state.CanReview = state.Completed && !state.Expired
+	default:
+		logconfig.Log.Error("Randevu yorumu getirilemedi", zap.Uint("appointment_id", appointment.ID), zap.Error(err))
+		return nil, err
+	}
+	return state, nil
+}
+
+func (s *ReviewService) Submit(ctx context.Context, token string, req requests.ReviewRequest) (*models.Review, error) {
+	if token == "" {
+		return nil, ErrAppointmentNotFound
+	}
+	appointment, err := s.appointmentRepo.FindByToken(ctx, token)
+	if err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return nil, ErrAppointmentNotFound
+		}
+		return nil, err
+	}
+	if !s.completed(appointment) {
+		return nil, ErrReviewNotAllowed
+	}
+	if s.expired(appointment) {
+		return nil, ErrReviewPeriodExpired
+	}
+
+	review := &models.Review{
+		BusinessID:     appointment.BusinessID,
+		ProfessionalID: appointment.ProfessionalID,
+		AppointmentID:  appointment.ID,
+		Rating:         req.RatingValue(),
+		Comment:        req.Comment,
+		CustomerName:   appointment.CustomerName,
+		Status:         models.ReviewStatusPending,
+	}
+	if s.autoApprove {
+		review.Status = models.ReviewStatusApproved
+	}
+
+	if err := s.repo.Create(ctx, review); err != nil {
+		if errors.Is(err, repositories.ErrReviewExists) {
+			return nil, ErrReviewAlreadyExists
+		}
+		logconfig.Log.Error("Yorum kaydedilemedi", zap.Uint("appointment_id", appointment.ID), zap.Error(err))
+		return nil, err
+	}
+	return review, nil
+}
+
+func (s *ReviewService) GetBusinessReviews(ctx context.Context, businessID uint, limit int) ([]models.Review, error) {
+	reviews, err := s.repo.GetBusinessReviews(ctx, businessID, limit)
+	if err != nil {
+		logconfig.Log.Error("İşletme yorumları getirilemedi", zap.Uint("business_id", businessID), zap.Error(err))
+		return nil, err
+	}
+	return reviews, nil
+}
+
+func (s *ReviewService) GetOwnerReviews(ctx context.Context, ownerID uint, params requests.ReviewListParams) (*requests.PaginatedResult, error) {
+	reviews, total, err := s.repo.GetOwnerReviews(ctx, ownerID, params)
+	if err != nil {
+		logconfig.Log.Error("Yorumlar getirilemedi", zap.Uint("owner_id", ownerID), zap.Error(err))
+		return nil, err
+	}
+	return requests.CreatePaginatedResult(reviews, total, params.Page, params.PerPage), nil
+}
+
+// Reply, işletme sahibinin yanıtını kaydeder; boş yanıt mevcut yanıtı kaldırır.
+func (s *ReviewService) Reply(ctx context.Context, ownerID, id uint, reply string) error {
+	review, err := s.repo.GetOwnerReviewByID(ctx, ownerID, id)
+	if err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return ErrReviewNotFound
+		}
+		return err
+	}
+	if review.Status == models.ReviewStatusRejected {
+		return ErrReviewReplyNotAllowed
+	}
+
+	var repliedAt *time.Time
+	if reply != "" {
+		now := s.now()
+		repliedAt = &now
+	}
+	if err := s.repo.UpdateReply(ctx, review.ID, reply, repliedAt); err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return ErrReviewNotFound
+		}
+		logconfig.Log.Error("Yorum yanıtı kaydedilemedi", zap.Uint("review_id", review.ID), zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+func (s *ReviewService) GetReviews(ctx context.Context, params requests.ReviewListParams) (*requests.PaginatedResult, error) {
+	reviews, total, err := s.repo.GetReviews(ctx, params)
+	if err != nil {
+		logconfig.Log.Error("Moderasyon kuyruğu getirilemedi", zap.Error(err))
+		return nil, err
+	}
+	return requests.CreatePaginatedResult(reviews, total, params.Page, params.PerPage), nil
+}
+
+func (s *ReviewService) Approve(ctx context.Context, id uint, note string) error {
+	return s.moderate(ctx, id, models.ReviewStatusApproved, note)
+}
+
+func (s *ReviewService) Reject(ctx context.Context, id uint, note string) error {
+	return s.moderate(ctx, id, models.ReviewStatusRejected, note)
+}
+
+func (s *ReviewService) moderate(ctx context.Context, id uint, status, note string) error {
+	review, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return ErrReviewNotFound
+		}
+		return err
+	}
+	if review.Status == status {
+		return ErrReviewAlreadyModerated
+	}
+
+	if err := s.repo.Moderate(ctx, review, status, note); err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return ErrReviewNotFound
+		}
+		logconfig.Log.Error("Yorum durumu güncellenemedi", zap.Uint("review_id", id), zap.String("status", status), zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// completed, yalnızca onaylanmış ve bitiş saati geçmiş randevuların değerlendirilebilmesini sağlar.
+func (s *ReviewService) completed(appointment *models.Appointment) bool {
+	return appointment.Status == models.AppointmentStatusConfirmed && !appointment.EndsAt.After(s.now())
+}
+
+func (s *ReviewService) expired(appointment *models.Appointment) bool {
+	if s.windowDays <= 0 {
+		return false
+	}
+	return s.now().After(appointment.EndsAt.AddDate(0, 0, s.windowDays))
+}
+
+var _ IReviewService = (*ReviewService)(nil)
@@ -0,0 +1,139 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"zatrano/models"
+	"zatrano/pkg/slots"
+	"zatrano/pkg/trholidays"
+	"zatrano/repositories"
+)
+
+// DayPlan, bir günün hesaplanmış çalışma planıdır.
+type DayPlan struct {
+	Date     time.Time
+	Windows  []slots.Interval
+	Holiday  *trholidays.Holiday
+	TimeOffs []models.ProfessionalTimeOff
+}
+
+// WorkMinutes, gün içindeki toplam çalışma süresidir.
+func (p DayPlan) WorkMinutes() int {
+	total := 0
+	for _, w := range p.Windows {
+		total += int(w.End.Sub(w.Start).Minutes())
+	}
+	return total
+}
+
+func (p DayPlan) WorkHours() float64 {
+	return float64(p.WorkMinutes()) / 60
+}
+
+// scheduleWorkingHours, uzmanın haftalık takvimini, resmi tatilleri ve izinlerini birleştirir.
+// Haftalık takvimi girilmemiş uzmanlar için ortam değişkenlerindeki varsayılan saatler kullanılır.
+type scheduleWorkingHours struct {
+	repo     repositories.IScheduleRepository
+	fallback *envWorkingHours
+}
+
+func NewScheduleWorkingHours() IWorkingHoursProvider {
+	return newScheduleWorkingHours()
+}
+
+func newScheduleWorkingHours() *scheduleWorkingHours {
+	return &scheduleWorkingHours{
+		repo:     repositories.NewScheduleRepository(),
+		fallback: newEnvWorkingHours(),
+	}
+}
+
+func (h *scheduleWorkingHours) Windows(ctx context.Context, professional *models.Professional, from, to time.Time) (map[string][]slots.Interval, error) {
+	plans, err := h.Plan(ctx, professional, from, to)
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[string][]slots.Interval, len(plans))
+	for _, p := range plans {
+		result[p.Date.Format("2006-01-02")] = p.Windows
+	}
+	return result, nil
+}
+
+// Plan, aralıktaki günleri sırayla hesaplar. Veritabanına gün sayısından bağımsız olarak
+// iki sorgu atılır (haftalık takvim ve izinler); tatiller bellekte üretilir.
+func (h *scheduleWorkingHours) Plan(ctx context.Context, professional *models.Professional, from, to time.Time) ([]DayPlan, error) {
+	entries, err := h.repo.GetWorkingHours(ctx, professional.ID)
+	if err != nil {
+		return nil, err
+	}
+	timeOffs, err := h.repo.GetTimeOffs(ctx, professional.ID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	holidays := trholidays.Between(from, to, appointmentLocation)
+
+	var work, breaks [7][]models.ProfessionalWorkingHour
+	for _, e := range entries {
+		if e.Weekday < 0 || e.Weekday > 6 {
+			continue
+		}
+		if e.IsBreak {
+			breaks[e.Weekday] = append(breaks[e.Weekday], e)
+		} else {
+			work[e.Weekday] = append(work[e.Weekday], e)
+		}
+	}
+
+	var plans []DayPlan
+	for day := from; day.Before(to); day = day.AddDate(0, 0, 1) {
+		plan := DayPlan{Date: day}
+		dayEnd := day.AddDate(0, 0, 1)
+
+		if len(entries) == 0 {
+			plan.Windows = h.fallback.day(day)
+		} else {
+			weekday := day.Weekday()
+			plan.Windows = slots.Subtract(entryIntervals(work[weekday], day), entryIntervals(breaks[weekday], day))
+		}
+
+		if holiday, ok := holidays[day.Format("2006-01-02")]; ok {
+			plan.Holiday = &holiday
+			if !professional.WorksOnHolidays {
+				offFrom, offTo := holiday.Off()
+				plan.Windows = slots.Subtract(plan.Windows, []slots.Interval{{Start: offFrom, End: offTo}})
+			}
+		}
+
+		var cuts []slots.Interval
+		for _, off := range timeOffs {
+			if off.StartsAt.Before(dayEnd) && off.EndsAt.After(day) {
+				plan.TimeOffs = append(plan.TimeOffs, off)
+				cuts = append(cuts, slots.Interval{Start: off.StartsAt.In(appointmentLocation), End: off.EndsAt.In(appointmentLocation)})
+			}
+		}
+		plan.Windows = slots.Subtract(plan.Windows, cuts)
+
+		plans = append(plans, plan)
+	}
+	return plans, nil
+}
+
+func entryIntervals(entries []models.ProfessionalWorkingHour, day time.Time) []slots.Interval {
+	var result []slots.Interval
+	for _, e := range entries {
+		start, okStart := parseClock(e.StartTime)
+		end, okEnd := parseClock(e.EndTime)
+		if !okStart || !okEnd {
+			continue
+		}
+		interval := slots.Interval{Start: start.On(day), End: end.On(day)}
+		if interval.Start.Before(interval.End) {
+			result = append(result, interval)
+		}
+	}
+	return result
+}
+
+var _ IWorkingHoursProvider = (*scheduleWorkingHours)(nil)
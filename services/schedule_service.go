@@ -0,0 +1,275 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"zatrano/configs/logconfig"
+	"zatrano/models"
+	"zatrano/repositories"
+	"zatrano/requests"
+
+	"go.uber.org/zap"
+)
+
+var (
+	ErrScheduleProfessionalNotFound = errors.New("uzman bulunamadı")
+	ErrScheduleTimeOffNotFound      = errors.New("izin kaydı bulunamadı")
+	ErrScheduleInvalidMonth         = errors.New("geçersiz ay")
+)
+
+// CalendarDay, ay görünümündeki bir hücredir.
+type CalendarDay struct {
+	DayPlan
+	Appointments int
+	InMonth      bool
+	Today        bool
+}
+
+// MonthCalendar, Pazartesi ile başlayan haftalara bölünmüş ay görünümüdür.
+type MonthCalendar struct {
+	Month     time.Time
+	Weeks     [][]CalendarDay
+	PrevMonth string
+	NextMonth string
+}
+
+type IScheduleService interface {
+	GetOwnerProfessionals(ctx context.Context, ownerID uint) ([]models.Professional, error)
+	GetOwnerProfessional(ctx context.Context, ownerID, professionalID uint) (*models.Professional, error)
+
+	// GetWeeklySchedule, takvim girilmemişse varsayılan saatleri usingDefaults=true ile döner.
+	GetWeeklySchedule(ctx context.Context, professional *models.Professional) (schedule requests.WeeklyScheduleRequest, usingDefaults bool, err error)
+	SaveWeeklySchedule(ctx context.Context, ownerID, professionalID uint, req requests.WeeklyScheduleRequest) error
+
+	GetUpcomingTimeOffs(ctx context.Context, professionalID uint) ([]models.ProfessionalTimeOff, error)
+	// AddTimeOff, izinle çakışan ve iptal edilmemiş randevu sayısını döner; randevular otomatik iptal edilmez.
+	AddTimeOff(ctx context.Context, ownerID, professionalID uint, req requests.TimeOffRequest) (conflicts int, err error)
+	DeleteTimeOff(ctx context.Context, ownerID, professionalID, timeOffID uint) error
+
+	// MonthCalendar, month "2006-01" biçimindedir; boşsa içinde bulunulan ay kullanılır.
+	MonthCalendar(ctx context.Context, professional *models.Professional, month string) (*MonthCalendar, error)
+}
+
+type ScheduleService struct {
+	repo            repositories.IScheduleRepository
+	appointmentRepo repositories.IAppointmentRepository
+	hours           *scheduleWorkingHours
+	now             func() time.Time
+}
+
+func NewScheduleService() IScheduleService {
+	return &ScheduleService{
+		repo:            repositories.NewScheduleRepository(),
+		appointmentRepo: repositories.NewAppointmentRepository(),
+		hours:           newScheduleWorkingHours(),
+		now:             time.Now,
+	}
+}
+
+func (s *ScheduleService) GetOwnerProfessionals(ctx context.Context, ownerID uint) ([]models.Professional, error) {
+	professionals, err := s.repo.GetOwnerProfessionals(ctx, ownerID)
+	if err != nil {
+		logconfig.Log.Error("Uzmanlar getirilemedi", zap.Uint("owner_id", ownerID), zap.Error(err))
+		return nil, err
+	}
+	return professionals, nil
+}
+
+func (s *ScheduleService) GetOwnerProfessional(ctx context.Context, ownerID, professionalID uint) (*models.Professional, error) {
+	professional, err := s.repo.GetOwnerProfessional(ctx, ownerID, professionalID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return nil, ErrScheduleProfessionalNotFound
+		}
+		logconfig.Log.Error("Uzman getirilemedi", zap.Uint("professional_id", professionalID), zap.Error(err))
+		return nil, err
+	}
+	return professional, nil
+}
+
+func (s *ScheduleService) GetWeeklySchedule(ctx context.Context, professional *models.Professional) (requests.WeeklyScheduleRequest, bool, error) {
+	schedule := requests.WeeklyScheduleRequest{WorksOnHolidays: professional.WorksOnHolidays}
+
+	entries, err := s.repo.GetWorkingHours(ctx, professional.ID)
+	if err != nil {
+		logconfig.Log.Error("Çalışma saatleri getirilemedi", zap.Uint("professional_id", professional.ID), zap.Error(err))
+		return schedule, false, err
+	}
+
+	if len(entries) == 0 {
+		defaults := s.hours.fallback
+		for d := 0; d < 7; d++ {
+			day := requests.WeeklyScheduleDay{
+				Open:  !defaults.closedDays[time.Weekday(d)],
+				Start: defaults.start.String(),
+				End:   defaults.end.String(),
+			}
+			if len(defaults.breaks) > 0 {
+				day.BreakStart = defaults.breaks[0][0].String()
+				day.BreakEnd = defaults.breaks[0][1].String()
+			}
+			schedule.Days[d] = day
+		}
+		return schedule, true, nil
+	}
+
+	// Form gün başına tek çalışma aralığı ve tek mola gösterir; ilk kayıtlar kullanılır
+	for _, e := range entries {
+		if e.Weekday < 0 || e.Weekday > 6 || e.StartTime == e.EndTime {
+			continue
+		}
+		day := &schedule.Days[e.Weekday]
+		if e.IsBreak {
+			if day.BreakStart == "" {
+				day.BreakStart, day.BreakEnd = e.StartTime, e.EndTime
+			}
+			continue
+		}
+		if !day.Open {
+			day.Open = true
+			day.Start, day.End = e.StartTime, e.EndTime
+		}
+	}
+	return schedule, false, nil
+}
+
+func (s *ScheduleService) SaveWeeklySchedule(ctx context.Context, ownerID, professionalID uint, req requests.WeeklyScheduleRequest) error {
+	if _, err := s.GetOwnerProfessional(ctx, ownerID, professionalID); err != nil {
+		return err
+	}
+
+	var entries []models.ProfessionalWorkingHour
+	for d, day := range req.Days {
+		if !day.Open {
+			continue
+		}
+		entries = append(entries, models.ProfessionalWorkingHour{Weekday: d, StartTime: day.Start, EndTime: day.End})
+		if day.BreakStart != "" && day.BreakEnd != "" {
+			entries = append(entries, models.ProfessionalWorkingHour{Weekday: d, StartTime: day.BreakStart, EndTime: day.BreakEnd, IsBreak: true})
+		}
+	}
+
+	// Tüm günler kapalıysa satır kalmaz ve varsayılan saatlere dönülürdü;
+	// takvimin girildiğini belirtmek için sıfır uzunluklu bir aralık saklanır
+	if len(entries) == 0 {
+		entries = append(entries, models.ProfessionalWorkingHour{Weekday: 0, StartTime: "00:00", EndTime: "00:00"})
+	}
+
+	if err := s.repo.ReplaceWorkingHours(ctx, professionalID, entries, req.WorksOnHolidays); err != nil {
+		logconfig.Log.Error("Çalışma saatleri kaydedilemedi", zap.Uint("professional_id", professionalID), zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+func (s *ScheduleService) GetUpcomingTimeOffs(ctx context.Context, professionalID uint) ([]models.ProfessionalTimeOff, error) {
+	now := s.now()
+	items, err := s.repo.GetTimeOffs(ctx, professionalID, now, now.AddDate(2, 0, 0))
+	if err != nil {
+		logconfig.Log.Error("İzinler getirilemedi", zap.Uint("professional_id", professionalID), zap.Error(err))
+		return nil, err
+	}
+	return items, nil
+}
+
+func (s *ScheduleService) AddTimeOff(ctx context.Context, ownerID, professionalID uint, req requests.TimeOffRequest) (int, error) {
+	if _, err := s.GetOwnerProfessional(ctx, ownerID, professionalID); err != nil {
+		return 0, err
+	}
+	startsAt, errStart := time.ParseInLocation(requests.AppointmentStartLayout, req.StartsAt, appointmentLocation)
+	endsAt, errEnd := time.ParseInLocation(requests.AppointmentStartLayout, req.EndsAt, appointmentLocation)
+	if errStart != nil || errEnd != nil || !startsAt.Before(endsAt) {
+		return 0, ErrAppointmentInvalidDate
+	}
+
+	timeOff := &models.ProfessionalTimeOff{
+		ProfessionalID: professionalID,
+		StartsAt:       startsAt,
+		EndsAt:         endsAt,
+		Reason:         req.Reason,
+	}
+	if err := s.repo.CreateTimeOff(ctx, timeOff); err != nil {
+		logconfig.Log.Error("İzin eklenemedi", zap.Uint("professional_id", professionalID), zap.Error(err))
+		return 0, err
+	}
+
+	conflicts, err := s.appointmentRepo.GetBusyAppointments(ctx, professionalID, startsAt, endsAt, 0)
+	if err != nil {
+		logconfig.Log.Warn("İzinle çakışan randevular sayılamadı", zap.Uint("professional_id", professionalID), zap.Error(err))
+		return 0, nil
+	}
+	return len(conflicts), nil
+}
+
+func (s *ScheduleService) DeleteTimeOff(ctx context.Context, ownerID, professionalID, timeOffID uint) error {
+	if _, err := s.GetOwnerProfessional(ctx, ownerID, professionalID); err != nil {
+		return err
+	}
+	if err := s.repo.DeleteTimeOff(ctx, professionalID, timeOffID); err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return ErrScheduleTimeOffNotFound
+		}
+		logconfig.Log.Error("İzin silinemedi", zap.Uint("time_off_id", timeOffID), zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+func (s *ScheduleService) MonthCalendar(ctx context.Context, professional *models.Professional, month string) (*MonthCalendar, error) {
+	now := s.now().In(appointmentLocation)
+	first := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, appointmentLocation)
+	if month != "" {
+		parsed, err := time.ParseInLocation("2006-01", month, appointmentLocation)
+		if err != nil {
+			return nil, ErrScheduleInvalidMonth
+		}
+		first = parsed
+	}
+	next := first.AddDate(0, 1, 0)
+
+	// Izgara Pazartesi'den başlar ve Pazar'la biter
+	gridStart := first.AddDate(0, 0, -((int(first.Weekday()) + 6) % 7))
+	lastDay := next.AddDate(0, 0, -1)
+	gridEnd := lastDay.AddDate(0, 0, 7-(int(lastDay.Weekday())+6)%7)
+
+	plans, err := s.hours.Plan(ctx, professional, gridStart, gridEnd)
+	if err != nil {
+		logconfig.Log.Error("Ay takvimi hesaplanamadı", zap.Uint("professional_id", professional.ID), zap.Error(err))
+		return nil, err
+	}
+	appointments, err := s.appointmentRepo.GetBusyAppointments(ctx, professional.ID, gridStart, gridEnd, 0)
+	if err != nil {
+		logconfig.Log.Error("Ay randevuları getirilemedi", zap.Uint("professional_id", professional.ID), zap.Error(err))
+		return nil, err
+	}
+	perDay := make(map[string]int)
+	for _, a := range appointments {
+		perDay[a.StartsAt.In(appointmentLocation).Format("2006-01-02")]++
+	}
+
+	today := now.Format("2006-01-02")
+	calendar := &MonthCalendar{
+		Month:     first,
+		PrevMonth: first.AddDate(0, -1, 0).Format("2006-01"),
+		NextMonth: next.Format("2006-01"),
+	}
+	var week []CalendarDay
+	for _, plan := range plans {
+		key := plan.Date.Format("2006-01-02")
+		week = append(week, CalendarDay{
+			DayPlan:      plan,
+			Appointments: perDay[key],
+			InMonth:      plan.Date.Month() == first.Month(),
+			Today:        key == today,
+		})
+		if len(week) == 7 {
+			calendar.Weeks = append(calendar.Weeks, week)
+			week = nil
+		}
+	}
+	return calendar, nil
+}
+
+var _ IScheduleService = (*ScheduleService)(nil)
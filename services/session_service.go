@@ -0,0 +1,327 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"zatrano/configs/envconfig"
+	"zatrano/configs/logconfig"
+	"zatrano/configs/redisconfig"
+	"zatrano/configs/sessionconfig"
+	"zatrano/pkg/iplocation"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+var ErrSessionNotFound = errors.New("oturum bulunamadı")
+
+// SessionIndexedKey, oturumun dizine kaydedildiğini işaretler. İşaretli bir oturum dizinde yoksa
+// uzaktan kapatılmış demektir; işaretsiz (özellik öncesi açılmış) oturumlar ilk istekte dizine eklenir.
+const SessionIndexedKey = "session_indexed"
+
+const (
+	sessionIndexPrefix = "user_sessions"
+	// sessionTouchInterval, son görülme zamanının Redis'e en fazla hangi sıklıkla yazılacağıdır.
+	sessionTouchInterval = time.Minute
+)
+
+// UserSession, kullanıcının açık oturumlarından biridir. Sayfaya yalnızca Handle çıkar;
+// asıl oturum kimliği (çerez değeri) sunucuda kalır.
+type UserSession struct {
+	SessionID  string    `json:"session_id"`
+	Handle     string    `json:"-"`
+	UserAgent  string    `json:"user_agent"`
+	IP         string    `json:"ip"`
+	Location   string    `json:"location"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastSeenAt time.Time `json:"last_seen_at"`
+	Current    bool      `json:"-"`
+}
+
+// Device, tarayıcı ve işletim sistemini kısa bir etiketle gösterir (ör. "Chrome · Windows").
+func (s UserSession) Device() string {
+	return describeUserAgent(s.UserAgent)
+}
+
+// ISessionService, Redis'teki oturumlar için kullanıcı bazlı bir dizin tutar; böylece kullanıcı
+// açık oturumlarını görebilir ve uzaktan kapatabilir.
+type ISessionService interface {
+	// Register, giriş sonrası oturumu dizine ekler.
+	Register(ctx context.Context, userID uint, sessionID, userAgent, ip string) error
+	// Touch, son görülme zamanını ve IP'yi günceller; oturum dizinde yoksa (uzaktan kapatılmışsa) false döner.
+	Touch(ctx context.Context, userID uint, sessionID, userAgent, ip string) (bool, error)
+	List(ctx context.Context, userID uint, currentSessionID string) ([]UserSession, error)
+	Revoke(ctx context.Context, userID uint, handle string) error
+	// RevokeOthers, mevcut oturum dışındaki tüm oturumları kapatır ve kapatılan sayısını döner.
+	RevokeOthers(ctx context.Context, userID uint, currentSessionID string) (int, error)
+	RevokeAll(ctx context.Context, userID uint) error
+	// Unregister, çıkış yapılan oturumu dizinden siler.
+	Unregister(ctx context.Context, userID uint, sessionID string) error
+}
+
+type SessionService struct {
+	redis     *redis.Client
+	locations *iplocation.DB
+	now       func() time.Time
+}
+
+// NewSessionService; IP_LOCATION_DB tanımlıysa konumlar gömülü veritabanı yerine bu CSV dosyasından çözülür.
+func NewSessionService() ISessionService {
+	return &SessionService{
+		redis:     redisconfig.GetClient(),
+		locations: locationDB(),
+		now:       time.Now,
+	}
+}
+
+var (
+	locationOnce sync.Once
+	locations    *iplocation.DB
+)
+
+func locationDB() *iplocation.DB {
+	locationOnce.Do(func() {
+		locations = iplocation.Default()
+		if path := envconfig.String("IP_LOCATION_DB", ""); path != "" {
+			external, err := iplocation.LoadFile(path)
+			if err != nil {
+				logconfig.Log.Error("IP konum veritabanı yüklenemedi, gömülü veri kullanılıyor", zap.String("path", path), zap.Error(err))
+				return
+			}
+			locations = external
+		}
+	})
+	return locations
+}
+
+func sessionIndexKey(userID uint) string {
+	return redisconfig.GetPrefixedKey(sessionIndexPrefix, fmt.Sprint(userID))
+}
+
+// sessionHandle, oturum kimliğinden sayfada kullanılabilecek, geri çevrilemeyen kısa bir anahtar üretir.
+func sessionHandle(sessionID string) string {
+	sum := sha256.Sum256([]byte(sessionID))
+	return hex.EncodeToString(sum[:])[:16]
+}
+
+func sessionTTL() time.Duration {
+	if sessionconfig.Store != nil && sessionconfig.Store.Expiration > 0 {
+		return sessionconfig.Store.Expiration
+	}
+	return 24 * time.Hour
+}
+
+func (s *SessionService) locate(ip string) string {
+	if loc, ok := s.locations.Lookup(ip); ok {
+		return loc.String()
+	}
+	return ""
+}
+
+func (s *SessionService) save(ctx context.Context, userID uint, entry UserSession) error {
+	raw, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	key := sessionIndexKey(userID)
+	pipe := s.redis.TxPipeline()
+	pipe.HSet(ctx, key, sessionHandle(entry.SessionID), raw)
+	pipe.Expire(ctx, key, sessionTTL())
+	_, err = pipe.Exec(ctx)
+	return err
+}
+
+func (s *SessionService) get(ctx context.Context, userID uint, handle string) (*UserSession, error) {
+	raw, err := s.redis.HGet(ctx, sessionIndexKey(userID), handle).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, ErrSessionNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	var entry UserSession
+	if err := json.Unmarshal(raw, &entry); err != nil {
+		return nil, err
+	}
+	entry.Handle = handle
+	return &entry, nil
+}
+
+func (s *SessionService) Register(ctx context.Context, userID uint, sessionID, userAgent, ip string) error {
+	now := s.now()
+	entry := UserSession{
+		SessionID:  sessionID,
+		UserAgent:  userAgent,
+		IP:         ip,
+		Location:   s.locate(ip),
+		CreatedAt:  now,
+		LastSeenAt: now,
+	}
+	if err := s.save(ctx, userID, entry); err != nil {
+		logconfig.Log.Error("Oturum dizine eklenemedi", zap.Uint("user_id", userID), zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+func (s *SessionService) Touch(ctx context.Context, userID uint, sessionID, userAgent, ip string) (bool, error) {
+	entry, err := s.get(ctx, userID, sessionHandle(sessionID))
+	if errors.Is(err, ErrSessionNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return true, err
+	}
+
+	if s.now().Sub(entry.LastSeenAt) < sessionTouchInterval && entry.IP == ip {
+		return true, nil
+	}
+	entry.LastSeenAt = s.now()
+	entry.UserAgent = userAgent
+	if entry.IP != ip {
+		entry.IP = ip
+		entry.Location = s.locate(ip)
+	}
+	return true, s.save(ctx, userID, *entry)
+}
+
+func (s *SessionService) List(ctx context.Context, userID uint, currentSessionID string) ([]UserSession, error) {
+	key := sessionIndexKey(userID)
+	all, err := s.redis.HGetAll(ctx, key).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	sessions := make([]UserSession, 0, len(all))
+	var stale []string
+	for handle, raw := range all {
+		var entry UserSession
+		if err := json.Unmarshal([]byte(raw), &entry); err != nil {
+			stale = append(stale, handle)
+			continue
+		}
+		// Süresi dolan oturumlar dizinden ayıklanır
+		if data, err := sessionconfig.Store.Storage.Get(entry.SessionID); err == nil && data == nil {
+			stale = append(stale, handle)
+			continue
+		}
+		entry.Handle = handle
+		entry.Current = entry.SessionID == currentSessionID
+		sessions = append(sessions, entry)
+	}
+	if len(stale) > 0 {
+		_ = s.redis.HDel(ctx, key, stale...).Err()
+	}
+
+	sort.Slice(sessions, func(i, j int) bool {
+		if sessions[i].Current != sessions[j].Current {
+			return sessions[i].Current
+		}
+		return sessions[i].LastSeenAt.After(sessions[j].LastSeenAt)
+	})
+	return sessions, nil
+}
+
+func (s *SessionService) revoke(ctx context.Context, userID uint, entry UserSession) error {
+	if err := sessionconfig.Store.Delete(entry.SessionID); err != nil {
+		return err
+	}
+	return s.redis.HDel(ctx, sessionIndexKey(userID), sessionHandle(entry.SessionID)).Err()
+}
+
+func (s *SessionService) Revoke(ctx context.Context, userID uint, handle string) error {
+	entry, err := s.get(ctx, userID, handle)
+	if err != nil {
+		return err
+	}
+	if err := s.revoke(ctx, userID, *entry); err != nil {
+		logconfig.Log.Error("Oturum kapatılamadı", zap.Uint("user_id", userID), zap.Error(err))
+		return err
+	}
+	logconfig.Log.Info("Oturum uzaktan kapatıldı", zap.Uint("user_id", userID), zap.String("ip", entry.IP))
+	return nil
+}
+
+func (s *SessionService) RevokeOthers(ctx context.Context, userID uint, currentSessionID string) (int, error) {
+	sessions, err := s.List(ctx, userID, currentSessionID)
+	if err != nil {
+		return 0, err
+	}
+	count := 0
+	for _, entry := range sessions {
+		if entry.Current {
+			continue
+		}
+		if err := s.revoke(ctx, userID, entry); err != nil {
+			logconfig.Log.Error("Oturum kapatılamadı", zap.Uint("user_id", userID), zap.Error(err))
+			return count, err
+		}
+		count++
+	}
+	return count, nil
+}
+
+func (s *SessionService) RevokeAll(ctx context.Context, userID uint) error {
+	_, err := s.RevokeOthers(ctx, userID, "")
+	return err
+}
+
+func (s *SessionService) Unregister(ctx context.Context, userID uint, sessionID string) error {
+	return s.redis.HDel(ctx, sessionIndexKey(userID), sessionHandle(sessionID)).Err()
+}
+
+// describeUserAgent, yaygın tarayıcı ve işletim sistemlerini tanır; tanınmayanlar için "Bilinmeyen cihaz" döner.
+func describeUserAgent(ua string) string {
+	browsers := []struct{ token, name string }{
+		{"Edg/", "Edge"},
+		{"OPR/", "Opera"},
+		{"SamsungBrowser/", "Samsung Internet"},
+		{"YaBrowser/", "Yandex"},
+		{"Firefox/", "Firefox"},
+		{"Chrome/", "Chrome"},
+		{"Safari/", "Safari"},
+	}
+	systems := []struct{ token, name string }{
+		{"Windows", "Windows"},
+		{"iPhone", "iPhone"},
+		{"iPad", "iPad"},
+		{"Android", "Android"},
+		{"Mac OS X", "macOS"},
+		{"Linux", "Linux"},
+	}
+
+	var browser, system string
+	for _, b := range browsers {
+		if strings.Contains(ua, b.token) {
+			browser = b.name
+			break
+		}
+	}
+	for _, o := range systems {
+		if strings.Contains(ua, o.token) {
+			system = o.name
+			break
+		}
+	}
+
+	switch {
+	case browser != "" && system != "":
+		return browser + " · " + system
+	case browser != "":
+		return browser
+	case system != "":
+		return system
+	}
+	return "Bilinmeyen cihaz"
+}
+
+var _ ISessionService = (*SessionService)(nil)
@@ -0,0 +1,261 @@
+package services
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"zatrano/configs/envconfig"
+	"zatrano/configs/logconfig"
+	"zatrano/models"
+
+	"go.uber.org/zap"
+)
+
+// ISmsDriver, bir SMS sağlayıcısıyla konuşan sürücüyü tanımlar.
+type ISmsDriver interface {
+	Name() string
+	// SupportsTurkishShift, sağlayıcının GSM-7 Türkçe single shift tablosunu desteklediğini belirtir.
+	SupportsTurkishShift() bool
+	// AcceptsNumber, sağlayıcının bu E.164 numaraya gönderim yapabildiğini belirtir.
+	AcceptsNumber(to string) bool
+	Send(ctx context.Context, to, body string) (providerMessageID string, err error)
+	// ParseReport, teslim raporu isteğinden mesaj kimliğini ve durumunu çıkarır.
+	ParseReport(value func(key string) string) (providerMessageID, status, errorMessage string)
+}
+
+// newSmsDriver, SMS_DRIVER ortam değişkenine göre sürücü seçer (log, netgsm, twilio).
+func newSmsDriver() ISmsDriver {
+	switch strings.ToLower(envconfig.String("SMS_DRIVER", "log")) {
+	case "netgsm":
+		return &netgsmDriver{
+			userCode: envconfig.String("NETGSM_USERCODE", ""),
+			password: envconfig.String("NETGSM_PASSWORD", ""),
+			header:   envconfig.String("NETGSM_HEADER", ""),
+			endpoint: envconfig.String("NETGSM_ENDPOINT", "https://api.netgsm.com.tr/sms/send/get"),
+			client:   &http.Client{Timeout: 15 * time.Second},
+		}
+	case "twilio":
+		return &twilioDriver{
+			accountSID: envconfig.String("TWILIO_ACCOUNT_SID", ""),
+			authToken:  envconfig.String("TWILIO_AUTH_TOKEN", ""),
+			from:       envconfig.String("TWILIO_FROM", ""),
+			endpoint:   envconfig.String("TWILIO_ENDPOINT", "https://api.twilio.com/2010-04-01"),
+			client:     &http.Client{Timeout: 15 * time.Second},
+		}
+	default:
+		return &logSmsDriver{capturePath: envconfig.String("SMS_CAPTURE_PATH", "")}
+	}
+}
+
+// ---------------------------------------------------------------------
+// log: geliştirme ve test ortamı için mesajları loglar ve dosyaya yazar
+// ---------------------------------------------------------------------
+
+type logSmsDriver struct {
+	capturePath string
+	mu          sync.Mutex
+}
+
+type capturedSms struct {
+	ID     string    `json:"id"`
+	To     string    `json:"to"`
+	Body   string    `json:"body"`
+	SentAt time.Time `json:"sent_at"`
+}
+
+func (d *logSmsDriver) Name() string               { return "log" }
+func (d *logSmsDriver) SupportsTurkishShift() bool { return true }
+func (d *logSmsDriver) AcceptsNumber(string) bool  { return true }
+
+func (d *logSmsDriver) Send(ctx context.Context, to, body string) (string, error) {
+	id, err := randomSmsID()
+	if err != nil {
+		return "", err
+	}
+	logconfig.Log.Info("SMS (log sürücüsü)", zap.String("id", id), zap.String("to", to), zap.String("body", body))
+
+	if d.capturePath == "" {
+		return id, nil
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if err := os.MkdirAll(filepath.Dir(d.capturePath), 0755); err != nil {
+		return "", fmt.Errorf("sms yakalama klasörü oluşturulamadı: %w", err)
+	}
+	f, err := os.OpenFile(d.capturePath, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return "", fmt.Errorf("sms yakalama dosyası açılamadı: %w", err)
+	}
+	defer f.Close()
+
+	line, _ := json.Marshal(capturedSms{ID: id, To: to, Body: body, SentAt: time.Now().UTC()})
+	w := bufio.NewWriter(f)
+	w.Write(line)
+	w.WriteByte('\n')
+	if err := w.Flush(); err != nil {
+		return "", fmt.Errorf("sms yakalama dosyasına yazılamadı: %w", err)
+	}
+	return id, nil
+}
+
+func (d *logSmsDriver) ParseReport(value func(string) string) (string, string, string) {
+	return value("id"), normalizeSmsStatus(value("status")), value("error")
+}
+
+// ---------------------------------------------------------------------
+// netgsm
+// ---------------------------------------------------------------------
+
+type netgsmDriver struct {
+	userCode string
+	password string
+	header   string
+	endpoint string
+	client   *http.Client
+}
+
+func (d *netgsmDriver) Name() string               { return "netgsm" }
+func (d *netgsmDriver) SupportsTurkishShift() bool { return true }
+
+// netgsm yurt içi gönderim yapar; yalnızca +90 ile başlayan numaralar kabul edilir.
+func (d *netgsmDriver) AcceptsNumber(to string) bool {
+	return strings.HasPrefix(to, "+90")
+}
+
+// netgsm numarayı başında 90 olmadan (5xxxxxxxxx) bekler.
+func (d *netgsmDriver) Send(ctx context.Context, to, body string) (string, error) {
+	gsmno, ok := strings.CutPrefix(to, "+90")
+	if !ok {
+		return "", fmt.Errorf("netgsm yalnızca Türkiye numaralarına gönderim yapar: %s", to)
+	}
+	q := url.Values{}
+	q.Set("usercode", d.userCode)
+	q.Set("password", d.password)
+	q.Set("gsmno", gsmno)
+	q.Set("message", body)
+	q.Set("msgheader", d.header)
+	q.Set("dil", "TR")
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, d.endpoint+"?"+q.Encode(), nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("netgsm isteği başarısız: %w", err)
+	}
+	defer resp.Body.Close()
+	raw, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+
+	// Başarılı yanıt: "00 <bulkid>" (01/02 tarih uyarısıyla yine gönderilir)
+	fields := strings.Fields(string(raw))
+	if len(fields) >= 2 && (fields[0] == "00" || fields[0] == "01" || fields[0] == "02") {
+		return fields[1], nil
+	}
+	return "", fmt.Errorf("netgsm hata kodu: %s", strings.TrimSpace(string(raw)))
+}
+
+// netgsm durum kodları: 0 bekliyor, 1 iletildi, 2 zaman aşımı, 3 hatalı numara, 4 iletilemedi
+func (d *netgsmDriver) ParseReport(value func(string) string) (string, string, string) {
+	id := value("bulkid")
+	switch value("status") {
+	case "1":
+		return id, models.SmsStatusDelivered, ""
+	case "0":
+		return id, models.SmsStatusSent, ""
+	default:
+		return id, models.SmsStatusFailed, "netgsm durum kodu: " + value("status")
+	}
+}
+
+// ---------------------------------------------------------------------
+// twilio
+// ---------------------------------------------------------------------
+
+type twilioDriver struct {
+	accountSID string
+	authToken  string
+	from       string
+	endpoint   string
+	client     *http.Client
+}
+
+func (d *twilioDriver) Name() string { return "twilio" }
+
+// Twilio Türkçe karakter içeren mesajları UCS-2 ile gönderir.
+func (d *twilioDriver) SupportsTurkishShift() bool { return false }
+func (d *twilioDriver) AcceptsNumber(string) bool  { return true }
+
+func (d *twilioDriver) Send(ctx context.Context, to, body string) (string, error) {
+	form := url.Values{}
+	form.Set("To", to)
+	form.Set("From", d.from)
+	form.Set("Body", body)
+
+	endpoint := fmt.Sprintf("%s/Accounts/%s/Messages.json", strings.TrimRight(d.endpoint, "/"), d.accountSID)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.SetBasicAuth(d.accountSID, d.authToken)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("twilio isteği başarısız: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var out struct {
+		SID     string `json:"sid"`
+		Message string `json:"message"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 64*1024)).Decode(&out); err != nil {
+		return "", fmt.Errorf("twilio yanıtı okunamadı: %w", err)
+	}
+	if resp.StatusCode >= 300 || out.SID == "" {
+		return "", fmt.Errorf("twilio hatası (%d): %s", resp.StatusCode, out.Message)
+	}
+	return out.SID, nil
+}
+
+func (d *twilioDriver) ParseReport(value func(string) string) (string, string, string) {
+	return value("MessageSid"), normalizeSmsStatus(value("MessageStatus")), value("ErrorCode")
+}
+
+// ---------------------------------------------------------------------
+
+func normalizeSmsStatus(raw string) string {
+	switch strings.ToLower(strings.TrimSpace(raw)) {
+	case "delivered":
+		return models.SmsStatusDelivered
+	case "sent", "sending", "accepted":
+		return models.SmsStatusSent
+	case "queued":
+		return models.SmsStatusQueued
+	default:
+		return models.SmsStatusFailed
+	}
+}
+
+func randomSmsID() (string, error) {
+	b := make([]byte, 12)
+	if _, err := rand.Read(b); err != nil {
+		return "", errors.New("sms kimliği üretilemedi")
+	}
+	return "log-" + hex.EncodeToString(b), nil
+}
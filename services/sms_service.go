@@ -0,0 +1,223 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+	"unicode"
+
+	"zatrano/configs/envconfig"
+	"zatrano/configs/logconfig"
+	"zatrano/models"
+	"zatrano/pkg/smsencoding"
+	"zatrano/pkg/trvalidators"
+	"zatrano/repositories"
+
+	"go.uber.org/zap"
+)
+
+var (
+	ErrSmsEmptyBody      = errors.New("sms içeriği boş olamaz")
+	ErrSmsInvalidNumber  = errors.New("geçersiz telefon numarası")
+	ErrSmsUnsupported    = errors.New("bu numaraya seçili sms sağlayıcısıyla gönderim yapılamaz")
+	ErrSmsQuotaExceeded  = errors.New("aylık sms kotanız doldu")
+	ErrSmsUnknownReport  = errors.New("teslim raporu eşleşen bir mesaj bulunamadı")
+	ErrSmsProviderReport = errors.New("teslim raporu farklı bir sağlayıcıya ait")
+)
+
+// ISmsService, SMS gönderimi, teslim durumu takibi ve kullanıcı kotası işlemlerini tanımlar.
+type ISmsService interface {
+	// Send, mesajı gönderir; userID nil ise sistem gönderimidir ve kotaya tabi değildir.
+	Send(ctx context.Context, userID *uint, to, body string) (*models.SmsMessage, error)
+	HandleDeliveryReport(ctx context.Context, provider string, value func(key string) string) error
+	RemainingQuota(ctx context.Context, userID uint) (int, error)
+	DriverName() string
+}
+
+type SmsService struct {
+	repo         repositories.ISmsRepository
+	driver       ISmsDriver
+	monthlyQuota int
+}
+
+// NewSmsService, SMS_DRIVER ile seçilen sürücüyü kullanır. SMS_MONTHLY_QUOTA 0 ise kota uygulanmaz.
+func NewSmsService() ISmsService {
+	return &SmsService{
+		repo:         repositories.NewSmsRepository(),
+		driver:       newSmsDriver(),
+		monthlyQuota: envconfig.Int("SMS_MONTHLY_QUOTA", 500),
+	}
+}
+
+func (s *SmsService) DriverName() string {
+	return s.driver.Name()
+}
+
+func (s *SmsService) Send(ctx context.Context, userID *uint, to, body string) (*models.SmsMessage, error) {
+	body = strings.TrimSpace(body)
+	if body == "" {
+		return nil, ErrSmsEmptyBody
+	}
+	phone, ok := NormalizeTRPhone(to)
+	if !ok {
+		return nil, ErrSmsInvalidNumber
+	}
+	if !s.driver.AcceptsNumber(phone) {
+		return nil, ErrSmsUnsupported
+	}
+
+	info := smsencoding.Analyze(body, s.driver.SupportsTurkishShift())
+
+	msg := &models.SmsMessage{
+		UserID:   userID,
+		To:       phone,
+		Body:     body,
+		Provider: s.driver.Name(),
+		Encoding: info.Encoding,
+		Segments: uint(info.Segments),
+		Status:   models.SmsStatusQueued,
+	}
+	var err error
+	if userID != nil && s.monthlyQuota > 0 {
+		err = s.repo.ReserveMessage(ctx, msg, monthStart(time.Now()), s.monthlyQuota)
+	} else {
+		err = s.repo.CreateMessage(ctx, msg)
+	}
+	if errors.Is(err, repositories.ErrSmsQuotaExceeded) {
+		return nil, ErrSmsQuotaExceeded
+	}
+	if err != nil {
+		logconfig.Log.Error("SMS kaydı oluşturulamadı", zap.Error(err))
+		return nil, err
+	}
+
+	providerID, sendErr := s.driver.Send(ctx, phone, body)
+	now := time.Now()
+	update := map[string]interface{}{}
+	if sendErr != nil {
+		msg.Status = models.SmsStatusFailed
+		msg.ErrorMessage = sendErr.Error()
+		update["status"] = msg.Status
+		update["error_message"] = msg.ErrorMessage
+		logconfig.Log.Warn("SMS gönderilemedi",
+			zap.String("provider", msg.Provider), zap.String("to", phone), zap.Error(sendErr))
+	} else {
+		msg.Status = models.SmsStatusSent
+		msg.ProviderMessageID = providerID
+		msg.SentAt = &now
+		update["status"] = msg.Status
+		update["provider_message_id"] = providerID
+		update["sent_at"] = now
+	}
+	if err := s.repo.UpdateMessage(ctx, msg.ID, update); err != nil {
+		logconfig.Log.Error("SMS durumu güncellenemedi", zap.Uint("sms_id", msg.ID), zap.Error(err))
+	}
+
+	if sendErr != nil {
+		return msg, sendErr
+	}
+	return msg, nil
+}
+
+// smsStatusOrder, teslim durumlarının sırasıdır. Raporlar geç ya da sırasız gelebilir; durum yalnızca
+// ileri taşınır, "delivered" ve "failed" son durumlardır.
+var smsStatusOrder = map[string]int{
+	models.SmsStatusQueued:    0,
+	models.SmsStatusSent:      1,
+	models.SmsStatusDelivered: 2,
+	models.SmsStatusFailed:    2,
+}
+
+// smsEarlierStatuses, verilen duruma geçilebilecek önceki durumları döner.
+func smsEarlierStatuses(status string) []string {
+	var earlier []string
+	// Son durumlar hiçbir durumdan önce gelmez; yalnızca ara durumlar denetlenir
+	for _, s := range []string{models.SmsStatusQueued, models.SmsStatusSent} {
+		if smsStatusOrder[s] < smsStatusOrder[status] {
+			earlier = append(earlier, s)
+		}
+	}
+	return earlier
+}
+
+// HandleDeliveryReport, sağlayıcının teslim raporunu ilgili mesaja işler. Mesajın mevcut durumundan
+// geride kalan raporlar (ör. "delivered" sonrası gelen "sent") yok sayılır.
+func (s *SmsService) HandleDeliveryReport(ctx context.Context, provider string, value func(key string) string) error {
+	if provider != s.driver.Name() {
+		return ErrSmsProviderReport
+	}
+	providerID, status, errMsg := s.driver.ParseReport(value)
+	if providerID == "" {
+		return ErrSmsUnknownReport
+	}
+
+	msg, err := s.repo.FindByProviderMessageID(ctx, provider, providerID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return ErrSmsUnknownReport
+		}
+		return err
+	}
+
+	update := map[string]interface{}{"status": status}
+	switch status {
+	case models.SmsStatusDelivered:
+		update["delivered_at"] = time.Now()
+	case models.SmsStatusFailed:
+		update["error_message"] = errMsg
+	}
+	advanced, err := s.repo.AdvanceStatus(ctx, msg.ID, smsEarlierStatuses(status), update)
+	if err != nil {
+		return err
+	}
+	if !advanced {
+		logconfig.Log.Info("Geride kalan SMS teslim raporu yok sayıldı",
+			zap.Uint("sms_id", msg.ID), zap.String("status", status))
+	}
+	return nil
+}
+
+// RemainingQuota, kullanıcının bu ay kalan segment hakkını döner. Kota yoksa -1 döner.
+func (s *SmsService) RemainingQuota(ctx context.Context, userID uint) (int, error) {
+	if s.monthlyQuota <= 0 {
+		return -1, nil
+	}
+	used, err := s.repo.SumUserSegmentsSince(ctx, userID, monthStart(time.Now()))
+	if err != nil {
+		return 0, err
+	}
+	remaining := s.monthlyQuota - int(used)
+	if remaining < 0 {
+		remaining = 0
+	}
+	return remaining, nil
+}
+
+func monthStart(now time.Time) time.Time {
+	return time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+}
+
+// NormalizeTRPhone, Türkiye cep numarasını +905xxxxxxxxx biçimine getirir.
+// Başka ülke kodlu E.164 numaralar olduğu gibi kabul edilir.
+func NormalizeTRPhone(raw string) (string, bool) {
+	var digits strings.Builder
+	for _, r := range raw {
+		if unicode.IsDigit(r) {
+			digits.WriteRune(r)
+		}
+	}
+	d := digits.String()
+	international := strings.HasPrefix(strings.TrimSpace(raw), "+") || strings.HasPrefix(d, "00")
+	d = strings.TrimPrefix(d, "00")
+
+	if phone, ok := trvalidators.NormalizeMobile(d); ok {
+		return phone, true
+	}
+	if international && !strings.HasPrefix(d, "90") && len(d) >= 8 && len(d) <= 15 {
+		return "+" + d, true
+	}
+	return "", false
+}
+
+var _ ISmsService = (*SmsService)(nil)
@@ -0,0 +1,158 @@
+package services
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"zatrano/models"
+	"zatrano/repositories"
+)
+
+// fakeSmsRepo, mesajları bellekte tutan ISmsRepository'dir.
+type fakeSmsRepo struct {
+	messages map[uint]*models.SmsMessage
+}
+
+func (r *fakeSmsRepo) CreateMessage(ctx context.Context, msg *models.SmsMessage) error {
+	msg.ID = uint(len(r.messages) + 1)
+	m := *msg
+	r.messages[msg.ID] = &m
+	return nil
+}
+
+func (r *fakeSmsRepo) ReserveMessage(ctx context.Context, msg *models.SmsMessage, since time.Time, quota int) error {
+	return r.CreateMessage(ctx, msg)
+}
+
+func (r *fakeSmsRepo) apply(msg *models.SmsMessage, data map[string]interface{}) {
+	for column, value := range data {
+		switch column {
+		case "status":
+			msg.Status = value.(string)
+		case "provider_message_id":
+			msg.ProviderMessageID = value.(string)
+		case "error_message":
+			msg.ErrorMessage = value.(string)
+		case "delivered_at":
+			at := value.(time.Time)
+			msg.DeliveredAt = &at
+		}
+	}
+}
+
+func (r *fakeSmsRepo) UpdateMessage(ctx context.Context, id uint, data map[string]interface{}) error {
+	msg, ok := r.messages[id]
+	if !ok {
+		return repositories.ErrNotFound
+	}
+	r.apply(msg, data)
+	return nil
+}
+
+func (r *fakeSmsRepo) AdvanceStatus(ctx context.Context, id uint, from []string, data map[string]interface{}) (bool, error) {
+	msg, ok := r.messages[id]
+	if !ok || !slices.Contains(from, msg.Status) {
+		return false, nil
+	}
+	r.apply(msg, data)
+	return true, nil
+}
+
+func (r *fakeSmsRepo) FindByProviderMessageID(ctx context.Context, provider, providerMessageID string) (*models.SmsMessage, error) {
+	for _, msg := range r.messages {
+		if msg.Provider == provider && msg.ProviderMessageID == providerMessageID {
+			m := *msg
+			return &m, nil
+		}
+	}
+	return nil, repositories.ErrNotFound
+}
+
+func (r *fakeSmsRepo) SumUserSegmentsSince(ctx context.Context, userID uint, since time.Time) (int64, error) {
+	return 0, nil
+}
+
+func TestLogSmsDriverCapturesMessages(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sms", "captured.jsonl")
+	driver := &logSmsDriver{capturePath: path}
+
+	sent := map[string]string{}
+	for _, body := range []string{"Randevunuz onaylandı.", "Şifreniz: 123456"} {
+		id, err := driver.Send(context.Background(), "+905321234567", body)
+		if err != nil {
+			t.Fatalf("Send: %v", err)
+		}
+		sent[id] = body
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("yakalama dosyası açılamadı: %v", err)
+	}
+	defer f.Close()
+	var lines int
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var captured capturedSms
+		if err := json.Unmarshal(scanner.Bytes(), &captured); err != nil {
+			t.Fatalf("satır çözülemedi: %v", err)
+		}
+		if captured.To != "+905321234567" || sent[captured.ID] != captured.Body || captured.SentAt.IsZero() {
+			t.Fatalf("beklenmeyen kayıt: %+v", captured)
+		}
+		lines++
+	}
+	if lines != len(sent) {
+		t.Fatalf("%d satır yazıldı, beklenen %d", lines, len(sent))
+	}
+}
+
+func newSmsTestService() (*SmsService, *fakeSmsRepo) {
+	repo := &fakeSmsRepo{messages: make(map[uint]*models.SmsMessage)}
+	return &SmsService{repo: repo, driver: &logSmsDriver{}}, repo
+}
+
+// report, log sürücüsünün teslim raporunu işler.
+func report(t *testing.T, s *SmsService, id, status string) {
+	t.Helper()
+	values := map[string]string{"id": id, "status": status}
+	if err := s.HandleDeliveryReport(context.Background(), "log", func(key string) string { return values[key] }); err != nil {
+		t.Fatalf("HandleDeliveryReport(%s): %v", status, err)
+	}
+}
+
+func TestSmsDeliveryReportsOnlyMoveForward(t *testing.T) {
+	tests := []struct {
+		name    string
+		reports []string
+		want    string
+	}{
+		{"teslim edildi", []string{"delivered"}, models.SmsStatusDelivered},
+		{"teslimden sonra geç gelen gönderildi", []string{"delivered", "sent"}, models.SmsStatusDelivered},
+		{"teslimden sonra gelen kuyrukta", []string{"delivered", "queued"}, models.SmsStatusDelivered},
+		{"teslimden sonra gelen başarısız", []string{"delivered", "failed"}, models.SmsStatusDelivered},
+		{"başarısızdan sonra gelen teslim", []string{"failed", "delivered"}, models.SmsStatusFailed},
+		{"gönderildiden sonra teslim", []string{"sent", "delivered"}, models.SmsStatusDelivered},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, repo := newSmsTestService()
+			msg, err := s.Send(context.Background(), nil, "05321234567", "Randevunuz onaylandı.")
+			if err != nil {
+				t.Fatalf("Send: %v", err)
+			}
+			for _, status := range tt.reports {
+				report(t, s, msg.ProviderMessageID, status)
+			}
+			if got := repo.messages[msg.ID].Status; got != tt.want {
+				t.Fatalf("durum = %s, beklenen %s", got, tt.want)
+			}
+		})
+	}
+}
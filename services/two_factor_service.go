@@ -0,0 +1,297 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+
+	"zatrano/configs/envconfig"
+	"zatrano/configs/logconfig"
+	"zatrano/models"
+	"zatrano/pkg/totp"
+	"zatrano/repositories"
+
+	"github.com/skip2/go-qrcode"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrTwoFactorInvalidCode    = errors.New("doğrulama kodu hatalı")
+	ErrTwoFactorCodeUsed       = errors.New("bu doğrulama kodu zaten kullanıldı, yeni kodu bekleyin")
+	ErrTwoFactorAlreadyEnabled = errors.New("iki adımlı doğrulama zaten etkin")
+	ErrTwoFactorNotEnabled     = errors.New("iki adımlı doğrulama etkin değil")
+	ErrTwoFactorRequired       = errors.New("rolünüz iki adımlı doğrulamayı zorunlu kılıyor")
+)
+
+const (
+	recoveryCodeCount = 10
+	// totpSkew, saat kaymalarını tolere etmek için önceki/sonraki kaç adımın kabul edileceğidir.
+	totpSkew = 1
+)
+
+// TwoFactorStatus, profil sayfasındaki iki adımlı doğrulama bölümünün durumunu taşır.
+type TwoFactorStatus struct {
+	Enabled           bool
+	Required          bool
+	EnabledAt         *time.Time
+	RecoveryCodesLeft int64
+	TrustedDevices    int64
+}
+
+// ITwoFactorService, RFC 6238 TOTP tabanlı iki adımlı doğrulamayı, tek kullanımlık kurtarma kodlarını
+// ve "bu cihaza güven" kayıtlarını yönetir. Parola doğrulaması (AuthService.Authenticate) ile
+// oturum açılması arasında çalışır.
+type ITwoFactorService interface {
+	NewSecret() (string, error)
+	// ProvisioningQR, otpauth:// adresini içeren QR kodunu PNG olarak üretir; gizli anahtar sunucudan çıkmaz.
+	ProvisioningQR(account, secret string) ([]byte, error)
+	Enable(ctx context.Context, user *models.User, secret, code string) ([]string, error)
+	// Verify, TOTP kodunu ya da kurtarma kodunu doğrular; kurtarma kodu kullanıldıysa true döner.
+	Verify(ctx context.Context, user *models.User, code string) (bool, error)
+	Disable(ctx context.Context, user *models.User, code string) error
+	RegenerateRecoveryCodes(ctx context.Context, user *models.User, code string) ([]string, error)
+	// Reset, kodlarını kaybeden kullanıcı için yöneticinin iki adımlı doğrulamayı kapatmasıdır.
+	Reset(ctx context.Context, userID uint) error
+	Status(ctx context.Context, user *models.User) (*TwoFactorStatus, error)
+
+	TrustDuration() time.Duration
+	TrustDevice(ctx context.Context, userID uint, userAgent, ip string) (string, error)
+	IsTrustedDevice(ctx context.Context, userID uint, token string) bool
+	ForgetDevices(ctx context.Context, userID uint) error
+}
+
+type TwoFactorService struct {
+	repo      repositories.ITwoFactorRepository
+	issuer    string
+	trustDays int
+	now       func() time.Time
+}
+
+// NewTwoFactorService; TWO_FACTOR_ISSUER doğrulama uygulamasında görünen adı,
+// TWO_FACTOR_TRUST_DAYS güvenilen cihazların ikinci adımı kaç gün atlayacağını belirler.
+func NewTwoFactorService() ITwoFactorService {
+	return &TwoFactorService{
+		repo:      repositories.NewTwoFactorRepository(),
+		issuer:    envconfig.String("TWO_FACTOR_ISSUER", "ZATRANO"),
+		trustDays: envconfig.Int("TWO_FACTOR_TRUST_DAYS", 30),
+		now:       time.Now,
+	}
+}
+
+func hashSecretToken(v string) string {
+	sum := sha256.Sum256([]byte(v))
+	return hex.EncodeToString(sum[:])
+}
+
+// normalizeRecoveryCode, kullanıcının tire/boşluk/büyük harfle girdiği kodu saklanan biçime çevirir.
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(strings.TrimSpace(code))
+	return strings.NewReplacer("-", "", " ", "").Replace(code)
+}
+
+func isNumericCode(code string) bool {
+	if len(code) != totp.Digits {
+		return false
+	}
+	for _, r := range code {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// generateRecoveryCodes, "abcde-fghij" biçiminde kodlar ve saklanacak özetlerini üretir.
+func generateRecoveryCodes() ([]string, []string, error) {
+	enc := base32.StdEncoding.WithPadding(base32.NoPadding)
+	codes := make([]string, 0, recoveryCodeCount)
+	hashes := make([]string, 0, recoveryCodeCount)
+	for i := 0; i < recoveryCodeCount; i++ {
+		buf := make([]byte, 7)
+		if _, err := rand.Read(buf); err != nil {
+			return nil, nil, err
+		}
+		raw := strings.ToLower(enc.EncodeToString(buf))[:10]
+		codes = append(codes, raw[:5]+"-"+raw[5:])
+		hashes = append(hashes, hashSecretToken(raw))
+	}
+	return codes, hashes, nil
+}
+
+func (s *TwoFactorService) NewSecret() (string, error) {
+	return totp.GenerateSecret()
+}
+
+func (s *TwoFactorService) ProvisioningQR(account, secret string) ([]byte, error) {
+	return qrcode.Encode(totp.URI(s.issuer, account, secret), qrcode.Medium, 220)
+}
+
+func (s *TwoFactorService) Enable(ctx context.Context, user *models.User, secret, code string) ([]string, error) {
+	if user.TwoFactorEnabled() {
+		return nil, ErrTwoFactorAlreadyEnabled
+	}
+	step, ok := totp.Validate(secret, code, s.now(), totpSkew)
+	if !ok {
+		return nil, ErrTwoFactorInvalidCode
+	}
+
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.Enable(ctx, user.ID, secret, step, hashes); err != nil {
+		logconfig.Log.Error("İki adımlı doğrulama etkinleştirilemedi", zap.Uint("user_id", user.ID), zap.Error(err))
+		return nil, err
+	}
+	logconfig.Log.Info("İki adımlı doğrulama etkinleştirildi", zap.Uint("user_id", user.ID))
+	return codes, nil
+}
+
+func (s *TwoFactorService) Verify(ctx context.Context, user *models.User, code string) (bool, error) {
+	if !user.TwoFactorEnabled() {
+		return false, ErrTwoFactorNotEnabled
+	}
+
+	code = normalizeRecoveryCode(code)
+	if isNumericCode(code) {
+		step, ok := totp.Validate(user.TwoFactorSecret, code, s.now(), totpSkew)
+		if !ok {
+			return false, ErrTwoFactorInvalidCode
+		}
+		advanced, err := s.repo.AdvanceStep(ctx, user.ID, step)
+		if err != nil {
+			return false, err
+		}
+		if !advanced {
+			return false, ErrTwoFactorCodeUsed
+		}
+		return false, nil
+	}
+
+	used, err := s.repo.UseRecoveryCode(ctx, user.ID, hashSecretToken(code))
+	if err != nil {
+		return false, err
+	}
+	if !used {
+		return false, ErrTwoFactorInvalidCode
+	}
+	logconfig.Log.Warn("Kurtarma kodu ile giriş yapıldı", zap.Uint("user_id", user.ID))
+	return true, nil
+}
+
+func (s *TwoFactorService) Disable(ctx context.Context, user *models.User, code string) error {
+	if !user.TwoFactorEnabled() {
+		return ErrTwoFactorNotEnabled
+	}
+	if user.UserType.RequireTwoFactor {
+		return ErrTwoFactorRequired
+	}
+	if _, err := s.Verify(ctx, user, code); err != nil {
+		return err
+	}
+	if err := s.repo.Disable(ctx, user.ID); err != nil {
+		logconfig.Log.Error("İki adımlı doğrulama kapatılamadı", zap.Uint("user_id", user.ID), zap.Error(err))
+		return err
+	}
+	logconfig.Log.Info("İki adımlı doğrulama kapatıldı", zap.Uint("user_id", user.ID))
+	return nil
+}
+
+func (s *TwoFactorService) RegenerateRecoveryCodes(ctx context.Context, user *models.User, code string) ([]string, error) {
+	if _, err := s.Verify(ctx, user, code); err != nil {
+		return nil, err
+	}
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.ReplaceRecoveryCodes(ctx, user.ID, hashes); err != nil {
+		logconfig.Log.Error("Kurtarma kodları yenilenemedi", zap.Uint("user_id", user.ID), zap.Error(err))
+		return nil, err
+	}
+	return codes, nil
+}
+
+func (s *TwoFactorService) Reset(ctx context.Context, userID uint) error {
+	if err := s.repo.Disable(ctx, userID); err != nil {
+		logconfig.Log.Error("İki adımlı doğrulama sıfırlanamadı", zap.Uint("user_id", userID), zap.Error(err))
+		return err
+	}
+	logconfig.Log.Warn("İki adımlı doğrulama yönetici tarafından sıfırlandı", zap.Uint("user_id", userID))
+	return nil
+}
+
+func (s *TwoFactorService) Status(ctx context.Context, user *models.User) (*TwoFactorStatus, error) {
+	status := &TwoFactorStatus{
+		Enabled:   user.TwoFactorEnabled(),
+		Required:  user.UserType.RequireTwoFactor,
+		EnabledAt: user.TwoFactorEnabledAt,
+	}
+	if !status.Enabled {
+		return status, nil
+	}
+
+	var err error
+	if status.RecoveryCodesLeft, err = s.repo.CountUnusedRecoveryCodes(ctx, user.ID); err != nil {
+		return nil, err
+	}
+	if status.TrustedDevices, err = s.repo.CountTrustedDevices(ctx, user.ID); err != nil {
+		return nil, err
+	}
+	return status, nil
+}
+
+func (s *TwoFactorService) TrustDuration() time.Duration {
+	return time.Duration(s.trustDays) * 24 * time.Hour
+}
+
+// TrustDevice, çereze yazılacak rastgele anahtarı döner; veritabanında yalnızca özeti tutulur.
+func (s *TwoFactorService) TrustDevice(ctx context.Context, userID uint, userAgent, ip string) (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	token := hex.EncodeToString(buf)
+
+	if r := []rune(userAgent); len(r) > 255 {
+		userAgent = string(r[:255])
+	}
+	device := &models.TrustedDevice{
+		UserID:    userID,
+		TokenHash: hashSecretToken(token),
+		UserAgent: userAgent,
+		IP:        ip,
+		ExpiresAt: s.now().Add(s.TrustDuration()),
+	}
+	if err := s.repo.CreateTrustedDevice(ctx, device); err != nil {
+		logconfig.Log.Error("Güvenilen cihaz kaydedilemedi", zap.Uint("user_id", userID), zap.Error(err))
+		return "", err
+	}
+	return token, nil
+}
+
+func (s *TwoFactorService) IsTrustedDevice(ctx context.Context, userID uint, token string) bool {
+	if token == "" || s.trustDays <= 0 {
+		return false
+	}
+	device, err := s.repo.FindTrustedDevice(ctx, userID, hashSecretToken(token))
+	if err != nil {
+		if !errors.Is(err, repositories.ErrNotFound) {
+			logconfig.Log.Error("Güvenilen cihaz sorgulanamadı", zap.Uint("user_id", userID), zap.Error(err))
+		}
+		return false
+	}
+	_ = s.repo.TouchTrustedDevice(ctx, device.ID)
+	return true
+}
+
+func (s *TwoFactorService) ForgetDevices(ctx context.Context, userID uint) error {
+	return s.repo.DeleteTrustedDevices(ctx, userID)
+}
+
+var _ ITwoFactorService = (*TwoFactorService)(nil)
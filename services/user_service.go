@@ -22,11 +22,12 @@ type IUserService interface {
 }
 
 type UserService struct {
-	repo repositories.IUserRepository
+	repo     repositories.IUserRepository
+	sessions ISessionService
 }
 
 func NewUserService() IUserService {
-	return &UserService{repo: repositories.NewUserRepository()}
+	return &UserService{repo: repositories.NewUserRepository(), sessions: NewSessionService()}
 }
 
 func (s *UserService) GetAllUsers(ctx context.Context, params requests.UserListParams) (*requests.PaginatedResult, error) {
@@ -64,15 +65,11 @@ func (s *UserService) CreateUser(ctx context.Context, req requests.CreateUserReq
 		BaseModel: models.BaseModel{
 			IsActive: converted.IsActive != nil && *converted.IsActive,
 		},
-		Name:              converted.Name,
-		Email:             converted.Email,
-		Password:          req.Password,
-		UserTypeID:        *converted.UserTypeID,
-		ResetToken:        converted.ResetToken,
-		EmailVerified:     converted.EmailVerified != nil && *converted.EmailVerified,
-		VerificationToken: converted.VerificationToken,
-		Provider:          converted.Provider,
-		ProviderID:        converted.ProviderID,
+		Name:          converted.Name,
+		Email:         converted.Email,
+		Password:      req.Password,
+		UserTypeID:    *converted.UserTypeID,
+		EmailVerified: converted.EmailVerified != nil && *converted.EmailVerified,
 	}
 
 	// Şifre kontrolü ve hash'leme
@@ -105,15 +102,11 @@ func (s *UserService) UpdateUser(ctx context.Context, id uint, req requests.Upda
 
 	// Update data hazırla
 	updateData := map[string]interface{}{
-		"name":               converted.Name,
-		"email":              converted.Email,
-		"is_active":          converted.IsActive != nil && *converted.IsActive,
-		"user_type_id":       *converted.UserTypeID,
-		"reset_token":        converted.ResetToken,
-		"email_verified":     converted.EmailVerified != nil && *converted.EmailVerified,
-		"verification_token": converted.VerificationToken,
-		"provider":           converted.Provider,
-		"provider_id":        converted.ProviderID,
+		"name":           converted.Name,
+		"email":          converted.Email,
+		"is_active":      converted.IsActive != nil && *converted.IsActive,
+		"user_type_id":   *converted.UserTypeID,
+		"email_verified": converted.EmailVerified != nil && *converted.EmailVerified,
 	}
 
 	// Şifre değişikliği (optional)
@@ -126,7 +119,17 @@ func (s *UserService) UpdateUser(ctx context.Context, id uint, req requests.Upda
 	}
 
 	// Repository'de güncelle
-	return s.repo.UpdateUser(ctx, id, updateData)
+	if err := s.repo.UpdateUser(ctx, id, updateData); err != nil {
+		return err
+	}
+
+	// Parolası değiştirilen kullanıcının açık oturumları kapatılır
+	if req.Password != "" {
+		if err := s.sessions.RevokeAll(ctx, id); err != nil {
+			logconfig.Log.Error("Parola değişikliği sonrası oturumlar kapatılamadı", zap.Uint("user_id", id), zap.Error(err))
+		}
+	}
+	return nil
 }
 
 func (s *UserService) DeleteUser(ctx context.Context, id uint) error {
@@ -0,0 +1,189 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"zatrano/configs/envconfig"
+	"zatrano/configs/logconfig"
+	"zatrano/models"
+	"zatrano/repositories"
+
+	"go.uber.org/zap"
+)
+
+var (
+	ErrUserTokenInvalid      = errors.New("bağlantı geçersiz ya da süresi dolmuş")
+	ErrUserTokenWrongBrowser = errors.New("bağlantı bu tarayıcıda istenmedi")
+)
+
+// IUserTokenService, e-postayla gönderilen tek kullanımlık bağlantı anahtarlarını üretir ve doğrular.
+type IUserTokenService interface {
+	// Issue, bağlantıya yazılacak anahtarı döner; aynı amaçla daha önce verilmiş anahtarlar geçersiz olur.
+	Issue(ctx context.Context, userID uint, purpose, userAgent, ip string) (string, error)
+	// IssueForBrowser, anahtarı browser değerine bağlar; anahtar yalnızca aynı değerle kullanılabilir.
+	IssueForBrowser(ctx context.Context, userID uint, purpose, browser, userAgent, ip string) (string, error)
+	// Check, anahtarı kullanmadan geçerliliğini denetler (ör. formu göstermeden önce).
+	Check(ctx context.Context, purpose, token string) (*models.UserToken, error)
+	// Consume, anahtarı kullanılmış olarak işaretler ve ait olduğu kullanıcıyı taşıyan kaydı döner.
+	Consume(ctx context.Context, purpose, token, userAgent, ip string) (*models.UserToken, error)
+	// ConsumeForBrowser, tarayıcıya bağlı anahtarı kullanır. Tarayıcı eşleşmezse anahtar harcanmaz ve
+	// ErrUserTokenWrongBrowser döner; böylece bağlantıyı önceden açan e-posta tarayıcıları onu geçersiz kılamaz.
+	ConsumeForBrowser(ctx context.Context, purpose, token, browser, userAgent, ip string) (*models.UserToken, error)
+	PurgeExpired(ctx context.Context) (int64, error)
+	// RunCleanup, süresi dolan anahtarları ctx kapanana kadar belirli aralıklarla siler.
+	RunCleanup(ctx context.Context, interval time.Duration)
+}
+
+type UserTokenService struct {
+	repo repositories.IUserTokenRepository
+	ttl  map[string]time.Duration
+	now  func() time.Time
+}
+
+func NewUserTokenService() IUserTokenService {
+	return &UserTokenService{
+		repo: repositories.NewUserTokenRepository(),
+		ttl: map[string]time.Duration{
+			models.UserTokenPurposePasswordReset:     time.Duration(envconfig.Int("PASSWORD_RESET_TOKEN_MINUTES", 60)) * time.Minute,
+			models.UserTokenPurposeEmailVerification: time.Duration(envconfig.Int("EMAIL_VERIFICATION_TOKEN_HOURS", 48)) * time.Hour,
+			models.UserTokenPurposeMagicLogin:        time.Duration(envconfig.Int("MAGIC_LINK_TOKEN_MINUTES", 15)) * time.Minute,
+		},
+		now: time.Now,
+	}
+}
+
+func (s *UserTokenService) Issue(ctx context.Context, userID uint, purpose, userAgent, ip string) (string, error) {
+	return s.issue(ctx, userID, purpose, "", userAgent, ip)
+}
+
+func (s *UserTokenService) IssueForBrowser(ctx context.Context, userID uint, purpose, browser, userAgent, ip string) (string, error) {
+	if browser == "" {
+		return "", errors.New("tarayıcı bağlama değeri boş olamaz")
+	}
+	return s.issue(ctx, userID, purpose, hashSecretToken(browser), userAgent, ip)
+}
+
+func (s *UserTokenService) issue(ctx context.Context, userID uint, purpose, browserHash, userAgent, ip string) (string, error) {
+	ttl, ok := s.ttl[purpose]
+	if !ok {
+		return "", errors.New("bilinmeyen anahtar amacı: " + purpose)
+	}
+
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		logconfig.Log.Error("Bağlantı anahtarı oluşturulamadı", zap.Error(err))
+		return "", err
+	}
+	raw := hex.EncodeToString(buf)
+
+	if r := []rune(userAgent); len(r) > 255 {
+		userAgent = string(r[:255])
+	}
+	token := &models.UserToken{
+		UserID:      userID,
+		Purpose:     purpose,
+		TokenHash:   hashSecretToken(raw),
+		ExpiresAt:   s.now().Add(ttl),
+		BrowserHash: browserHash,
+		IP:          ip,
+		UserAgent:   userAgent,
+	}
+	if err := s.repo.Create(ctx, token); err != nil {
+		logconfig.Log.Error("Bağlantı anahtarı kaydedilemedi", zap.Uint("user_id", userID), zap.String("purpose", purpose), zap.Error(err))
+		return "", err
+	}
+	return raw, nil
+}
+
+func (s *UserTokenService) Check(ctx context.Context, purpose, token string) (*models.UserToken, error) {
+	if token == "" {
+		return nil, ErrUserTokenInvalid
+	}
+	found, err := s.repo.FindValid(ctx, purpose, hashSecretToken(token), s.now())
+	if err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return nil, ErrUserTokenInvalid
+		}
+		logconfig.Log.Error("Bağlantı anahtarı sorgulanamadı", zap.String("purpose", purpose), zap.Error(err))
+		return nil, err
+	}
+	return found, nil
+}
+
+func (s *UserTokenService) Consume(ctx context.Context, purpose, token, userAgent, ip string) (*models.UserToken, error) {
+	return s.consume(ctx, purpose, token, "", userAgent, ip)
+}
+
+func (s *UserTokenService) ConsumeForBrowser(ctx context.Context, purpose, token, browser, userAgent, ip string) (*models.UserToken, error) {
+	if browser == "" {
+		// Çerez hiç yoksa anahtar yine de geçerli olabilir; harcamadan ayırt etmek için denetlenir
+		if _, err := s.Check(ctx, purpose, token); err != nil {
+			return nil, err
+		}
+		logconfig.Log.Warn("Tarayıcıya bağlı bağlantı çerezsiz açıldı", zap.String("purpose", purpose), zap.String("ip", ip))
+		return nil, ErrUserTokenWrongBrowser
+	}
+	return s.consume(ctx, purpose, token, hashSecretToken(browser), userAgent, ip)
+}
+
+func (s *UserTokenService) consume(ctx context.Context, purpose, token, browserHash, userAgent, ip string) (*models.UserToken, error) {
+	if token == "" {
+		return nil, ErrUserTokenInvalid
+	}
+	if r := []rune(userAgent); len(r) > 255 {
+		userAgent = string(r[:255])
+	}
+	used, err := s.repo.Consume(ctx, purpose, hashSecretToken(token), browserHash, userAgent, ip, s.now())
+	if err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			// Anahtar geçerli ama başka bir tarayıcıya bağlıysa kullanıcıya bunu söyleyebiliriz
+			if browserHash != "" {
+				if found, checkErr := s.Check(ctx, purpose, token); checkErr == nil && found.BrowserHash != browserHash {
+					logconfig.Log.Warn("Bağlantı farklı bir tarayıcıda açıldı", zap.String("purpose", purpose), zap.String("ip", ip))
+					return nil, ErrUserTokenWrongBrowser
+				}
+			}
+			logconfig.Log.Warn("Geçersiz ya da süresi dolmuş bağlantı anahtarı", zap.String("purpose", purpose), zap.String("ip", ip))
+			return nil, ErrUserTokenInvalid
+		}
+		logconfig.Log.Error("Bağlantı anahtarı kullanılamadı", zap.String("purpose", purpose), zap.Error(err))
+		return nil, err
+	}
+	return used, nil
+}
+
+func (s *UserTokenService) PurgeExpired(ctx context.Context) (int64, error) {
+	deleted, err := s.repo.DeleteExpired(ctx, s.now())
+	if err != nil {
+		logconfig.Log.Error("Süresi dolan bağlantı anahtarları silinemedi", zap.Error(err))
+		return 0, err
+	}
+	if deleted > 0 {
+		logconfig.Log.Info("Süresi dolan bağlantı anahtarları silindi", zap.Int64("count", deleted))
+	}
+	return deleted, nil
+}
+
+func (s *UserTokenService) RunCleanup(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	_, _ = s.PurgeExpired(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			_, _ = s.PurgeExpired(ctx)
+		}
+	}
+}
+
+var _ IUserTokenService = (*UserTokenService)(nil)
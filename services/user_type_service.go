@@ -4,29 +4,45 @@ import (
 	"context"
 	"errors"
 
+	"zatrano/configs/envconfig"
 	"zatrano/configs/logconfig"
 	"zatrano/models"
+	"zatrano/pkg/currentuser"
 	"zatrano/repositories"
 	"zatrano/requests"
 
 	"go.uber.org/zap"
 )
 
+var (
+	ErrUnknownPermission = errors.New("tanımsız yetki seçildi")
+	ErrOwnRoleLockout    = errors.New("kendi rolünüzden kullanıcı türü yönetimi yetkisini kaldıramaz veya rolü pasifleştiremezsiniz")
+	ErrUserTypeNotFound  = errors.New("kullanıcı tipi bulunamadı")
+	ErrOwnRoleDelete     = errors.New("kendi rolünüzü silemezsiniz")
+	ErrDefaultUserType   = errors.New("varsayılan kullanıcı rolü bulunamadı")
+)
+
 type IUserTypeService interface {
 	GetAllUserTypes(ctx context.Context, params requests.UserTypeListParams) (*requests.PaginatedResult, error)
 	GetUserTypeByID(ctx context.Context, id uint) (*models.UserType, error)
+	GetUserTypeOptions(ctx context.Context) ([]models.UserType, error)
 	CreateUserType(ctx context.Context, req requests.UserTypeRequest) error
 	UpdateUserType(ctx context.Context, id uint, req requests.UserTypeRequest) error
 	DeleteUserType(ctx context.Context, id uint) error
+	// DefaultUserTypeID, kendi kaydolan ve sosyal girişle oluşturulan kullanıcılara verilecek rolü döner.
+	DefaultUserTypeID(ctx context.Context) (uint, error)
 }
 
 type UserTypeService struct {
-	repo repositories.IUserTypeRepository
+	repo            repositories.IUserTypeRepository
+	defaultTypeName string
 }
 
+// NewUserTypeService; DEFAULT_USER_TYPE yeni kullanıcılara verilecek rolün adıdır.
 func NewUserTypeService() IUserTypeService {
 	return &UserTypeService{
-		repo: repositories.NewUserTypeRepository(),
+		repo:            repositories.NewUserTypeRepository(),
+		defaultTypeName: envconfig.String("DEFAULT_USER_TYPE", "User"),
 	}
 }
 
@@ -46,19 +62,38 @@ func (s *UserTypeService) GetUserTypeByID(ctx context.Context, id uint) (*models
 	userType, err := s.repo.GetUserTypeByID(ctx, id)
 	if err != nil {
 		logconfig.Log.Warn("Kullanıcı Tipi bulunamadı", zap.Uint("user_type_id", id), zap.Error(err))
-		return nil, errors.New("kullanıcı tipi bulunamadı")
+		return nil, ErrUserTypeNotFound
 	}
 	return userType, nil
 }
 
+// GetUserTypeOptions, kullanıcı formlarındaki rol seçimleri için tüm rolleri ada göre sıralı döner.
+func (s *UserTypeService) GetUserTypeOptions(ctx context.Context) ([]models.UserType, error) {
+	userTypes, err := s.repo.GetUserTypeOptions(ctx)
+	if err != nil {
+		logconfig.Log.Error("Kullanıcı tipleri getirilemedi", zap.Error(err))
+		return nil, err
+	}
+	return userTypes, nil
+}
+
 func (s *UserTypeService) CreateUserType(ctx context.Context, req requests.UserTypeRequest) error {
 	// Request'i convert et
 	converted := req.BaseUserTypeRequest.Convert()
 
+	if err := checkPermissions(converted.Permissions); err != nil {
+		return err
+	}
+
 	// Model oluştur
 	userType := &models.UserType{
-		BaseModel: models.BaseModel{IsActive: false}, // default
-		Name:      converted.Name,
+		BaseModel:        models.BaseModel{IsActive: false}, // default
+		Name:             converted.Name,
+		LandingPage:      converted.LandingPage,
+		RequireTwoFactor: converted.RequireTwoFactor,
+	}
+	for _, permission := range converted.Permissions {
+		userType.Permissions = append(userType.Permissions, models.UserTypePermission{Permission: permission})
 	}
 
 	// IsActive nil kontrolü
@@ -74,22 +109,63 @@ func (s *UserTypeService) UpdateUserType(ctx context.Context, id uint, req reque
 	// Mevcut user type'ı kontrol et
 	_, err := s.repo.GetUserTypeByID(ctx, id)
 	if err != nil {
-		return errors.New("kullanıcı tipi bulunamadı")
+		return ErrUserTypeNotFound
 	}
 
 	// Request'i convert et
 	converted := req.BaseUserTypeRequest.Convert()
+	if err := checkPermissions(converted.Permissions); err != nil {
+		return err
+	}
+	isActive := converted.IsActive != nil && *converted.IsActive
+
+	// Yöneticinin kendi rolünü kilitlemesini engelle
+	if currentuser.FromContext(ctx).UserTypeID == id {
+		keepsManagement := false
+		for _, permission := range converted.Permissions {
+			if permission == models.PermissionUserTypesManage {
+				keepsManagement = true
+			}
+		}
+		if !keepsManagement || !isActive {
+			return ErrOwnRoleLockout
+		}
+	}
 
 	// Update data hazırla
 	updateData := map[string]interface{}{
-		"name":      converted.Name,
-		"is_active": converted.IsActive != nil && *converted.IsActive,
+		"name":               converted.Name,
+		"is_active":          isActive,
+		"landing_page":       converted.LandingPage,
+		"require_two_factor": converted.RequireTwoFactor,
 	}
 
 	// Repository'de güncelle
-	return s.repo.UpdateUserType(ctx, id, updateData)
+	return s.repo.UpdateUserType(ctx, id, updateData, converted.Permissions)
+}
+
+func checkPermissions(permissions []string) error {
+	for _, permission := range permissions {
+		if !models.IsKnownPermission(permission) {
+			logconfig.Log.Warn("Tanımsız yetki", zap.String("permission", permission))
+			return ErrUnknownPermission
+		}
+	}
+	return nil
 }
 
 func (s *UserTypeService) DeleteUserType(ctx context.Context, id uint) error {
+	if currentuser.FromContext(ctx).UserTypeID == id {
+		return ErrOwnRoleDelete
+	}
 	return s.repo.DeleteUserType(ctx, id)
 }
+
+func (s *UserTypeService) DefaultUserTypeID(ctx context.Context) (uint, error) {
+	userType, err := s.repo.GetUserTypeByName(ctx, s.defaultTypeName)
+	if err != nil {
+		logconfig.Log.Error("Varsayılan kullanıcı rolü bulunamadı", zap.String("name", s.defaultTypeName), zap.Error(err))
+		return 0, ErrDefaultUserType
+	}
+	return userType.ID, nil
+}
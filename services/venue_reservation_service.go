@@ -0,0 +1,324 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"zatrano/configs/envconfig"
+	"zatrano/configs/logconfig"
+	"zatrano/models"
+	"zatrano/repositories"
+	"zatrano/requests"
+
+	"go.uber.org/zap"
+)
+
+var (
+	ErrVenueNotFound             = errors.New("mekan bulunamadı")
+	ErrVenueNoCapacity           = errors.New("rezervasyon alabilmek için işletmenin kapasitesi tanımlanmalıdır")
+	ErrVenueReservationNotFound  = errors.New("rezervasyon bulunamadı")
+	ErrVenueSessionTaken         = errors.New("seçilen tarih ve seans dolu")
+	ErrVenueOverCapacity         = errors.New("davetli sayısı mekan kapasitesini aşıyor")
+	ErrVenueInvalidDate          = errors.New("geçersiz tarih")
+	ErrVenuePastDate             = errors.New("geçmiş bir tarihe rezervasyon yapılamaz")
+	ErrVenueDepositTooHigh       = errors.New("kapora toplam ücretten fazla olamaz")
+	ErrVenueReservationNotOption = errors.New("yalnızca opsiyonlu rezervasyonlar kesinleştirilebilir")
+	ErrVenueReservationClosed    = errors.New("iptal edilmiş ya da süresi dolmuş rezervasyon değiştirilemez")
+)
+
+var activeVenueStatuses = []string{models.VenueReservationStatusOption, models.VenueReservationStatusConfirmed}
+
+// VenueCalendarDay, takvimde bir günün sabah ve akşam seansını tutan rezervasyonları taşır.
+// Tam gün rezervasyonu her iki seansta da görünür.
+type VenueCalendarDay struct {
+	Date    time.Time
+	InMonth bool
+	Past    bool
+	Today   bool
+	Morning *models.VenueReservation
+	Evening *models.VenueReservation
+}
+
+// VenueCalendar, seçilen mekanın aylık takvimini ve o aydaki tüm rezervasyonları taşır.
+type VenueCalendar struct {
+	Venue        *models.Business
+	Venues       []models.Business
+	Month        time.Time
+	Weeks        [][]VenueCalendarDay
+	Reservations []models.VenueReservation
+}
+
+type IVenueReservationService interface {
+	// GetCalendar, sahibin kapasitesi tanımlı mekanlarından seçileni (yoksa ilkini) aylık takvimle döner.
+	GetCalendar(ctx context.Context, ownerID uint, params requests.VenueCalendarParams) (*VenueCalendar, error)
+	GetVenue(ctx context.Context, ownerID, businessID uint) (*models.Business, error)
+	Create(ctx context.Context, ownerID, businessID uint, req requests.VenueReservationRequest) (*models.VenueReservation, error)
+	Confirm(ctx context.Context, ownerID, id uint) error
+	RecordDeposit(ctx context.Context, ownerID, id uint, amount float64) error
+	Cancel(ctx context.Context, ownerID, id uint, reason string) error
+	OptionDays() int
+}
+
+type VenueReservationService struct {
+	repo         repositories.IVenueReservationRepository
+	businessRepo repositories.IBusinessRepository
+	optionDays   int
+	now          func() time.Time
+}
+
+// NewVenueReservationService; RESERVATION_OPTION_DAYS opsiyonun kaç gün tarih tutacağını belirler.
+func NewVenueReservationService() IVenueReservationService {
+	return &VenueReservationService{
+		repo:         repositories.NewVenueReservationRepository(),
+		businessRepo: repositories.NewBusinessRepository(),
+		optionDays:   envconfig.Int("RESERVATION_OPTION_DAYS", 7),
+		now:          time.Now,
+	}
+}
+
+func (s *VenueReservationService) GetCalendar(ctx context.Context, ownerID uint, params requests.VenueCalendarParams) (*VenueCalendar, error) {
+	venues, err := s.venues(ctx, ownerID)
+	if err != nil {
+		return nil, err
+	}
+
+	calendar := &VenueCalendar{Venues: venues, Month: params.Month}
+	for i := range venues {
+		if params.BusinessID == 0 || venues[i].ID == params.BusinessID {
+			calendar.Venue = &venues[i]
+			break
+		}
+	}
+	if calendar.Venue == nil {
+		if params.BusinessID != 0 {
+			return nil, ErrVenueNotFound
+		}
+		return calendar, nil
+	}
+
+	now := s.now()
+	if err := s.repo.ExpireOptions(ctx, calendar.Venue.ID, now); err != nil {
+		logconfig.Log.Warn("Süresi dolan opsiyonlar güncellenemedi", zap.Uint("business_id", calendar.Venue.ID), zap.Error(err))
+	}
+
+	// Takvim pazartesiden başlar; ayın ilk haftasının başı ile son haftasının sonu arası yüklenir
+	first := params.Month
+	start := first.AddDate(0, 0, -((int(first.Weekday()) + 6) % 7))
+	last := first.AddDate(0, 1, -1)
+	end := last.AddDate(0, 0, 7-((int(last.Weekday())+6)%7))
+
+	items, err := s.repo.GetReservations(ctx, calendar.Venue.ID, venueDate(start), venueDate(end))
+	if err != nil {
+		logconfig.Log.Error("Rezervasyonlar getirilemedi", zap.Uint("business_id", calendar.Venue.ID), zap.Error(err))
+		return nil, err
+	}
+
+	byDay := make(map[string][]*models.VenueReservation)
+	for i := range items {
+		item := &items[i]
+		if !item.Date.Before(venueDate(first)) && item.Date.Before(venueDate(first.AddDate(0, 1, 0))) {
+			calendar.Reservations = append(calendar.Reservations, *item)
+		}
+		if item.Blocks(now) {
+			key := item.Date.Format("2006-01-02")
+			byDay[key] = append(byDay[key], item)
+		}
+	}
+
+	today := now.In(appointmentLocation).Format("2006-01-02")
+	var week []VenueCalendarDay
+	for day := start; day.Before(end); day = day.AddDate(0, 0, 1) {
+		key := day.Format("2006-01-02")
+		cell := VenueCalendarDay{
+			Date:    day,
+			InMonth: day.Month() == first.Month(),
+			Past:    key < today,
+			Today:   key == today,
+		}
+		for _, item := range byDay[key] {
+			if item.Covers(models.VenueSessionMorning) {
+				cell.Morning = item
+			}
+			if item.Covers(models.VenueSessionEvening) {
+				cell.Evening = item
+			}
+		}
+		week = append(week, cell)
+		if len(week) == 7 {
+			calendar.Weeks = append(calendar.Weeks, week)
+			week = nil
+		}
+	}
+	return calendar, nil
+}
+
+func (s *VenueReservationService) GetVenue(ctx context.Context, ownerID, businessID uint) (*models.Business, error) {
+	business, err := s.businessRepo.GetUserBusinessByID(ctx, ownerID, businessID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return nil, ErrVenueNotFound
+		}
+		return nil, err
+	}
+	if business.Capacity == 0 {
+		return nil, ErrVenueNoCapacity
+	}
+	return business, nil
+}
+
+func (s *VenueReservationService) Create(ctx context.Context, ownerID, businessID uint, req requests.VenueReservationRequest) (*models.VenueReservation, error) {
+	venue, err := s.GetVenue(ctx, ownerID, businessID)
+	if err != nil {
+		return nil, err
+	}
+
+	day, err := time.ParseInLocation("2006-01-02", req.Date, appointmentLocation)
+	if err != nil {
+		return nil, ErrVenueInvalidDate
+	}
+	now := s.now()
+	if day.Format("2006-01-02") < now.In(appointmentLocation).Format("2006-01-02") {
+		return nil, ErrVenuePastDate
+	}
+	if req.GuestCountUint() > venue.Capacity {
+		return nil, ErrVenueOverCapacity
+	}
+	total, deposit := req.TotalPriceValue(), req.DepositAmountValue()
+	if total > 0 && deposit > total {
+		return nil, ErrVenueDepositTooHigh
+	}
+
+	reservation := &models.VenueReservation{
+		BusinessID:    venue.ID,
+		Date:          venueDate(day),
+		Session:       req.Session,
+		EventType:     req.EventType,
+		GuestCount:    req.GuestCountUint(),
+		CustomerName:  req.CustomerName,
+		CustomerPhone: req.CustomerPhone,
+		CustomerEmail: req.CustomerEmail,
+		Note:          req.Note,
+		TotalPrice:    total,
+		DepositAmount: deposit,
+		Status:        req.Status,
+	}
+	if reservation.Status == models.VenueReservationStatusConfirmed {
+		reservation.ConfirmedAt = &now
+	} else {
+		expires := now.AddDate(0, 0, s.optionDays)
+		reservation.OptionExpiresAt = &expires
+	}
+
+	if err := s.repo.Create(ctx, reservation, now); err != nil {
+		if errors.Is(err, repositories.ErrVenueSessionTaken) {
+			return nil, ErrVenueSessionTaken
+		}
+		logconfig.Log.Error("Rezervasyon oluşturulamadı", zap.Uint("business_id", venue.ID), zap.Error(err))
+		return nil, err
+	}
+	return reservation, nil
+}
+
+// Confirm, opsiyonu kesin rezervasyona çevirir; süresi dolmuş opsiyon tarih hâlâ boşsa kesinleştirilebilir.
+func (s *VenueReservationService) Confirm(ctx context.Context, ownerID, id uint) error {
+	reservation, err := s.ownerReservation(ctx, ownerID, id)
+	if err != nil {
+		return err
+	}
+	if reservation.Status != models.VenueReservationStatusOption && reservation.Status != models.VenueReservationStatusExpired {
+		return ErrVenueReservationNotOption
+	}
+
+	if err := s.repo.Confirm(ctx, reservation, s.now()); err != nil {
+		switch {
+		case errors.Is(err, repositories.ErrVenueSessionTaken):
+			return ErrVenueSessionTaken
+		case errors.Is(err, repositories.ErrNotFound):
+			return ErrVenueReservationNotFound
+		}
+		logconfig.Log.Error("Rezervasyon kesinleştirilemedi", zap.Uint("reservation_id", id), zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+func (s *VenueReservationService) RecordDeposit(ctx context.Context, ownerID, id uint, amount float64) error {
+	reservation, err := s.ownerReservation(ctx, ownerID, id)
+	if err != nil {
+		return err
+	}
+	if reservation.TotalPrice > 0 && amount > reservation.TotalPrice {
+		return ErrVenueDepositTooHigh
+	}
+	return s.updateStatus(ctx, reservation, map[string]interface{}{
+		"deposit_amount":  amount,
+		"deposit_paid_at": s.now(),
+	})
+}
+
+func (s *VenueReservationService) Cancel(ctx context.Context, ownerID, id uint, reason string) error {
+	reservation, err := s.ownerReservation(ctx, ownerID, id)
+	if err != nil {
+		return err
+	}
+	return s.updateStatus(ctx, reservation, map[string]interface{}{
+		"status":        models.VenueReservationStatusCancelled,
+		"cancel_reason": reason,
+	})
+}
+
+func (s *VenueReservationService) OptionDays() int {
+	return s.optionDays
+}
+
+func (s *VenueReservationService) ownerReservation(ctx context.Context, ownerID, id uint) (*models.VenueReservation, error) {
+	reservation, err := s.repo.GetOwnerReservation(ctx, ownerID, id)
+	if err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return nil, ErrVenueReservationNotFound
+		}
+		return nil, err
+	}
+	return reservation, nil
+}
+
+// updateStatus, yalnızca süresi dolmamış opsiyon ve kesin rezervasyonları günceller.
+func (s *VenueReservationService) updateStatus(ctx context.Context, reservation *models.VenueReservation, data map[string]interface{}) error {
+	if !reservation.Blocks(s.now()) {
+		return ErrVenueReservationClosed
+	}
+	if err := s.repo.UpdateStatus(ctx, reservation.ID, activeVenueStatuses, data); err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return ErrVenueReservationClosed
+		}
+		logconfig.Log.Error("Rezervasyon güncellenemedi", zap.Uint("reservation_id", reservation.ID), zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// venues, sahibin kapasitesi tanımlı işletmelerini döner; yalnızca bunlar rezervasyon alabilir.
+func (s *VenueReservationService) venues(ctx context.Context, ownerID uint) ([]models.Business, error) {
+	businesses, _, err := s.businessRepo.GetUserBusinesses(ctx, ownerID, requests.BusinessListParams{
+		SortBy: "title", OrderBy: "asc", Page: 1, PerPage: 200,
+	})
+	if err != nil {
+		logconfig.Log.Error("Mekanlar getirilemedi", zap.Uint("owner_id", ownerID), zap.Error(err))
+		return nil, err
+	}
+	venues := make([]models.Business, 0, len(businesses))
+	for _, b := range businesses {
+		if b.Capacity > 0 {
+			venues = append(venues, b)
+		}
+	}
+	return venues, nil
+}
+
+// venueDate, yerel takvim gününü UTC gece yarısına çevirir; date kolonu veritabanı saat diliminden etkilenmez.
+func venueDate(day time.Time) time.Time {
+	return time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+var _ IVenueReservationService = (*VenueReservationService)(nil)